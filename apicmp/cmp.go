@@ -1,7 +1,14 @@
-package cmp
+// Package apicmp provides generic comparison helpers.
+//
+// Equal methods of types in this module are built on them,
+// so code using these helpers compares values with the same semantics as the types do.
+package apicmp
 
 import "maps"
 
+// SliceEqualUnordered returns true if a and b have the same elements by their Equal method, ignoring order.
+//
+// Duplicated elements are counted.
 func SliceEqualUnordered[T interface{ Equal(T) bool }](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -25,6 +32,9 @@ A:
 	return len(b) == 0
 }
 
+// SliceEqEqUnordered returns true if a and b have the same elements by ==, ignoring order.
+//
+// Duplicated elements are counted.
 func SliceEqEqUnordered[T comparable](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -48,6 +58,7 @@ A:
 	return len(b) == 0
 }
 
+// SliceEqual returns true if a and b have the same elements by their Equal method, in the same order.
 func SliceEqual[T interface{ Equal(T) bool }](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -62,6 +73,7 @@ func SliceEqual[T interface{ Equal(T) bool }](a, b []T) bool {
 	return true
 }
 
+// SliceEqEq returns true if a and b have the same elements by ==, in the same order.
 func SliceEqEq[T comparable](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -76,10 +88,28 @@ func SliceEqEq[T comparable](a, b []T) bool {
 	return true
 }
 
+// PtrEqual returns true if both a and b are nil, or both are not nil and same by their Equal method.
+func PtrEqual[T interface{ Equal(T) bool }](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return (*a).Equal(*b)
+}
+
+// PtrEqEq returns true if both a and b are nil, or both are not nil and same by ==.
+func PtrEqEq[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// MapEqual returns true if a and b have the same keys, and values for each key are same by their Equal method.
 func MapEqual[K comparable, V interface{ Equal(V) bool }](a, b map[K]V) bool {
 	return MapEqualWith(a, b, V.Equal)
 }
 
+// MapEqualWith returns true if a and b have the same keys, and values for each key are same by pred.
 func MapEqualWith[K comparable, V any](a, b map[K]V, pred func(a, b V) bool) bool {
 	if len(a) != len(b) {
 		return false
@@ -1,9 +1,9 @@
-package cmp_test
+package apicmp_test
 
 import (
 	"testing"
 
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"github.com/opst/knitfab-api-types/apicmp"
 )
 
 type Int int
@@ -24,7 +24,7 @@ func TestSliceEqualUnordered(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.SliceEqualUnordered(when.A, when.B)
+			got := apicmp.SliceEqualUnordered(when.A, when.B)
 			if got != then.Want {
 				t.Errorf("got %v, want %v", got, then.Want)
 			}
@@ -71,7 +71,7 @@ func TestSliceEqEqUnordered(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.SliceEqEqUnordered(when.A, when.B)
+			got := apicmp.SliceEqEqUnordered(when.A, when.B)
 			if got != then.Want {
 				t.Errorf("got %v, want %v", got, then.Want)
 			}
@@ -117,7 +117,7 @@ func TestSliceEqual(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.SliceEqual(when.A, when.B)
+			got := apicmp.SliceEqual(when.A, when.B)
 			if got != then.Want {
 				t.Errorf("got %v, want %v", got, then.Want)
 			}
@@ -146,6 +146,48 @@ func TestSliceEqual(t *testing.T) {
 	))
 }
 
+func TestPtrEqual(t *testing.T) {
+	one, another, two := Int(1), Int(1), Int(2)
+
+	for name, testcase := range map[string]struct {
+		A, B *Int
+		Want bool
+	}{
+		"when A and B are nil":               {A: nil, B: nil, Want: true},
+		"when only A is nil":                 {A: nil, B: &one, Want: false},
+		"when only B is nil":                 {A: &one, B: nil, Want: false},
+		"when A and B point the same value":  {A: &one, B: &another, Want: true},
+		"when A and B point different value": {A: &one, B: &two, Want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := apicmp.PtrEqual(testcase.A, testcase.B); got != testcase.Want {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
+
+func TestPtrEqEq(t *testing.T) {
+	one, another, two := "1", "1", "2"
+
+	for name, testcase := range map[string]struct {
+		A, B *string
+		Want bool
+	}{
+		"when A and B are nil":               {A: nil, B: nil, Want: true},
+		"when only A is nil":                 {A: nil, B: &one, Want: false},
+		"when only B is nil":                 {A: &one, B: nil, Want: false},
+		"when A and B point the same value":  {A: &one, B: &another, Want: true},
+		"when A and B point different value": {A: &one, B: &two, Want: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := apicmp.PtrEqEq(testcase.A, testcase.B); got != testcase.Want {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
+
 func TestSliceEqEq(t *testing.T) {
 	type When struct {
 		A []int
@@ -157,7 +199,7 @@ func TestSliceEqEq(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.SliceEqEq(when.A, when.B)
+			got := apicmp.SliceEqEq(when.A, when.B)
 			if got != then.Want {
 				t.Errorf("got %v, want %v", got, then.Want)
 			}
@@ -198,7 +240,7 @@ func TestMapEqual(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.MapEqual(when.A, when.B)
+			got := apicmp.MapEqual(when.A, when.B)
 			if got != then.Want {
 				t.Errorf("got %v, want %v", got, then.Want)
 			}
@@ -263,7 +305,7 @@ func TestMapEqualWith(t *testing.T) {
 
 	theory := func(when When, then Then) func(t *testing.T) {
 		return func(t *testing.T) {
-			got := cmp.MapEqualWith(
+			got := apicmp.MapEqualWith(
 				when.A, when.B,
 				func(a, b Int) bool { return a == b },
 			)
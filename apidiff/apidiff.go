@@ -0,0 +1,149 @@
+// Package apidiff reports differences between values of API types, field by field.
+//
+// Values are compared in their JSON representation, so paths in reports are JSON paths
+// like "$.inputs[0].tags[1]", and values are JSON texts.
+package apidiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldDiff is a difference at a field.
+type FieldDiff struct {
+	// Path is the JSON path of the field, like "$.inputs[0].path".
+	Path string
+
+	// A is the value of the field in the first value, as JSON.
+	//
+	// If the field is absent in the first value, this is nil.
+	A json.RawMessage
+
+	// B is the value of the field in the second value, as JSON.
+	//
+	// If the field is absent in the second value, this is nil.
+	B json.RawMessage
+}
+
+func (d FieldDiff) String() string {
+	show := func(v json.RawMessage) string {
+		if v == nil {
+			return "(absent)"
+		}
+		return string(v)
+	}
+	return fmt.Sprintf("%s: %s != %s", d.Path, show(d.A), show(d.B))
+}
+
+// Of compares a and b in JSON, and returns differences.
+//
+// Objects are compared key by key, and arrays are compared element by element in order.
+// If a or b cannot be marshalled, it is reported as a difference at "$".
+func Of(a, b any) []FieldDiff {
+	ja, errA := toJSON(a)
+	jb, errB := toJSON(b)
+	if errA != nil || errB != nil {
+		return []FieldDiff{{Path: "$", A: errorJSON(errA), B: errorJSON(errB)}}
+	}
+	diffs := []FieldDiff{}
+	walk(&diffs, "$", ja, jb)
+	return diffs
+}
+
+// Format formats diffs in lines.
+func Format(diffs []FieldDiff) string {
+	lines := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func toJSON(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var ret any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func errorJSON(err error) json.RawMessage {
+	if err == nil {
+		return nil
+	}
+	b, _ := json.Marshal("error: " + err.Error())
+	return b
+}
+
+func raw(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errorJSON(err)
+	}
+	return b
+}
+
+func walk(diffs *[]FieldDiff, path string, a, b any) {
+	switch va := a.(type) {
+	case map[string]any:
+		vb, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := []string{}
+		for k := range va {
+			keys = append(keys, k)
+		}
+		for k := range vb {
+			if _, ok := va[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			ea, inA := va[k]
+			eb, inB := vb[k]
+			p := path + "." + k
+			switch {
+			case !inA:
+				*diffs = append(*diffs, FieldDiff{Path: p, B: raw(eb)})
+			case !inB:
+				*diffs = append(*diffs, FieldDiff{Path: p, A: raw(ea)})
+			default:
+				walk(diffs, p, ea, eb)
+			}
+		}
+		return
+	case []any:
+		vb, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(va), len(vb)); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case len(va) <= i:
+				*diffs = append(*diffs, FieldDiff{Path: p, B: raw(vb[i])})
+			case len(vb) <= i:
+				*diffs = append(*diffs, FieldDiff{Path: p, A: raw(va[i])})
+			default:
+				walk(diffs, p, va[i], vb[i])
+			}
+		}
+		return
+	}
+
+	ra, rb := raw(a), raw(b)
+	if !bytes.Equal(ra, rb) {
+		*diffs = append(*diffs, FieldDiff{Path: path, A: ra, B: rb})
+	}
+}
@@ -0,0 +1,57 @@
+package apidiff_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apidiff"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestOf(t *testing.T) {
+	a := plans.PlanSpec{
+		Image:  plans.Image{Repository: "example.com/train", Tag: "v1"},
+		Inputs: []plans.Mountpoint{{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+		Outputs: []plans.Mountpoint{
+			{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+		},
+		ServiceAccount: "trainer",
+	}
+	b := plans.PlanSpec{
+		Image:  plans.Image{Repository: "example.com/train", Tag: "v2"},
+		Inputs: []plans.Mountpoint{{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+		Outputs: []plans.Mountpoint{
+			{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+			{Path: "/metrics", Tags: []tags.Tag{{Key: "type", Value: "metrics"}}},
+		},
+		Project: "demo",
+	}
+
+	got := a.Diff(b)
+	want := []string{
+		`$.image: "example.com/train:v1" != "example.com/train:v2"`,
+		`$.outputs[1]: (absent) != {"path":"/metrics","tags":["type:metrics"]}`,
+		`$.project: (absent) != "demo"`,
+		`$.service_account: "trainer" != (absent)`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected diffs:\n%s", apidiff.Format(got))
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("diffs[%d]: got %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	// equal values have no diffs, even if their order differs.
+	c := a
+	c.Inputs = append(c.Inputs, plans.Mountpoint{Path: "/in2", Tags: []tags.Tag{{Key: "type", Value: "extra"}}})
+	d := a
+	d.Inputs = []plans.Mountpoint{c.Inputs[1], c.Inputs[0]}
+	if diffs := c.Diff(d); diffs != nil {
+		t.Errorf("equal values have diffs:\n%s", apidiff.Format(diffs))
+	}
+	if diffs := apidiff.Of(c, d); len(diffs) == 0 {
+		t.Error("Of should compare arrays in order")
+	}
+}
@@ -0,0 +1,257 @@
+// Package audit defines entries of the audit log, which records who changed what in Knitfab.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Action is what was done, in the form "<resource kind>.<verb>" or "<resource kind>.<field>.<verb>".
+type Action string
+
+// Known Actions. Servers can record other Actions; clients should be tolerant of them.
+const (
+	// Actions on Plans.
+	PlanCreate           Action = "plan.create"
+	PlanActivate         Action = "plan.activate"
+	PlanDeactivate       Action = "plan.deactivate"
+	PlanResourcesChange  Action = "plan.resources.change"
+	PlanAnnotationChange Action = "plan.annotation.change"
+	PlanDelete           Action = "plan.delete"
+
+	// Actions on Runs.
+	RunAbort   Action = "run.abort"
+	RunTearoff Action = "run.tearoff"
+	RunRetry   Action = "run.retry"
+	RunDelete  Action = "run.delete"
+
+	// Actions on Data.
+	DataCreate    Action = "data.create"
+	DataTagChange Action = "data.tag.change"
+	DataPurge     Action = "data.purge"
+	DataRestore   Action = "data.restore"
+)
+
+func (a Action) String() string {
+	return string(a)
+}
+
+// Kind returns the kind of the resource which the Action is on, like "plan" for "plan.create".
+func (a Action) Kind() string {
+	k, _, _ := strings.Cut(string(a), ".")
+	return k
+}
+
+// Resource is the target of an Entry.
+type Resource struct {
+	// Kind is the kind of the resource, like "plan", "run" or "data".
+	Kind string `json:"kind"`
+
+	// Id is the id of the resource: planId, runId or knitId.
+	Id string `json:"id"`
+}
+
+func (r Resource) Equal(o Resource) bool {
+	return r.Kind == o.Kind && r.Id == o.Id
+}
+
+func (r Resource) String() string {
+	return r.Kind + "/" + r.Id
+}
+
+// Entry is the format for response body from Knitfab APIs below:
+//
+// - GET /api/audit[?...] (as list)
+type Entry struct {
+	// At is when the Action was done.
+	At rfctime.RFC3339 `json:"at"`
+
+	// Actor is who did the Action: the name of the user, or the service account.
+	Actor string `json:"actor"`
+
+	// Action is what was done.
+	Action Action `json:"action"`
+
+	// Resource is the target of the Action.
+	Resource Resource `json:"resource"`
+
+	// RequestBody is the request body of the Action, as it was sent.
+	//
+	// Secrets in the body are masked by the server. If empty, the request had no body.
+	RequestBody json.RawMessage `json:"requestBody,omitempty"`
+}
+
+// Equal compares Entries. RequestBody are compared ignoring insignificant spaces.
+func (e Entry) Equal(o Entry) bool {
+	return e.At.Equal(o.At) &&
+		e.Actor == o.Actor &&
+		e.Action == o.Action &&
+		e.Resource.Equal(o.Resource) &&
+		jsonEqual(e.RequestBody, o.RequestBody)
+}
+
+// Validate checks required fields are given, and Resource.Kind matches with Action.
+func (e Entry) Validate() error {
+	switch {
+	case e.Actor == "":
+		return fmt.Errorf(`required field missing: "actor"`)
+	case e.Action == "":
+		return fmt.Errorf(`required field missing: "action"`)
+	case e.Resource.Kind == "" || e.Resource.Id == "":
+		return fmt.Errorf(`required field missing: "resource"`)
+	case e.Resource.Kind != e.Action.Kind():
+		return fmt.Errorf(`resource %s does not match with action %q`, e.Resource, e.Action)
+	}
+	if len(e.RequestBody) != 0 && !json.Valid(e.RequestBody) {
+		return fmt.Errorf(`"requestBody" should be a JSON`)
+	}
+	return nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	ca, cb := &bytes.Buffer{}, &bytes.Buffer{}
+	if json.Compact(ca, a) != nil || json.Compact(cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// Query parameter names for Query.
+const (
+	ParamActor  = "actor"
+	ParamAction = "action"
+	ParamKind   = "kind"
+	ParamId     = "id"
+	ParamSince  = "since"
+	ParamUntil  = "until"
+)
+
+// Query is the query parameters for Knitfab APIs below:
+//
+// - GET /api/audit[?...]
+//
+// Entries matching all of the conditions are found.
+// For conditions with multiple values, Entries matching any of the values are found.
+type Query struct {
+	// Actors are who did the Actions.
+	Actors []string
+
+	// Actions are what were done.
+	Actions []Action
+
+	// Resource is the target of the Actions.
+	//
+	// If Id is empty, Entries on any resources of the Kind are found.
+	Resource *Resource
+
+	// Since is the lower bound (inclusive) of At of Entries.
+	//
+	// If nil, it is unbounded.
+	Since *rfctime.RFC3339
+
+	// Until is the upper bound (exclusive) of At of Entries.
+	//
+	// If nil, it is unbounded.
+	Until *rfctime.RFC3339
+}
+
+func (q Query) Equal(o Query) bool {
+	return apicmp.SliceEqEqUnordered(q.Actors, o.Actors) &&
+		apicmp.SliceEqEqUnordered(q.Actions, o.Actions) &&
+		apicmp.PtrEqual(q.Resource, o.Resource) &&
+		apicmp.PtrEqual(q.Since, o.Since) &&
+		apicmp.PtrEqual(q.Until, o.Until)
+}
+
+// Encode returns the query as url.Values.
+func (q Query) Encode() url.Values {
+	v := url.Values{}
+	for _, a := range q.Actors {
+		v.Add(ParamActor, a)
+	}
+	for _, a := range q.Actions {
+		v.Add(ParamAction, a.String())
+	}
+	if q.Resource != nil {
+		v.Set(ParamKind, q.Resource.Kind)
+		if q.Resource.Id != "" {
+			v.Set(ParamId, q.Resource.Id)
+		}
+	}
+	if q.Since != nil {
+		v.Set(ParamSince, q.Since.String())
+	}
+	if q.Until != nil {
+		v.Set(ParamUntil, q.Until.String())
+	}
+	return v
+}
+
+// Decode reads the query from url.Values.
+func (q *Query) Decode(v url.Values) error {
+	ret := Query{Actors: v[ParamActor]}
+	for _, a := range v[ParamAction] {
+		ret.Actions = append(ret.Actions, Action(a))
+	}
+
+	kind, id := v.Get(ParamKind), v.Get(ParamId)
+	switch {
+	case kind != "":
+		ret.Resource = &Resource{Kind: kind, Id: id}
+	case id != "":
+		return fmt.Errorf(`query parameter "%s" requires "%s"`, ParamId, ParamKind)
+	}
+
+	for _, p := range []struct {
+		name string
+		dest **rfctime.RFC3339
+	}{
+		{name: ParamSince, dest: &ret.Since},
+		{name: ParamUntil, dest: &ret.Until},
+	} {
+		expr := v.Get(p.name)
+		if expr == "" {
+			continue
+		}
+		t, err := rfctime.ParseRFC3339DateTime(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s" should be RFC3339 date-time: %q`, p.name, expr)
+		}
+		*p.dest = &t
+	}
+
+	*q = ret
+	return nil
+}
+
+// Matches returns true if e matches all of the conditions of q.
+func (q Query) Matches(e Entry) bool {
+	if len(q.Actors) != 0 && !slices.Contains(q.Actors, e.Actor) {
+		return false
+	}
+	if len(q.Actions) != 0 && !slices.Contains(q.Actions, e.Action) {
+		return false
+	}
+	if r := q.Resource; r != nil {
+		if r.Kind != e.Resource.Kind || (r.Id != "" && r.Id != e.Resource.Id) {
+			return false
+		}
+	}
+	if q.Since != nil && e.At.Time().Before(q.Since.Time()) {
+		return false
+	}
+	if q.Until != nil && !e.At.Time().Before(q.Until.Time()) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/audit"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+func TestEntry(t *testing.T) {
+	at := rfctime.RFC3339(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	e := audit.Entry{
+		At:          at,
+		Actor:       "alice",
+		Action:      audit.DataTagChange,
+		Resource:    audit.Resource{Kind: "data", Id: "0190a1b2-0000-7000-8000-000000000301"},
+		RequestBody: json.RawMessage(`{"add": ["type:model"], "remove": []}`),
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertJSONRoundTrip(t, e)
+
+	compacted := e
+	compacted.RequestBody = json.RawMessage(`{"add":["type:model"],"remove":[]}`)
+	if !e.Equal(compacted) {
+		t.Error("request bodies differing only in spaces should be equal")
+	}
+
+	for name, invalid := range map[string]audit.Entry{
+		"no actor":       {Action: audit.RunAbort, Resource: audit.Resource{Kind: "run", Id: "run-1"}},
+		"kind mismatch":  {Actor: "alice", Action: audit.RunAbort, Resource: audit.Resource{Kind: "plan", Id: "plan-1"}},
+		"malformed body": {Actor: "alice", Action: audit.RunAbort, Resource: audit.Resource{Kind: "run", Id: "run-1"}, RequestBody: json.RawMessage(`{`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid entry is accepted: %+v", invalid)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	since := rfctime.RFC3339(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	until := rfctime.RFC3339(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	q := audit.Query{
+		Actors:   []string{"alice", "bob"},
+		Actions:  []audit.Action{audit.RunAbort, audit.RunRetry},
+		Resource: &audit.Resource{Kind: "run"},
+		Since:    &since,
+		Until:    &until,
+	}
+
+	got := audit.Query{}
+	if err := got.Decode(q.Encode()); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(q) {
+		t.Errorf("round trip: got %+v, want %+v", got, q)
+	}
+	if err := got.Decode(url.Values{audit.ParamId: {"run-1"}}); err == nil {
+		t.Error("id without kind is accepted")
+	}
+
+	e := audit.Entry{
+		At:       rfctime.RFC3339(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+		Actor:    "bob",
+		Action:   audit.RunAbort,
+		Resource: audit.Resource{Kind: "run", Id: "run-1"},
+	}
+	if !q.Matches(e) {
+		t.Errorf("%+v should match", e)
+	}
+	for name, modify := range map[string]func(*audit.Entry){
+		"actor":  func(e *audit.Entry) { e.Actor = "carol" },
+		"action": func(e *audit.Entry) { e.Action = audit.RunDelete },
+		"kind":   func(e *audit.Entry) { e.Resource = audit.Resource{Kind: "plan", Id: "plan-1"} },
+		"time":   func(e *audit.Entry) { e.At = until },
+	} {
+		t.Run(name, func(t *testing.T) {
+			other := e
+			modify(&other)
+			if q.Matches(other) {
+				t.Errorf("%+v should not match", other)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package auth defines payloads to authenticate to Knitfab and container registries.
+//
+// Types holding secrets print them redacted with String (and GoString),
+// so that they can be logged safely. Marshalled forms are not redacted.
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Redacted is printed instead of secrets.
+const Redacted = "[REDACTED]"
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GrantType is how TokenRequest authenticates.
+type GrantType string
+
+const (
+	// GrantPassword authenticates with Username and Password.
+	GrantPassword GrantType = "password"
+
+	// GrantRefreshToken authenticates with RefreshToken, issued with the last Token.
+	GrantRefreshToken GrantType = "refresh_token"
+)
+
+// TokenRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/auth/token
+type TokenRequest struct {
+	// GrantType is how the request authenticates.
+	GrantType GrantType `json:"grantType"`
+
+	// Username is the name of the user. Required for GrantPassword.
+	Username string `json:"username,omitempty"`
+
+	// Password is the password of the user. Required for GrantPassword.
+	Password string `json:"password,omitempty"`
+
+	// RefreshToken is the refresh token. Required for GrantRefreshToken.
+	RefreshToken string `json:"refreshToken,omitempty"`
+
+	// Scope are the scopes requested for the Token.
+	//
+	// If empty, the default scopes of the user are granted.
+	Scope []string `json:"scope,omitempty"`
+}
+
+func (r TokenRequest) Equal(o TokenRequest) bool {
+	return r.GrantType == o.GrantType &&
+		r.Username == o.Username &&
+		r.Password == o.Password &&
+		r.RefreshToken == o.RefreshToken &&
+		apicmp.SliceEqEqUnordered(r.Scope, o.Scope)
+}
+
+// Validate checks fields required for the GrantType are given, and others are not.
+func (r TokenRequest) Validate() error {
+	switch r.GrantType {
+	case GrantPassword:
+		if r.Username == "" {
+			return fmt.Errorf(`required field missing: "username"`)
+		}
+		if r.Password == "" {
+			return fmt.Errorf(`required field missing: "password"`)
+		}
+		if r.RefreshToken != "" {
+			return fmt.Errorf(`"refreshToken" should not be set for grant type %q`, r.GrantType)
+		}
+	case GrantRefreshToken:
+		if r.RefreshToken == "" {
+			return fmt.Errorf(`required field missing: "refreshToken"`)
+		}
+		if r.Password != "" {
+			return fmt.Errorf(`"password" should not be set for grant type %q`, r.GrantType)
+		}
+	case "":
+		return fmt.Errorf(`required field missing: "grantType"`)
+	default:
+		return fmt.Errorf(`"grantType" should be "%s" or "%s": %q`, GrantPassword, GrantRefreshToken, r.GrantType)
+	}
+	return nil
+}
+
+// String returns the request with secrets redacted.
+func (r TokenRequest) String() string {
+	return fmt.Sprintf(
+		"{GrantType:%s Username:%s Password:%s RefreshToken:%s Scope:%v}",
+		r.GrantType, r.Username, redact(r.Password), redact(r.RefreshToken), r.Scope,
+	)
+}
+
+// GoString is String, so that "%#v" does not print secrets.
+func (r TokenRequest) GoString() string {
+	return "auth.TokenRequest" + r.String()
+}
+
+// Token is the format for response body from Knitfab APIs below:
+//
+// - POST /api/auth/token
+type Token struct {
+	// AccessToken is the token to be sent in the "Authorization" header.
+	AccessToken string `json:"accessToken"`
+
+	// ExpiresAt is the time when AccessToken expires.
+	ExpiresAt rfctime.RFC3339 `json:"expiresAt"`
+
+	// RefreshToken is the token to get a new Token with GrantRefreshToken.
+	//
+	// If empty, the Token cannot be refreshed.
+	RefreshToken string `json:"refreshToken,omitempty"`
+
+	// Scope are the scopes granted for the Token.
+	Scope []string `json:"scope,omitempty"`
+}
+
+func (t Token) Equal(o Token) bool {
+	return t.AccessToken == o.AccessToken &&
+		t.ExpiresAt.Equal(o.ExpiresAt) &&
+		t.RefreshToken == o.RefreshToken &&
+		apicmp.SliceEqEqUnordered(t.Scope, o.Scope)
+}
+
+// Expired returns true if AccessToken does not work at now.
+//
+// To refresh Tokens before they expire, pass now with some margin, like time.Now().Add(time.Minute).
+func (t Token) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt.Time())
+}
+
+// HasScope returns true if the scope is granted.
+func (t Token) HasScope(scope string) bool {
+	return slices.Contains(t.Scope, scope)
+}
+
+// SetHeader sets the "Authorization" header with AccessToken as a bearer token.
+func (t Token) SetHeader(h http.Header) {
+	h.Set("Authorization", "Bearer "+t.AccessToken)
+}
+
+// String returns the Token with secrets redacted.
+func (t Token) String() string {
+	return fmt.Sprintf(
+		"{AccessToken:%s ExpiresAt:%s RefreshToken:%s Scope:%v}",
+		redact(t.AccessToken), t.ExpiresAt, redact(t.RefreshToken), t.Scope,
+	)
+}
+
+// GoString is String, so that "%#v" does not print secrets.
+func (t Token) GoString() string {
+	return "auth.Token" + t.String()
+}
+
+// RegistryCredential is a credential for a container registry,
+// used to push images for Plans by "knit" login flows.
+//
+// Either of Username and Password, or IdentityToken is required.
+type RegistryCredential struct {
+	// Registry is the host (and port) of the registry, like "registry.example.com:5000".
+	Registry string `json:"registry"`
+
+	// Username is the name of the user.
+	Username string `json:"username,omitempty"`
+
+	// Password is the password (or the access token) of the user.
+	Password string `json:"password,omitempty"`
+
+	// IdentityToken is the token of the registry, used instead of Username and Password.
+	IdentityToken string `json:"identityToken,omitempty"`
+}
+
+func (c RegistryCredential) Equal(o RegistryCredential) bool {
+	return c.Registry == o.Registry &&
+		c.Username == o.Username &&
+		c.Password == o.Password &&
+		c.IdentityToken == o.IdentityToken
+}
+
+// Validate checks Registry is given, with either of Username and Password, or IdentityToken.
+func (c RegistryCredential) Validate() error {
+	if c.Registry == "" {
+		return fmt.Errorf(`required field missing: "registry"`)
+	}
+	if strings.Contains(c.Registry, "/") {
+		return fmt.Errorf(`"registry" should be a host, not a URL or a repository: %q`, c.Registry)
+	}
+	basic := c.Username != "" || c.Password != ""
+	switch {
+	case basic && c.IdentityToken != "":
+		return fmt.Errorf(`"username"/"password" and "identityToken" are mutually exclusive`)
+	case basic && (c.Username == "" || c.Password == ""):
+		return fmt.Errorf(`"username" and "password" should be given together`)
+	case !basic && c.IdentityToken == "":
+		return fmt.Errorf(`one of "username"/"password" or "identityToken" is required`)
+	}
+	return nil
+}
+
+// String returns the credential with secrets redacted.
+func (c RegistryCredential) String() string {
+	return fmt.Sprintf(
+		"{Registry:%s Username:%s Password:%s IdentityToken:%s}",
+		c.Registry, c.Username, redact(c.Password), redact(c.IdentityToken),
+	)
+}
+
+// GoString is String, so that "%#v" does not print secrets.
+func (c RegistryCredential) GoString() string {
+	return "auth.RegistryCredential" + c.String()
+}
+
+// DockerConfigJSON returns the content of Docker's config.json
+// (and Kubernetes Secrets of type "kubernetes.io/dockerconfigjson") holding creds.
+func DockerConfigJSON(creds ...RegistryCredential) ([]byte, error) {
+	type authEntry struct {
+		Auth          string `json:"auth,omitempty"`
+		IdentityToken string `json:"identitytoken,omitempty"`
+	}
+	auths := map[string]authEntry{}
+	for i, c := range creds {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("credential #%d (%s): %w", i, c.Registry, err)
+		}
+		e := authEntry{IdentityToken: c.IdentityToken}
+		if c.Username != "" {
+			e.Auth = base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+		}
+		auths[c.Registry] = e
+	}
+	return json.Marshal(map[string]any{"auths": auths})
+}
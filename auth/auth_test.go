@@ -0,0 +1,119 @@
+package auth_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/auth"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+const secret = "s3cr3t-value"
+
+func assertRedacted(t *testing.T, v any) {
+	t.Helper()
+	for _, format := range []string{"%v", "%+v", "%s", "%#v"} {
+		if s := fmt.Sprintf(format, v); strings.Contains(s, secret) {
+			t.Errorf("%s prints secret: %s", format, s)
+		}
+	}
+}
+
+func TestTokenRequest(t *testing.T) {
+	req := auth.TokenRequest{GrantType: auth.GrantPassword, Username: "alice", Password: secret, Scope: []string{"plans:write"}}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertJSONRoundTrip(t, req)
+	assertRedacted(t, req)
+	assertRedacted(t, &req)
+
+	for name, invalid := range map[string]auth.TokenRequest{
+		"no grant type":    {Username: "alice", Password: secret},
+		"unknown grant":    {GrantType: "implicit"},
+		"no password":      {GrantType: auth.GrantPassword, Username: "alice"},
+		"no refresh token": {GrantType: auth.GrantRefreshToken},
+		"mixed":            {GrantType: auth.GrantRefreshToken, RefreshToken: secret, Password: secret},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid request is accepted: %v", invalid)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tok := auth.Token{
+		AccessToken:  secret,
+		ExpiresAt:    rfctime.RFC3339(expiresAt),
+		RefreshToken: secret,
+		Scope:        []string{"plans:read"},
+	}
+	knittest.AssertJSONRoundTrip(t, tok)
+	assertRedacted(t, tok)
+	assertRedacted(t, struct{ Token auth.Token }{Token: tok})
+
+	if tok.Expired(expiresAt.Add(-time.Second)) || !tok.Expired(expiresAt) {
+		t.Error("unexpected expiry")
+	}
+	if !tok.HasScope("plans:read") || tok.HasScope("plans:write") {
+		t.Error("unexpected scope")
+	}
+
+	h := http.Header{}
+	tok.SetHeader(h)
+	if got := h.Get("Authorization"); got != "Bearer "+secret {
+		t.Errorf("Authorization: %s", got)
+	}
+}
+
+func TestRegistryCredential(t *testing.T) {
+	basic := auth.RegistryCredential{Registry: "registry.example.com:5000", Username: "alice", Password: secret}
+	token := auth.RegistryCredential{Registry: "ghcr.io", IdentityToken: secret}
+	for _, c := range []auth.RegistryCredential{basic, token} {
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		knittest.AssertJSONRoundTrip(t, c)
+		assertRedacted(t, c)
+	}
+
+	for name, invalid := range map[string]auth.RegistryCredential{
+		"no registry":   {Username: "alice", Password: secret},
+		"url":           {Registry: "https://registry.example.com", IdentityToken: secret},
+		"no secret":     {Registry: "ghcr.io"},
+		"no password":   {Registry: "ghcr.io", Username: "alice"},
+		"both of kinds": {Registry: "ghcr.io", Username: "alice", Password: secret, IdentityToken: secret},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid credential is accepted: %v", invalid)
+			}
+		})
+	}
+
+	b, err := auth.DockerConfigJSON(basic, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := struct {
+		Auths map[string]map[string]string `json:"auths"`
+	}{}
+	if err := json.Unmarshal(b, &config); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Auths[basic.Registry]["auth"]; got != base64.StdEncoding.EncodeToString([]byte("alice:"+secret)) {
+		t.Errorf("auth: %s", got)
+	}
+	if got := config.Auths[token.Registry]["identitytoken"]; got != secret {
+		t.Errorf("identitytoken: %s", got)
+	}
+}
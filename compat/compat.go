@@ -0,0 +1,119 @@
+// Package compat provides golden documents of WebAPI payloads.
+//
+// Golden documents are JSON (and YAML, for types written by users) expressions of
+// request/response types in this module, in the canonical form.
+// Servers, clients and third-party SDKs can assert wire compatibility
+// by decoding and encoding these documents in their own test suites.
+//
+// Documents are named as "<package>/<Type>.<json|yaml>", like "plans/PlanSpec.yaml".
+package compat
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed golden
+var golden embed.FS
+
+// Names returns sorted names of all golden documents.
+func Names() []string {
+	names := []string{}
+	fs.WalkDir(golden, "golden", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names = append(names, p[len("golden/"):])
+		return nil
+	})
+	slices.Sort(names)
+	return names
+}
+
+// Load returns the content of the golden document.
+func Load(name string) ([]byte, error) {
+	return golden.ReadFile(path.Join("golden", name))
+}
+
+// MustLoad is same as Load, but panics on error.
+func MustLoad(name string) []byte {
+	b, err := Load(name)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Decode decodes the golden document as T.
+//
+// The format is chosen by the extension of name.
+func Decode[T any](name string) (T, error) {
+	var v T
+	b, err := Load(name)
+	if err != nil {
+		return v, err
+	}
+	switch ext := path.Ext(name); ext {
+	case ".json":
+		err = json.Unmarshal(b, &v)
+	case ".yaml":
+		err = yaml.Unmarshal(b, &v)
+	default:
+		err = fmt.Errorf("unknown format: %s", ext)
+	}
+	return v, err
+}
+
+// Encode encodes v in the same style as golden documents:
+// indented with 2 spaces, and terminated with a newline.
+//
+// The format is chosen by the extension of name.
+func Encode(name string, v any) ([]byte, error) {
+	switch ext := path.Ext(name); ext {
+	case ".json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	case ".yaml":
+		buf := new(bytes.Buffer)
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", ext)
+	}
+}
+
+// Check decodes the golden document as T, encodes it again,
+// and reports error if the result is not identical to the golden document.
+//
+// It returns the decoded value for further assertions.
+func Check[T any](name string) (T, error) {
+	v, err := Decode[T](name)
+	if err != nil {
+		return v, fmt.Errorf("%s: decode: %w", name, err)
+	}
+	b, err := Encode(name, v)
+	if err != nil {
+		return v, fmt.Errorf("%s: encode: %w", name, err)
+	}
+	if want := MustLoad(name); !bytes.Equal(b, want) {
+		return v, fmt.Errorf("%s: not identical:\n=== golden ===\n%s\n=== encoded ===\n%s", name, want, b)
+	}
+	return v, nil
+}
@@ -0,0 +1,189 @@
+package compat_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/audit"
+	"github.com/opst/knitfab-api-types/auth"
+	"github.com/opst/knitfab-api-types/compat"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/digests"
+	"github.com/opst/knitfab-api-types/errors"
+	"github.com/opst/knitfab-api-types/events"
+	"github.com/opst/knitfab-api-types/federation"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/nodes"
+	"github.com/opst/knitfab-api-types/notes"
+	"github.com/opst/knitfab-api-types/orphans"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/policies"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/searches"
+	"github.com/opst/knitfab-api-types/stars"
+	"github.com/opst/knitfab-api-types/tags"
+	"github.com/opst/knitfab-api-types/webhooks"
+)
+
+func check[T any](name string) func(*testing.T) {
+	return func(t *testing.T) {
+		if _, err := compat.Check[T](name); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	checks := map[string]func(*testing.T){
+		"audit/Entry.json":                 check[audit.Entry]("audit/Entry.json"),
+		"auth/Token.json":                  check[auth.Token]("auth/Token.json"),
+		"auth/TokenRequest.json":           check[auth.TokenRequest]("auth/TokenRequest.json"),
+		"data/BulkRegistration.json":       check[data.BulkRegistration]("data/BulkRegistration.json"),
+		"data/BulkRegistrationResult.json": check[data.BulkRegistrationResult]("data/BulkRegistrationResult.json"),
+		"data/Deps.json":                   check[data.Deps]("data/Deps.json"),
+		"data/Detail.json":                 check[data.Detail]("data/Detail.json"),
+		"data/DownloadRef.json":            check[data.DownloadRef]("data/DownloadRef.json"),
+		"data/Lineage.json":                check[data.Lineage]("data/Lineage.json"),
+		"data/Page.json":                   check[data.Page]("data/Page.json"),
+		"data/PurgeRequest.json":           check[data.PurgeRequest]("data/PurgeRequest.json"),
+		"data/PurgeResult.json":            check[data.PurgeResult]("data/PurgeResult.json"),
+		"data/Replication.json":            check[data.Replication]("data/Replication.json"),
+		"data/ReplicationRequest.json":     check[data.ReplicationRequest]("data/ReplicationRequest.json"),
+		"data/RestoreRequest.json":         check[data.RestoreRequest]("data/RestoreRequest.json"),
+		"data/RetentionPolicy.json":        check[data.RetentionPolicy]("data/RetentionPolicy.json"),
+		"data/Rewrap.json":                 check[data.Rewrap]("data/Rewrap.json"),
+		"data/SummarizedLineage.json":      check[data.SummarizedLineage]("data/SummarizedLineage.json"),
+		"data/Summary.json":                check[data.Summary]("data/Summary.json"),
+		"digests/Config.json":              check[digests.Config]("digests/Config.json"),
+		"digests/Digest.json":              check[digests.Digest]("digests/Digest.json"),
+		"errors/ErrorResponse.json":        check[errors.ErrorResponse]("errors/ErrorResponse.json"),
+		"events/Event.json":                check[events.Event]("events/Event.json"),
+		"federation/Cluster.json":          check[federation.Cluster]("federation/Cluster.json"),
+		"federation/Link.json":             check[federation.Link]("federation/Link.json"),
+		"meta/Health.json":                 check[meta.Health]("meta/Health.json"),
+		"meta/Version.json":                check[meta.Version]("meta/Version.json"),
+		"nodes/List.json":                  check[nodes.List]("nodes/List.json"),
+		"notes/Create.json":                check[notes.Create]("notes/Create.json"),
+		"notes/Note.json":                  check[notes.Note]("notes/Note.json"),
+		"orphans/Report.json":              check[orphans.Report]("orphans/Report.json"),
+		"plans/Admission.json":             check[plans.Admission]("plans/Admission.json"),
+		"plans/AnnotationChange.json":      check[plans.AnnotationChange]("plans/AnnotationChange.json"),
+		"plans/Detail.json":                check[plans.Detail]("plans/Detail.json"),
+		"plans/EstimateRequest.json":       check[plans.EstimateRequest]("plans/EstimateRequest.json"),
+		"plans/EstimateResult.json":        check[plans.EstimateResult]("plans/EstimateResult.json"),
+		"plans/Graph.json":                 check[plans.Graph]("plans/Graph.json"),
+		"plans/Page.json":                  check[plans.Page]("plans/Page.json"),
+		"plans/PlanSpec.json":              check[plans.PlanSpec]("plans/PlanSpec.json"),
+		"plans/PlanSpec.yaml":              check[plans.PlanSpec]("plans/PlanSpec.yaml"),
+		"plans/ResourceLimitChange.json":   check[plans.ResourceLimitChange]("plans/ResourceLimitChange.json"),
+		"plans/SetServiceAccount.json":     check[plans.SetServiceAccount]("plans/SetServiceAccount.json"),
+		"plans/Summary.json":               check[plans.Summary]("plans/Summary.json"),
+		"plans/Update.json":                check[plans.Update]("plans/Update.json"),
+		"policies/Decision.json":           check[policies.Decision]("policies/Decision.json"),
+		"policies/Policy.json":             check[policies.Policy]("policies/Policy.json"),
+		"policies/Request.json":            check[policies.Request]("policies/Request.json"),
+		"projects/Detail.json":             check[projects.Detail]("projects/Detail.json"),
+		"projects/Project.json":            check[projects.Project]("projects/Project.json"),
+		"runs/BulkRequest.json":            check[runs.BulkRequest]("runs/BulkRequest.json"),
+		"runs/BulkResult.json":             check[runs.BulkResult]("runs/BulkResult.json"),
+		"runs/Detail.json":                 check[runs.Detail]("runs/Detail.json"),
+		"runs/History.json":                check[runs.History]("runs/History.json"),
+		"runs/Metrics.json":                check[runs.Metrics]("runs/Metrics.json"),
+		"runs/Page.json":                   check[runs.Page]("runs/Page.json"),
+		"runs/Spec.json":                   check[runs.Spec]("runs/Spec.json"),
+		"runs/Summary.json":                check[runs.Summary]("runs/Summary.json"),
+		"searches/Detail.json":             check[searches.Detail]("searches/Detail.json"),
+		"searches/Spec.json":               check[searches.Spec]("searches/Spec.json"),
+		"stars/Request.json":               check[stars.Request]("stars/Request.json"),
+		"stars/Star.json":                  check[stars.Star]("stars/Star.json"),
+		"tags/Change.json":                 check[tags.Change]("tags/Change.json"),
+		"webhooks/Payload.json":            check[webhooks.Payload]("webhooks/Payload.json"),
+		"webhooks/Subscription.json":       check[webhooks.Subscription]("webhooks/Subscription.json"),
+	}
+
+	for _, name := range compat.Names() {
+		c, ok := checks[name]
+		if !ok {
+			t.Errorf("golden document %s is not checked", name)
+			continue
+		}
+		t.Run(name, c)
+	}
+}
+
+func TestGolden_formats(t *testing.T) {
+	fromJson, err := compat.Decode[plans.PlanSpec]("plans/PlanSpec.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYaml, err := compat.Decode[plans.PlanSpec]("plans/PlanSpec.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromJson.Equal(fromYaml) {
+		t.Errorf("JSON and YAML documents are not equivalent:\n%+v\n%+v", fromJson, fromYaml)
+	}
+}
+
+func mustDecode[T any](t *testing.T, name string) T {
+	t.Helper()
+	v, err := compat.Decode[T](name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// TestGolden_fields checks that golden documents have optional fields,
+// so that their wire formats are covered.
+func TestGolden_fields(t *testing.T) {
+	spec := mustDecode[plans.PlanSpec](t, "plans/PlanSpec.json")
+	plan := mustDecode[plans.Detail](t, "plans/Detail.json")
+	run := mustDecode[runs.Detail](t, "runs/Detail.json")
+	dataDetail := mustDecode[data.Detail](t, "data/Detail.json")
+
+	for name, ok := range map[string]bool{
+		"plans/PlanSpec: image digest": spec.Image.Digest != "",
+		"plans/Detail: image digest":   plan.Image != nil && plan.Image.Digest != "",
+		"plans/PlanSpec: env":          len(spec.Env) != 0,
+		"plans/PlanSpec: working_dir":  spec.WorkingDir != "",
+		"plans/PlanSpec: run_as_user":  spec.RunAsUser != nil,
+		"plans/PlanSpec: run_as_group": spec.RunAsGroup != nil,
+		"plans/Detail: env":            len(plan.Env) != 0,
+		"plans/Detail: working_dir":    plan.WorkingDir != "",
+		"plans/Detail: run_as_user":    plan.RunAsUser != nil,
+		"plans/Detail: run_as_group":   plan.RunAsGroup != nil,
+		"plans/PlanSpec: sidecars":     len(spec.Sidecars) != 0,
+		"plans/Detail: sidecars":       len(plan.Sidecars) != 0,
+		"plans/PlanSpec: timeout":      spec.Timeout != nil,
+		"plans/PlanSpec: deadline":     spec.Deadline != nil,
+		"plans/Detail: timeout":        plan.Timeout != nil,
+		"plans/Detail: deadline":       plan.Deadline != nil,
+		"plans/PlanSpec: schedule":     spec.Schedule != nil,
+		"plans/Detail: schedule":       plan.Schedule != nil,
+		"plans/PlanSpec: priority":     spec.Priority != "",
+		"plans/Detail: priority":       plan.Priority != "",
+		"runs/Detail: priority":        run.Priority != "",
+		"plans/PlanSpec: secrets":      len(spec.Secrets) != 0,
+		"plans/Detail: secrets":        len(plan.Secrets) != 0,
+		"plans/PlanSpec: read_only":    spec.Inputs[0].ReadOnly,
+		"plans/PlanSpec: sub_path":     spec.Inputs[0].SubPath != "",
+		"plans/Detail: read_only":      plan.Inputs[0].ReadOnly,
+		"plans/Detail: sub_path":       plan.Inputs[0].SubPath != "",
+		"runs/Detail: read_only":       run.Inputs[0].ReadOnly,
+		"runs/Detail: sub_path":        run.Inputs[0].SubPath != "",
+		"plans/PlanSpec: cache_policy": spec.Outputs[0].CachePolicy != "",
+		"plans/Detail: cache_policy":   plan.Outputs[0].CachePolicy != "",
+		"runs/Detail: cache_policy":    run.Outputs[0].CachePolicy != "",
+		"runs/Detail: queuedAt":        run.QueuedAt != nil,
+		"runs/Detail: startedAt":       run.StartedAt != nil,
+		"runs/Detail: finishedAt":      run.FinishedAt != nil,
+		"plans/Detail: x-ext":          len(plan.Extensions) != 0,
+		"runs/Detail: x-ext":           len(run.Extensions) != 0,
+		"data/Detail: x-ext":           len(dataDetail.Extensions) != 0,
+	} {
+		if !ok {
+			t.Errorf("%s: not in the golden document", name)
+		}
+	}
+}
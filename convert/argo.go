@@ -0,0 +1,245 @@
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+type argoWorkflow struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name         string `yaml:"name"`
+		GenerateName string `yaml:"generateName"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Templates []argoTemplate `yaml:"templates"`
+	} `yaml:"spec"`
+}
+
+type argoTemplate struct {
+	Name   string `yaml:"name"`
+	Inputs struct {
+		Parameters []argoParameter `yaml:"parameters"`
+		Artifacts  []argoArtifact  `yaml:"artifacts"`
+	} `yaml:"inputs"`
+	Outputs struct {
+		Parameters []argoParameter `yaml:"parameters"`
+		Artifacts  []argoArtifact  `yaml:"artifacts"`
+	} `yaml:"outputs"`
+	Container    *argoContainer    `yaml:"container"`
+	Script       any               `yaml:"script"`
+	Resource     any               `yaml:"resource"`
+	Steps        any               `yaml:"steps"`
+	DAG          any               `yaml:"dag"`
+	Sidecars     []argoContainer   `yaml:"sidecars"`
+	NodeSelector map[string]string `yaml:"nodeSelector"`
+	Volumes      any               `yaml:"volumes"`
+}
+
+type argoParameter struct {
+	Name  string  `yaml:"name"`
+	Value *string `yaml:"value"`
+}
+
+type argoArtifact struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+type argoContainer struct {
+	Name    string   `yaml:"name"`
+	Image   string   `yaml:"image"`
+	Command []string `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Env     []struct {
+		Name      string  `yaml:"name"`
+		Value     *string `yaml:"value"`
+		ValueFrom any     `yaml:"valueFrom"`
+	} `yaml:"env"`
+	Resources struct {
+		Requests map[string]string `yaml:"requests"`
+		Limits   map[string]string `yaml:"limits"`
+	} `yaml:"resources"`
+	VolumeMounts any `yaml:"volumeMounts"`
+}
+
+// FromArgo converts an Argo Workflow or WorkflowTemplate (in YAML or JSON) into PlanSpecs.
+//
+// Each template with "container" becomes a PlanSpec:
+//
+// - input/output artifacts become input/output mountpoints tagged "artifact:<name>".
+//
+// - input parameters referred in command and args are replaced with their values.
+//
+// - "nodeSelector" becomes "must" labels of OnNode.
+//
+// - resource requests (or limits, if no requests) become Resources.
+//
+// - env with literal values becomes Env.
+//
+// - sidecars become Sidecars.
+//
+// Other constructs (steps, dag, script, resource templates, volumes, env from sources, ...) are reported as Issues.
+func FromArgo(b []byte) (Result, error) {
+	wf := argoWorkflow{}
+	if err := yaml.Unmarshal(b, &wf); err != nil {
+		return Result{}, fmt.Errorf("argo: %w", err)
+	}
+	switch wf.Kind {
+	case "Workflow", "WorkflowTemplate", "ClusterWorkflowTemplate":
+	default:
+		return Result{}, fmt.Errorf("argo: unsupported kind: %q", wf.Kind)
+	}
+	wfName := wf.Metadata.Name
+	if wfName == "" {
+		wfName = strings.TrimSuffix(wf.Metadata.GenerateName, "-")
+	}
+
+	r := Result{Specs: []plans.PlanSpec{}}
+	for i, t := range wf.Spec.Templates {
+		field := fmt.Sprintf("spec.templates[%d]", i)
+		switch {
+		case t.Steps != nil:
+			r.issue(field+".steps", "steps are not converted. Knitfab wires Plans by tags of artifacts")
+			continue
+		case t.DAG != nil:
+			r.issue(field+".dag", "dag is not converted. Knitfab wires Plans by tags of artifacts")
+			continue
+		case t.Script != nil:
+			r.issue(field+".script", "script template is not supported. build an image with the script")
+			continue
+		case t.Resource != nil:
+			r.issue(field+".resource", "resource template is not supported")
+			continue
+		case t.Container == nil:
+			r.issue(field, "template without container is not converted")
+			continue
+		}
+		r.Specs = append(r.Specs, argoTemplateToSpec(&r, field, wfName, t))
+	}
+	return r, nil
+}
+
+func argoTemplateToSpec(r *Result, field string, wfName string, t argoTemplate) plans.PlanSpec {
+	c := t.Container
+	spec := plans.PlanSpec{
+		Annotations: plans.Annotations{
+			{Key: AnnotationConvertedFrom, Value: "argo:" + path.Join(wfName, t.Name)},
+		},
+		Inputs:  []plans.Mountpoint{},
+		Outputs: []plans.Mountpoint{},
+	}
+	if err := spec.Image.Parse(c.Image); err != nil {
+		r.issue(field+".container.image", "image %q is not converted: %s", c.Image, err)
+	}
+
+	params := map[string]*string{}
+	for _, p := range t.Inputs.Parameters {
+		params[p.Name] = p.Value
+	}
+	spec.Entrypoint = argoSubstitute(r, field+".container.command", c.Command, params)
+	spec.Args = argoSubstitute(r, field+".container.args", c.Args, params)
+
+	for j, a := range t.Inputs.Artifacts {
+		if a.Path == "" {
+			r.issue(fmt.Sprintf("%s.inputs.artifacts[%d]", field, j), "artifact without path is not converted")
+			continue
+		}
+		spec.Inputs = append(spec.Inputs, plans.Mountpoint{Path: a.Path, Tags: []tags.Tag{artifactTag(a.Name)}})
+	}
+	for j, a := range t.Outputs.Artifacts {
+		if a.Path == "" {
+			r.issue(fmt.Sprintf("%s.outputs.artifacts[%d]", field, j), "artifact without path is not converted")
+			continue
+		}
+		spec.Outputs = append(spec.Outputs, plans.Mountpoint{Path: a.Path, Tags: []tags.Tag{artifactTag(a.Name)}})
+	}
+	if len(t.Outputs.Parameters) != 0 {
+		r.issue(field+".outputs.parameters", "output parameters are not supported. write them into an output artifact")
+	}
+
+	if 0 < len(t.NodeSelector) {
+		spec.OnNode = &plans.OnNode{}
+		for _, k := range slices.Sorted(maps.Keys(t.NodeSelector)) {
+			spec.OnNode.Must = append(spec.OnNode.Must, plans.OnSpecLabel{Key: k, Value: t.NodeSelector[k]})
+		}
+	}
+
+	spec.Resources = argoResources(r, field+".container", *c)
+	spec.Env = argoEnv(r, field+".container", *c)
+	if c.VolumeMounts != nil {
+		r.issue(field+".container.volumeMounts", "volumeMounts are not converted")
+	}
+	if t.Volumes != nil {
+		r.issue(field+".volumes", "volumes are not converted")
+	}
+
+	for j, sc := range t.Sidecars {
+		f := fmt.Sprintf("%s.sidecars[%d]", field, j)
+		sidecar := plans.Container{
+			Name:       sc.Name,
+			Entrypoint: argoSubstitute(r, f+".command", sc.Command, params),
+			Args:       argoSubstitute(r, f+".args", sc.Args, params),
+			Env:        argoEnv(r, f, sc),
+			Resources:  argoResources(r, f, sc),
+		}
+		if err := sidecar.Image.Parse(sc.Image); err != nil {
+			r.issue(f+".image", "image %q is not converted: %s", sc.Image, err)
+			continue
+		}
+		if sc.VolumeMounts != nil {
+			r.issue(f+".volumeMounts", "volumeMounts are not converted")
+		}
+		spec.Sidecars = append(spec.Sidecars, sidecar)
+	}
+
+	return spec
+}
+
+// argoResources converts resource requests (or limits, if no requests) of the container.
+func argoResources(r *Result, field string, c argoContainer) plans.Resources {
+	if 0 < len(c.Resources.Requests) {
+		return resources(r, field+".resources.requests", c.Resources.Requests)
+	}
+	return resources(r, field+".resources.limits", c.Resources.Limits)
+}
+
+// argoEnv converts env with literal values of the container.
+func argoEnv(r *Result, field string, c argoContainer) []plans.EnvVar {
+	var env []plans.EnvVar
+	for j, e := range c.Env {
+		if e.ValueFrom != nil || e.Value == nil {
+			r.issue(fmt.Sprintf("%s.env[%d]", field, j), "env %q without literal value is not converted", e.Name)
+			continue
+		}
+		env = append(env, plans.EnvVar{Name: e.Name, Value: *e.Value})
+	}
+	return env
+}
+
+// argoSubstitute replaces "{{inputs.parameters.NAME}}" with values of the parameters.
+func argoSubstitute(r *Result, field string, ss []string, params map[string]*string) []string {
+	if ss == nil {
+		return nil
+	}
+	ret := make([]string, len(ss))
+	for i, s := range ss {
+		for _, ph := range placeholders(s) {
+			name, ok := strings.CutPrefix(ph, "inputs.parameters.")
+			if v, found := params[name]; ok && found && v != nil {
+				s = strings.ReplaceAll(s, "{{"+ph+"}}", *v)
+				continue
+			}
+			r.issue(fmt.Sprintf("%s[%d]", field, i), "placeholder {{%s}} is not resolved", ph)
+		}
+		ret[i] = s
+	}
+	return ret
+}
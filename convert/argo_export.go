@@ -0,0 +1,204 @@
+package convert
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"gopkg.in/yaml.v3"
+)
+
+type argoOutWorkflow struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		GenerateName string `yaml:"generateName"`
+	} `yaml:"metadata"`
+	Spec struct {
+		Entrypoint string `yaml:"entrypoint"`
+		Arguments  *struct {
+			Artifacts []argoOutArtifact `yaml:"artifacts"`
+		} `yaml:"arguments,omitempty"`
+		Templates []argoOutTemplate `yaml:"templates"`
+	} `yaml:"spec"`
+}
+
+type argoOutArtifact struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path,omitempty"`
+	From string `yaml:"from,omitempty"`
+}
+
+type argoOutArtifacts struct {
+	Artifacts []argoOutArtifact `yaml:"artifacts"`
+}
+
+type argoOutTemplate struct {
+	Name     string `yaml:"name"`
+	Metadata *struct {
+		Annotations map[string]string `yaml:"annotations"`
+	} `yaml:"metadata,omitempty"`
+	Inputs  *argoOutArtifacts `yaml:"inputs,omitempty"`
+	Outputs *argoOutArtifacts `yaml:"outputs,omitempty"`
+	DAG     *struct {
+		Tasks []argoOutTask `yaml:"tasks"`
+	} `yaml:"dag,omitempty"`
+	Container          *argoOutContainer  `yaml:"container,omitempty"`
+	Sidecars           []argoOutContainer `yaml:"sidecars,omitempty"`
+	NodeSelector       map[string]string  `yaml:"nodeSelector,omitempty"`
+	ServiceAccountName string             `yaml:"serviceAccountName,omitempty"`
+}
+
+type argoOutContainer struct {
+	Name      string       `yaml:"name,omitempty"`
+	Image     string       `yaml:"image"`
+	Command   []string     `yaml:"command,omitempty"`
+	Args      []string     `yaml:"args,omitempty"`
+	Env       []argoOutEnv `yaml:"env,omitempty"`
+	Resources *struct {
+		Requests map[string]string `yaml:"requests"`
+		Limits   map[string]string `yaml:"limits"`
+	} `yaml:"resources,omitempty"`
+}
+
+type argoOutEnv struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type argoOutTask struct {
+	Name         string            `yaml:"name"`
+	Template     string            `yaml:"template"`
+	Dependencies []string          `yaml:"dependencies,omitempty"`
+	Arguments    *argoOutArtifacts `yaml:"arguments,omitempty"`
+}
+
+// ToArgo renders steps into an Argo Workflow in YAML.
+//
+// Each step becomes a container template, and they are wired in a DAG template "main".
+// Inputs and outputs become artifacts named "in-N" and "out-N".
+// Inputs without upstream in steps become arguments of the Workflow, named "<step>-in-N".
+//
+// Features which cannot be expressed in Argo are reported as Issues.
+func ToArgo(name string, steps []Step) ([]byte, []Issue, error) {
+	r := Result{}
+	w := wire(steps, r.issue)
+
+	wf := argoOutWorkflow{APIVersion: "argoproj.io/v1alpha1", Kind: "Workflow"}
+	wf.Metadata.GenerateName = name + "-"
+	wf.Spec.Entrypoint = "main"
+
+	main := argoOutTemplate{Name: "main"}
+	main.DAG = &struct {
+		Tasks []argoOutTask `yaml:"tasks"`
+	}{Tasks: []argoOutTask{}}
+	workflowArgs := []argoOutArtifact{}
+	templates := []argoOutTemplate{}
+
+	for i, s := range steps {
+		unmapped(s, r.issue)
+		templates = append(templates, argoStepTemplate(s))
+
+		task := argoOutTask{Name: s.Name, Template: s.Name}
+		args := []argoOutArtifact{}
+		for j := range s.Spec.Inputs {
+			in := fmt.Sprintf("in-%d", j)
+			src := w[i][j]
+			if src == nil {
+				wfArg := fmt.Sprintf("%s-%s", s.Name, in)
+				workflowArgs = append(workflowArgs, argoOutArtifact{Name: wfArg})
+				args = append(args, argoOutArtifact{Name: in, From: "{{workflow.artifacts." + wfArg + "}}"})
+				continue
+			}
+			up := steps[src.step].Name
+			if !slices.Contains(task.Dependencies, up) {
+				task.Dependencies = append(task.Dependencies, up)
+			}
+			args = append(args, argoOutArtifact{
+				Name: in,
+				From: fmt.Sprintf("{{tasks.%s.outputs.artifacts.out-%d}}", up, src.output),
+			})
+		}
+		if 0 < len(args) {
+			task.Arguments = &argoOutArtifacts{Artifacts: args}
+		}
+		main.DAG.Tasks = append(main.DAG.Tasks, task)
+	}
+
+	wf.Spec.Templates = append([]argoOutTemplate{main}, templates...)
+	if 0 < len(workflowArgs) {
+		wf.Spec.Arguments = &struct {
+			Artifacts []argoOutArtifact `yaml:"artifacts"`
+		}{Artifacts: workflowArgs}
+	}
+
+	b, err := yaml.Marshal(wf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, r.Issues, nil
+}
+
+func argoStepTemplate(s Step) argoOutTemplate {
+	t := argoOutTemplate{Name: s.Name, ServiceAccountName: s.Spec.ServiceAccount}
+	if 0 < len(s.Spec.Annotations) {
+		t.Metadata = &struct {
+			Annotations map[string]string `yaml:"annotations"`
+		}{Annotations: map[string]string{}}
+		for _, an := range s.Spec.Annotations {
+			t.Metadata.Annotations[an.Key] = an.Value
+		}
+	}
+	if 0 < len(s.Spec.Inputs) {
+		t.Inputs = &argoOutArtifacts{}
+		for j, in := range s.Spec.Inputs {
+			t.Inputs.Artifacts = append(t.Inputs.Artifacts, argoOutArtifact{Name: fmt.Sprintf("in-%d", j), Path: in.Path})
+		}
+	}
+	if 0 < len(s.Spec.Outputs) {
+		t.Outputs = &argoOutArtifacts{}
+		for j, out := range s.Spec.Outputs {
+			t.Outputs.Artifacts = append(t.Outputs.Artifacts, argoOutArtifact{Name: fmt.Sprintf("out-%d", j), Path: out.Path})
+		}
+	}
+
+	main := argoOutContainerOf("", s.Spec.Image, s.Spec.Entrypoint, s.Spec.Args, s.Spec.Env, s.Spec.Resources)
+	t.Container = &main
+	for _, sc := range s.Spec.Sidecars {
+		t.Sidecars = append(t.Sidecars, argoOutContainerOf(sc.Name, sc.Image, sc.Entrypoint, sc.Args, sc.Env, sc.Resources))
+	}
+
+	if on := s.Spec.OnNode; on != nil && 0 < len(on.Must) {
+		t.NodeSelector = map[string]string{}
+		for _, l := range on.Must {
+			t.NodeSelector[l.Key] = l.Value
+		}
+	}
+	return t
+}
+
+func argoOutContainerOf(
+	name string, image plans.Image, command []string, args []string, env []plans.EnvVar, res plans.Resources,
+) argoOutContainer {
+	c := argoOutContainer{Name: name, Image: image.String(), Command: command, Args: args}
+	for _, e := range env {
+		c.Env = append(c.Env, argoOutEnv{Name: e.Name, Value: e.Value})
+	}
+	if 0 < len(res) {
+		// Knitfab uses resources as both of requests and limits.
+		q := quantities(res)
+		c.Resources = &struct {
+			Requests map[string]string `yaml:"requests"`
+			Limits   map[string]string `yaml:"limits"`
+		}{Requests: q, Limits: q}
+	}
+	return c
+}
+
+func quantities(r plans.Resources) map[string]string {
+	m := map[string]string{}
+	for k, v := range r {
+		m[k] = v.String()
+	}
+	return m
+}
@@ -0,0 +1,89 @@
+// Package convert translates pipeline definitions of other orchestrators into PlanSpecs, and vice versa.
+//
+// Each container step of the source becomes a PlanSpec.
+// Artifacts passed between steps become input/output mountpoints tagged with "artifact:<name>",
+// so that Plans converted from a pipeline are wired by the same names.
+//
+// Constructs which cannot be expressed in Knitfab are reported as Issues, not as errors,
+// so that users can bootstrap Plans and fix them up by hand.
+//
+// In the other direction, ToArgo and ToCWL render PlanSpecs into pipelines,
+// wiring them by tags as Knitfab does.
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TagKeyArtifact is the tag key for mountpoints converted from artifacts.
+const TagKeyArtifact = "artifact"
+
+// AnnotationConvertedFrom is the annotation key recording the source of converted PlanSpecs.
+const AnnotationConvertedFrom = "converted-from"
+
+// Issue is a construct in the source which is not converted, or converted lossy.
+//
+// Field is the path in the source document, like "spec.templates[1].script".
+type Issue = meta.Warning
+
+// Result is the result of conversion.
+type Result struct {
+	// Specs are the converted PlanSpecs.
+	Specs []plans.PlanSpec
+
+	// Issues are constructs not converted.
+	Issues []Issue
+}
+
+func (r *Result) issue(field string, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func artifactTag(name string) tags.Tag {
+	return tags.Tag{Key: TagKeyArtifact, Value: name}
+}
+
+// resources converts Kubernetes style resource map into plans.Resources.
+//
+// Malformed quantities are reported as issues.
+func resources(r *Result, field string, m map[string]string) plans.Resources {
+	if len(m) == 0 {
+		return nil
+	}
+	res := plans.Resources{}
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		v := m[k]
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			r.issue(field+"."+k, "malformed quantity %q", v)
+			continue
+		}
+		res[k] = q
+	}
+	return res
+}
+
+// placeholders finds "{{...}}" in s, and returns their contents.
+func placeholders(s string) []string {
+	found := []string{}
+	for {
+		open := strings.Index(s, "{{")
+		if open < 0 {
+			return found
+		}
+		close := strings.Index(s[open:], "}}")
+		if close < 0 {
+			return found
+		}
+		found = append(found, strings.TrimSpace(s[open+2:open+close]))
+		s = s[open+close+2:]
+	}
+}
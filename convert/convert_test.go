@@ -0,0 +1,304 @@
+package convert_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/convert"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func fields(issues []convert.Issue) []string {
+	fs := []string{}
+	for _, i := range issues {
+		fs = append(fs, i.Field)
+	}
+	return fs
+}
+
+func TestFromArgo(t *testing.T) {
+	src := `
+apiVersion: argoproj.io/v1alpha1
+kind: WorkflowTemplate
+metadata:
+  name: training
+spec:
+  templates:
+  - name: pipeline
+    dag:
+      tasks: []
+  - name: train
+    inputs:
+      parameters:
+      - name: epochs
+        value: "10"
+      artifacts:
+      - name: dataset
+        path: /in/dataset
+    outputs:
+      artifacts:
+      - name: model
+        path: /out/model
+    nodeSelector:
+      accelerator: gpu
+    container:
+      image: registry.invalid/trainer:v1
+      command: [python, train.py]
+      args: ["--epochs", "{{inputs.parameters.epochs}}", "--seed", "{{workflow.uid}}"]
+      resources:
+        requests:
+          cpu: 1
+          memory: 1Gi
+      env:
+      - name: DEBUG
+        value: "1"
+    sidecars:
+    - name: tensorboard
+      image: registry.invalid/tensorboard:v2
+      args: ["--logdir", "/out/model"]
+      env:
+      - name: TOKEN
+        valueFrom: {secretKeyRef: {name: tb, key: token}}
+  - name: notify
+    script:
+      image: alpine
+      source: echo done
+`
+	got, err := convert.FromArgo([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := plans.PlanSpec{
+		Annotations: plans.Annotations{{Key: convert.AnnotationConvertedFrom, Value: "argo:training/train"}},
+		Image:       plans.Image{Repository: "registry.invalid/trainer", Tag: "v1"},
+		Entrypoint:  []string{"python", "train.py"},
+		Args:        []string{"--epochs", "10", "--seed", "{{workflow.uid}}"},
+		Inputs:      []plans.Mountpoint{{Path: "/in/dataset", Tags: []tags.Tag{{Key: "artifact", Value: "dataset"}}}},
+		Outputs:     []plans.Mountpoint{{Path: "/out/model", Tags: []tags.Tag{{Key: "artifact", Value: "model"}}}},
+		OnNode:      &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}}},
+		Resources: plans.Resources{
+			"cpu":    resource.MustParse("1"),
+			"memory": resource.MustParse("1Gi"),
+		},
+		Env: []plans.EnvVar{{Name: "DEBUG", Value: "1"}},
+		Sidecars: []plans.Container{{
+			Name:  "tensorboard",
+			Image: plans.Image{Repository: "registry.invalid/tensorboard", Tag: "v2"},
+			Args:  []string{"--logdir", "/out/model"},
+		}},
+	}
+	if len(got.Specs) != 1 || !got.Specs[0].Equal(want) {
+		t.Errorf("unexpected specs:\n%+v", got.Specs)
+	}
+
+	wantIssues := []string{
+		"spec.templates[0].dag",
+		"spec.templates[1].container.args[3]",
+		"spec.templates[1].sidecars[0].env[0]",
+		"spec.templates[2].script",
+	}
+	if gotIssues := fields(got.Issues); strings.Join(gotIssues, ",") != strings.Join(wantIssues, ",") {
+		t.Errorf("unexpected issues: %v", got.Issues)
+	}
+}
+
+func TestFromArgo_unsupportedKind(t *testing.T) {
+	if _, err := convert.FromArgo([]byte("kind: CronJob\n")); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestFromArgo_malformedResources(t *testing.T) {
+	src := `
+apiVersion: argoproj.io/v1alpha1
+kind: WorkflowTemplate
+metadata:
+  name: training
+spec:
+  templates:
+  - name: train
+    container:
+      image: registry.invalid/trainer:v1
+      resources:
+        requests:
+          memory: lots
+          cpu: many
+          gpu: some
+          ephemeral-storage: 1Gi
+`
+	for range 10 {
+		got, err := convert.FromArgo([]byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wantIssues := []string{
+			"spec.templates[0].container.resources.requests.cpu",
+			"spec.templates[0].container.resources.requests.gpu",
+			"spec.templates[0].container.resources.requests.memory",
+		}
+		if gotIssues := fields(got.Issues); strings.Join(gotIssues, ",") != strings.Join(wantIssues, ",") {
+			t.Fatalf("issues: got %v, want %v", gotIssues, wantIssues)
+		}
+	}
+}
+
+func TestFromKubeflow(t *testing.T) {
+	src := `
+name: Train
+inputs:
+- {name: dataset, type: Dataset}
+- {name: epochs, type: Integer, default: '10'}
+- {name: seed, type: Integer}
+outputs:
+- {name: model, type: Model}
+implementation:
+  container:
+    image: registry.invalid/trainer:v1
+    command: [python, train.py]
+    args:
+    - --data
+    - {inputPath: dataset}
+    - --epochs
+    - {inputValue: epochs}
+    - --seed
+    - {inputValue: seed}
+    - --out
+    - {outputPath: model}
+`
+	got, err := convert.FromKubeflow([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := plans.PlanSpec{
+		Annotations: plans.Annotations{{Key: convert.AnnotationConvertedFrom, Value: "kubeflow:Train"}},
+		Image:       plans.Image{Repository: "registry.invalid/trainer", Tag: "v1"},
+		Entrypoint:  []string{"python", "train.py"},
+		Args: []string{
+			"--data", "/in/dataset", "--epochs", "10", "--seed", "{{seed}}", "--out", "/out/model",
+		},
+		Inputs:  []plans.Mountpoint{{Path: "/in/dataset", Tags: []tags.Tag{{Key: "artifact", Value: "dataset"}}}},
+		Outputs: []plans.Mountpoint{{Path: "/out/model", Tags: []tags.Tag{{Key: "artifact", Value: "model"}}}},
+	}
+	if len(got.Specs) != 1 || !got.Specs[0].Equal(want) {
+		t.Errorf("unexpected specs:\n%+v", got.Specs)
+	}
+	if gotIssues := fields(got.Issues); len(gotIssues) != 1 || gotIssues[0] != "implementation.container.args[5]" {
+		t.Errorf("unexpected issues: %v", got.Issues)
+	}
+}
+
+func exportSteps() []convert.Step {
+	active := true
+	return []convert.Step{
+		{
+			Name: "train",
+			Spec: plans.PlanSpec{
+				Image:      plans.Image{Repository: "example.com/train", Tag: "v1"},
+				Entrypoint: []string{"python", "train.py"},
+				Inputs: []plans.Mountpoint{
+					{Path: "/in/dataset", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}},
+				},
+				Outputs: []plans.Mountpoint{
+					{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}, {Key: "format", Value: "onnx"}}},
+				},
+				Log:       &plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}},
+				Resources: plans.Resources{"cpu": resource.MustParse("1500m"), "memory": resource.MustParse("1Gi")},
+				OnNode: &plans.OnNode{
+					Must:   []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}},
+					Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+				},
+				Active: &active,
+			},
+		},
+		{
+			Name: "evaluate",
+			Spec: plans.PlanSpec{
+				Image: plans.Image{Repository: "example.com/evaluate", Tag: "v1"},
+				Args:  []string{"--strict"},
+				Inputs: []plans.Mountpoint{
+					{Path: "/in/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+				},
+				Outputs: []plans.Mountpoint{
+					{Path: "/out/report", Tags: []tags.Tag{{Key: "type", Value: "report"}}},
+				},
+				ServiceAccount: "evaluator",
+				Active:         &active,
+			},
+		},
+	}
+}
+
+func TestToArgo(t *testing.T) {
+	b, issues, err := convert.ToArgo("pipeline", exportSteps())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"generateName: pipeline-",
+		"from: '{{tasks.train.outputs.artifacts.out-0}}'",
+		"from: '{{workflow.artifacts.train-in-0}}'",
+		"accelerator: gpu",
+		"serviceAccountName: evaluator",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("missing %q in:\n%s", want, b)
+		}
+	}
+	if got, want := fields(issues), []string{"train.log", "train.on_node"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("issues: got %v, want %v", got, want)
+	}
+
+	// exported workflow should be imported back.
+	res, err := convert.FromArgo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Specs) != 2 {
+		t.Fatalf("specs: got %d, want 2", len(res.Specs))
+	}
+	for i, s := range exportSteps() {
+		got := res.Specs[i]
+		if got.Image.String() != s.Spec.Image.String() {
+			t.Errorf("specs[%d].image: got %s, want %s", i, got.Image.String(), s.Spec.Image.String())
+		}
+		if got.Inputs[0].Path != s.Spec.Inputs[0].Path || got.Outputs[0].Path != s.Spec.Outputs[0].Path {
+			t.Errorf("specs[%d]: paths are not round tripped: %+v", i, got)
+		}
+	}
+}
+
+func TestToCWL(t *testing.T) {
+	b, issues, err := convert.ToCWL(exportSteps())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"cwlVersion: v1.2",
+		"in-0: train/out-0",
+		"train_in-0: Directory",
+		"outputSource: evaluate/out-0",
+		"dockerPull: example.com/train:v1",
+		"coresMin: 2",
+		"ramMin: 1024",
+		"entryname: /in/model",
+	} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("missing %q in:\n%s", want, b)
+		}
+	}
+	if strings.Contains(string(b), "train_out-0") {
+		t.Errorf("consumed output is exported as workflow output:\n%s", b)
+	}
+	if got, want := fields(issues), []string{
+		"train.log", "train.on_node", "train.on_node.must", "evaluate.service_account",
+	}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("issues: got %v, want %v", got, want)
+	}
+}
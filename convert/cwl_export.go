@@ -0,0 +1,173 @@
+package convert
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"gopkg.in/yaml.v3"
+)
+
+type cwlOutWorkflow struct {
+	CWLVersion string                       `yaml:"cwlVersion"`
+	Class      string                       `yaml:"class"`
+	Inputs     map[string]string            `yaml:"inputs"`
+	Outputs    map[string]cwlOutWorkflowOut `yaml:"outputs"`
+	Steps      map[string]cwlOutStep        `yaml:"steps"`
+}
+
+type cwlOutWorkflowOut struct {
+	Type         string `yaml:"type"`
+	OutputSource string `yaml:"outputSource"`
+}
+
+type cwlOutStep struct {
+	In  map[string]string `yaml:"in"`
+	Out []string          `yaml:"out"`
+	Run cwlOutTool        `yaml:"run"`
+}
+
+type cwlOutTool struct {
+	Class        string                   `yaml:"class"`
+	BaseCommand  []string                 `yaml:"baseCommand,omitempty"`
+	Arguments    []string                 `yaml:"arguments,omitempty"`
+	Requirements map[string]any           `yaml:"requirements"`
+	Inputs       map[string]string        `yaml:"inputs"`
+	Outputs      map[string]cwlOutToolOut `yaml:"outputs"`
+}
+
+type cwlOutToolOut struct {
+	Type          string `yaml:"type"`
+	OutputBinding struct {
+		Glob string `yaml:"glob"`
+	} `yaml:"outputBinding"`
+}
+
+type cwlOutListing struct {
+	Entry     string `yaml:"entry"`
+	Entryname string `yaml:"entryname"`
+}
+
+// ToCWL renders steps into a CWL (v1.2) Workflow in YAML.
+//
+// Each step becomes a CommandLineTool run in Docker, and inputs and outputs become Directories
+// named "in-N" and "out-N".
+// Inputs are placed at their paths with InitialWorkDirRequirement, and outputs are collected by glob of their paths.
+//
+// Inputs without upstream in steps become inputs of the Workflow, named "<step>_in-N",
+// and outputs without downstream become outputs of the Workflow, named "<step>_out-N".
+//
+// Features which cannot be expressed in CWL are reported as Issues.
+func ToCWL(steps []Step) ([]byte, []Issue, error) {
+	r := Result{}
+	w := wire(steps, r.issue)
+
+	wf := cwlOutWorkflow{
+		CWLVersion: "v1.2",
+		Class:      "Workflow",
+		Inputs:     map[string]string{},
+		Outputs:    map[string]cwlOutWorkflowOut{},
+		Steps:      map[string]cwlOutStep{},
+	}
+
+	consumed := map[source]bool{}
+	for i := range steps {
+		for _, src := range w[i] {
+			if src != nil {
+				consumed[*src] = true
+			}
+		}
+	}
+
+	for i, s := range steps {
+		unmapped(s, r.issue)
+		if s.Spec.ServiceAccount != "" {
+			r.issue(s.Name+".service_account", "service account is not exported")
+		}
+		if 0 < len(s.Spec.Annotations) {
+			r.issue(s.Name+".annotations", "annotations are not exported")
+		}
+		if on := s.Spec.OnNode; on != nil && 0 < len(on.Must) {
+			r.issue(s.Name+".on_node.must", "node constraints are not exported")
+		}
+
+		step := cwlOutStep{In: map[string]string{}, Out: []string{}}
+		tool := cwlOutTool{
+			Class:       "CommandLineTool",
+			BaseCommand: s.Spec.Entrypoint,
+			Arguments:   s.Spec.Args,
+			Requirements: map[string]any{
+				"DockerRequirement": map[string]string{"dockerPull": s.Spec.Image.String()},
+			},
+			Inputs:  map[string]string{},
+			Outputs: map[string]cwlOutToolOut{},
+		}
+
+		listing := []cwlOutListing{}
+		for j, in := range s.Spec.Inputs {
+			name := fmt.Sprintf("in-%d", j)
+			tool.Inputs[name] = "Directory"
+			listing = append(listing, cwlOutListing{Entry: "$(inputs." + name + ")", Entryname: in.Path})
+
+			if src := w[i][j]; src != nil {
+				step.In[name] = fmt.Sprintf("%s/out-%d", steps[src.step].Name, src.output)
+				continue
+			}
+			wfIn := fmt.Sprintf("%s_%s", s.Name, name)
+			wf.Inputs[wfIn] = "Directory"
+			step.In[name] = wfIn
+		}
+		if 0 < len(listing) {
+			tool.Requirements["InitialWorkDirRequirement"] = map[string]any{"listing": listing}
+		}
+
+		for j, out := range s.Spec.Outputs {
+			name := fmt.Sprintf("out-%d", j)
+			o := cwlOutToolOut{Type: "Directory"}
+			o.OutputBinding.Glob = out.Path
+			tool.Outputs[name] = o
+			step.Out = append(step.Out, name)
+
+			if !consumed[source{step: i, output: j}] {
+				wf.Outputs[fmt.Sprintf("%s_%s", s.Name, name)] = cwlOutWorkflowOut{
+					Type: "Directory", OutputSource: s.Name + "/" + name,
+				}
+			}
+		}
+
+		req := map[string]int64{}
+		for k, q := range s.Spec.Resources {
+			switch k {
+			case plans.ResourceCPU:
+				req["coresMin"] = int64(math.Ceil(q.AsApproximateFloat64()))
+			case plans.ResourceMemory:
+				req["ramMin"] = int64(math.Ceil(q.AsApproximateFloat64() / (1 << 20)))
+			default:
+				r.issue(s.Name+".resources."+k, "resource %q is not exported", k)
+			}
+		}
+		if 0 < len(req) {
+			tool.Requirements["ResourceRequirement"] = req
+		}
+		if 0 < len(s.Spec.Env) {
+			envDef := []map[string]string{}
+			for _, e := range s.Spec.Env {
+				envDef = append(envDef, map[string]string{"envName": e.Name, "envValue": e.Value})
+			}
+			tool.Requirements["EnvVarRequirement"] = map[string]any{"envDef": envDef}
+		}
+
+		if 0 < len(s.Spec.Sidecars) {
+			r.issue(s.Name+".sidecars", "sidecars are not exported")
+		}
+
+		step.Run = tool
+		wf.Steps[s.Name] = step
+	}
+
+	b, err := yaml.Marshal(wf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, r.Issues, nil
+}
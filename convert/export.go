@@ -0,0 +1,105 @@
+package convert
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+// Step is a Plan to be exported, with its name in the exported pipeline.
+type Step struct {
+	// Name is the name of the step. It should be unique in the pipeline.
+	Name string
+
+	// Spec is the Plan.
+	Spec plans.PlanSpec
+}
+
+// StepsFromDetails makes Steps from Plans, named by their PlanIds.
+func StepsFromDetails(ds []plans.Detail) []Step {
+	steps := make([]Step, 0, len(ds))
+	for _, d := range ds {
+		steps = append(steps, Step{Name: "plan-" + d.PlanId.String(), Spec: d.ToSpec()})
+	}
+	return steps
+}
+
+// source is an output of a step.
+type source struct {
+	step   int
+	output int
+}
+
+// wiring is the upstream outputs of each input of each step.
+//
+// wiring[i][j] is the source of the j-th input of the i-th step, or nil if there is no upstream in steps.
+type wiring [][]*source
+
+// wire finds upstreams of inputs by tags, as Knitfab does:
+// an output is upstream of an input if the output has all tags of the input.
+//
+// When an input has multiple upstreams, the first one is used and it is reported.
+func wire(steps []Step, issue func(field, format string, args ...any)) wiring {
+	w := make(wiring, len(steps))
+	for i, s := range steps {
+		w[i] = make([]*source, len(s.Spec.Inputs))
+		for j, in := range s.Spec.Inputs {
+			found := []source{}
+			for k, up := range steps {
+				if k == i {
+					continue
+				}
+				for l, out := range up.Spec.Outputs {
+					if covers(out, in) {
+						found = append(found, source{step: k, output: l})
+					}
+				}
+				if up.Spec.Log != nil && covers(plans.Mountpoint{Tags: up.Spec.Log.Tags}, in) {
+					issue(
+						fmt.Sprintf("%s.inputs[%d]", s.Name, j),
+						"log of %s is upstream, but logs are not exported", up.Name,
+					)
+				}
+			}
+			if len(found) == 0 {
+				continue
+			}
+			if 1 < len(found) {
+				issue(
+					fmt.Sprintf("%s.inputs[%d]", s.Name, j),
+					"multiple upstreams are found; only %s.outputs[%d] is wired",
+					steps[found[0].step].Name, found[0].output,
+				)
+			}
+			w[i][j] = &found[0]
+		}
+	}
+	return w
+}
+
+// covers returns true if out has all tags of in.
+func covers(out, in plans.Mountpoint) bool {
+	if len(in.Tags) == 0 {
+		return false
+	}
+	for _, t := range in.Tags {
+		if !slices.ContainsFunc(out.Tags, t.Equal) {
+			return false
+		}
+	}
+	return true
+}
+
+// unmapped reports features of PlanSpec which are common to exporters and not exported.
+func unmapped(s Step, issue func(field, format string, args ...any)) {
+	if s.Spec.Log != nil {
+		issue(s.Name+".log", "logs are not exported")
+	}
+	if on := s.Spec.OnNode; on != nil && (0 < len(on.May) || 0 < len(on.Prefer)) {
+		issue(s.Name+".on_node", `"may" and "prefer" are not exported`)
+	}
+	if s.Spec.Active != nil && !*s.Spec.Active {
+		issue(s.Name+".active", "inactive plan is exported as a normal step")
+	}
+}
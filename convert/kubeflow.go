@@ -0,0 +1,156 @@
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+type kfpComponent struct {
+	Name    string         `yaml:"name"`
+	Inputs  []kfpInterface `yaml:"inputs"`
+	Outputs []kfpInterface `yaml:"outputs"`
+
+	Implementation struct {
+		Container *struct {
+			Image   string            `yaml:"image"`
+			Command []yaml.Node       `yaml:"command"`
+			Args    []yaml.Node       `yaml:"args"`
+			Env     map[string]string `yaml:"env"`
+		} `yaml:"container"`
+		Graph any `yaml:"graph"`
+	} `yaml:"implementation"`
+}
+
+type kfpInterface struct {
+	Name    string  `yaml:"name"`
+	Default *string `yaml:"default"`
+}
+
+// Directories where artifacts of Kubeflow components are mounted.
+const (
+	KubeflowInputDir  = "/in"
+	KubeflowOutputDir = "/out"
+)
+
+// FromKubeflow converts a Kubeflow Pipelines component specification (component.yaml) into a PlanSpec.
+//
+// Placeholders in command and args are converted as below:
+//
+// - {inputPath: NAME} becomes an input mountpoint at "/in/NAME" tagged "artifact:NAME".
+//
+// - {outputPath: NAME} becomes an output mountpoint at "/out/NAME" tagged "artifact:NAME".
+//
+// - {inputValue: NAME} is replaced with the default value of the input.
+//
+// Env becomes Env of the PlanSpec, sorted by names.
+//
+// Other placeholders and graph components are reported as Issues.
+func FromKubeflow(b []byte) (Result, error) {
+	comp := kfpComponent{}
+	if err := yaml.Unmarshal(b, &comp); err != nil {
+		return Result{}, fmt.Errorf("kubeflow: %w", err)
+	}
+
+	r := Result{Specs: []plans.PlanSpec{}}
+	impl := comp.Implementation
+	if impl.Graph != nil {
+		r.issue("implementation.graph", "graph component is not converted. convert each component of the graph")
+		return r, nil
+	}
+	if impl.Container == nil {
+		return Result{}, fmt.Errorf("kubeflow: implementation.container is missing")
+	}
+	c := impl.Container
+
+	spec := plans.PlanSpec{
+		Annotations: plans.Annotations{
+			{Key: AnnotationConvertedFrom, Value: "kubeflow:" + comp.Name},
+		},
+		Inputs:  []plans.Mountpoint{},
+		Outputs: []plans.Mountpoint{},
+	}
+	if err := spec.Image.Parse(c.Image); err != nil {
+		r.issue("implementation.container.image", "image %q is not converted: %s", c.Image, err)
+	}
+
+	defaults := map[string]*string{}
+	for _, in := range comp.Inputs {
+		defaults[in.Name] = in.Default
+	}
+
+	conv := &kfpConverter{result: &r, spec: &spec, defaults: defaults, mounted: map[string]bool{}}
+	spec.Entrypoint = conv.args("implementation.container.command", c.Command)
+	spec.Args = conv.args("implementation.container.args", c.Args)
+
+	for _, name := range slices.Sorted(maps.Keys(c.Env)) {
+		spec.Env = append(spec.Env, plans.EnvVar{Name: name, Value: c.Env[name]})
+	}
+
+	r.Specs = append(r.Specs, spec)
+	return r, nil
+}
+
+type kfpConverter struct {
+	result   *Result
+	spec     *plans.PlanSpec
+	defaults map[string]*string
+	mounted  map[string]bool
+}
+
+func (k *kfpConverter) args(field string, nodes []yaml.Node) []string {
+	if nodes == nil {
+		return nil
+	}
+	ret := []string{}
+	for i, n := range nodes {
+		f := fmt.Sprintf("%s[%d]", field, i)
+		if n.Kind == yaml.ScalarNode {
+			ret = append(ret, n.Value)
+			continue
+		}
+
+		ph := map[string]string{}
+		if err := n.Decode(&ph); err != nil || len(ph) != 1 {
+			k.result.issue(f, "unsupported placeholder")
+			continue
+		}
+		for kind, name := range ph {
+			switch kind {
+			case "inputPath":
+				p := path.Join(KubeflowInputDir, name)
+				k.mount(&k.spec.Inputs, "in:"+name, p, name)
+				ret = append(ret, p)
+			case "outputPath":
+				p := path.Join(KubeflowOutputDir, name)
+				k.mount(&k.spec.Outputs, "out:"+name, p, name)
+				ret = append(ret, p)
+			case "inputValue":
+				v, ok := k.defaults[name]
+				if !ok || v == nil {
+					k.result.issue(f, "{inputValue: %s} has no default value", name)
+					ret = append(ret, "{{"+name+"}}")
+					continue
+				}
+				ret = append(ret, *v)
+			default:
+				k.result.issue(f, "placeholder %s is not supported", strings.TrimSpace(kind))
+			}
+		}
+	}
+	return ret
+}
+
+func (k *kfpConverter) mount(mps *[]plans.Mountpoint, key string, p string, name string) {
+	if k.mounted[key] {
+		return
+	}
+	k.mounted[key] = true
+	*mps = append(*mps, plans.Mountpoint{Path: p, Tags: []tags.Tag{artifactTag(name)}})
+}
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/errors"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// BulkRegistration is the format for request body to Knitfab APIs below:
+//
+// - POST /api/data/bulk
+//
+// It registers many Data in one request.
+// Contents of Data are sent as parts of a multipart request, or referred as external locations.
+type BulkRegistration struct {
+	// Project is the name of the Project which the new Data belong to.
+	//
+	// If empty, the Data are not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// Items are the Data to be registered.
+	Items []RegistrationItem `json:"items" yaml:"items"`
+}
+
+func (b BulkRegistration) Equal(o BulkRegistration) bool {
+	return b.Project == o.Project && apicmp.SliceEqual(b.Items, o.Items)
+}
+
+// Validate checks all items, and returns the first error found.
+func (b BulkRegistration) Validate() error {
+	if err := projects.ValidateName(b.Project); err != nil {
+		return err
+	}
+	for i, item := range b.Items {
+		if err := item.Validate(); err != nil {
+			return fmt.Errorf("items[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// RegistrationItem is a Data to be registered by BulkRegistration.
+type RegistrationItem struct {
+	// Tags are the tags to be attached to the new Data.
+	Tags []tags.UserTag `json:"tags" yaml:"tags"`
+
+	// Part is the name of the multipart part which holds the content of the Data.
+	//
+	// This and External are mutually exclusive.
+	Part string `json:"part,omitempty" yaml:"part,omitempty"`
+
+	// External is the location of the content of the Data outside of Knitfab.
+	//
+	// This and Part are mutually exclusive.
+	External *ExternalRef `json:"external,omitempty" yaml:"external,omitempty"`
+}
+
+func (r RegistrationItem) Equal(o RegistrationItem) bool {
+	return r.Part == o.Part &&
+		apicmp.PtrEqual(r.External, o.External) &&
+		apicmp.SliceEqualUnordered(r.Tags, o.Tags)
+}
+
+// Validate checks that exactly one of Part or External is set.
+func (r RegistrationItem) Validate() error {
+	switch {
+	case r.Part == "" && r.External == nil:
+		return fmt.Errorf(`one of "part" or "external" is required`)
+	case r.Part != "" && r.External != nil:
+		return fmt.Errorf(`"part" and "external" are mutually exclusive`)
+	case r.External != nil && r.External.URL == "":
+		return fmt.Errorf(`required field missing: "external.url"`)
+	}
+	return nil
+}
+
+// ExternalRef is the location of Data content stored outside of Knitfab.
+type ExternalRef struct {
+	// URL is the location of the content. It should be readable from Knitfab.
+	URL string `json:"url" yaml:"url"`
+}
+
+func (e ExternalRef) Equal(o ExternalRef) bool {
+	return e.URL == o.URL
+}
+
+// BulkRegistrationResult is the format for response body from Knitfab APIs below:
+//
+// - POST /api/data/bulk
+//
+// Each item of the request is registered independently,
+// so some items can be failed even if others are succeeded.
+type BulkRegistrationResult struct {
+	// Results are the results of each item, in the order of the request.
+	Results []RegistrationResult `json:"results" yaml:"results"`
+}
+
+func (b BulkRegistrationResult) Equal(o BulkRegistrationResult) bool {
+	return apicmp.SliceEqual(b.Results, o.Results)
+}
+
+// Failed returns results which have been failed.
+func (b BulkRegistrationResult) Failed() []RegistrationResult {
+	failed := []RegistrationResult{}
+	for _, r := range b.Results {
+		if !r.Ok() {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// RegistrationResult is the result of a RegistrationItem.
+type RegistrationResult struct {
+	// Index is the index of the item in BulkRegistration.Items.
+	Index int `json:"index" yaml:"index"`
+
+	// KnitId is the id of the registered Data.
+	//
+	// This is empty if the registration has been failed.
+	KnitId knitid.KnitId `json:"knitId,omitempty" yaml:"knitId,omitempty"`
+
+	// Error is the reason why the registration has been failed.
+	//
+	// This is nil if the registration has been succeeded.
+	Error *errors.ErrorMessage `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Ok returns true if the item has been registered.
+func (r RegistrationResult) Ok() bool {
+	return r.Error == nil
+}
+
+func (r RegistrationResult) Equal(o RegistrationResult) bool {
+	return r.Index == o.Index &&
+		r.KnitId == o.KnitId &&
+		apicmp.PtrEqual(r.Error, o.Error)
+}
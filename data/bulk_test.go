@@ -0,0 +1,191 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/errors"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestBulkRegistration_Validate(t *testing.T) {
+	tagged := []tags.UserTag{{Key: "type", Value: "csv"}}
+
+	for name, testcase := range map[string]struct {
+		When    data.BulkRegistration
+		WantErr bool
+	}{
+		"no items": {
+			When: data.BulkRegistration{},
+		},
+		"part and external": {
+			When: data.BulkRegistration{
+				Project: "demo",
+				Items: []data.RegistrationItem{
+					{Tags: tagged, Part: "file-1"},
+					{Tags: tagged, External: &data.ExternalRef{URL: "s3://bucket/file-2"}},
+				},
+			},
+		},
+		"invalid project": {
+			When: data.BulkRegistration{
+				Project: "Demo_Project",
+				Items:   []data.RegistrationItem{{Part: "file-1"}},
+			},
+			WantErr: true,
+		},
+		"item without content": {
+			When: data.BulkRegistration{
+				Items: []data.RegistrationItem{{Part: "file-1"}, {Tags: tagged}},
+			},
+			WantErr: true,
+		},
+		"item with both part and external": {
+			When: data.BulkRegistration{
+				Items: []data.RegistrationItem{
+					{Part: "file-1", External: &data.ExternalRef{URL: "s3://bucket/file-1"}},
+				},
+			},
+			WantErr: true,
+		},
+		"external without url": {
+			When: data.BulkRegistration{
+				Items: []data.RegistrationItem{{External: &data.ExternalRef{}}},
+			},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestBulkRegistrationResult_Failed(t *testing.T) {
+	ok1 := data.RegistrationResult{Index: 0, KnitId: knitid.KnitId("0190a1b2-0000-7000-8000-000000000301")}
+	ng1 := data.RegistrationResult{Index: 1, Error: &errors.ErrorMessage{Reason: "part not found"}}
+	ok2 := data.RegistrationResult{Index: 2, KnitId: knitid.KnitId("0190a1b2-0000-7000-8000-000000000302")}
+	ng2 := data.RegistrationResult{Index: 3, Error: &errors.ErrorMessage{Reason: "external is not readable"}}
+
+	if !ok1.Ok() || !ok2.Ok() {
+		t.Errorf("succeeded results are not ok: %+v, %+v", ok1, ok2)
+	}
+	if ng1.Ok() || ng2.Ok() {
+		t.Errorf("failed results are ok: %+v, %+v", ng1, ng2)
+	}
+
+	for name, testcase := range map[string]struct {
+		When data.BulkRegistrationResult
+		Want []data.RegistrationResult
+	}{
+		"empty": {
+			When: data.BulkRegistrationResult{},
+			Want: []data.RegistrationResult{},
+		},
+		"all succeeded": {
+			When: data.BulkRegistrationResult{Results: []data.RegistrationResult{ok1, ok2}},
+			Want: []data.RegistrationResult{},
+		},
+		"partially failed": {
+			When: data.BulkRegistrationResult{Results: []data.RegistrationResult{ok1, ng1, ok2, ng2}},
+			Want: []data.RegistrationResult{ng1, ng2},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := testcase.When.Failed()
+			if got == nil {
+				t.Errorf("got nil, want empty slice")
+			}
+			if !apicmp.SliceEqual(got, testcase.Want) {
+				t.Errorf("got %+v, want %+v", got, testcase.Want)
+			}
+		})
+	}
+}
+
+func TestRegistrationResult_Equal(t *testing.T) {
+	base := func() data.RegistrationResult {
+		return data.RegistrationResult{
+			Index: 1,
+			Error: &errors.ErrorMessage{
+				Reason:   "tag type:dataset is reserved",
+				Advice:   "use another key",
+				See:      "https://example.com/docs/tags",
+				Template: "tag {tag} is reserved",
+				Params:   map[string]string{"tag": "type:dataset"},
+			},
+		}
+	}
+
+	for name, testcase := range map[string]struct {
+		When func(*data.RegistrationResult)
+		Want bool
+	}{
+		"same":             {When: func(*data.RegistrationResult) {}, Want: true},
+		"index differs":    {When: func(r *data.RegistrationResult) { r.Index = 2 }},
+		"knitId differs":   {When: func(r *data.RegistrationResult) { r.KnitId = "0190a1b2-0000-7000-8000-000000000301" }},
+		"no error":         {When: func(r *data.RegistrationResult) { r.Error = nil }},
+		"reason differs":   {When: func(r *data.RegistrationResult) { r.Error.Reason = "tag type:model is reserved" }},
+		"advice differs":   {When: func(r *data.RegistrationResult) { r.Error.Advice = "" }},
+		"see differs":      {When: func(r *data.RegistrationResult) { r.Error.See = "" }},
+		"template differs": {When: func(r *data.RegistrationResult) { r.Error.Template = "tag {tag} is not allowed" }},
+		"params differ":    {When: func(r *data.RegistrationResult) { r.Error.Params["tag"] = "type:model" }},
+		"params missing":   {When: func(r *data.RegistrationResult) { r.Error.Params = nil }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			a, b := base(), base()
+			testcase.When(&b)
+			if got := a.Equal(b); got != testcase.Want {
+				t.Errorf("a.Equal(b): got %v, want %v", got, testcase.Want)
+			}
+			if got := b.Equal(a); got != testcase.Want {
+				t.Errorf("b.Equal(a): got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
+
+func TestRegistrationItem_Equal(t *testing.T) {
+	external := func(url string) *data.ExternalRef { return &data.ExternalRef{URL: url} }
+
+	for name, testcase := range map[string]struct {
+		A, B data.RegistrationItem
+		Want bool
+	}{
+		"same part": {
+			A:    data.RegistrationItem{Part: "file-1"},
+			B:    data.RegistrationItem{Part: "file-1"},
+			Want: true,
+		},
+		"same external": {
+			A:    data.RegistrationItem{External: external("s3://bucket/file-1")},
+			B:    data.RegistrationItem{External: external("s3://bucket/file-1")},
+			Want: true,
+		},
+		"external differs": {
+			A: data.RegistrationItem{External: external("s3://bucket/file-1")},
+			B: data.RegistrationItem{External: external("s3://bucket/file-2")},
+		},
+		"external and nil": {
+			A: data.RegistrationItem{External: external("s3://bucket/file-1")},
+			B: data.RegistrationItem{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.A.Equal(testcase.B); got != testcase.Want {
+				t.Errorf("A.Equal(B): got %v, want %v", got, testcase.Want)
+			}
+			if got := testcase.B.Equal(testcase.A); got != testcase.Want {
+				t.Errorf("B.Equal(A): got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// Algorithms of Checksum.
+const (
+	ChecksumSHA256 = "sha256"
+	ChecksumSHA512 = "sha512"
+)
+
+// Checksum is a digest of the content of Data.
+type Checksum struct {
+	// Algorithm is the hash algorithm, "sha256" or "sha512".
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+
+	// Value is the digest in lower case hex.
+	Value string `json:"value" yaml:"value"`
+}
+
+func (c Checksum) Equal(o Checksum) bool {
+	return c.Algorithm == o.Algorithm && strings.EqualFold(c.Value, o.Value)
+}
+
+// String returns the checksum in the form "algorithm:value", like "sha256:e3b0c442...".
+func (c Checksum) String() string {
+	return c.Algorithm + ":" + c.Value
+}
+
+// ParseChecksum parses the checksum in the form "algorithm:value".
+func ParseChecksum(s string) (Checksum, error) {
+	algorithm, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return Checksum{}, fmt.Errorf(`checksum should be in the form "algorithm:value": %q`, s)
+	}
+	c := Checksum{Algorithm: algorithm, Value: strings.ToLower(value)}
+	if err := c.Validate(); err != nil {
+		return Checksum{}, err
+	}
+	return c, nil
+}
+
+// Validate checks the algorithm is known, and the value is a hex digest of its size.
+func (c Checksum) Validate() error {
+	var size int
+	switch c.Algorithm {
+	case ChecksumSHA256:
+		size = 32
+	case ChecksumSHA512:
+		size = 64
+	default:
+		return fmt.Errorf(`checksum algorithm should be "%s" or "%s": %q`, ChecksumSHA256, ChecksumSHA512, c.Algorithm)
+	}
+	b, err := hex.DecodeString(c.Value)
+	if err != nil || len(b) != size {
+		return fmt.Errorf("checksum value should be %d bytes in hex: %q", size, c.Value)
+	}
+	return nil
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case ChecksumSHA256:
+		return sha256.New(), nil
+	case ChecksumSHA512:
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf(`checksum algorithm should be "%s" or "%s": %q`, ChecksumSHA256, ChecksumSHA512, algorithm)
+}
+
+// ComputeChecksum reads r until its end, and returns its Checksum by the algorithm with its size.
+func ComputeChecksum(algorithm string, r io.Reader) (Checksum, int64, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return Checksum{}, 0, err
+	}
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return Checksum{}, n, err
+	}
+	return Checksum{Algorithm: algorithm, Value: hex.EncodeToString(h.Sum(nil))}, n, nil
+}
+
+// Verify reads r until its end, and returns error if its checksum does not match c.
+func (c Checksum) Verify(r io.Reader) error {
+	got, _, err := ComputeChecksum(c.Algorithm, r)
+	if err != nil {
+		return err
+	}
+	if !got.Equal(c) {
+		return fmt.Errorf("checksum mismatch: expected %s, but got %s", c, got)
+	}
+	return nil
+}
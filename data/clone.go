@@ -0,0 +1,66 @@
+package data
+
+import (
+	"slices"
+
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+// Clone returns a deep copy of the Summary.
+func (s Summary) Clone() Summary {
+	return Summary{KnitId: s.KnitId, Tags: slices.Clone(s.Tags), Project: s.Project, deprecated: slices.Clone(s.deprecated)}
+}
+
+// Clone returns a deep copy of the Detail.
+func (d Detail) Clone() Detail {
+	return Detail{
+		KnitId:      d.KnitId,
+		Tags:        slices.Clone(d.Tags),
+		Upstream:    d.Upstream.Clone(),
+		Downstreams: clone.SliceWith(d.Downstreams, AssignedTo.Clone),
+		Nomination:  clone.SliceWith(d.Nomination, NominatedBy.Clone),
+		Encryption:  clone.PtrWith(d.Encryption, Encryption.Clone),
+		Replicas:    clone.SliceWith(d.Replicas, Replication.Clone),
+		Warnings:    slices.Clone(d.Warnings),
+		Size:        d.Size,
+		Checksum:    clone.Ptr(d.Checksum),
+		Project:     d.Project,
+		Extensions:  d.Extensions.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the CreatedFrom.
+func (c CreatedFrom) Clone() CreatedFrom {
+	return CreatedFrom{
+		Mountpoint: clone.PtrWith(c.Mountpoint, plans.Mountpoint.Clone),
+		Log:        clone.PtrWith(c.Log, plans.LogPoint.Clone),
+		Run:        c.Run.Clone(),
+		Origin:     clone.Ptr(c.Origin),
+	}
+}
+
+// Clone returns a deep copy of the AssignedTo.
+func (a AssignedTo) Clone() AssignedTo {
+	return AssignedTo{Mountpoint: a.Mountpoint.Clone(), Run: a.Run.Clone()}
+}
+
+// Clone returns a deep copy of the NominatedBy.
+func (n NominatedBy) Clone() NominatedBy {
+	return NominatedBy{
+		Mountpoint: n.Mountpoint.Clone(),
+		Plan:       n.Plan.Clone(),
+		Blocked:    clone.PtrWith(n.Blocked, Blocked.Clone),
+	}
+}
+
+// Clone returns a deep copy of the Encryption.
+func (e Encryption) Clone() Encryption {
+	return Encryption{Enabled: e.Enabled, Algorithm: e.Algorithm, Key: clone.Ptr(e.Key)}
+}
+
+// Clone returns a deep copy of the Replication.
+func (r Replication) Clone() Replication {
+	r.LastSyncedAt = clone.Ptr(r.LastSyncedAt)
+	return r
+}
@@ -0,0 +1,60 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestDetail_Clone(t *testing.T) {
+	syncedAt, err := rfctime.ParseRFC3339DateTime("2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := runs.Summary{
+		RunId: "run-1", Status: runs.Done,
+		Exit: &runs.Exit{Code: 0, Message: "Completed"},
+		Plan: plans.Summary{PlanId: "plan-1", Image: &plans.Image{Repository: "repo", Tag: "v1"}},
+	}
+	original := data.Detail{
+		KnitId: "knit-1",
+		Tags:   []tags.Tag{{Key: "type", Value: "model"}},
+		Upstream: data.CreatedFrom{
+			Mountpoint: &plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+			Run:        run,
+		},
+		Downstreams: []data.AssignedTo{{Mountpoint: plans.Mountpoint{Path: "/in"}, Run: run}},
+		Nomination: []data.NominatedBy{{
+			Mountpoint: plans.Mountpoint{Path: "/in"},
+			Plan:       run.Plan,
+			Blocked: &data.Blocked{
+				Reason:        data.BlockMissingInputs,
+				MissingInputs: []plans.Mountpoint{{Path: "/in/2", Tags: []tags.Tag{{Key: "type", Value: "config"}}}},
+			},
+		}},
+		Encryption: &data.Encryption{Enabled: true, Key: &data.KeyRef{Provider: "vault", Id: "k"}},
+		Replicas:   []data.Replication{{State: data.ReplicationInSync, LastSyncedAt: &syncedAt}},
+	}
+	snapshot := original.Clone()
+
+	cloned := original.Clone()
+	if !cloned.Equal(original) {
+		t.Fatalf("clone is not equal: %+v", cloned)
+	}
+	cloned.Tags[0].Value = "mutated"
+	cloned.Upstream.Mountpoint.Path = "/mutated"
+	cloned.Upstream.Run.Exit.Code = 1
+	cloned.Upstream.Run.Plan.Image.Tag = "mutated"
+	cloned.Downstreams[0].Run.Exit.Message = "mutated"
+	cloned.Nomination[0].Blocked.MissingInputs[0].Tags[0].Value = "mutated"
+	cloned.Encryption.Key.Id = "mutated"
+	*cloned.Replicas[0].LastSyncedAt = rfctime.RFC3339{}
+
+	if !original.Equal(snapshot) {
+		t.Errorf("original is modified through clone: %v", original.Diff(snapshot))
+	}
+}
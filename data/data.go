@@ -1,20 +1,49 @@
 package data
 
 import (
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/federation"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/meta"
 	"github.com/opst/knitfab-api-types/plans"
 	"github.com/opst/knitfab-api-types/runs"
 	"github.com/opst/knitfab-api-types/tags"
 )
 
+// Summary is a brief of Data.
+//
+// Summary was marshalled with the key "knitid" for KnitId.
+// It is marshalled with "knitId" now, as the other types,
+// and decoded from both of them. See DeprecatedSummaryKnitid.
 type Summary struct {
-	KnitId string     `json:"knitid"`
-	Tags   []tags.Tag `json:"tags"`
+	KnitId knitid.KnitId `json:"knitId" yaml:"knitId"`
+	Tags   []tags.Tag    `json:"tags" yaml:"tags"`
+
+	// Project is the name of the Project which the Data belongs to.
+	//
+	// If empty, the Data is not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// deprecated are deprecated forms found when the Summary is decoded.
+	deprecated []meta.Deprecation
+}
+
+// Deprecations returns deprecated forms found when the Summary was decoded,
+// like the legacy key "knitid".
+//
+// Clients can use this to find payloads which should be migrated.
+// It is empty for Summaries not decoded or decoded from the current form.
+func (s Summary) Deprecations() []meta.Deprecation {
+	return slices.Clone(s.deprecated)
 }
 
 func (s *Summary) Equal(o *Summary) bool {
 	return s.KnitId == o.KnitId &&
-		cmp.SliceEqualUnordered(s.Tags, o.Tags)
+		s.Project == o.Project &&
+		apicmp.SliceEqualUnordered(s.Tags, o.Tags)
 }
 
 // Detail is the format for response body from WebAPIs below:
@@ -30,27 +59,72 @@ func (s *Summary) Equal(o *Summary) bool {
 // - GET  /api/data/{knitId} : as binary stream (Content-Type: application/octet-stream)
 type Detail struct {
 	// KnitId is the id of the Data.
-	KnitId string `json:"knitId"`
+	KnitId knitid.KnitId `json:"knitId" yaml:"knitId"`
 
 	// Tags are the tags of the Data.
-	Tags []tags.Tag `json:"tags"`
+	Tags []tags.Tag `json:"tags" yaml:"tags"`
 
 	// Upstream is the upsteram Run and its mountpoint outputs this Data.
-	Upstream CreatedFrom `json:"upstream"`
+	Upstream CreatedFrom `json:"upstream" yaml:"upstream"`
 
 	// Downstreams are the downstream Runs and their mountpoint inputs this Data.
-	Downstreams []AssignedTo `json:"downstreams"`
+	Downstreams []AssignedTo `json:"downstreams" yaml:"downstreams"`
 
 	// Nomination is the nominated Plan and its mountpoint can inputs this Data.
-	Nomination []NominatedBy `json:"nomination"`
+	Nomination []NominatedBy `json:"nomination" yaml:"nomination"`
+
+	// Encryption is the encryption status of the Data at rest.
+	//
+	// If nil, the status is not reported.
+	Encryption *Encryption `json:"encryption,omitempty" yaml:"encryption,omitempty"`
+
+	// Replicas are the replicas of the Data in other clusters or storages.
+	Replicas []Replication `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+
+	// Warnings are soft issues found while registering the Data or changing its tags.
+	//
+	// This is set only in responses of mutating WebAPIs.
+	Warnings []meta.Warning `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// Size is the size of the content of the Data in bytes.
+	//
+	// If 0, it is unknown.
+	Size int64 `json:"size,omitempty" yaml:"size,omitempty"`
+
+	// Checksum is the checksum of the content of the Data, as downloaded.
+	//
+	// If nil, it is unknown.
+	Checksum *Checksum `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+
+	// Project is the name of the Project which the Data belongs to.
+	//
+	// If empty, the Data is not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+
+	// Extensions are custom fields of the Data, set by plugins or experimental features of Knitfab.
+	//
+	// Clients should keep ones they do not know as they are.
+	Extensions extensions.Extensions `json:"x-ext,omitempty" yaml:"x-ext,omitempty"`
 }
 
 func (d Detail) Equal(o Detail) bool {
 	return d.KnitId == o.KnitId &&
+		apicmp.PtrEqual(d.Encryption, o.Encryption) &&
 		d.Upstream.Equal(o.Upstream) &&
-		cmp.SliceEqualUnordered(d.Tags, o.Tags) &&
-		cmp.SliceEqualUnordered(d.Downstreams, o.Downstreams) &&
-		cmp.SliceEqualUnordered(d.Nomination, o.Nomination)
+		apicmp.SliceEqualUnordered(d.Tags, o.Tags) &&
+		apicmp.SliceEqualUnordered(d.Downstreams, o.Downstreams) &&
+		apicmp.SliceEqualUnordered(d.Nomination, o.Nomination) &&
+		apicmp.SliceEqualUnordered(d.Replicas, o.Replicas) &&
+		apicmp.SliceEqualUnordered(d.Warnings, o.Warnings) &&
+		d.Size == o.Size &&
+		apicmp.PtrEqual(d.Checksum, o.Checksum) &&
+		d.Project == o.Project &&
+		d.Extensions.Equal(o.Extensions)
+}
+
+// ToSummary returns the Summary of the Data, sharing nothing with d.
+func (d Detail) ToSummary() Summary {
+	return Summary{KnitId: d.KnitId, Tags: slices.Clone(d.Tags), Project: d.Project}
 }
 
 // CreatedFrom represents the source of the data
@@ -58,29 +132,33 @@ type CreatedFrom struct {
 	// Mountpoint is the mountpoint which created this Data.
 	//
 	// This and Log are mutually exclusive.
-	Mountpoint *plans.Mountpoint `json:"mountpoint,omitempty"`
+	Mountpoint *plans.Mountpoint `json:"mountpoint,omitempty" yaml:"mountpoint,omitempty"`
 
 	// Log is the log point which created this Data.
 	//
 	// This and Mountpoint are mutually exclusive.
-	Log *plans.LogPoint `json:"log,omitempty"`
+	Log *plans.LogPoint `json:"log,omitempty" yaml:"log,omitempty"`
 
 	// Run is the Run which created this Data.
-	Run runs.Summary `json:"run"`
+	Run runs.Summary `json:"run" yaml:"run"`
+
+	// Origin is the Data in a remote cluster which this Data is imported from.
+	//
+	// If nil, this Data is not imported from other clusters.
+	Origin *federation.Ref `json:"origin,omitempty" yaml:"origin,omitempty"`
 }
 
 func (c CreatedFrom) Equal(o CreatedFrom) bool {
-	mountpointEq := (c.Mountpoint == nil && o.Mountpoint == nil) ||
-		(c.Mountpoint != nil && o.Mountpoint != nil && c.Mountpoint.Equal(*o.Mountpoint))
-	logEq := (c.Log == nil && o.Log == nil) ||
-		(c.Log != nil && o.Log != nil && c.Log.Equal(*o.Log))
-	return c.Run.Equal(o.Run) && mountpointEq && logEq
+	return c.Run.Equal(o.Run) &&
+		apicmp.PtrEqual(c.Mountpoint, o.Mountpoint) &&
+		apicmp.PtrEqual(c.Log, o.Log) &&
+		apicmp.PtrEqual(c.Origin, o.Origin)
 }
 
 // assigment representation, looking from data
 type AssignedTo struct {
-	Mountpoint plans.Mountpoint `json:"mountpoint"`
-	Run        runs.Summary     `json:"run"`
+	Mountpoint plans.Mountpoint `json:"mountpoint" yaml:"mountpoint"`
+	Run        runs.Summary     `json:"run" yaml:"run"`
 }
 
 func (a AssignedTo) Equal(o AssignedTo) bool {
@@ -89,10 +167,17 @@ func (a AssignedTo) Equal(o AssignedTo) bool {
 
 // nomination representation, looking from data
 type NominatedBy struct {
-	plans.Mountpoint
-	Plan plans.Summary `json:"plan"`
+	plans.Mountpoint `yaml:",inline"`
+	Plan             plans.Summary `json:"plan" yaml:"plan"`
+
+	// Blocked tells why the Plan has not started a Run with this Data.
+	//
+	// If nil, the Plan is not blocked, or the reason is not reported.
+	Blocked *Blocked `json:"blocked,omitempty" yaml:"blocked,omitempty"`
 }
 
 func (n NominatedBy) Equal(o NominatedBy) bool {
-	return n.Plan.Equal(o.Plan) && n.Mountpoint.Equal(o.Mountpoint)
+	return n.Plan.Equal(o.Plan) &&
+		n.Mountpoint.Equal(o.Mountpoint) &&
+		apicmp.PtrEqual(n.Blocked, o.Blocked)
 }
@@ -0,0 +1,281 @@
+package data
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+// DepsDirection is the direction to expand dependencies of Data.
+type DepsDirection string
+
+const (
+	// DepsDown expands downstream: Data created by Runs which took the Data, and so on.
+	DepsDown DepsDirection = "down"
+
+	// DepsUp expands upstream: Data taken by the Run which created the Data, and so on.
+	DepsUp DepsDirection = "up"
+
+	// DepsBoth expands both of upstream and downstream.
+	DepsBoth DepsDirection = "both"
+)
+
+// Valid returns true if d is a known direction.
+func (d DepsDirection) Valid() bool {
+	switch d {
+	case DepsDown, DepsUp, DepsBoth:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasUp returns true if d expands upstream.
+func (d DepsDirection) hasUp() bool {
+	return d == DepsUp || d == DepsBoth
+}
+
+// hasDown returns true if d expands downstream.
+func (d DepsDirection) hasDown() bool {
+	return d == DepsDown || d == DepsBoth
+}
+
+// DepsOptions is the query parameters for Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/deps[?...]
+//
+// Detail.Downstreams is single hop. This expands dependencies transitively.
+type DepsOptions struct {
+	// Depth is the max number of hops from the root Data.
+	//
+	// If 0, the depth is unlimited.
+	Depth int
+
+	// Direction is the direction to expand.
+	//
+	// If empty, it is DepsDown.
+	Direction DepsDirection
+}
+
+func (o DepsOptions) Equal(oo DepsOptions) bool {
+	return o.Depth == oo.Depth && o.direction() == oo.direction()
+}
+
+func (o DepsOptions) direction() DepsDirection {
+	if o.Direction == "" {
+		return DepsDown
+	}
+	return o.Direction
+}
+
+// Encode returns the options as url.Values.
+func (o DepsOptions) Encode() url.Values {
+	v := url.Values{}
+	if o.Depth != 0 {
+		v.Set(ParamDepth, strconv.Itoa(o.Depth))
+	}
+	if d := o.direction(); d != DepsDown {
+		v.Set(ParamDirection, string(d))
+	}
+	return v
+}
+
+// Decode reads the options from url.Values.
+func (o *DepsOptions) Decode(v url.Values) error {
+	ret := DepsOptions{Direction: DepsDown}
+
+	if d := v.Get(ParamDepth); d != "" {
+		depth, err := strconv.Atoi(d)
+		if err != nil || depth < 0 {
+			return fmt.Errorf(`query parameter "%s" should be a non-negative integer: %q`, ParamDepth, d)
+		}
+		ret.Depth = depth
+	}
+
+	if d := DepsDirection(v.Get(ParamDirection)); d != "" {
+		if !d.Valid() {
+			return fmt.Errorf(
+				`query parameter "%s" should be "%s", "%s" or "%s": %q`,
+				ParamDirection, DepsUp, DepsDown, DepsBoth, d,
+			)
+		}
+		ret.Direction = d
+	}
+
+	*o = ret
+	return nil
+}
+
+// Dep is a Data depended on by (or depending on) the root Data.
+type Dep struct {
+	// Data is the depended (or depending) Data.
+	Data Summary `json:"data" yaml:"data"`
+
+	// Hops is the number of Runs between the root Data and this Data. It is 1 or more.
+	Hops int `json:"hops" yaml:"hops"`
+
+	// Via is the knitId of the Data one hop closer to the root Data.
+	//
+	// For Dep with Hops = 1, it is the root Data.
+	Via knitid.KnitId `json:"via" yaml:"via"`
+
+	// Run is the Run connecting Via and Data.
+	Run runs.Summary `json:"run" yaml:"run"`
+}
+
+func (d Dep) Equal(o Dep) bool {
+	return d.Data.Equal(&o.Data) &&
+		d.Hops == o.Hops &&
+		d.Via == o.Via &&
+		d.Run.Equal(o.Run)
+}
+
+// Deps is the format for response body from Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/deps[?...] (with DepsOptions)
+//
+// Each Data appears at most once in each of Upstreams and Downstreams, at its smallest Hops.
+// So, Deps is finite even if lineage has cycles (for example, by federation).
+type Deps struct {
+	// Root is the knitId of the Data whose dependencies are requested.
+	Root knitid.KnitId `json:"root" yaml:"root"`
+
+	// Upstreams are Data which the root Data depends on.
+	//
+	// It is empty unless the direction is DepsUp or DepsBoth.
+	Upstreams []Dep `json:"upstreams" yaml:"upstreams"`
+
+	// Downstreams are Data which depend on the root Data.
+	//
+	// It is empty unless the direction is DepsDown or DepsBoth.
+	Downstreams []Dep `json:"downstreams" yaml:"downstreams"`
+
+	// Truncated is true if the expansion has been stopped by the depth limit,
+	// so there can be more Data beyond.
+	Truncated bool `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+}
+
+// Equal returns true if d and o have the same Data, ignoring their order and duplications.
+func (d Deps) Equal(o Deps) bool {
+	dn, on := d.Normalize(), o.Normalize()
+	return dn.Root == on.Root &&
+		dn.Truncated == on.Truncated &&
+		apicmp.SliceEqual(dn.Upstreams, on.Upstreams) &&
+		apicmp.SliceEqual(dn.Downstreams, on.Downstreams)
+}
+
+// Normalize returns Deps sharing nothing with d, whose Upstreams and Downstreams are
+//
+// - without the root Data,
+//
+// - without duplicated Data: only one with the smallest Hops is kept, and
+//
+// - sorted by Hops, knitId of Data, Via and RunId.
+func (d Deps) Normalize() Deps {
+	return Deps{
+		Root:        d.Root,
+		Upstreams:   normalizeDeps(d.Root, d.Upstreams),
+		Downstreams: normalizeDeps(d.Root, d.Downstreams),
+		Truncated:   d.Truncated,
+	}
+}
+
+// Filter returns Deps having only Data within depth and direction, sharing nothing with d.
+//
+// If depth is 0, it is unlimited. Truncated is set when any Data is removed by depth.
+func (d Deps) Filter(opts DepsOptions) Deps {
+	ret := d.Normalize()
+	dir := opts.direction()
+	within := func(dep Dep) bool {
+		if opts.Depth != 0 && opts.Depth < dep.Hops {
+			ret.Truncated = true
+			return false
+		}
+		return true
+	}
+	if dir.hasUp() {
+		ret.Upstreams = slices.DeleteFunc(ret.Upstreams, func(dep Dep) bool { return !within(dep) })
+	} else {
+		ret.Upstreams = []Dep{}
+	}
+	if dir.hasDown() {
+		ret.Downstreams = slices.DeleteFunc(ret.Downstreams, func(dep Dep) bool { return !within(dep) })
+	} else {
+		ret.Downstreams = []Dep{}
+	}
+	return ret
+}
+
+// KnitIds returns knitIds of Data in Upstreams and Downstreams, without duplications, sorted.
+func (d Deps) KnitIds() []knitid.KnitId {
+	ids := []knitid.KnitId{}
+	for _, dep := range slices.Concat(d.Upstreams, d.Downstreams) {
+		if dep.Data.KnitId == d.Root {
+			continue
+		}
+		ids = append(ids, dep.Data.KnitId)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// MarshalJSON marshals the Deps normalized, so that the same Deps is always marshalled into the same bytes.
+func (d Deps) MarshalJSON() ([]byte, error) {
+	type deps Deps
+	return json.Marshal(deps(d.Normalize()))
+}
+
+// MarshalYAML marshals the Deps normalized, as MarshalJSON.
+func (d Deps) MarshalYAML() (interface{}, error) {
+	type deps Deps
+	return deps(d.Normalize()), nil
+}
+
+func normalizeDeps(root knitid.KnitId, ds []Dep) []Dep {
+	ret := make([]Dep, 0, len(ds))
+	for _, dep := range ds {
+		if dep.Data.KnitId == root {
+			continue
+		}
+		ret = append(ret, Dep{
+			Data: dep.Data.Clone(),
+			Hops: dep.Hops,
+			Via:  dep.Via,
+			Run:  dep.Run.Clone(),
+		})
+	}
+
+	// keep the nearest one for each Data.
+	slices.SortStableFunc(ret, func(a, b Dep) int {
+		if c := cmp.Compare(a.Data.KnitId, b.Data.KnitId); c != 0 {
+			return c
+		}
+		return compareDeps(a, b)
+	})
+	ret = slices.CompactFunc(ret, func(a, b Dep) bool {
+		return a.Data.KnitId == b.Data.KnitId
+	})
+
+	slices.SortStableFunc(ret, compareDeps)
+	return ret
+}
+
+func compareDeps(a, b Dep) int {
+	if c := cmp.Compare(a.Hops, b.Hops); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Data.KnitId, b.Data.KnitId); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Via, b.Via); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Run.RunId, b.Run.RunId)
+}
@@ -0,0 +1,126 @@
+package data_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestDepsOptions_roundTrip(t *testing.T) {
+	for name, o := range map[string]data.DepsOptions{
+		"default": {Direction: data.DepsDown},
+		"up":      {Depth: 2, Direction: data.DepsUp},
+		"both":    {Depth: 1, Direction: data.DepsBoth},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := data.DepsOptions{}
+			if err := got.Decode(o.Encode()); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(o) {
+				t.Errorf("round trip: got %+v, want %+v", got, o)
+			}
+		})
+	}
+
+	if !(data.DepsOptions{}).Equal(data.DepsOptions{Direction: data.DepsDown}) {
+		t.Error("empty direction should be down")
+	}
+
+	for name, v := range map[string]url.Values{
+		"unknown direction": {data.ParamDirection: {"upstream"}},
+		"negative depth":    {data.ParamDepth: {"-1"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			o := data.DepsOptions{}
+			if err := o.Decode(v); err == nil {
+				t.Errorf("invalid query is accepted: %+v", o)
+			}
+		})
+	}
+}
+
+func TestDeps(t *testing.T) {
+	const (
+		root  knitid.KnitId = "0190a1b2-0000-7000-8000-000000000301"
+		knit2 knitid.KnitId = "0190a1b2-0000-7000-8000-000000000302"
+		knit3 knitid.KnitId = "0190a1b2-0000-7000-8000-000000000303"
+		knit4 knitid.KnitId = "0190a1b2-0000-7000-8000-000000000304"
+	)
+	run1 := runs.Summary{RunId: "0190a1b2-0000-7000-8000-000000000201", Status: runs.Done}
+	run2 := runs.Summary{RunId: "0190a1b2-0000-7000-8000-000000000202", Status: runs.Done}
+
+	// root -> run1 -> knit2 -> run2 -> knit3, and knit3 is also an output of run1 (reached twice).
+	// knit4 is upstream of root, and the cycle back to root is dropped.
+	deps := data.Deps{
+		Root: root,
+		Downstreams: []data.Dep{
+			{Data: data.Summary{KnitId: knit3}, Hops: 2, Via: knit2, Run: run2},
+			{Data: data.Summary{KnitId: knit2}, Hops: 1, Via: root, Run: run1},
+			{Data: data.Summary{KnitId: knit3}, Hops: 1, Via: root, Run: run1},
+			{Data: data.Summary{KnitId: root}, Hops: 3, Via: knit3, Run: run1},
+		},
+		Upstreams: []data.Dep{
+			{Data: data.Summary{KnitId: knit4}, Hops: 1, Via: root, Run: run1},
+		},
+	}
+
+	norm := deps.Normalize()
+	wantDown := []knitid.KnitId{knit2, knit3}
+	gotDown := []knitid.KnitId{}
+	for _, d := range norm.Downstreams {
+		gotDown = append(gotDown, d.Data.KnitId)
+		if d.Hops != 1 {
+			t.Errorf("not nearest: %+v", d)
+		}
+	}
+	if !slices.Equal(gotDown, wantDown) {
+		t.Errorf("downstreams: got %v, want %v", gotDown, wantDown)
+	}
+	if !norm.Equal(deps) {
+		t.Error("normalized deps should equal to the original")
+	}
+	if got := deps.KnitIds(); !slices.Equal(got, []knitid.KnitId{knit2, knit3, knit4}) {
+		t.Errorf("knitIds: %v", got)
+	}
+
+	t.Run("marshal is stable", func(t *testing.T) {
+		shuffled := deps
+		shuffled.Downstreams = slices.Clone(deps.Downstreams)
+		slices.Reverse(shuffled.Downstreams)
+
+		a, err := json.Marshal(deps)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(shuffled)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(a) != string(b) {
+			t.Errorf("unstable:\n%s\n%s", a, b)
+		}
+		knittest.AssertRoundTrip(t, deps)
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		got := deps.Filter(data.DepsOptions{Depth: 1, Direction: data.DepsUp})
+		if len(got.Downstreams) != 0 || len(got.Upstreams) != 1 || got.Truncated {
+			t.Errorf("unexpected: %+v", got)
+		}
+
+		got = data.Deps{Root: root, Downstreams: []data.Dep{
+			{Data: data.Summary{KnitId: knit2}, Hops: 1, Via: root, Run: run1},
+			{Data: data.Summary{KnitId: knit3}, Hops: 2, Via: knit2, Run: run2},
+		}}.Filter(data.DepsOptions{Depth: 1})
+		if len(got.Downstreams) != 1 || !got.Truncated {
+			t.Errorf("unexpected: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package data
+
+import "github.com/opst/knitfab-api-types/apidiff"
+
+// Diff returns differences from o, field by field.
+//
+// If d.Equal(o), it returns nil even if their JSON differ, for example, in order of Tags.
+func (d Detail) Diff(o Detail) []apidiff.FieldDiff {
+	if d.Equal(o) {
+		return nil
+	}
+	return apidiff.Of(d, o)
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// DownloadRef is the format for response body from Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/link
+//
+// It is a direct link to the content of the Data, typically a presigned URL of an object storage.
+// Clients download the content from URL, instead of GET /api/data/{knitId}.
+type DownloadRef struct {
+	// URL is the location of the content.
+	URL string `json:"url" yaml:"url"`
+
+	// ExpiresAt is the time when URL stops working.
+	ExpiresAt rfctime.RFC3339 `json:"expiresAt" yaml:"expiresAt"`
+
+	// Headers are HTTP headers to be sent with the request to URL.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Checksum is the checksum of the content, to verify the download.
+	//
+	// If nil, it is unknown.
+	Checksum *Checksum `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+}
+
+func (ref DownloadRef) Equal(o DownloadRef) bool {
+	return ref.URL == o.URL &&
+		ref.ExpiresAt.Equal(o.ExpiresAt) &&
+		apicmp.MapEqualWith(ref.Headers, o.Headers, func(a, b string) bool { return a == b }) &&
+		apicmp.PtrEqual(ref.Checksum, o.Checksum)
+}
+
+// Validate checks URL is an absolute http(s) URL and ExpiresAt is set.
+func (ref DownloadRef) Validate() error {
+	u, err := url.Parse(ref.URL)
+	if err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf(`url: should be an absolute http(s) URL: %q`, ref.URL)
+	}
+	if ref.ExpiresAt.Time().IsZero() {
+		return fmt.Errorf(`required field missing: "expiresAt"`)
+	}
+	if ref.Checksum != nil {
+		if err := ref.Checksum.Validate(); err != nil {
+			return fmt.Errorf("checksum: %w", err)
+		}
+	}
+	return nil
+}
+
+// Expired returns true if the link does not work at now.
+func (ref DownloadRef) Expired(now time.Time) bool {
+	return !now.Before(ref.ExpiresAt.Time())
+}
+
+// ExpiresIn returns the time left until the link expires at now.
+//
+// It is not positive if the link has expired.
+func (ref DownloadRef) ExpiresIn(now time.Time) time.Duration {
+	return ref.ExpiresAt.Time().Sub(now)
+}
+
+// UsableFor returns true if the link works at now, and keeps working for the duration d.
+//
+// Use this with the expected time of the transfer, to avoid downloads broken by expiry.
+func (ref DownloadRef) UsableFor(now time.Time, d time.Duration) bool {
+	return d <= ref.ExpiresIn(now)
+}
+
+// NewRequest makes a GET request to URL with Headers.
+//
+// It returns error if the link has expired.
+func (ref DownloadRef) NewRequest(ctx context.Context) (*http.Request, error) {
+	if ref.Expired(time.Now()) {
+		return nil, fmt.Errorf("download link has expired at %s", ref.ExpiresAt)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range ref.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
@@ -0,0 +1,62 @@
+package data_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+func TestDownloadRef(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	ref := data.DownloadRef{
+		URL:       "https://storage.example.com/bucket/knit-1?X-Amz-Signature=abc",
+		ExpiresAt: rfctime.RFC3339(now.Add(15 * time.Minute)),
+		Headers:   map[string]string{"x-amz-server-side-encryption-customer-algorithm": "AES256"},
+	}
+	if err := ref.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, ref)
+
+	if ref.Expired(now) || !ref.Expired(now.Add(15*time.Minute)) {
+		t.Error("unexpected expiry")
+	}
+	if got := ref.ExpiresIn(now); got != 15*time.Minute {
+		t.Errorf("ExpiresIn: %s", got)
+	}
+	if !ref.UsableFor(now, 10*time.Minute) || ref.UsableFor(now, 20*time.Minute) {
+		t.Error("unexpected usability")
+	}
+
+	for name, r := range map[string]data.DownloadRef{
+		"relative url":  {URL: "/bucket/knit-1", ExpiresAt: ref.ExpiresAt},
+		"other scheme":  {URL: "s3://bucket/knit-1", ExpiresAt: ref.ExpiresAt},
+		"no expiration": {URL: ref.URL},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := r.Validate(); err == nil {
+				t.Errorf("invalid ref is accepted: %+v", r)
+			}
+		})
+	}
+
+	t.Run("NewRequest", func(t *testing.T) {
+		live := ref
+		live.ExpiresAt = rfctime.RFC3339(time.Now().Add(time.Hour))
+		req, err := live.NewRequest(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req.URL.String() != ref.URL || req.Header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "AES256" {
+			t.Errorf("unexpected request: %s %v", req.URL, req.Header)
+		}
+
+		if _, err := ref.NewRequest(context.Background()); err == nil {
+			t.Error("request for expired link is made")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// Encryption describes encryption at rest of a Data.
+type Encryption struct {
+	// Enabled is true if the content of the Data is encrypted at rest.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Algorithm is the encryption algorithm, like "AES-256-GCM".
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+
+	// Key is the reference to the key encrypting the Data.
+	//
+	// This is nil if the Data is not encrypted.
+	Key *KeyRef `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+func (e Encryption) Equal(o Encryption) bool {
+	return e.Enabled == o.Enabled && e.Algorithm == o.Algorithm &&
+		apicmp.PtrEqual(e.Key, o.Key)
+}
+
+// KeyRef refers a key managed by a key management service (KMS).
+//
+// KeyRef never carries key material itself.
+type KeyRef struct {
+	// Provider is the name of KMS, like "aws-kms", "gcp-kms" or "vault".
+	Provider string `json:"provider" yaml:"provider"`
+
+	// Id is the id of the key in the KMS, like ARN or resource name.
+	Id string `json:"id" yaml:"id"`
+
+	// Version is the version of the key.
+	//
+	// If empty, the primary version of the key is meant.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+func (k KeyRef) Equal(o KeyRef) bool {
+	return k.Provider == o.Provider && k.Id == o.Id && k.Version == o.Version
+}
+
+func (k KeyRef) String() string {
+	s := k.Provider + ":" + k.Id
+	if k.Version != "" {
+		s += "@" + k.Version
+	}
+	return s
+}
+
+// Validate checks Provider and Id are set.
+func (k KeyRef) Validate() error {
+	if k.Provider == "" {
+		return fmt.Errorf(`required field missing: "provider"`)
+	}
+	if k.Id == "" {
+		return fmt.Errorf(`required field missing: "id"`)
+	}
+	return nil
+}
+
+// Rewrap is the format for request body to Knitfab APIs below:
+//
+// - PUT /api/data/{knitId}/encryption
+//
+// It rotates the key of the Data: the Data is re-encrypted (or its data key is rewrapped) with Key.
+//
+// The response is Detail with updated Encryption.
+type Rewrap struct {
+	// Key is the new key for the Data.
+	Key KeyRef `json:"key" yaml:"key"`
+}
+
+// Validate checks the request is well-formed.
+func (r Rewrap) Validate() error {
+	if err := r.Key.Validate(); err != nil {
+		return fmt.Errorf("key: %w", err)
+	}
+	return nil
+}
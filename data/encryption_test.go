@@ -0,0 +1,81 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+)
+
+func TestKeyRef(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When       data.KeyRef
+		WantString string
+		WantErr    bool
+	}{
+		"without version": {
+			When:       data.KeyRef{Provider: "aws-kms", Id: "alias/knitfab"},
+			WantString: "aws-kms:alias/knitfab",
+		},
+		"with version": {
+			When:       data.KeyRef{Provider: "vault", Id: "transit/knitfab", Version: "3"},
+			WantString: "vault:transit/knitfab@3",
+		},
+		"missing provider": {
+			When:       data.KeyRef{Id: "alias/knitfab"},
+			WantString: ":alias/knitfab",
+			WantErr:    true,
+		},
+		"missing id": {
+			When:       data.KeyRef{Provider: "aws-kms", Version: "3"},
+			WantString: "aws-kms:@3",
+			WantErr:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.When.String(); got != testcase.WantString {
+				t.Errorf("String: got %q, want %q", got, testcase.WantString)
+			}
+
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid key is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid key is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestRewrap_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    data.Rewrap
+		WantErr bool
+	}{
+		"valid": {
+			When: data.Rewrap{Key: data.KeyRef{Provider: "gcp-kms", Id: "projects/p/locations/l/keyRings/r/cryptoKeys/k"}},
+		},
+		"empty key": {
+			When:    data.Rewrap{},
+			WantErr: true,
+		},
+		"missing provider": {
+			When:    data.Rewrap{Key: data.KeyRef{Id: "alias/knitfab"}},
+			WantErr: true,
+		},
+		"missing id": {
+			When:    data.Rewrap{Key: data.KeyRef{Provider: "aws-kms"}},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package data
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Query parameter names for FindQuery.
+//
+// Tags are passed with ParamTag, as LineageOptions.
+const (
+	ParamSelector  = "selector"
+	ParamTransient = "transient"
+	ParamSince     = "since"
+	ParamUntil     = "until"
+)
+
+// TransientFilter filters Data by their transient state ("knit#transient" tag).
+type TransientFilter string
+
+const (
+	// TransientAny does not filter Data by transient state.
+	TransientAny TransientFilter = ""
+
+	// TransientProcessing finds Data being written by Runs.
+	TransientProcessing TransientFilter = TransientFilter(tags.ValueKnitTransientProcessing)
+
+	// TransientFailed finds Data left by failed Runs.
+	TransientFailed TransientFilter = TransientFilter(tags.ValueKnitTransientFailed)
+
+	// TransientExclude finds Data which are not transient.
+	TransientExclude TransientFilter = "exclude"
+)
+
+// Valid returns true if f is one of known TransientFilters.
+func (f TransientFilter) Valid() bool {
+	switch f {
+	case TransientAny, TransientProcessing, TransientFailed, TransientExclude:
+		return true
+	}
+	return false
+}
+
+// FindQuery is the query parameters for Knitfab APIs below:
+//
+// - GET /api/data/[?...]
+//
+// Data matching all of the conditions are found.
+type FindQuery struct {
+	// Tags are tags which the Data should have all of.
+	Tags []tags.Tag
+
+	// Selector selects Data by their tags, in addition to Tags.
+	//
+	// Each Requirement is passed as a ParamSelector parameter.
+	Selector tags.Selector
+
+	// Transient filters Data by their transient state.
+	Transient TransientFilter
+
+	// Since is the lower bound (inclusive) of the timestamp ("knit#timestamp") of the Data.
+	//
+	// If nil, it is unbounded.
+	Since *rfctime.RFC3339
+
+	// Until is the upper bound (exclusive) of the timestamp ("knit#timestamp") of the Data.
+	//
+	// If nil, it is unbounded.
+	Until *rfctime.RFC3339
+}
+
+func (q FindQuery) Equal(o FindQuery) bool {
+	return apicmp.SliceEqualUnordered(q.Tags, o.Tags) &&
+		q.Selector.Equal(o.Selector) &&
+		q.Transient == o.Transient &&
+		apicmp.PtrEqual(q.Since, o.Since) &&
+		apicmp.PtrEqual(q.Until, o.Until)
+}
+
+// Encode returns the query as url.Values.
+func (q FindQuery) Encode() url.Values {
+	v := url.Values{}
+	for _, t := range q.Tags {
+		v.Add(ParamTag, t.String())
+	}
+	for _, r := range q.Selector {
+		v.Add(ParamSelector, r.String())
+	}
+	if q.Transient != TransientAny {
+		v.Set(ParamTransient, string(q.Transient))
+	}
+	if q.Since != nil {
+		v.Set(ParamSince, q.Since.String())
+	}
+	if q.Until != nil {
+		v.Set(ParamUntil, q.Until.String())
+	}
+	return v
+}
+
+// Decode reads the query from url.Values.
+func (q *FindQuery) Decode(v url.Values) error {
+	ret := FindQuery{}
+
+	for _, expr := range v[ParamTag] {
+		t := tags.Tag{}
+		if err := t.Parse(expr); err != nil {
+			return fmt.Errorf(`query parameter "%s": %w`, ParamTag, err)
+		}
+		ret.Tags = append(ret.Tags, t)
+	}
+
+	for _, expr := range v[ParamSelector] {
+		r := tags.Requirement{}
+		if err := r.Parse(expr); err != nil {
+			return fmt.Errorf(`query parameter "%s": %w`, ParamSelector, err)
+		}
+		ret.Selector = append(ret.Selector, r)
+	}
+
+	ret.Transient = TransientFilter(v.Get(ParamTransient))
+	if !ret.Transient.Valid() {
+		return fmt.Errorf(
+			`query parameter "%s" should be one of "%s", "%s" or "%s": %q`,
+			ParamTransient, TransientProcessing, TransientFailed, TransientExclude, ret.Transient,
+		)
+	}
+
+	for _, p := range []struct {
+		name string
+		dest **rfctime.RFC3339
+	}{
+		{name: ParamSince, dest: &ret.Since},
+		{name: ParamUntil, dest: &ret.Until},
+	} {
+		expr := v.Get(p.name)
+		if expr == "" {
+			continue
+		}
+		t, err := rfctime.ParseRFC3339DateTime(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s" should be RFC3339 date-time: %q`, p.name, expr)
+		}
+		*p.dest = &t
+	}
+
+	if ret.Since != nil && ret.Until != nil && ret.Until.Time().Before(ret.Since.Time()) {
+		return fmt.Errorf(`query parameter "%s" should not be before "%s"`, ParamUntil, ParamSince)
+	}
+
+	*q = ret
+	return nil
+}
@@ -0,0 +1,89 @@
+package data_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestFindQuery_roundTrip(t *testing.T) {
+	since, err := rfctime.ParseRFC3339DateTime("2024-01-01T00:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	until, err := rfctime.ParseRFC3339DateTime("2024-02-01T12:34:56.789Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, q := range map[string]data.FindQuery{
+		"empty": {},
+		"tags": {
+			Tags: []tags.Tag{
+				{Key: "type", Value: "dataset"},
+				{Key: "note", Value: "has:colon and space"},
+				{Key: tags.KeyKnitTimestamp, Value: "2024-01-01T00:00:00Z"},
+			},
+		},
+		"selector": {
+			Selector: tags.Selector{
+				{Key: "project", Values: []string{"foo"}},
+				{Key: "type", Values: []string{"log"}, Not: true},
+				{Key: "type", Values: []string{"csv", "json"}},
+				{Key: "owner"},
+			},
+		},
+		"transient processing": {Transient: data.TransientProcessing},
+		"transient failed":     {Transient: data.TransientFailed},
+		"transient exclude":    {Transient: data.TransientExclude},
+		"time range":           {Since: &since, Until: &until},
+		"until only":           {Until: &until},
+		"full": {
+			Tags:      []tags.Tag{{Key: "project", Value: "demo"}},
+			Transient: data.TransientExclude,
+			Since:     &since,
+			Until:     &until,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := q.Encode()
+
+			// should survive encoding as a query string.
+			parsed, err := url.ParseQuery(v.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := data.FindQuery{}
+			if err := got.Decode(parsed); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(q) {
+				t.Errorf("round trip: got %+v, want %+v", got, q)
+			}
+		})
+	}
+}
+
+func TestFindQuery_malformed(t *testing.T) {
+	for name, v := range map[string]url.Values{
+		"tag without colon": {data.ParamTag: {"no-colon"}},
+		"empty selector":    {data.ParamSelector: {"!"}},
+		"unknown transient": {data.ParamTransient: {"maybe"}},
+		"malformed until":   {data.ParamUntil: {"tomorrow"}},
+		"reversed range": {
+			data.ParamSince: {"2024-02-01T00:00:00Z"},
+			data.ParamUntil: {"2024-01-01T00:00:00Z"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := data.FindQuery{}
+			if err := q.Decode(v); err == nil {
+				t.Errorf("malformed query is accepted: %+v", q)
+			}
+		})
+	}
+}
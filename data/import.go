@@ -0,0 +1,81 @@
+package data
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Multipart field names of the request body to Knitfab APIs below:
+//
+// - POST /api/data/
+//
+// The request is "multipart/form-data" with two parts, in this order:
+// FieldMetadata with ImportSpec in JSON, then FieldContent with the content of the Data.
+const (
+	FieldMetadata = "metadata"
+	FieldContent  = "content"
+)
+
+// ImportSpec is the metadata of Data to be pushed with POST /api/data/.
+//
+// See FieldMetadata and FieldContent for the layout of the request.
+type ImportSpec struct {
+	// Tags are the tags to be attached to the new Data.
+	Tags []tags.UserTag `json:"tags" yaml:"tags"`
+
+	// Filename is the name of the content, like "dataset.tar.gz".
+	//
+	// This is informative; Knitfab does not use it as a path.
+	Filename string `json:"filename,omitempty" yaml:"filename,omitempty"`
+
+	// ContentType is the media type of the content, like "application/x-tar".
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+
+	// TotalSize is the size of the content in bytes.
+	//
+	// If 0, it is unknown, and the content is read until its end.
+	TotalSize int64 `json:"totalSize,omitempty" yaml:"totalSize,omitempty"`
+
+	// Checksum is the checksum of the content.
+	//
+	// If given, Knitfab rejects the content which does not match it.
+	Checksum *Checksum `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+}
+
+func (s ImportSpec) Equal(o ImportSpec) bool {
+	return apicmp.SliceEqualUnordered(s.Tags, o.Tags) &&
+		s.Filename == o.Filename &&
+		s.ContentType == o.ContentType &&
+		s.TotalSize == o.TotalSize &&
+		apicmp.PtrEqual(s.Checksum, o.Checksum)
+}
+
+// Validate checks the ImportSpec is well-formed.
+func (s ImportSpec) Validate() error {
+	for i, t := range s.Tags {
+		if tags.Tag(t).IsSystem() {
+			return fmt.Errorf(`tags[%d]: tag key "%s..." is reserved for system tags`, i, tags.SystemTagPrefix)
+		}
+	}
+	if strings.ContainsAny(s.Filename, `/\`) {
+		return fmt.Errorf(`filename: should not contain path separators: %q`, s.Filename)
+	}
+	if s.ContentType != "" {
+		if _, _, err := mime.ParseMediaType(s.ContentType); err != nil {
+			return fmt.Errorf("contentType: %w", err)
+		}
+	}
+	if s.TotalSize < 0 {
+		return fmt.Errorf("totalSize: should not be negative: %d", s.TotalSize)
+	}
+	if s.Checksum != nil {
+		if err := s.Checksum.Validate(); err != nil {
+			return fmt.Errorf("checksum: %w", err)
+		}
+	}
+	return nil
+}
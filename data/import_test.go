@@ -0,0 +1,96 @@
+package data_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestImportSpec(t *testing.T) {
+	payload := `{
+		"tags": ["type:dataset", {"key": "project", "value": "demo"}],
+		"filename": "dataset.tar.gz",
+		"contentType": "application/gzip",
+		"totalSize": 1048576,
+		"checksum": {"algorithm": "sha256", "value": "` + strings.Repeat("ab", 32) + `"}
+	}`
+	got := data.ImportSpec{}
+	if err := json.Unmarshal([]byte(payload), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := data.ImportSpec{
+		Tags: []tags.UserTag{
+			{Key: "project", Value: "demo"},
+			{Key: "type", Value: "dataset"},
+		},
+		Filename:    "dataset.tar.gz",
+		ContentType: "application/gzip",
+		TotalSize:   1048576,
+		Checksum:    &data.Checksum{Algorithm: data.ChecksumSHA256, Value: strings.Repeat("AB", 32)},
+	}
+	if !got.Equal(want) {
+		t.Errorf("unexpected spec: %+v", got)
+	}
+	if err := got.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	for name, spec := range map[string]data.ImportSpec{
+		"system tag":           {Tags: []tags.UserTag{{Key: tags.KeyKnitId, Value: "x"}}},
+		"path in filename":     {Filename: "../dataset.tar.gz"},
+		"malformed type":       {ContentType: "application/"},
+		"negative size":        {TotalSize: -1},
+		"unknown algorithm":    {Checksum: &data.Checksum{Algorithm: "md5", Value: strings.Repeat("ab", 16)}},
+		"short checksum value": {Checksum: &data.Checksum{Algorithm: data.ChecksumSHA256, Value: "abcd"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := spec.Validate(); err == nil {
+				t.Errorf("invalid spec is accepted: %+v", spec)
+			}
+		})
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	value := strings.Repeat("0F", 64)
+	got, err := data.ParseChecksum("sha512:" + value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Algorithm != data.ChecksumSHA512 || got.Value != strings.ToLower(value) {
+		t.Errorf("unexpected checksum: %s", got)
+	}
+
+	for _, expr := range []string{"", "sha256", "sha256:xyz", "crc32:00000000"} {
+		if _, err := data.ParseChecksum(expr); err == nil {
+			t.Errorf("%q: malformed checksum is accepted", expr)
+		}
+	}
+}
+
+func TestChecksum_Verify(t *testing.T) {
+	content := "hello, knitfab\n"
+	c, size, err := data.ComputeChecksum(data.ChecksumSHA256, strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(content)) {
+		t.Errorf("unexpected size: %d", size)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Verify(strings.NewReader(content)); err != nil {
+		t.Error(err)
+	}
+	if err := c.Verify(strings.NewReader("tampered")); err == nil {
+		t.Error("tampered content is verified")
+	}
+	if _, _, err := data.ComputeChecksum("md5", strings.NewReader(content)); err == nil {
+		t.Error("unknown algorithm is accepted")
+	}
+}
@@ -0,0 +1,11 @@
+package data
+
+import "github.com/opst/knitfab-api-types/meta"
+
+// KindDataDetail is the kind of Detail, for meta.TypeMeta.
+const KindDataDetail = "DataDetail"
+
+// AddToScheme registers kinds of this package to s.
+func AddToScheme(s *meta.Scheme) error {
+	return meta.Register[Detail](s, KindDataDetail)
+}
@@ -0,0 +1,60 @@
+package data
+
+import (
+	"encoding/json"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+// DeprecatedSummaryKnitid is the Deprecation of the legacy key "knitid" of Summary.
+var DeprecatedSummaryKnitid = meta.Deprecation{
+	Kind:        meta.DeprecatedField,
+	Target:      "data.Summary.knitid",
+	Replacement: "data.Summary.knitId",
+}
+
+// summaryWire is the wire form of Summary, with the legacy key.
+type summaryWire struct {
+	KnitId       knitid.KnitId `json:"knitId" yaml:"knitId"`
+	LegacyKnitId knitid.KnitId `json:"knitid" yaml:"knitid"`
+	Tags         []tags.Tag    `json:"tags" yaml:"tags"`
+	Project      string        `json:"project,omitempty" yaml:"project,omitempty"`
+}
+
+func (w summaryWire) summary() Summary {
+	s := Summary{KnitId: w.KnitId, Tags: w.Tags, Project: w.Project}
+	if s.KnitId.IsZero() && !w.LegacyKnitId.IsZero() {
+		s.KnitId = w.LegacyKnitId
+		s.deprecated = append(s.deprecated, DeprecatedSummaryKnitid)
+	}
+	return s
+}
+
+// UnmarshalJSON decodes Summary from both "knitId" and the legacy "knitid".
+//
+// If both are present, "knitId" takes precedence.
+// Decoding the legacy key is reported by Summary.Deprecations.
+func (s *Summary) UnmarshalJSON(b []byte) error {
+	w := summaryWire{}
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	*s = w.summary()
+	return nil
+}
+
+// UnmarshalYAML decodes Summary from both "knitId" and the legacy "knitid".
+//
+// If both are present, "knitId" takes precedence.
+// Decoding the legacy key is reported by Summary.Deprecations.
+func (s *Summary) UnmarshalYAML(node *yaml.Node) error {
+	w := summaryWire{}
+	if err := node.Decode(&w); err != nil {
+		return err
+	}
+	*s = w.summary()
+	return nil
+}
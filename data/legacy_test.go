@@ -0,0 +1,72 @@
+package data_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/meta"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSummary_legacyKnitid(t *testing.T) {
+	const id = "0190a1b2-0000-7000-8000-000000000301"
+	const other = "0190a1b2-0000-7000-8000-000000000302"
+
+	for name, tc := range map[string]struct {
+		unmarshal  func([]byte, any) error
+		payload    string
+		deprecated bool
+	}{
+		"json": {
+			unmarshal: json.Unmarshal,
+			payload:   `{"knitId": "` + id + `", "tags": ["type:csv"]}`,
+		},
+		"json legacy": {
+			unmarshal: json.Unmarshal, deprecated: true,
+			payload: `{"knitid": "` + id + `", "tags": ["type:csv"]}`,
+		},
+		"json both": {
+			unmarshal: json.Unmarshal,
+			payload:   `{"knitid": "` + other + `", "knitId": "` + id + `", "tags": ["type:csv"]}`,
+		},
+		"yaml": {
+			unmarshal: yaml.Unmarshal,
+			payload:   "knitId: " + id + "\ntags: [\"type:csv\"]\n",
+		},
+		"yaml legacy": {
+			unmarshal: yaml.Unmarshal, deprecated: true,
+			payload: "knitid: " + id + "\ntags: [\"type:csv\"]\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := data.Summary{}
+			if err := tc.unmarshal([]byte(tc.payload), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.KnitId != id || len(got.Tags) != 1 {
+				t.Errorf("unexpected Summary: %+v", got)
+			}
+
+			want := []meta.Deprecation{}
+			if tc.deprecated {
+				want = append(want, data.DeprecatedSummaryKnitid)
+			}
+			if got := got.Deprecations(); !apicmp.SliceEqual(got, want) {
+				t.Errorf("unexpected deprecations: %+v", got)
+			}
+			if got := got.Clone().Deprecations(); !apicmp.SliceEqual(got, want) {
+				t.Errorf("unexpected deprecations of clone: %+v", got)
+			}
+		})
+	}
+
+	b, err := json.Marshal(data.Summary{KnitId: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"knitId":"` + id + `","tags":null}`; string(b) != want {
+		t.Errorf("json.Marshal: got %s", b)
+	}
+}
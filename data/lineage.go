@@ -0,0 +1,413 @@
+package data
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/internal/render"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Query parameter names for LineageOptions.
+const (
+	ParamDepth       = "depth"
+	ParamCollapse    = "collapse"
+	ParamTag         = "tag"
+	ParamExcludeLogs = "excludeLogs"
+	ParamDirection   = "direction"
+)
+
+// LineageDirection is the direction to trace lineage from the root Data.
+type LineageDirection string
+
+const (
+	// LineageBoth traces both of upstream and downstream.
+	LineageBoth LineageDirection = ""
+
+	// LineageUpstream traces upstream only: Runs which created the Data, their inputs, and so on.
+	LineageUpstream LineageDirection = "upstream"
+
+	// LineageDownstream traces downstream only: Runs which took the Data, their outputs, and so on.
+	LineageDownstream LineageDirection = "downstream"
+)
+
+// LineageOptions is the query parameters for Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/lineage[?...]
+//
+// It prunes and summarizes the lineage graph, so that lineage of long-lived pipelines stays renderable.
+type LineageOptions struct {
+	// MaxDepth is the max number of hops from the root Data.
+	//
+	// Nodes beyond the depth are replaced with "hidden" placeholder nodes.
+	// If 0, the depth is unlimited.
+	MaxDepth int
+
+	// CollapseChains replaces chains of Runs having single input and single output
+	// with "hidden" placeholder nodes.
+	CollapseChains bool
+
+	// Tags filters Data nodes. Data without all of these tags are hidden.
+	//
+	// The root Data is always shown.
+	Tags []tags.Tag
+
+	// ExcludeLogs hides log Data and their edges.
+	ExcludeLogs bool
+
+	// Direction is the direction to trace lineage.
+	Direction LineageDirection
+}
+
+func (o LineageOptions) Equal(oo LineageOptions) bool {
+	return o.MaxDepth == oo.MaxDepth &&
+		o.CollapseChains == oo.CollapseChains &&
+		o.ExcludeLogs == oo.ExcludeLogs &&
+		o.Direction == oo.Direction &&
+		apicmp.SliceEqualUnordered(o.Tags, oo.Tags)
+}
+
+// Encode returns the options as url.Values.
+func (o LineageOptions) Encode() url.Values {
+	v := url.Values{}
+	if o.MaxDepth != 0 {
+		v.Set(ParamDepth, strconv.Itoa(o.MaxDepth))
+	}
+	if o.CollapseChains {
+		v.Set(ParamCollapse, "true")
+	}
+	for _, t := range o.Tags {
+		v.Add(ParamTag, t.String())
+	}
+	if o.ExcludeLogs {
+		v.Set(ParamExcludeLogs, "true")
+	}
+	if o.Direction != LineageBoth {
+		v.Set(ParamDirection, string(o.Direction))
+	}
+	return v
+}
+
+// Decode reads the options from url.Values.
+func (o *LineageOptions) Decode(v url.Values) error {
+	ret := LineageOptions{}
+
+	if d := v.Get(ParamDepth); d != "" {
+		depth, err := strconv.Atoi(d)
+		if err != nil || depth < 0 {
+			return fmt.Errorf(`query parameter "%s" should be a non-negative integer: %q`, ParamDepth, d)
+		}
+		ret.MaxDepth = depth
+	}
+
+	for _, p := range []struct {
+		name string
+		dest *bool
+	}{
+		{name: ParamCollapse, dest: &ret.CollapseChains},
+		{name: ParamExcludeLogs, dest: &ret.ExcludeLogs},
+	} {
+		if b := v.Get(p.name); b != "" {
+			parsed, err := strconv.ParseBool(b)
+			if err != nil {
+				return fmt.Errorf(`query parameter "%s" should be boolean: %q`, p.name, b)
+			}
+			*p.dest = parsed
+		}
+	}
+
+	for _, expr := range v[ParamTag] {
+		t := tags.Tag{}
+		if err := t.Parse(expr); err != nil {
+			return fmt.Errorf(`query parameter "%s": %w`, ParamTag, err)
+		}
+		ret.Tags = append(ret.Tags, t)
+	}
+
+	switch d := LineageDirection(v.Get(ParamDirection)); d {
+	case LineageBoth, LineageUpstream, LineageDownstream:
+		ret.Direction = d
+	default:
+		return fmt.Errorf(
+			`query parameter "%s" should be "%s" or "%s": %q`,
+			ParamDirection, LineageUpstream, LineageDownstream, d,
+		)
+	}
+
+	*o = ret
+	return nil
+}
+
+// LineageNodeKind is the kind of nodes in lineage graphs.
+type LineageNodeKind string
+
+const (
+	// NodeData is a node for a Data.
+	NodeData LineageNodeKind = "data"
+
+	// NodeRun is a node for a Run.
+	NodeRun LineageNodeKind = "run"
+
+	// NodeHidden is a placeholder node for pruned or collapsed nodes.
+	NodeHidden LineageNodeKind = "hidden"
+)
+
+// LineageNode is a node of lineage graphs.
+type LineageNode struct {
+	// Id is the id of the node.
+	//
+	// It is the knitId for Data nodes, the runId for Run nodes,
+	// and an id unique in the graph for hidden nodes.
+	Id string `json:"id"`
+
+	// Kind is the kind of the node.
+	Kind LineageNodeKind `json:"kind"`
+
+	// Data is the Data of the node. It is set only for Data nodes.
+	Data *Summary `json:"data,omitempty"`
+
+	// Run is the Run of the node. It is set only for Run nodes.
+	Run *runs.Summary `json:"run,omitempty"`
+
+	// HiddenCount is the number of nodes replaced with this node.
+	// It is set only for hidden nodes.
+	HiddenCount int `json:"hiddenCount,omitempty"`
+}
+
+func (n LineageNode) Equal(o LineageNode) bool {
+	// Summary.Equal takes a pointer, so apicmp.PtrEqual does not fit Data.
+	dataEq := (n.Data == nil && o.Data == nil) ||
+		(n.Data != nil && o.Data != nil && n.Data.Equal(o.Data))
+	return n.Id == o.Id &&
+		n.Kind == o.Kind &&
+		n.HiddenCount == o.HiddenCount &&
+		dataEq &&
+		apicmp.PtrEqual(n.Run, o.Run)
+}
+
+// LineageEdgeKind is the kind of edges in lineage graphs.
+type LineageEdgeKind string
+
+const (
+	// EdgeInput is an edge from a Data to a Run taking it as input.
+	EdgeInput LineageEdgeKind = "input"
+
+	// EdgeOutput is an edge from a Run to a Data created as its output.
+	EdgeOutput LineageEdgeKind = "output"
+
+	// EdgeLog is an edge from a Run to a Data created as its log.
+	EdgeLog LineageEdgeKind = "log"
+)
+
+// LineageEdge is an edge of lineage graphs.
+//
+// Edges are from Data to Run (input), or from Run to Data (output or log).
+// Edges from or to hidden nodes may have neither Kind, Mountpoint nor Log.
+type LineageEdge struct {
+	// From is the id of the source node.
+	From string `json:"from"`
+
+	// To is the id of the destination node.
+	To string `json:"to"`
+
+	// Kind is the kind of the edge.
+	Kind LineageEdgeKind `json:"kind,omitempty"`
+
+	// Mountpoint is the input or output of the Run connected by this edge.
+	//
+	// This and Log are mutually exclusive.
+	Mountpoint *plans.Mountpoint `json:"mountpoint,omitempty"`
+
+	// Log is the log point of the Run connected by this edge.
+	//
+	// This and Mountpoint are mutually exclusive.
+	Log *plans.LogPoint `json:"log,omitempty"`
+}
+
+func (e LineageEdge) Equal(o LineageEdge) bool {
+	return e.From == o.From && e.To == o.To && e.Kind == o.Kind &&
+		apicmp.PtrEqual(e.Mountpoint, o.Mountpoint) &&
+		apicmp.PtrEqual(e.Log, o.Log)
+}
+
+// SummarizedLineage is the format for response body from Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/lineage[?...] (with LineageOptions)
+type SummarizedLineage struct {
+	// Root is the knitId of the Data whose lineage is requested.
+	Root string `json:"root"`
+
+	// Nodes are the nodes shown in the graph, including hidden placeholders.
+	Nodes []LineageNode `json:"nodes"`
+
+	// Edges are the edges between Nodes.
+	Edges []LineageEdge `json:"edges"`
+
+	// HiddenCount is the total number of hidden nodes.
+	HiddenCount int `json:"hiddenCount"`
+}
+
+func (s SummarizedLineage) Equal(o SummarizedLineage) bool {
+	return s.Root == o.Root &&
+		s.HiddenCount == o.HiddenCount &&
+		apicmp.SliceEqualUnordered(s.Nodes, o.Nodes) &&
+		apicmp.SliceEqualUnordered(s.Edges, o.Edges)
+}
+
+// Lineage is the format for response body from Knitfab APIs below:
+//
+// - GET /api/data/{knitId}/lineage[?...] (with LineageOptions having neither CollapseChains nor Tags)
+//
+// It is the upstream/downstream closure of the root Data, without summarization.
+// Traversal is still limited by LineageOptions.MaxDepth and LineageOptions.Direction.
+type Lineage struct {
+	// Root is the knitId of the Data whose lineage is requested.
+	Root string `json:"root"`
+
+	// Nodes are Data and Runs in the closure.
+	Nodes []LineageNode `json:"nodes"`
+
+	// Edges are the edges between Nodes.
+	Edges []LineageEdge `json:"edges"`
+
+	// Truncated is true if the traversal has been stopped by the depth limit,
+	// so there can be more nodes beyond Nodes.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func (l Lineage) Equal(o Lineage) bool {
+	return l.Root == o.Root &&
+		l.Truncated == o.Truncated &&
+		apicmp.SliceEqualUnordered(l.Nodes, o.Nodes) &&
+		apicmp.SliceEqualUnordered(l.Edges, o.Edges)
+}
+
+// Node returns the node with id.
+func (l Lineage) Node(id string) (LineageNode, bool) {
+	for _, n := range l.Nodes {
+		if n.Id == id {
+			return n, true
+		}
+	}
+	return LineageNode{}, false
+}
+
+// Upstreams returns edges coming into the node with id.
+func (l Lineage) Upstreams(id string) []LineageEdge {
+	ret := []LineageEdge{}
+	for _, e := range l.Edges {
+		if e.To == id {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Downstreams returns edges going out of the node with id.
+func (l Lineage) Downstreams(id string) []LineageEdge {
+	ret := []LineageEdge{}
+	for _, e := range l.Edges {
+		if e.From == id {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Depth returns the number of hops from the root to each node, ignoring direction of edges.
+//
+// Nodes not connected to the root are not included.
+func (l Lineage) Depth() map[string]int {
+	depth := map[string]int{l.Root: 0}
+	queue := []string{l.Root}
+	for len(queue) != 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, e := range l.Edges {
+			next := ""
+			switch id {
+			case e.From:
+				next = e.To
+			case e.To:
+				next = e.From
+			default:
+				continue
+			}
+			if _, seen := depth[next]; seen {
+				continue
+			}
+			depth[next] = depth[id] + 1
+			queue = append(queue, next)
+		}
+	}
+	return depth
+}
+
+// WriteDOT writes the lineage in DOT language of Graphviz.
+//
+// Nodes and edges are written in sorted order.
+func (l Lineage) WriteDOT(w io.Writer) error {
+	return render.DOT(w, renderLineage(l.Nodes, l.Edges))
+}
+
+// WriteMermaid writes the lineage as a Mermaid flowchart.
+//
+// Nodes and edges are written in sorted order.
+func (l Lineage) WriteMermaid(w io.Writer) error {
+	return render.Mermaid(w, renderLineage(l.Nodes, l.Edges))
+}
+
+// WriteDOT writes the lineage in DOT language of Graphviz.
+//
+// Nodes and edges are written in sorted order.
+func (s SummarizedLineage) WriteDOT(w io.Writer) error {
+	return render.DOT(w, renderLineage(s.Nodes, s.Edges))
+}
+
+// WriteMermaid writes the lineage as a Mermaid flowchart.
+//
+// Nodes and edges are written in sorted order.
+func (s SummarizedLineage) WriteMermaid(w io.Writer) error {
+	return render.Mermaid(w, renderLineage(s.Nodes, s.Edges))
+}
+
+func renderLineage(nodes []LineageNode, edges []LineageEdge) render.Graph {
+	r := render.Graph{}
+	for _, n := range nodes {
+		rn := render.Node{Id: n.Id, Label: n.Id}
+		switch n.Kind {
+		case NodeData:
+			rn.Shape = render.Cylinder
+		case NodeRun:
+			rn.Shape = render.Round
+			if n.Run != nil {
+				p := n.Run.Plan
+				if p.Image != nil {
+					rn.Label += "\n" + p.Image.String()
+				} else if p.Name != "" {
+					rn.Label += "\n" + p.Name
+				}
+			}
+		case NodeHidden:
+			rn.Shape = render.Dashed
+			rn.Label = fmt.Sprintf("(%d hidden)", n.HiddenCount)
+		}
+		r.Nodes = append(r.Nodes, rn)
+	}
+	for _, e := range edges {
+		re := render.Edge{From: e.From, To: e.To}
+		switch {
+		case e.Mountpoint != nil:
+			re.Label = e.Mountpoint.Path
+		case e.Log != nil:
+			re.Label = "(log)"
+		}
+		r.Edges = append(r.Edges, re)
+	}
+	return r
+}
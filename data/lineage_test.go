@@ -0,0 +1,172 @@
+package data_test
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestLineageOptions_roundTrip(t *testing.T) {
+	for name, o := range map[string]data.LineageOptions{
+		"empty": {},
+		"full": {
+			MaxDepth:       3,
+			CollapseChains: true,
+			Tags:           []tags.Tag{{Key: "type", Value: "model"}},
+			ExcludeLogs:    true,
+			Direction:      data.LineageUpstream,
+		},
+		"downstream": {Direction: data.LineageDownstream},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := data.LineageOptions{}
+			if err := got.Decode(o.Encode()); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(o) {
+				t.Errorf("round trip: got %+v, want %+v", got, o)
+			}
+		})
+	}
+
+	o := data.LineageOptions{}
+	if err := o.Decode(url.Values{data.ParamDirection: {"sideways"}}); err == nil {
+		t.Errorf("unknown direction is accepted: %+v", o)
+	}
+}
+
+func TestLineage(t *testing.T) {
+	in := plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "raw"}}}
+	out := plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "cleaned"}}}
+	log := plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}}
+
+	// raw -> run-1 -> cleaned, log
+	l := data.Lineage{
+		Root: "raw",
+		Nodes: []data.LineageNode{
+			{Id: "raw", Kind: data.NodeData, Data: &data.Summary{KnitId: "raw"}},
+			{Id: "run-1", Kind: data.NodeRun},
+			{Id: "cleaned", Kind: data.NodeData, Data: &data.Summary{KnitId: "cleaned"}},
+			{Id: "log", Kind: data.NodeData, Data: &data.Summary{KnitId: "log"}},
+			{Id: "orphan", Kind: data.NodeData, Data: &data.Summary{KnitId: "orphan"}},
+		},
+		Edges: []data.LineageEdge{
+			{From: "raw", To: "run-1", Kind: data.EdgeInput, Mountpoint: &in},
+			{From: "run-1", To: "cleaned", Kind: data.EdgeOutput, Mountpoint: &out},
+			{From: "run-1", To: "log", Kind: data.EdgeLog, Log: &log},
+		},
+	}
+
+	if n, ok := l.Node("run-1"); !ok || n.Kind != data.NodeRun {
+		t.Errorf("unexpected node: %+v", n)
+	}
+	if ups := l.Upstreams("cleaned"); len(ups) != 1 || ups[0].Kind != data.EdgeOutput {
+		t.Errorf("unexpected upstreams: %+v", ups)
+	}
+	if downs := l.Downstreams("run-1"); len(downs) != 2 {
+		t.Errorf("unexpected downstreams: %+v", downs)
+	}
+
+	want := map[string]int{"raw": 0, "run-1": 1, "cleaned": 2, "log": 2}
+	if got := l.Depth(); !maps.Equal(got, want) {
+		t.Errorf("depth: got %v, want %v", got, want)
+	}
+}
+
+func TestSummarizedLineage(t *testing.T) {
+	in := plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "raw"}}}
+	out := plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "cleaned"}}}
+
+	const (
+		raw     = "0190a1b2-0000-7000-8000-000000000301"
+		cleaned = "0190a1b2-0000-7000-8000-000000000302"
+		other   = "0190a1b2-0000-7000-8000-000000000303"
+		run     = "0190a1b2-0000-7000-8000-000000000201"
+	)
+
+	// raw -> (2 hidden) -> run -> cleaned
+	base := func() data.SummarizedLineage {
+		return data.SummarizedLineage{
+			Root: cleaned,
+			Nodes: []data.LineageNode{
+				{Id: raw, Kind: data.NodeData, Data: &data.Summary{KnitId: raw, Tags: []tags.Tag{}}},
+				{Id: "hidden-1", Kind: data.NodeHidden, HiddenCount: 2},
+				{Id: run, Kind: data.NodeRun},
+				{Id: cleaned, Kind: data.NodeData, Data: &data.Summary{KnitId: cleaned, Tags: []tags.Tag{}}},
+			},
+			Edges: []data.LineageEdge{
+				{From: raw, To: "hidden-1"},
+				{From: "hidden-1", To: run, Kind: data.EdgeInput, Mountpoint: &in},
+				{From: run, To: cleaned, Kind: data.EdgeOutput, Mountpoint: &out},
+			},
+			HiddenCount: 2,
+		}
+	}
+
+	t.Run("marshalling", func(t *testing.T) {
+		knittest.AssertJSONRoundTrip(t, base())
+
+		b, err := json.Marshal(data.SummarizedLineage{
+			Root:        cleaned,
+			Nodes:       []data.LineageNode{{Id: "hidden-1", Kind: data.NodeHidden, HiddenCount: 2}},
+			Edges:       []data.LineageEdge{{From: "hidden-1", To: cleaned}},
+			HiddenCount: 2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"root":"` + cleaned + `","nodes":[{"id":"hidden-1","kind":"hidden","hiddenCount":2}],"edges":[{"from":"hidden-1","to":"` + cleaned + `"}],"hiddenCount":2}`
+		if string(b) != want {
+			t.Errorf("got %s, want %s", b, want)
+		}
+	})
+
+	for name, testcase := range map[string]struct {
+		When func(*data.SummarizedLineage)
+		Want bool
+	}{
+		"same": {When: func(*data.SummarizedLineage) {}, Want: true},
+		"reordered": {
+			When: func(s *data.SummarizedLineage) {
+				slices.Reverse(s.Nodes)
+				slices.Reverse(s.Edges)
+			},
+			Want: true,
+		},
+		"root differs":         {When: func(s *data.SummarizedLineage) { s.Root = raw }},
+		"hidden count differs": {When: func(s *data.SummarizedLineage) { s.HiddenCount = 3 }},
+		"node hidden count differs": {
+			When: func(s *data.SummarizedLineage) { s.Nodes[1].HiddenCount = 3 },
+		},
+		"node data differs": {
+			When: func(s *data.SummarizedLineage) { s.Nodes[0].Data = &data.Summary{KnitId: other} },
+		},
+		"node missing": {
+			When: func(s *data.SummarizedLineage) { s.Nodes = s.Nodes[1:] },
+		},
+		"edge mountpoint differs": {
+			When: func(s *data.SummarizedLineage) { s.Edges[1].Mountpoint = &out },
+		},
+		"edge kind differs": {
+			When: func(s *data.SummarizedLineage) { s.Edges[0].Kind = data.EdgeInput },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			a, b := base(), base()
+			testcase.When(&b)
+			if got := a.Equal(b); got != testcase.Want {
+				t.Errorf("a.Equal(b): got %v, want %v", got, testcase.Want)
+			}
+			if got := b.Equal(a); got != testcase.Want {
+				t.Errorf("b.Equal(a): got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+// BlockReason is the reason why a nominated Plan has not started a Run with the Data.
+type BlockReason string
+
+const (
+	// BlockPlanInactive means that the Plan is deactivated.
+	BlockPlanInactive BlockReason = "plan-inactive"
+
+	// BlockMissingInputs means that other inputs of the Plan have no Data to be assigned.
+	BlockMissingInputs BlockReason = "missing-inputs"
+
+	// BlockResourceQuota means that Runs cannot be started due to resource quota.
+	BlockResourceQuota BlockReason = "resource-quota"
+)
+
+func (r BlockReason) String() string {
+	return string(r)
+}
+
+// Valid returns true if r is one of known BlockReasons.
+func (r BlockReason) Valid() bool {
+	switch r {
+	case BlockPlanInactive, BlockMissingInputs, BlockResourceQuota:
+		return true
+	}
+	return false
+}
+
+// Blocked tells why a nominated Plan has not started a Run with the Data.
+type Blocked struct {
+	// Reason is the reason of the blockage.
+	//
+	// Clients should be tolerant of unknown reasons, since new reasons can be added.
+	Reason BlockReason `json:"reason" yaml:"reason"`
+
+	// Message is a human readable detail of the blockage.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// MissingInputs are inputs of the Plan without Data to be assigned.
+	//
+	// This is set only when Reason is BlockMissingInputs.
+	MissingInputs []plans.Mountpoint `json:"missingInputs,omitempty" yaml:"missingInputs,omitempty"`
+}
+
+func (b Blocked) Equal(o Blocked) bool {
+	return b.Reason == o.Reason &&
+		b.Message == o.Message &&
+		apicmp.SliceEqualUnordered(b.MissingInputs, o.MissingInputs)
+}
+
+// Validate checks the Reason is given, and MissingInputs is set only for BlockMissingInputs.
+func (b Blocked) Validate() error {
+	if b.Reason == "" {
+		return fmt.Errorf(`required field missing: "reason"`)
+	}
+	if b.Reason != BlockMissingInputs && len(b.MissingInputs) != 0 {
+		return fmt.Errorf(`"missingInputs" should be empty for reason %q`, b.Reason)
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the Blocked.
+func (b Blocked) Clone() Blocked {
+	b.MissingInputs = clone.SliceWith(b.MissingInputs, plans.Mountpoint.Clone)
+	return b
+}
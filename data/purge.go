@@ -0,0 +1,137 @@
+package data
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+// PurgeRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/data/purge
+//
+// It removes contents of Data to free the storage.
+// Purged Data are kept as records, so lineage are not broken, but Runs taking them cannot be retried.
+type PurgeRequest struct {
+	// KnitIds are ids of Data to be purged.
+	KnitIds []knitid.KnitId `json:"knitIds" yaml:"knitIds"`
+
+	// DryRun reports what would be purged, without purging.
+	DryRun bool `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+
+	// Archive is the URL of the location where contents are archived before purged,
+	// like "s3://bucket/prefix". Archived Data can be restored with RestoreRequest.
+	//
+	// If empty, contents are discarded.
+	Archive string `json:"archive,omitempty" yaml:"archive,omitempty"`
+}
+
+func (p PurgeRequest) Equal(o PurgeRequest) bool {
+	return p.DryRun == o.DryRun &&
+		p.Archive == o.Archive &&
+		apicmp.SliceEqEqUnordered(p.KnitIds, o.KnitIds)
+}
+
+// Validate checks that KnitIds are given, well-formed and not duplicated,
+// and Archive is an absolute URL if specified.
+func (p PurgeRequest) Validate() error {
+	if err := validateKnitIds(p.KnitIds); err != nil {
+		return err
+	}
+	if p.Archive != "" {
+		if err := validateLocation(p.Archive); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+	}
+	return nil
+}
+
+// PurgeResult is the format for response body from Knitfab APIs below:
+//
+// - POST /api/data/purge
+type PurgeResult struct {
+	// Purged are ids of Data purged (or to be purged, if DryRun).
+	Purged []knitid.KnitId `json:"purged" yaml:"purged"`
+
+	// FreedBytes is the total size of purged contents in bytes.
+	FreedBytes int64 `json:"freedBytes" yaml:"freedBytes"`
+
+	// AffectedRuns are Runs which have taken the purged Data as their inputs.
+	// They cannot be retried until the Data are restored.
+	AffectedRuns []runs.RunId `json:"affectedRuns" yaml:"affectedRuns"`
+
+	// DryRun is true if nothing has been purged actually.
+	DryRun bool `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+}
+
+func (p PurgeResult) Equal(o PurgeResult) bool {
+	return p.FreedBytes == o.FreedBytes &&
+		p.DryRun == o.DryRun &&
+		apicmp.SliceEqEqUnordered(p.Purged, o.Purged) &&
+		apicmp.SliceEqEqUnordered(p.AffectedRuns, o.AffectedRuns)
+}
+
+// RestoreRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/data/restore
+//
+// It restores contents of purged Data from the archive.
+type RestoreRequest struct {
+	// KnitIds are ids of Data to be restored.
+	KnitIds []knitid.KnitId `json:"knitIds" yaml:"knitIds"`
+
+	// Archive is the URL of the location where contents have been archived.
+	//
+	// If empty, the location specified on purge is used.
+	Archive string `json:"archive,omitempty" yaml:"archive,omitempty"`
+}
+
+func (r RestoreRequest) Equal(o RestoreRequest) bool {
+	return r.Archive == o.Archive &&
+		apicmp.SliceEqEqUnordered(r.KnitIds, o.KnitIds)
+}
+
+// Validate checks that KnitIds are given, well-formed and not duplicated,
+// and Archive is an absolute URL if specified.
+func (r RestoreRequest) Validate() error {
+	if err := validateKnitIds(r.KnitIds); err != nil {
+		return err
+	}
+	if r.Archive != "" {
+		if err := validateLocation(r.Archive); err != nil {
+			return fmt.Errorf("archive: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateKnitIds(ids []knitid.KnitId) error {
+	if len(ids) == 0 {
+		return fmt.Errorf(`"knitIds" should have at least one knitId`)
+	}
+	seen := map[knitid.KnitId]bool{}
+	for i, id := range ids {
+		if _, err := knitid.ParseKnitId(string(id)); err != nil {
+			return fmt.Errorf("knitIds[%d]: %w", i, err)
+		}
+		if seen[id] {
+			return fmt.Errorf(`knitIds[%d]: duplicated: "%s"`, i, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func validateLocation(loc string) error {
+	u, err := url.Parse(loc)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return fmt.Errorf("should be an absolute URL: %q", loc)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestPurge(t *testing.T) {
+	const (
+		knit1 knitid.KnitId = "0190a1b2-0000-7000-8000-000000000301"
+		knit2 knitid.KnitId = "0190a1b2-0000-7000-8000-000000000302"
+	)
+
+	req := data.PurgeRequest{KnitIds: []knitid.KnitId{knit1, knit2}, DryRun: true, Archive: "s3://archive/knitfab"}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, req)
+
+	res := data.PurgeResult{
+		Purged:       []knitid.KnitId{knit1, knit2},
+		FreedBytes:   1 << 30,
+		AffectedRuns: []runs.RunId{"0190a1b2-0000-7000-8000-000000000201"},
+		DryRun:       true,
+	}
+	knittest.AssertRoundTrip(t, res)
+
+	restore := data.RestoreRequest{KnitIds: []knitid.KnitId{knit1}}
+	if err := restore.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, restore)
+
+	for name, invalid := range map[string]interface{ Validate() error }{
+		"no knitIds":         data.PurgeRequest{},
+		"malformed knitId":   data.PurgeRequest{KnitIds: []knitid.KnitId{"knit-1"}},
+		"duplicated knitIds": data.PurgeRequest{KnitIds: []knitid.KnitId{knit1, knit1}},
+		"relative archive":   data.PurgeRequest{KnitIds: []knitid.KnitId{knit1}, Archive: "archive/knitfab"},
+		"restore nothing":    data.RestoreRequest{},
+		"restore relative":   data.RestoreRequest{KnitIds: []knitid.KnitId{knit1}, Archive: "archive"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid request is accepted: %+v", invalid)
+			}
+		})
+	}
+}
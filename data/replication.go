@@ -0,0 +1,107 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// ReplicationState is the state of a replica of Data.
+type ReplicationState string
+
+const (
+	// ReplicationPending means that replication is requested but not started.
+	ReplicationPending ReplicationState = "pending"
+
+	// ReplicationSyncing means that the content is being transferred.
+	ReplicationSyncing ReplicationState = "syncing"
+
+	// ReplicationInSync means that the replica has the same content as the Data.
+	ReplicationInSync ReplicationState = "in-sync"
+
+	// ReplicationFailed means that the last replication was failed.
+	ReplicationFailed ReplicationState = "failed"
+)
+
+// ReplicationTarget is where Data is replicated to.
+//
+// Cluster and Location are mutually exclusive.
+type ReplicationTarget struct {
+	// Cluster is the alias of a remote Knitfab cluster (see package federation).
+	Cluster string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+
+	// Location is the URL of a storage location, like "s3://bucket/prefix".
+	Location string `json:"location,omitempty" yaml:"location,omitempty"`
+}
+
+func (t ReplicationTarget) Equal(o ReplicationTarget) bool {
+	return t.Cluster == o.Cluster && t.Location == o.Location
+}
+
+func (t ReplicationTarget) String() string {
+	if t.Cluster != "" {
+		return "cluster:" + t.Cluster
+	}
+	return t.Location
+}
+
+// Validate checks that exactly one of Cluster or Location is set.
+func (t ReplicationTarget) Validate() error {
+	switch {
+	case t.Cluster == "" && t.Location == "":
+		return fmt.Errorf(`one of "cluster" or "location" is required`)
+	case t.Cluster != "" && t.Location != "":
+		return fmt.Errorf(`"cluster" and "location" are mutually exclusive`)
+	}
+	return nil
+}
+
+// ReplicationRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/data/{knitId}/replicas
+//
+// The response is Replication for the Target.
+type ReplicationRequest struct {
+	// Target is where the Data is replicated to.
+	Target ReplicationTarget `json:"target" yaml:"target"`
+}
+
+// Validate checks the request is well-formed.
+func (r ReplicationRequest) Validate() error {
+	return r.Target.Validate()
+}
+
+// Replication is the format for response body from Knitfab APIs below:
+//
+// - GET  /api/data/{knitId}/replicas (as list)
+//
+// - POST /api/data/{knitId}/replicas
+//
+// It describes a replica of Data.
+type Replication struct {
+	// Target is where the Data is replicated to.
+	Target ReplicationTarget `json:"target" yaml:"target"`
+
+	// State is the state of the replica.
+	State ReplicationState `json:"state" yaml:"state"`
+
+	// LastSyncedAt is the time when the replica was in sync at last.
+	//
+	// If nil, it has never been in sync.
+	LastSyncedAt *rfctime.RFC3339 `json:"lastSyncedAt,omitempty" yaml:"lastSyncedAt,omitempty"`
+
+	// BytesTransferred is the size transferred in the current or last replication, in bytes.
+	BytesTransferred int64 `json:"bytesTransferred" yaml:"bytesTransferred"`
+
+	// Message is the human readable description of the state, typically the reason of failure.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (r Replication) Equal(o Replication) bool {
+	return r.Target.Equal(o.Target) &&
+		r.State == o.State &&
+		apicmp.PtrEqual(r.LastSyncedAt, o.LastSyncedAt) &&
+		r.BytesTransferred == o.BytesTransferred &&
+		r.Message == o.Message
+}
@@ -0,0 +1,86 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+)
+
+func TestReplicationTarget(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When       data.ReplicationTarget
+		WantString string
+		WantErr    bool
+	}{
+		"cluster": {
+			When:       data.ReplicationTarget{Cluster: "osaka"},
+			WantString: "cluster:osaka",
+		},
+		"location": {
+			When:       data.ReplicationTarget{Location: "s3://replica/knitfab"},
+			WantString: "s3://replica/knitfab",
+		},
+		"empty": {
+			When:       data.ReplicationTarget{},
+			WantString: "",
+			WantErr:    true,
+		},
+		"cluster and location": {
+			When:       data.ReplicationTarget{Cluster: "osaka", Location: "s3://replica/knitfab"},
+			WantString: "cluster:osaka",
+			WantErr:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.When.String(); got != testcase.WantString {
+				t.Errorf("String: got %q, want %q", got, testcase.WantString)
+			}
+
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid target is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid target is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestReplicationRequest_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    data.ReplicationRequest
+		WantErr bool
+	}{
+		"to cluster": {
+			When: data.ReplicationRequest{Target: data.ReplicationTarget{Cluster: "osaka"}},
+		},
+		"to location": {
+			When: data.ReplicationRequest{Target: data.ReplicationTarget{Location: "s3://replica/knitfab"}},
+		},
+		"without target": {
+			When:    data.ReplicationRequest{},
+			WantErr: true,
+		},
+		"empty cluster": {
+			When:    data.ReplicationRequest{Target: data.ReplicationTarget{Cluster: ""}},
+			WantErr: true,
+		},
+		"both of cluster and location": {
+			When: data.ReplicationRequest{
+				Target: data.ReplicationTarget{Cluster: "osaka", Location: "s3://replica/knitfab"},
+			},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// RetentionPolicy is the format for request and response body of Knitfab APIs below:
+//
+// - GET /api/data/retention
+//
+// - PUT /api/data/retention
+//
+// It describes when Data become candidates to be purged.
+// A Data is kept while it is pinned, or is one of the latest KeepLast Data.
+// Otherwise, it expires at the time of its "knit#expires-at" tag if any,
+// or TTL after its "knit#timestamp".
+type RetentionPolicy struct {
+	// TTL is the duration to keep Data since they are created.
+	//
+	// If nil, Data without "knit#expires-at" never expire.
+	TTL *duration.Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+
+	// KeepLast is the number of the latest Data kept regardless of their expiry,
+	// for each output of Plans.
+	//
+	// If 0, no Data are kept by this.
+	KeepLast int `json:"keepLast,omitempty" yaml:"keepLast,omitempty"`
+
+	// PinnedTags are tags marking Data never to expire.
+	//
+	// Data having any of these tags are pinned.
+	PinnedTags []tags.Tag `json:"pinnedTags,omitempty" yaml:"pinnedTags,omitempty"`
+}
+
+func (p RetentionPolicy) Equal(o RetentionPolicy) bool {
+	return apicmp.PtrEqual(p.TTL, o.TTL) &&
+		p.KeepLast == o.KeepLast &&
+		apicmp.SliceEqualUnordered(p.PinnedTags, o.PinnedTags)
+}
+
+// Validate checks TTL is positive, KeepLast is not negative and PinnedTags are not system tags.
+func (p RetentionPolicy) Validate() error {
+	if p.TTL != nil && *p.TTL <= 0 {
+		return fmt.Errorf("ttl: should be positive: %s", p.TTL)
+	}
+	if p.KeepLast < 0 {
+		return fmt.Errorf("keepLast: should not be negative: %d", p.KeepLast)
+	}
+	for i, t := range p.PinnedTags {
+		if t.IsSystem() {
+			return fmt.Errorf(`pinnedTags[%d]: system tag is not allowed: "%s"`, i, t)
+		}
+	}
+	return nil
+}
+
+// Pinned returns true if ts has any of PinnedTags.
+func (p RetentionPolicy) Pinned(ts []tags.Tag) bool {
+	set := tags.NewSet(ts...)
+	for _, t := range p.PinnedTags {
+		if set.Has(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpiresAt returns when Data with ts expires by the policy.
+//
+// The "knit#expires-at" tag takes precedence over TTL.
+// It returns false if the Data is pinned, or never expires.
+// KeepLast is not considered, since it depends on other Data.
+func (p RetentionPolicy) ExpiresAt(ts []tags.Tag) (rfctime.RFC3339, bool) {
+	if p.Pinned(ts) {
+		return rfctime.RFC3339{}, false
+	}
+	if at, ok := tags.FindExpiresAt(ts); ok {
+		return at, true
+	}
+	if p.TTL == nil {
+		return rfctime.RFC3339{}, false
+	}
+	created, ok := tags.FindTimestamp(ts)
+	if !ok {
+		return rfctime.RFC3339{}, false
+	}
+	return rfctime.RFC3339(created.Time().Add(p.TTL.Duration())), true
+}
+
+// Expired returns true if Data with ts has expired at now by the policy.
+//
+// KeepLast is not considered, as ExpiresAt.
+func (p RetentionPolicy) Expired(ts []tags.Tag, now time.Time) bool {
+	at, ok := p.ExpiresAt(ts)
+	return ok && !now.Before(at.Time())
+}
+
+// ExpiresAtTag returns the "knit#expires-at" tag for Data with ts, by the policy.
+//
+// It returns false if the Data never expires.
+func (p RetentionPolicy) ExpiresAtTag(ts []tags.Tag) (tags.Tag, bool) {
+	at, ok := p.ExpiresAt(ts)
+	if !ok {
+		return tags.Tag{}, false
+	}
+	return tags.ExpiresAt(at.Time()), true
+}
@@ -0,0 +1,63 @@
+package data_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	ttl := duration.Duration(24 * time.Hour)
+	p := data.RetentionPolicy{
+		TTL:        &ttl,
+		KeepLast:   3,
+		PinnedTags: []tags.Tag{{Key: "keep", Value: "forever"}},
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, p)
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts := []tags.Tag{{Key: "type", Value: "csv"}, tags.Timestamp(created)}
+
+	if at, ok := p.ExpiresAt(ts); !ok || !at.Time().Equal(created.Add(24*time.Hour)) {
+		t.Errorf("by TTL: got (%s, %v)", at, ok)
+	}
+	if p.Expired(ts, created.Add(23*time.Hour)) || !p.Expired(ts, created.Add(24*time.Hour)) {
+		t.Error("unexpected expiry by TTL")
+	}
+
+	explicit := append(ts, tags.ExpiresAt(created.Add(time.Hour)))
+	if at, ok := p.ExpiresAt(explicit); !ok || !at.Time().Equal(created.Add(time.Hour)) {
+		t.Errorf("by tag: got (%s, %v)", at, ok)
+	}
+	if tag, ok := p.ExpiresAtTag(ts); !ok || !tag.Equal(tags.ExpiresAt(created.Add(24*time.Hour))) {
+		t.Errorf("ExpiresAtTag: got (%s, %v)", tag, ok)
+	}
+
+	pinned := append(explicit, tags.Tag{Key: "keep", Value: "forever"})
+	if _, ok := p.ExpiresAt(pinned); ok || p.Expired(pinned, created.Add(48*time.Hour)) {
+		t.Error("pinned Data expires")
+	}
+	if _, ok := (data.RetentionPolicy{}).ExpiresAt(ts); ok {
+		t.Error("Data expires without TTL")
+	}
+
+	negative := duration.Duration(-time.Hour)
+	for name, invalid := range map[string]data.RetentionPolicy{
+		"negative ttl":      {TTL: &negative},
+		"negative keepLast": {KeepLast: -1},
+		"system tag":        {PinnedTags: []tags.Tag{tags.Transient(tags.TransientFailed)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid policy is accepted: %+v", invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package data_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/federation"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetail_roundTrip(t *testing.T) {
+	updatedAt, err := rfctime.ParseRFC3339DateTime("2024-01-02T03:04:05+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := runs.Summary{
+		RunId:     "0190a1b2-0000-7000-8000-000000000201",
+		Status:    runs.Done,
+		UpdatedAt: updatedAt,
+		Plan:      plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101", Name: "knit#uploaded"},
+	}
+	out := plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}}
+
+	detail := data.Detail{
+		KnitId: "0190a1b2-0000-7000-8000-000000000301",
+		Tags: []tags.Tag{
+			{Key: "type", Value: "model"},
+			{Key: tags.KeyKnitId, Value: "0190a1b2-0000-7000-8000-000000000301"},
+			{Key: tags.KeyKnitTimestamp, Value: "2024-01-02T03:04:05+09:00"},
+		},
+		Upstream: data.CreatedFrom{
+			Mountpoint: &out,
+			Run:        run,
+			Origin:     &federation.Ref{Cluster: "remote", KnitId: "knit-remote"},
+		},
+		Downstreams: []data.AssignedTo{
+			{Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "model"}}}, Run: run},
+		},
+		Nomination: []data.NominatedBy{
+			{
+				Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+				Plan:       plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000102", Image: &plans.Image{Repository: "example.com/eval", Tag: "v1"}},
+				Blocked:    &data.Blocked{Reason: data.BlockPlanInactive, Message: "plan is deactivated"},
+			},
+		},
+		Encryption: &data.Encryption{
+			Enabled:   true,
+			Algorithm: "AES-256-GCM",
+			Key:       &data.KeyRef{Provider: "vault", Id: "key-1", Version: "2"},
+		},
+		Replicas: []data.Replication{
+			{
+				Target:       data.ReplicationTarget{Cluster: "remote"},
+				State:        data.ReplicationInSync,
+				LastSyncedAt: &updatedAt,
+			},
+		},
+		Size:     1048576,
+		Checksum: &data.Checksum{Algorithm: data.ChecksumSHA256, Value: strings.Repeat("ab", 32)},
+		Project:  "example",
+		Extensions: extensions.Extensions{
+			"example.com/lineage": json.RawMessage(`{"source": "s3://bucket/key"}`),
+		},
+	}
+
+	knittest.AssertRoundTrip(t, detail)
+}
+
+func TestDetail_warnings(t *testing.T) {
+	detail := func(ws ...meta.Warning) data.Detail {
+		return data.Detail{
+			KnitId: "0190a1b2-0000-7000-8000-000000000301",
+			Tags:   []tags.Tag{{Key: "type", Value: "model"}},
+			Upstream: data.CreatedFrom{
+				Run: runs.Summary{
+					RunId:  "0190a1b2-0000-7000-8000-000000000201",
+					Status: runs.Done,
+					Plan:   plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101", Name: "knit#uploaded"},
+				},
+			},
+			Downstreams: []data.AssignedTo{},
+			Nomination:  []data.NominatedBy{},
+			Warnings:    ws,
+		}
+	}
+	truncated := meta.Warning{Code: "tag-truncated", Field: "tags[0]", Message: "value is truncated"}
+	dropped := meta.Warning{Field: "tags[1]", Message: "tag is dropped"}
+
+	t.Run("marshalled", func(t *testing.T) {
+		d := detail(truncated, dropped)
+		knittest.AssertRoundTrip(t, d)
+
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), `"warnings":[{"code":"tag-truncated","field":"tags[0]","message":"value is truncated"},{"field":"tags[1]","message":"tag is dropped"}]`) {
+			t.Errorf("warnings are not marshalled: %s", b)
+		}
+	})
+
+	t.Run("omitted when empty", func(t *testing.T) {
+		for name, d := range map[string]data.Detail{"nil": detail(), "empty": detail([]meta.Warning{}...)} {
+			b, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), "warnings") {
+				t.Errorf("%s: warnings in JSON: %s", name, b)
+			}
+			y, err := yaml.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(y), "warnings") {
+				t.Errorf("%s: warnings in YAML: %s", name, y)
+			}
+		}
+	})
+
+	t.Run("compared by Equal", func(t *testing.T) {
+		a, b := detail(truncated, dropped), detail(dropped, truncated)
+		if !a.Equal(b) {
+			t.Error("order of warnings should not matter")
+		}
+		a, b = detail(truncated), detail(dropped)
+		if a.Equal(b) {
+			t.Error("different warnings should not be equal")
+		}
+		a, b = detail(truncated), detail()
+		if a.Equal(b) {
+			t.Error("detail with warnings should not be equal to one without warnings")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package digests
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Section is a section of Digest.
+type Section string
+
+const (
+	// FailedRuns lists Runs failed in the period.
+	FailedRuns Section = "failed_runs"
+
+	// NewData lists Data created in the period.
+	NewData Section = "new_data"
+
+	// StalePlans lists active Plans which have no Runs for a long time.
+	StalePlans Section = "stale_plans"
+)
+
+// Channel is a destination of Digests.
+type Channel struct {
+	// Type is the type of the destination, like "email" or "slack".
+	Type string `json:"type"`
+
+	// Target is the address of the destination: mail address, webhook URL and so on.
+	Target string `json:"target"`
+}
+
+func (c Channel) Equal(o Channel) bool {
+	return c.Type == o.Type && c.Target == o.Target
+}
+
+// Config is the format for request and response body for Knitfab APIs below:
+//
+// - GET    /api/digests/ (as list)
+//
+// - PUT    /api/digests/{name}
+//
+// - GET    /api/digests/{name}
+//
+// - DELETE /api/digests/{name}: empty response ("204 No Content" on success)
+//
+// It configures periodic summary notifications.
+type Config struct {
+	// Name is the name of the configuration.
+	Name string `json:"name"`
+
+	// Schedule is the cron expression when Digests are sent, like "0 9 * * *".
+	//
+	// The syntax is same as plans.Schedule.Cron.
+	Schedule string `json:"schedule"`
+
+	// Timezone is the IANA timezone name for Schedule, like "Asia/Tokyo".
+	//
+	// If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Project scopes resources in Digests to the Project.
+	//
+	// If empty, resources are not scoped.
+	Project string `json:"project,omitempty"`
+
+	// Tags scopes resources in Digests.
+	// Data with all of the tags, and Runs and Plans having such Data as input or output are included.
+	//
+	// If empty, resources are not scoped by tags.
+	Tags []tags.Tag `json:"tags,omitempty"`
+
+	// Sections are the sections included in Digests.
+	Sections []Section `json:"sections"`
+
+	// Channels are the destinations of Digests.
+	Channels []Channel `json:"channels"`
+}
+
+func (c Config) Equal(o Config) bool {
+	return c.Name == o.Name &&
+		c.Schedule == o.Schedule &&
+		c.Timezone == o.Timezone &&
+		c.Project == o.Project &&
+		apicmp.SliceEqualUnordered(c.Tags, o.Tags) &&
+		apicmp.SliceEqEqUnordered(c.Sections, o.Sections) &&
+		apicmp.SliceEqualUnordered(c.Channels, o.Channels)
+}
+
+// Has returns true if the section is included.
+func (c Config) Has(s Section) bool {
+	return slices.Contains(c.Sections, s)
+}
+
+// Validate checks the Config is well-formed.
+func (c Config) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	if err := (plans.Schedule{Cron: c.Schedule, Timezone: c.Timezone}).Validate(); err != nil {
+		return fmt.Errorf("schedule: %w", err)
+	}
+	if err := projects.ValidateName(c.Project); err != nil {
+		return err
+	}
+	if len(c.Sections) == 0 {
+		return fmt.Errorf(`required field missing: "sections"`)
+	}
+	for _, s := range c.Sections {
+		switch s {
+		case FailedRuns, NewData, StalePlans:
+		default:
+			return fmt.Errorf(`unknown section: %q`, s)
+		}
+	}
+	if len(c.Channels) == 0 {
+		return fmt.Errorf(`required field missing: "channels"`)
+	}
+	return nil
+}
+
+// Digest is a periodic summary, sent to Channels of the Config.
+//
+// Sections not included in the Config are nil.
+type Digest struct {
+	// Config is the name of the Config which produces this Digest.
+	Config string `json:"config"`
+
+	// Since is the start of the period summarized (inclusive).
+	Since rfctime.RFC3339 `json:"since"`
+
+	// Until is the end of the period summarized (exclusive).
+	Until rfctime.RFC3339 `json:"until"`
+
+	// FailedRuns are the Runs failed in the period.
+	FailedRuns []runs.Summary `json:"failedRuns,omitempty"`
+
+	// NewData are the Data created in the period.
+	NewData []data.Summary `json:"newData,omitempty"`
+
+	// StalePlans are the active Plans which have no Runs in the period.
+	StalePlans []plans.Summary `json:"stalePlans,omitempty"`
+}
+
+func (d Digest) Equal(o Digest) bool {
+	newDataEq := len(d.NewData) == len(o.NewData)
+	if newDataEq {
+		for i := range d.NewData {
+			if !d.NewData[i].Equal(&o.NewData[i]) {
+				newDataEq = false
+				break
+			}
+		}
+	}
+	return d.Config == o.Config &&
+		d.Since.Equal(o.Since) &&
+		d.Until.Equal(o.Until) &&
+		apicmp.SliceEqual(d.FailedRuns, o.FailedRuns) &&
+		newDataEq &&
+		apicmp.SliceEqual(d.StalePlans, o.StalePlans)
+}
+
+// IsEmpty returns true if the Digest has nothing to be reported.
+func (d Digest) IsEmpty() bool {
+	return len(d.FailedRuns) == 0 && len(d.NewData) == 0 && len(d.StalePlans) == 0
+}
@@ -0,0 +1,117 @@
+package digests_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/digests"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() digests.Config {
+		return digests.Config{
+			Name:     "daily",
+			Schedule: "0 9 * * mon-fri",
+			Timezone: "Asia/Tokyo",
+			Project:  "demo",
+			Tags:     []tags.Tag{{Key: "type", Value: "csv"}},
+			Sections: []digests.Section{digests.FailedRuns, digests.NewData, digests.StalePlans},
+			Channels: []digests.Channel{{Type: "email", Target: "team@example.com"}},
+		}
+	}
+
+	for name, testcase := range map[string]struct {
+		When    func(*digests.Config)
+		WantErr bool
+	}{
+		"valid":                {When: func(*digests.Config) {}},
+		"descriptor":           {When: func(c *digests.Config) { c.Schedule = "@daily" }},
+		"without timezone":     {When: func(c *digests.Config) { c.Timezone = "" }},
+		"missing name":         {When: func(c *digests.Config) { c.Name = "" }, WantErr: true},
+		"missing schedule":     {When: func(c *digests.Config) { c.Schedule = "" }, WantErr: true},
+		"too few fields":       {When: func(c *digests.Config) { c.Schedule = "0 9 * *" }, WantErr: true},
+		"garbage fields":       {When: func(c *digests.Config) { c.Schedule = "a b c d e" }, WantErr: true},
+		"out of range":         {When: func(c *digests.Config) { c.Schedule = "0 25 * * *" }, WantErr: true},
+		"unknown descriptor":   {When: func(c *digests.Config) { c.Schedule = "@fortnightly" }, WantErr: true},
+		"unknown timezone":     {When: func(c *digests.Config) { c.Timezone = "Mars/Olympus" }, WantErr: true},
+		"invalid project":      {When: func(c *digests.Config) { c.Project = "Demo_Project" }, WantErr: true},
+		"missing sections":     {When: func(c *digests.Config) { c.Sections = nil }, WantErr: true},
+		"unknown section":      {When: func(c *digests.Config) { c.Sections = []digests.Section{"new_plans"} }, WantErr: true},
+		"missing channels":     {When: func(c *digests.Config) { c.Channels = nil }, WantErr: true},
+		"empty channels slice": {When: func(c *digests.Config) { c.Channels = []digests.Channel{} }, WantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := valid()
+			testcase.When(&c)
+			err := c.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid config is accepted: %+v", c)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid config is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestDigest_Equal(t *testing.T) {
+	since := rfctime.RFC3339(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	until := rfctime.RFC3339(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	base := func() digests.Digest {
+		return digests.Digest{
+			Config: "daily",
+			Since:  since,
+			Until:  until,
+			FailedRuns: []runs.Summary{
+				{RunId: "0190a1b2-0000-7000-8000-000000000301", Status: runs.Failed, UpdatedAt: since},
+			},
+			NewData: []data.Summary{
+				{KnitId: "0190a1b2-0000-7000-8000-000000000302", Tags: []tags.Tag{{Key: "type", Value: "csv"}}},
+			},
+			StalePlans: []plans.Summary{
+				{PlanId: "0190a1b2-0000-7000-8000-000000000303"},
+			},
+		}
+	}
+
+	for name, testcase := range map[string]struct {
+		When func(*digests.Digest)
+		Want bool
+	}{
+		"same":              {When: func(*digests.Digest) {}, Want: true},
+		"config differs":    {When: func(d *digests.Digest) { d.Config = "weekly" }},
+		"since differs":     {When: func(d *digests.Digest) { d.Since = until }},
+		"until differs":     {When: func(d *digests.Digest) { d.Until = since }},
+		"failed runs empty": {When: func(d *digests.Digest) { d.FailedRuns = nil }},
+		"failed run differs": {When: func(d *digests.Digest) {
+			d.FailedRuns[0].Status = runs.Done
+		}},
+		"new data empty": {When: func(d *digests.Digest) { d.NewData = nil }},
+		"new data differs": {When: func(d *digests.Digest) {
+			d.NewData[0].KnitId = "0190a1b2-0000-7000-8000-000000000304"
+		}},
+		"new data added": {When: func(d *digests.Digest) {
+			d.NewData = append(d.NewData, data.Summary{KnitId: "0190a1b2-0000-7000-8000-000000000305"})
+		}},
+		"stale plans empty": {When: func(d *digests.Digest) { d.StalePlans = nil }},
+		"stale plan differs": {When: func(d *digests.Digest) {
+			d.StalePlans[0].PlanId = "0190a1b2-0000-7000-8000-000000000306"
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			a, b := base(), base()
+			testcase.When(&b)
+			if got := a.Equal(b); got != testcase.Want {
+				t.Errorf("a.Equal(b): got %v, want %v", got, testcase.Want)
+			}
+			if got := b.Equal(a); got != testcase.Want {
+				t.Errorf("b.Equal(a): got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
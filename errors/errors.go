@@ -2,35 +2,122 @@ package errors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"gopkg.in/yaml.v3"
 )
 
 type ErrorResponse struct {
-	Message ErrorMessage `json:"message"`
+	Message ErrorMessage `json:"message" yaml:"message"`
 }
 
 type ErrorMessage struct {
-	Reason string `json:"reason"`
-	Advice string `json:"advice,omitempty"`
-	See    string `json:"see,omitempty"`
-	Cause  error  `json:"-"`
+	Reason string `json:"reason" yaml:"reason"`
+	Advice string `json:"advice,omitempty" yaml:"advice,omitempty"`
+	See    string `json:"see,omitempty" yaml:"see,omitempty"`
+
+	// Template is the message template of Reason, like "tag {key} is reserved".
+	//
+	// Placeholders in braces are replaced with Params.
+	// Clients can use this to localize or re-render the message.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Params are the named parameters for Template.
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+
+	Cause error `json:"-" yaml:"-"`
 }
 
-func (em *ErrorMessage) UnmarshalJSON(bytes []byte) error {
-	f := new(struct {
-		Reason *string `json:"reason"`
-		Advice *string `json:"advice,omitempty"`
-		See    *string `json:"see,omitempty"`
-	})
-	if err := json.Unmarshal(bytes, f); err != nil {
-		return err
+// Wrap creates ErrorMessage caused by cause.
+func Wrap(cause error, reason string, advice string) ErrorMessage {
+	return ErrorMessage{Reason: reason, Advice: advice, Cause: cause}
+}
+
+// AsErrorMessage finds ErrorMessage in the chain of err.
+//
+// It also finds ErrorMessage in HTTPError.
+func AsErrorMessage(err error) (ErrorMessage, bool) {
+	var em ErrorMessage
+	if errors.As(err, &em) {
+		return em, true
+	}
+	var pem *ErrorMessage
+	if errors.As(err, &pem) && pem != nil {
+		return *pem, true
 	}
+	var he HTTPError
+	if errors.As(err, &he) {
+		return he.Message, true
+	}
+	return ErrorMessage{}, false
+}
+
+// NewTemplated creates ErrorMessage with the template and its parameters.
+//
+// Reason is set to the interpolated message.
+func NewTemplated(template string, params map[string]string) ErrorMessage {
+	return ErrorMessage{
+		Reason:   Interpolate(template, params),
+		Template: template,
+		Params:   params,
+	}
+}
+
+// Interpolate replaces placeholders "{name}" in template with params.
+//
+// Placeholders without corresponding params are left as they are.
+func Interpolate(template string, params map[string]string) string {
+	b := new(strings.Builder)
+	rest := template
+	for {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			break
+		}
+		close := strings.Index(rest[open:], "}")
+		if close < 0 {
+			break
+		}
+		close += open
 
-	if f.Reason == nil {
+		b.WriteString(rest[:open])
+		if v, ok := params[rest[open+1:close]]; ok {
+			b.WriteString(v)
+		} else {
+			b.WriteString(rest[open : close+1])
+		}
+		rest = rest[close+1:]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
+
+// errorMessageFields is the serialized form of ErrorMessage, to detect missing fields.
+type errorMessageFields struct {
+	Reason   *string           `json:"reason" yaml:"reason"`
+	Advice   *string           `json:"advice,omitempty" yaml:"advice,omitempty"`
+	See      *string           `json:"see,omitempty" yaml:"see,omitempty"`
+	Template *string           `json:"template,omitempty" yaml:"template,omitempty"`
+	Params   map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+func (em *ErrorMessage) fill(f errorMessageFields) error {
+	switch {
+	case f.Reason != nil:
+		em.Reason = *f.Reason
+	case f.Template != nil:
+		em.Reason = Interpolate(*f.Template, f.Params)
+	default:
 		return fmt.Errorf(`required field missing: "reason"`)
 	}
-	em.Reason = *f.Reason
+
+	if f.Template != nil {
+		em.Template = *f.Template
+	}
+	em.Params = f.Params
 
 	if f.Advice != nil {
 		em.Advice = *f.Advice
@@ -43,6 +130,33 @@ func (em *ErrorMessage) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
+func (em *ErrorMessage) UnmarshalJSON(bytes []byte) error {
+	f := errorMessageFields{}
+	if err := json.Unmarshal(bytes, &f); err != nil {
+		return err
+	}
+	return em.fill(f)
+}
+
+func (em *ErrorMessage) UnmarshalYAML(node *yaml.Node) error {
+	f := errorMessageFields{}
+	if err := node.Decode(&f); err != nil {
+		return err
+	}
+	return em.fill(f)
+}
+
+// Equal reports whether e and o have the same fields in the wire format.
+//
+// Cause is not compared.
+func (e ErrorMessage) Equal(o ErrorMessage) bool {
+	return e.Reason == o.Reason &&
+		e.Advice == o.Advice &&
+		e.See == o.See &&
+		e.Template == o.Template &&
+		apicmp.MapEqualWith(e.Params, o.Params, func(a, b string) bool { return a == b })
+}
+
 func (e ErrorMessage) String() string {
 	lines := []string{e.Reason}
 	if e.Advice != "" {
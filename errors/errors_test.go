@@ -0,0 +1,108 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/errors"
+)
+
+func TestInterpolate(t *testing.T) {
+	type When struct {
+		Template string
+		Params   map[string]string
+	}
+	theory := func(when When, then string) func(*testing.T) {
+		return func(t *testing.T) {
+			got := errors.Interpolate(when.Template, when.Params)
+			if got != then {
+				t.Errorf("unexpected result: %q (want %q)", got, then)
+			}
+		}
+	}
+
+	t.Run("no placeholders", theory(
+		When{Template: "something wrong"},
+		"something wrong",
+	))
+	t.Run("placeholders", theory(
+		When{
+			Template: "tag {key} is reserved for {who}",
+			Params:   map[string]string{"key": "knit#id", "who": "system"},
+		},
+		"tag knit#id is reserved for system",
+	))
+	t.Run("unknown placeholder is left", theory(
+		When{Template: "tag {key} is {what}", Params: map[string]string{"key": "k"}},
+		"tag k is {what}",
+	))
+	t.Run("unclosed brace", theory(
+		When{Template: "tag {key", Params: map[string]string{"key": "k"}},
+		"tag {key",
+	))
+}
+
+func TestErrorMessage_unmarshal(t *testing.T) {
+	t.Run("template without reason", func(t *testing.T) {
+		var em errors.ErrorMessage
+		payload := `{"template":"tag {key} is reserved","params":{"key":"knit#id"}}`
+		if err := json.Unmarshal([]byte(payload), &em); err != nil {
+			t.Fatal(err)
+		}
+		if em.Error() != "tag knit#id is reserved" {
+			t.Errorf("unexpected message: %s", em.Error())
+		}
+		if em.Template != "tag {key} is reserved" || em.Params["key"] != "knit#id" {
+			t.Errorf("template is lost: %+v", em)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		em := errors.NewTemplated("plan {planId} not found", map[string]string{"planId": "p1"})
+		b, err := json.Marshal(em)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got errors.ErrorMessage
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Reason != "plan p1 not found" || got.Template != em.Template || got.Params["planId"] != "p1" {
+			t.Errorf("unexpected result: %+v", got)
+		}
+	})
+
+	t.Run("neither reason nor template", func(t *testing.T) {
+		var em errors.ErrorMessage
+		if err := json.Unmarshal([]byte(`{"advice":"retry"}`), &em); err == nil {
+			t.Error("expected error does not occur")
+		}
+	})
+}
+
+func TestErrorMessage_Equal(t *testing.T) {
+	a := errors.ErrorMessage{
+		Reason:   "tag type:dataset is reserved",
+		Template: "tag {tag} is reserved",
+		Params:   map[string]string{"tag": "type:dataset"},
+	}
+
+	b := a
+	b.Cause = fmt.Errorf("cause is not in the wire format")
+	if !a.Equal(b) {
+		t.Errorf("Cause should be ignored: %+v, %+v", a, b)
+	}
+
+	b = a
+	b.Params = map[string]string{"tag": "type:model"}
+	if a.Equal(b) {
+		t.Errorf("Params should be compared: %+v, %+v", a, b)
+	}
+
+	b = a
+	b.Template = ""
+	if a.Equal(b) {
+		t.Errorf("Template should be compared: %+v, %+v", a, b)
+	}
+}
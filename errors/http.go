@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPError is an ErrorMessage with the HTTP status code of the response carrying it.
+type HTTPError struct {
+	// StatusCode is the HTTP status code, like 404.
+	StatusCode int
+
+	// Message is the error message in the response body.
+	Message ErrorMessage
+}
+
+// NewHTTPError creates HTTPError.
+func NewHTTPError(status int, message ErrorMessage) HTTPError {
+	return HTTPError{StatusCode: status, Message: message}
+}
+
+func (e HTTPError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message.Error())
+}
+
+func (e HTTPError) Unwrap() error {
+	return e.Message
+}
+
+// StatusOf returns the HTTP status code for err.
+//
+// If err is nil, it returns 200 OK.
+// If there is HTTPError in the chain of err, its StatusCode is returned.
+// Otherwise, it returns 500 Internal Server Error.
+func StatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var he HTTPError
+	if errors.As(err, &he) {
+		return he.StatusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// IsStatus returns true if the HTTP status code for err is status.
+func IsStatus(err error, status int) bool {
+	return StatusOf(err) == status
+}
+
+// Write writes err as an ErrorResponse, with the status code from StatusOf.
+//
+// If there is no ErrorMessage in err, the message of err is used as Reason.
+func Write(w http.ResponseWriter, err error) error {
+	em, ok := AsErrorMessage(err)
+	if !ok {
+		em = ErrorMessage{Reason: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(StatusOf(err))
+	return json.NewEncoder(w).Encode(ErrorResponse{Message: em})
+}
+
+// FromResponse reads an error response.
+//
+// If the status code of resp is not an error (less than 400), it returns nil.
+// Otherwise, it returns HTTPError. When the body is not an ErrorResponse,
+// the status text is used as Reason.
+//
+// This reads the body, but does not close it.
+func FromResponse(resp *http.Response) error {
+	if resp.StatusCode < 400 {
+		return nil
+	}
+	he := HTTPError{StatusCode: resp.StatusCode}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		he.Message = ErrorMessage{Reason: http.StatusText(resp.StatusCode), Cause: err}
+		return he
+	}
+	er := ErrorResponse{}
+	if err := json.Unmarshal(body, &er); err != nil {
+		he.Message = ErrorMessage{Reason: http.StatusText(resp.StatusCode)}
+		return he
+	}
+	he.Message = er.Message
+	return he
+}
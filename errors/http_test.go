@@ -0,0 +1,99 @@
+package errors_test
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/errors"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHTTPError_roundTrip(t *testing.T) {
+	cause := goerrors.New("no rows")
+	sent := errors.NewHTTPError(
+		http.StatusNotFound,
+		errors.Wrap(cause, "plan not found", "check plan id"),
+	)
+	if !goerrors.Is(sent, cause) {
+		t.Errorf("cause is lost: %v", sent)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errors.Write(w, fmt.Errorf("handling: %w", sent))
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got := errors.FromResponse(resp)
+	if !errors.IsStatus(got, http.StatusNotFound) {
+		t.Errorf("unexpected status: %d", errors.StatusOf(got))
+	}
+	em, ok := errors.AsErrorMessage(got)
+	if !ok {
+		t.Fatalf("no ErrorMessage in %v", got)
+	}
+	if em.Reason != "plan not found" || em.Advice != "check plan id" {
+		t.Errorf("unexpected message: %+v", em)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	if got := errors.StatusOf(nil); got != http.StatusOK {
+		t.Errorf("nil: got %d", got)
+	}
+	if got := errors.StatusOf(goerrors.New("boom")); got != http.StatusInternalServerError {
+		t.Errorf("plain error: got %d", got)
+	}
+}
+
+func TestFromResponse_notErrorResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusBadGateway)
+	rec.WriteString("<html>bad gateway</html>")
+
+	err := errors.FromResponse(rec.Result())
+	em, ok := errors.AsErrorMessage(err)
+	if !ok || em.Reason != http.StatusText(http.StatusBadGateway) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	ok200 := httptest.NewRecorder()
+	if err := errors.FromResponse(ok200.Result()); err != nil {
+		t.Errorf("non-error response: got %v", err)
+	}
+}
+
+func TestErrorResponse_YAML(t *testing.T) {
+	want := errors.ErrorResponse{
+		Message: errors.NewTemplated("tag {key} is reserved", map[string]string{"key": "knit#id"}),
+	}
+	want.Message.Advice = "use another key"
+
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := errors.ErrorResponse{}
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("YAML round trip: got %s, want %s", gotJSON, wantJSON)
+	}
+
+	if err := yaml.Unmarshal([]byte("message:\n  advice: nothing\n"), &got); err == nil {
+		t.Error("message without reason is accepted")
+	}
+}
@@ -0,0 +1,184 @@
+// Package events defines types of push notifications about Runs, Data and Plans,
+// for Server-Sent Events (SSE) and WebSocket APIs.
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+// EventType is the type of Events, in the form "<subject>.<what happened>".
+type EventType string
+
+const (
+	// RunCreated is sent when a Run is created.
+	RunCreated EventType = "run.created"
+
+	// RunStatusChanged is sent when the status of a Run is changed.
+	RunStatusChanged EventType = "run.status_changed"
+
+	// RunDeleted is sent when a Run is deleted.
+	RunDeleted EventType = "run.deleted"
+
+	// DataCreated is sent when a Data is created, by uploading or as an output of a Run.
+	DataCreated EventType = "data.created"
+
+	// DataTagsChanged is sent when tags of a Data are changed.
+	DataTagsChanged EventType = "data.tags_changed"
+
+	// PlanCreated is sent when a Plan is registered.
+	PlanCreated EventType = "plan.created"
+
+	// PlanUpdated is sent when a Plan is changed: activeness, resources, annotations and so on.
+	PlanUpdated EventType = "plan.updated"
+)
+
+// Subject is the kind of objects which Events are about.
+type Subject string
+
+const (
+	SubjectRun  Subject = "run"
+	SubjectData Subject = "data"
+	SubjectPlan Subject = "plan"
+)
+
+// EventTypes returns all known EventTypes.
+func EventTypes() []EventType {
+	return []EventType{
+		RunCreated, RunStatusChanged, RunDeleted,
+		DataCreated, DataTagsChanged,
+		PlanCreated, PlanUpdated,
+	}
+}
+
+func (t EventType) String() string {
+	return string(t)
+}
+
+// Valid returns true if t is one of known EventTypes.
+func (t EventType) Valid() bool {
+	return slices.Contains(EventTypes(), t)
+}
+
+// Subject returns the kind of objects which the EventType is about, like "run" for "run.created".
+func (t EventType) Subject() Subject {
+	s, _, _ := strings.Cut(string(t), ".")
+	return Subject(s)
+}
+
+// Event is the envelope of notifications pushed from Knitfab APIs below:
+//
+// - GET /api/events[?...] (as Server-Sent Events, or WebSocket messages)
+//
+// Exactly one of Run, Data or Plan is set, according to the Subject of Type.
+// Clients should ignore Events of unknown Types, since new Types can be added.
+type Event struct {
+	// Id is the id of the Event.
+	//
+	// It can be sent as the "Last-Event-ID" header to resume the stream after the Event.
+	Id string `json:"id"`
+
+	// Type is the type of the Event.
+	Type EventType `json:"type"`
+
+	// At is the time when the Event happened.
+	At rfctime.RFC3339 `json:"at"`
+
+	// Run is the Run after the Event.
+	Run *runs.Detail `json:"run,omitempty"`
+
+	// Data is the Data after the Event.
+	Data *data.Detail `json:"data,omitempty"`
+
+	// Plan is the Plan after the Event.
+	Plan *plans.Detail `json:"plan,omitempty"`
+}
+
+func (e Event) Equal(o Event) bool {
+	return e.Id == o.Id &&
+		e.Type == o.Type &&
+		e.At.Equal(o.At) &&
+		apicmp.PtrEqual(e.Run, o.Run) &&
+		apicmp.PtrEqual(e.Data, o.Data) &&
+		apicmp.PtrEqual(e.Plan, o.Plan)
+}
+
+// Validate checks that Type is given, and the object for its Subject (and only that) is set.
+//
+// Events of unknown Types are not rejected, as long as they are well-formed.
+func (e Event) Validate() error {
+	if e.Type == "" {
+		return fmt.Errorf(`required field missing: "type"`)
+	}
+
+	set := map[Subject]bool{
+		SubjectRun:  e.Run != nil,
+		SubjectData: e.Data != nil,
+		SubjectPlan: e.Plan != nil,
+	}
+	subject := e.Type.Subject()
+	if _, known := set[subject]; known && !set[subject] {
+		return fmt.Errorf(`required field missing: "%s" (for type %q)`, subject, e.Type)
+	}
+	for s, ok := range set {
+		if ok && s != subject {
+			return fmt.Errorf(`"%s" should not be set for type %q`, s, e.Type)
+		}
+	}
+	return nil
+}
+
+// Query parameter names for Filter.
+const (
+	ParamType = "type"
+)
+
+// Filter is the query parameters for Knitfab APIs below:
+//
+// - GET /api/events[?...]
+type Filter struct {
+	// Types are EventTypes to be received.
+	//
+	// If empty, all Events are received.
+	Types []EventType
+}
+
+func (f Filter) Equal(o Filter) bool {
+	return apicmp.SliceEqEqUnordered(f.Types, o.Types)
+}
+
+// Matches returns true if e passes the Filter.
+func (f Filter) Matches(e Event) bool {
+	return len(f.Types) == 0 || slices.Contains(f.Types, e.Type)
+}
+
+// Encode returns the filter as url.Values.
+func (f Filter) Encode() url.Values {
+	v := url.Values{}
+	for _, t := range f.Types {
+		v.Add(ParamType, t.String())
+	}
+	return v
+}
+
+// Decode reads the filter from url.Values.
+func (f *Filter) Decode(v url.Values) error {
+	ret := Filter{}
+	for _, t := range v[ParamType] {
+		et := EventType(t)
+		if !et.Valid() {
+			return fmt.Errorf(`query parameter "%s": unknown event type: %q`, ParamType, t)
+		}
+		ret.Types = append(ret.Types, et)
+	}
+	*f = ret
+	return nil
+}
@@ -0,0 +1,136 @@
+package events_test
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/events"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestEvent(t *testing.T) {
+	at, err := rfctime.ParseRFC3339DateTime("2024-01-02T03:04:05.678+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := &runs.Detail{
+		Summary: runs.Summary{
+			RunId:     "0190a1b2-0000-7000-8000-000000000201",
+			Status:    runs.Failed,
+			UpdatedAt: at,
+			Plan:      plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101", Name: "train"},
+		},
+	}
+	dat := &data.Detail{
+		KnitId: "0190a1b2-0000-7000-8000-000000000301",
+		Tags:   []tags.Tag{{Key: "type", Value: "model"}},
+		Upstream: data.CreatedFrom{
+			Mountpoint: &plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+			Run:        run.Summary,
+		},
+	}
+
+	for name, e := range map[string]events.Event{
+		"run":  {Id: "1", Type: events.RunStatusChanged, At: at, Run: run},
+		"data": {Id: "2", Type: events.DataCreated, At: at, Data: dat},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := e.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			knittest.AssertJSONRoundTrip(t, e)
+		})
+	}
+
+	for name, e := range map[string]events.Event{
+		"no type":       {Run: run},
+		"no subject":    {Type: events.RunCreated},
+		"wrong subject": {Type: events.PlanUpdated, Run: run},
+		"extra subject": {Type: events.RunCreated, Run: run, Data: dat},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := e.Validate(); err == nil {
+				t.Errorf("invalid event is accepted: %+v", e)
+			}
+		})
+	}
+
+	if err := (events.Event{Type: "node.added"}).Validate(); err != nil {
+		t.Errorf("event of unknown type is rejected: %v", err)
+	}
+	if events.DataTagsChanged.Subject() != events.SubjectData {
+		t.Errorf("unexpected subject: %s", events.DataTagsChanged.Subject())
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f := events.Filter{Types: []events.EventType{events.RunStatusChanged, events.DataCreated}}
+	got := events.Filter{}
+	if err := got.Decode(f.Encode()); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(f) {
+		t.Errorf("round trip: got %+v, want %+v", got, f)
+	}
+
+	if !f.Matches(events.Event{Type: events.DataCreated}) || f.Matches(events.Event{Type: events.PlanCreated}) {
+		t.Error("unexpected match")
+	}
+	if !(events.Filter{}).Matches(events.Event{Type: events.PlanCreated}) {
+		t.Error("empty filter should match everything")
+	}
+
+	if err := got.Decode(url.Values{events.ParamType: {"run.exploded"}}); err == nil {
+		t.Errorf("unknown type is accepted: %+v", got)
+	}
+}
+
+func TestSSE(t *testing.T) {
+	es := []events.Event{
+		{Id: "1", Type: events.RunCreated, Run: &runs.Detail{Summary: runs.Summary{RunId: "0190a1b2-0000-7000-8000-000000000201", Status: runs.Waiting}}},
+		{Id: "2", Type: events.PlanCreated, Plan: &plans.Detail{Summary: plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101"}}},
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(": keep-alive\n\n")
+	for _, e := range es {
+		if err := events.WriteSSE(buf, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), ": keep-alive\n\nid: 1\nevent: run.created\ndata: {") {
+		t.Errorf("unexpected stream:\n%s", buf)
+	}
+
+	got := []events.Event{}
+	if err := events.ScanSSE(buf, func(e events.Event) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(es) {
+		t.Fatalf("got %d events, want %d", len(got), len(es))
+	}
+	for i := range es {
+		if !got[i].Equal(es[i]) {
+			t.Errorf("events[%d]: got %+v, want %+v", i, got[i], es[i])
+		}
+	}
+
+	if err := events.ScanSSE(strings.NewReader("data: {\n\n"), func(events.Event) error { return nil }); err == nil {
+		t.Error("malformed event is accepted")
+	}
+	stop := errors.New("stop")
+	if err := events.ScanSSE(strings.NewReader("data: {}\n\ndata: {}\n"), func(events.Event) error { return stop }); err != stop {
+		t.Errorf("error from fn is not returned: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContentTypeSSE is the media type of Server-Sent Events streams.
+const ContentTypeSSE = "text/event-stream"
+
+// WriteSSE writes the Event into w as a message of Server-Sent Events.
+//
+// The message has "id", "event" (the Type) and "data" (the Event in JSON) fields.
+func WriteSSE(w io.Writer, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	buf := bytes.Buffer{}
+	if e.Id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", e.Id)
+	}
+	fmt.Fprintf(&buf, "event: %s\n", e.Type)
+	fmt.Fprintf(&buf, "data: %s\n\n", b)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ScanSSE reads a stream of Server-Sent Events from r, and calls fn with each Event.
+//
+// Comments (lines starting with ":", used as keep-alive) and messages without data are skipped.
+// Data of messages should be Events in JSON; the "event" and "id" fields are ignored in favor of them.
+// It stops at a malformed message, or when fn returns error.
+func ScanSSE(r io.Reader, fn func(Event) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	dat := []string{}
+	dispatch := func(line int) error {
+		if len(dat) == 0 {
+			return nil
+		}
+		e := Event{}
+		err := json.Unmarshal([]byte(strings.Join(dat, "\n")), &e)
+		dat = dat[:0]
+		if err != nil {
+			return fmt.Errorf("line %d: malformed event: %w", line, err)
+		}
+		return fn(e)
+	}
+
+	n := 0
+	for s.Scan() {
+		n++
+		line := s.Text()
+		switch {
+		case line == "":
+			if err := dispatch(n); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			if field == "data" {
+				dat = append(dat, strings.TrimPrefix(value, " "))
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return dispatch(n)
+}
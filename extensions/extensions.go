@@ -0,0 +1,124 @@
+// Package extensions provides a place for custom fields of API types.
+//
+// Plugins and experimental server features can put their own data in Extensions
+// of Detail types, under the key "x-ext", without adding fields to the core types.
+// Clients which do not know them keep them as they are, across decoding and encoding.
+//
+// Since Extensions decodes any JSON values, strict decoders (see package strict)
+// do not look into it, and still reject unknown fields in the rest of documents.
+package extensions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Key is the name of the field holding Extensions in JSON and YAML.
+const Key = "x-ext"
+
+// Extensions are custom fields, as pairs of names and JSON values.
+//
+// Names should be qualified by their owner, like "example.com/feature",
+// not to collide with others.
+type Extensions map[string]json.RawMessage
+
+// Equal reports whether e and o have the same names and equivalent JSON values.
+//
+// Values are compared as decoded, so spaces and the order of object keys do not matter.
+func (e Extensions) Equal(o Extensions) bool {
+	if len(e) != len(o) {
+		return false
+	}
+	for k, v := range e {
+		w, ok := o[k]
+		if !ok || !jsonEqual(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// Clone returns a deep copy of the Extensions.
+func (e Extensions) Clone() Extensions {
+	if e == nil {
+		return nil
+	}
+	ret := make(Extensions, len(e))
+	for k, v := range e {
+		ret[k] = bytes.Clone(v)
+	}
+	return ret
+}
+
+// Has reports whether e has the extension named name.
+func (e Extensions) Has(name string) bool {
+	_, ok := e[name]
+	return ok
+}
+
+// Get decodes the extension named name into v.
+//
+// It returns false if e does not have the extension.
+func (e Extensions) Get(name string, v any) (bool, error) {
+	raw, ok := e[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("%s.%s: %w", Key, name, err)
+	}
+	return true, nil
+}
+
+// Set encodes v as the extension named name.
+//
+// e should not be nil.
+func (e Extensions) Set(name string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", Key, name, err)
+	}
+	e[name] = raw
+	return nil
+}
+
+func (e Extensions) MarshalYAML() (interface{}, error) {
+	ret := make(map[string]any, len(e))
+	for k, raw := range e {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", Key, k, err)
+		}
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+func (e *Extensions) UnmarshalYAML(node *yaml.Node) error {
+	var m map[string]any
+	if err := node.Decode(&m); err != nil {
+		return err
+	}
+	ret := make(Extensions, len(m))
+	for k, v := range m {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", Key, k, err)
+		}
+		ret[k] = raw
+	}
+	*e = ret
+	return nil
+}
@@ -0,0 +1,104 @@
+package extensions_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/knittest"
+	"gopkg.in/yaml.v3"
+)
+
+type doc struct {
+	Name       string                `json:"name" yaml:"name"`
+	Extensions extensions.Extensions `json:"x-ext,omitempty" yaml:"x-ext,omitempty"`
+}
+
+func (d doc) Equal(o doc) bool {
+	return d.Name == o.Name && d.Extensions.Equal(o.Extensions)
+}
+
+func TestExtensions_roundTrip(t *testing.T) {
+	d := doc{
+		Name: "example",
+		Extensions: extensions.Extensions{
+			"example.com/flag":   json.RawMessage(`true`),
+			"example.com/object": json.RawMessage(`{"source": "s3://bucket/key", "items": [1, "two", null]}`),
+		},
+	}
+	knittest.AssertRoundTrip(t, d)
+	knittest.AssertRoundTrip(t, doc{Name: "no extensions"})
+
+	b, err := yaml.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "source: s3://bucket/key") {
+		t.Errorf("extensions should be YAML mappings:\n%s", b)
+	}
+
+	b, err = json.Marshal(doc{Name: "no extensions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), extensions.Key) {
+		t.Errorf("empty extensions should be omitted: %s", b)
+	}
+}
+
+func TestExtensions_Equal(t *testing.T) {
+	a := extensions.Extensions{"example.com/x": json.RawMessage(`{"a": 1, "b": [true]}`)}
+	for name, tc := range map[string]struct {
+		b    extensions.Extensions
+		want bool
+	}{
+		"key order":     {b: extensions.Extensions{"example.com/x": json.RawMessage(`{"b":[true],"a":1}`)}, want: true},
+		"spaces":        {b: extensions.Extensions{"example.com/x": json.RawMessage(`{"a":1,"b":[true]}`)}, want: true},
+		"another value": {b: extensions.Extensions{"example.com/x": json.RawMessage(`{"a":2,"b":[true]}`)}},
+		"another name":  {b: extensions.Extensions{"example.com/y": json.RawMessage(`{"a":1,"b":[true]}`)}},
+		"nil":           {b: nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := a.Equal(tc.b); got != tc.want {
+				t.Errorf("Equal = %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if !extensions.Extensions(nil).Equal(extensions.Extensions{}) {
+		t.Error("nil and empty should be equal")
+	}
+}
+
+func TestExtensions_GetSet(t *testing.T) {
+	type lineage struct {
+		Source string `json:"source"`
+	}
+	e := extensions.Extensions{}
+	if err := e.Set("example.com/lineage", lineage{Source: "s3://bucket/key"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got lineage
+	if ok, err := e.Get("example.com/lineage", &got); !ok || err != nil {
+		t.Fatalf("Get = %v, %v", ok, err)
+	}
+	if got.Source != "s3://bucket/key" {
+		t.Errorf("unexpected value: %+v", got)
+	}
+
+	if ok, err := e.Get("example.com/missing", &got); ok || err != nil {
+		t.Errorf("Get (missing) = %v, %v", ok, err)
+	}
+
+	var n int
+	if _, err := e.Get("example.com/lineage", &n); err == nil || !strings.Contains(err.Error(), "x-ext.example.com/lineage") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c := e.Clone()
+	c["example.com/lineage"][0] = '['
+	if !e.Has("example.com/lineage") || e["example.com/lineage"][0] != '{' {
+		t.Error("Clone shares values")
+	}
+}
@@ -0,0 +1,117 @@
+package federation
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Cluster is a remote Knitfab cluster known by this cluster.
+//
+// Cluster is the format for request and response body for Knitfab APIs below:
+//
+// - GET /api/federation/clusters/ (as list)
+//
+// - PUT /api/federation/clusters/{alias}
+type Cluster struct {
+	// Alias is the name of the remote cluster, unique in this cluster.
+	Alias string `json:"alias" yaml:"alias"`
+
+	// Endpoint is the base URL of the WebAPI of the remote cluster.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+}
+
+func (c Cluster) Equal(o Cluster) bool {
+	return c.Alias == o.Alias && c.Endpoint == o.Endpoint
+}
+
+// Validate checks the Cluster is well-formed.
+func (c Cluster) Validate() error {
+	if c.Alias == "" {
+		return fmt.Errorf(`required field missing: "alias"`)
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf(`"endpoint" should be an absolute http(s) URL: %q`, c.Endpoint)
+	}
+	return nil
+}
+
+// Ref refers objects in a remote Knitfab cluster.
+//
+// Ids not concerned are empty.
+type Ref struct {
+	// Cluster is the alias of the remote cluster.
+	Cluster string `json:"cluster" yaml:"cluster"`
+
+	// Endpoint is the base URL of the WebAPI of the remote cluster, when it is referred.
+	//
+	// This is informative; Cluster is the key to find the remote cluster.
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+
+	// KnitId is the id of the Data in the remote cluster.
+	KnitId string `json:"knitId,omitempty" yaml:"knitId,omitempty"`
+
+	// RunId is the id of the Run in the remote cluster.
+	RunId string `json:"runId,omitempty" yaml:"runId,omitempty"`
+
+	// PlanId is the id of the Plan in the remote cluster.
+	PlanId string `json:"planId,omitempty" yaml:"planId,omitempty"`
+}
+
+func (r Ref) Equal(o Ref) bool {
+	return r.Cluster == o.Cluster &&
+		r.Endpoint == o.Endpoint &&
+		r.KnitId == o.KnitId &&
+		r.RunId == o.RunId &&
+		r.PlanId == o.PlanId
+}
+
+func (r Ref) String() string {
+	s := r.Cluster
+	if r.PlanId != "" {
+		s += "/plans/" + r.PlanId
+	}
+	if r.RunId != "" {
+		s += "/runs/" + r.RunId
+	}
+	if r.KnitId != "" {
+		s += "/data/" + r.KnitId
+	}
+	return s
+}
+
+// Validate checks the Ref refers something in a cluster.
+func (r Ref) Validate() error {
+	if r.Cluster == "" {
+		return fmt.Errorf(`required field missing: "cluster"`)
+	}
+	if r.KnitId == "" && r.RunId == "" && r.PlanId == "" {
+		return fmt.Errorf(`one of "knitId", "runId" or "planId" is required`)
+	}
+	return nil
+}
+
+// Link is the format for response body from Knitfab APIs below:
+//
+// - GET  /api/federation/links/[?...] (as list)
+//
+// - POST /api/federation/links/
+//
+// It records that a Data in this cluster is imported from a remote cluster.
+type Link struct {
+	// KnitId is the id of the Data in this cluster.
+	KnitId knitid.KnitId `json:"knitId" yaml:"knitId"`
+
+	// Remote is the origin of the Data.
+	Remote Ref `json:"remote" yaml:"remote"`
+
+	// LinkedAt is the time when the Data was imported.
+	LinkedAt rfctime.RFC3339 `json:"linkedAt" yaml:"linkedAt"`
+}
+
+func (l Link) Equal(o Link) bool {
+	return l.KnitId == o.KnitId && l.Remote.Equal(o.Remote) && l.LinkedAt.Equal(o.LinkedAt)
+}
@@ -0,0 +1,113 @@
+package federation_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/federation"
+)
+
+func TestCluster_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    federation.Cluster
+		WantErr bool
+	}{
+		"https": {
+			When: federation.Cluster{Alias: "tokyo", Endpoint: "https://knitfab.tokyo.example.com/api"},
+		},
+		"http with port": {
+			When: federation.Cluster{Alias: "local", Endpoint: "http://localhost:30803"},
+		},
+		"missing alias": {
+			When:    federation.Cluster{Endpoint: "https://knitfab.tokyo.example.com"},
+			WantErr: true,
+		},
+		"missing endpoint": {
+			When:    federation.Cluster{Alias: "tokyo"},
+			WantErr: true,
+		},
+		"relative endpoint": {
+			When:    federation.Cluster{Alias: "tokyo", Endpoint: "/api"},
+			WantErr: true,
+		},
+		"no host": {
+			When:    federation.Cluster{Alias: "tokyo", Endpoint: "https:///api"},
+			WantErr: true,
+		},
+		"unsupported scheme": {
+			When:    federation.Cluster{Alias: "tokyo", Endpoint: "ftp://knitfab.tokyo.example.com"},
+			WantErr: true,
+		},
+		"malformed endpoint": {
+			When:    federation.Cluster{Alias: "tokyo", Endpoint: "https://knitfab tokyo/%zz"},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid cluster is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid cluster is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestRef(t *testing.T) {
+	const (
+		planId = "0190a1b2-0000-7000-8000-000000000301"
+		runId  = "0190a1b2-0000-7000-8000-000000000302"
+		knitId = "0190a1b2-0000-7000-8000-000000000303"
+	)
+
+	for name, testcase := range map[string]struct {
+		When       federation.Ref
+		WantString string
+		WantErr    bool
+	}{
+		"data": {
+			When:       federation.Ref{Cluster: "tokyo", KnitId: knitId},
+			WantString: "tokyo/data/" + knitId,
+		},
+		"run": {
+			When:       federation.Ref{Cluster: "tokyo", RunId: runId},
+			WantString: "tokyo/runs/" + runId,
+		},
+		"plan": {
+			When:       federation.Ref{Cluster: "tokyo", PlanId: planId},
+			WantString: "tokyo/plans/" + planId,
+		},
+		"all ids, with endpoint": {
+			When: federation.Ref{
+				Cluster: "tokyo", Endpoint: "https://knitfab.tokyo.example.com",
+				PlanId: planId, RunId: runId, KnitId: knitId,
+			},
+			WantString: "tokyo/plans/" + planId + "/runs/" + runId + "/data/" + knitId,
+		},
+		"missing cluster": {
+			When:       federation.Ref{KnitId: knitId},
+			WantString: "/data/" + knitId,
+			WantErr:    true,
+		},
+		"missing ids": {
+			When:       federation.Ref{Cluster: "tokyo", Endpoint: "https://knitfab.tokyo.example.com"},
+			WantString: "tokyo",
+			WantErr:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.When.String(); got != testcase.WantString {
+				t.Errorf("String: got %q, want %q", got, testcase.WantString)
+			}
+
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid ref is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid ref is rejected: %v", err)
+			}
+		})
+	}
+}
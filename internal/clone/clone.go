@@ -0,0 +1,34 @@
+// Package clone provides helpers for deep copy.
+package clone
+
+// Ptr returns a pointer to a copy of *p, or nil if p is nil.
+func Ptr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// PtrWith returns a pointer to f(*p), or nil if p is nil.
+func PtrWith[T any](p *T, f func(T) T) *T {
+	if p == nil {
+		return nil
+	}
+	v := f(*p)
+	return &v
+}
+
+// SliceWith returns a new slice with f applied to each element of s.
+//
+// If s is nil, it returns nil.
+func SliceWith[T any](s []T, f func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	ret := make([]T, len(s))
+	for i, v := range s {
+		ret[i] = f(v)
+	}
+	return ret
+}
@@ -0,0 +1,59 @@
+// Package ids provides common implementation of typed identifiers.
+package ids
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validate checks s is a UUID, like "0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b".
+//
+// Empty string is accepted as the zero value.
+// kind is the name of the identifier, used in error messages.
+func Validate(kind string, s string) error {
+	if s == "" {
+		return nil
+	}
+	if len(s) != 36 {
+		return fmt.Errorf("%s should be a UUID: %q", kind, s)
+	}
+	for i, c := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return fmt.Errorf("%s should be a UUID: %q", kind, s)
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return fmt.Errorf("%s should be a UUID: %q", kind, s)
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON reads a JSON string as an identifier, and validates it.
+func UnmarshalJSON(kind string, b []byte) (string, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return "", err
+	}
+	if err := Validate(kind, s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// UnmarshalYAML reads a YAML scalar as an identifier, and validates it.
+func UnmarshalYAML(kind string, node *yaml.Node) (string, error) {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return "", err
+	}
+	if err := Validate(kind, s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
@@ -0,0 +1,139 @@
+// Package render writes graphs in DOT (Graphviz) and Mermaid formats.
+//
+// Nodes and edges are written in sorted order, so that the same graph is always rendered into the same text.
+package render
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Shape is the shape of nodes.
+type Shape int
+
+const (
+	Box Shape = iota
+	Round
+	Cylinder
+	Dashed
+)
+
+type Node struct {
+	Id    string
+	Label string
+	Shape Shape
+}
+
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+func (g Graph) sorted() Graph {
+	nodes := slices.Clone(g.Nodes)
+	slices.SortStableFunc(nodes, func(a, b Node) int { return strings.Compare(a.Id, b.Id) })
+	edges := slices.Clone(g.Edges)
+	slices.SortStableFunc(edges, func(a, b Edge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.To, b.To); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Label, b.Label)
+	})
+	return Graph{Nodes: nodes, Edges: edges}
+}
+
+// DOT writes g in DOT language.
+func DOT(w io.Writer, g Graph) error {
+	g = g.sorted()
+	q := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("digraph {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		attrs := ""
+		switch n.Shape {
+		case Box:
+			attrs = "shape=box"
+		case Round:
+			attrs = "shape=box, style=rounded"
+		case Cylinder:
+			attrs = "shape=cylinder"
+		case Dashed:
+			attrs = "shape=box, style=dashed"
+		}
+		fmt.Fprintf(b, "  %s [label=%s, %s];\n", q(n.Id), q(n.Label), attrs)
+	}
+	for _, e := range g.Edges {
+		if e.Label == "" {
+			fmt.Fprintf(b, "  %s -> %s;\n", q(e.From), q(e.To))
+			continue
+		}
+		fmt.Fprintf(b, "  %s -> %s [label=%s];\n", q(e.From), q(e.To), q(e.Label))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mermaid writes g as a Mermaid flowchart.
+//
+// Node ids are replaced with "n0", "n1", ... in sorted order, because Mermaid restricts characters in ids.
+func Mermaid(w io.Writer, g Graph) error {
+	g = g.sorted()
+	q := func(s string) string {
+		return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br>").Replace(s) + `"`
+	}
+
+	ids := map[string]string{}
+	id := func(s string) string {
+		if i, ok := ids[s]; ok {
+			return i
+		}
+		i := fmt.Sprintf("n%d", len(ids))
+		ids[s] = i
+		return i
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		switch n.Shape {
+		case Box, Dashed:
+			fmt.Fprintf(b, "  %s[%s]\n", id(n.Id), q(n.Label))
+		case Round:
+			fmt.Fprintf(b, "  %s(%s)\n", id(n.Id), q(n.Label))
+		case Cylinder:
+			fmt.Fprintf(b, "  %s[(%s)]\n", id(n.Id), q(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		if e.Label == "" {
+			fmt.Fprintf(b, "  %s --> %s\n", id(e.From), id(e.To))
+			continue
+		}
+		fmt.Fprintf(b, "  %s -->|%s| %s\n", id(e.From), q(e.Label), id(e.To))
+	}
+	for _, n := range g.Nodes {
+		if n.Shape == Dashed {
+			fmt.Fprintf(b, "  style %s stroke-dasharray: 5 5\n", id(n.Id))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,89 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/internal/render"
+)
+
+func graphs() (render.Graph, render.Graph) {
+	nodes := []render.Node{
+		{Id: "run-1", Label: "run-1\nimage:v1", Shape: render.Round},
+		{Id: "data-a", Label: `data "a"`, Shape: render.Cylinder},
+		{Id: "data-b", Label: "data-b", Shape: render.Cylinder},
+		{Id: "hidden-1", Label: "(3 hidden)", Shape: render.Dashed},
+	}
+	edges := []render.Edge{
+		{From: "run-1", To: "data-b", Label: "/out"},
+		{From: "data-a", To: "run-1", Label: "/in"},
+		{From: "hidden-1", To: "data-a"},
+	}
+	a := render.Graph{Nodes: nodes, Edges: edges}
+
+	rnodes := []render.Node{nodes[3], nodes[1], nodes[2], nodes[0]}
+	redges := []render.Edge{edges[2], edges[1], edges[0]}
+	b := render.Graph{Nodes: rnodes, Edges: redges}
+	return a, b
+}
+
+func TestDOT(t *testing.T) {
+	a, b := graphs()
+
+	got := new(strings.Builder)
+	if err := render.DOT(got, a); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph {
+  rankdir=LR;
+  "data-a" [label="data \"a\"", shape=cylinder];
+  "data-b" [label="data-b", shape=cylinder];
+  "hidden-1" [label="(3 hidden)", shape=box, style=dashed];
+  "run-1" [label="run-1\nimage:v1", shape=box, style=rounded];
+  "data-a" -> "run-1" [label="/in"];
+  "hidden-1" -> "data-a";
+  "run-1" -> "data-b" [label="/out"];
+}
+`
+	if got.String() != want {
+		t.Errorf("unexpected DOT:\n%s", got)
+	}
+
+	other := new(strings.Builder)
+	if err := render.DOT(other, b); err != nil {
+		t.Fatal(err)
+	}
+	if other.String() != got.String() {
+		t.Errorf("output depends on order:\n%s", other)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	a, b := graphs()
+
+	got := new(strings.Builder)
+	if err := render.Mermaid(got, a); err != nil {
+		t.Fatal(err)
+	}
+	want := `flowchart LR
+  n0[("data #quot;a#quot;")]
+  n1[("data-b")]
+  n2["(3 hidden)"]
+  n3("run-1<br>image:v1")
+  n0 -->|"/in"| n3
+  n2 --> n0
+  n3 -->|"/out"| n1
+  style n2 stroke-dasharray: 5 5
+`
+	if got.String() != want {
+		t.Errorf("unexpected Mermaid:\n%s", got)
+	}
+
+	other := new(strings.Builder)
+	if err := render.Mermaid(other, b); err != nil {
+		t.Fatal(err)
+	}
+	if other.String() != got.String() {
+		t.Errorf("output depends on order:\n%s", other)
+	}
+}
@@ -0,0 +1,483 @@
+// Package jsonpatch creates and applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902) documents.
+//
+// Documents are compared in their JSON representation, so field names in patches are
+// the canonical ones defined by json tags of API types, like "service_account" or "knitId".
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Operation is an operation of JSON Patch (RFC 6902).
+type Operation struct {
+	// Op is one of "add", "remove", "replace", "move", "copy" and "test".
+	Op string `json:"op"`
+
+	// Path is the JSON Pointer (RFC 6901) to the target location, like "/inputs/0/path".
+	Path string `json:"path"`
+
+	// From is the JSON Pointer to the source location, for "move" and "copy".
+	From string `json:"from,omitempty"`
+
+	// Value is the value for "add", "replace" and "test".
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func decode(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// equal compares decoded JSON values.
+func equal(a, b any) bool {
+	ja, errA := encode(a)
+	jb, errB := encode(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+// CreateMergePatch returns the JSON Merge Patch which makes original to target.
+//
+// Keys only in original become null. Arrays are replaced as a whole, as RFC 7386 does.
+func CreateMergePatch(original, target []byte) ([]byte, error) {
+	o, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("original: %w", err)
+	}
+	t, err := decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+	return encode(mergeDiff(o, t))
+}
+
+func mergeDiff(o, t any) any {
+	om, oIsObj := o.(map[string]any)
+	tm, tIsObj := t.(map[string]any)
+	if !oIsObj || !tIsObj {
+		return t
+	}
+	patch := map[string]any{}
+	for k, ov := range om {
+		tv, ok := tm[k]
+		if !ok {
+			patch[k] = nil
+			continue
+		}
+		if equal(ov, tv) {
+			continue
+		}
+		patch[k] = mergeDiff(ov, tv)
+	}
+	for k, tv := range tm {
+		if _, ok := om[k]; !ok {
+			patch[k] = tv
+		}
+	}
+	return patch
+}
+
+// ApplyMergePatch applies the JSON Merge Patch to doc, and returns the result.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	d, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("patch: %w", err)
+	}
+	return encode(mergeApply(d, p))
+}
+
+func mergeApply(d, p any) any {
+	pm, ok := p.(map[string]any)
+	if !ok {
+		return p
+	}
+	dm, ok := d.(map[string]any)
+	if !ok {
+		dm = map[string]any{}
+	} else {
+		dm = maps.Clone(dm)
+	}
+	for k, pv := range pm {
+		if pv == nil {
+			delete(dm, k)
+			continue
+		}
+		dm[k] = mergeApply(dm[k], pv)
+	}
+	return dm
+}
+
+// CreatePatch returns JSON Patch operations which make original to target.
+//
+// Objects are compared key by key, in the order of keys.
+// Arrays with the same length are compared element by element; otherwise, they are replaced as a whole.
+func CreatePatch(original, target []byte) ([]Operation, error) {
+	o, err := decode(original)
+	if err != nil {
+		return nil, fmt.Errorf("original: %w", err)
+	}
+	t, err := decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("target: %w", err)
+	}
+	ops := []Operation{}
+	if err := diff(&ops, "", o, t); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func diff(ops *[]Operation, path string, o, t any) error {
+	if equal(o, t) {
+		return nil
+	}
+	switch o := o.(type) {
+	case map[string]any:
+		if t, ok := t.(map[string]any); ok {
+			keys := slices.Sorted(maps.Keys(o))
+			for _, k := range keys {
+				p := path + "/" + escape(k)
+				tv, ok := t[k]
+				if !ok {
+					*ops = append(*ops, Operation{Op: "remove", Path: p})
+					continue
+				}
+				if err := diff(ops, p, o[k], tv); err != nil {
+					return err
+				}
+			}
+			for _, k := range slices.Sorted(maps.Keys(t)) {
+				if _, ok := o[k]; ok {
+					continue
+				}
+				v, err := encode(t[k])
+				if err != nil {
+					return err
+				}
+				*ops = append(*ops, Operation{Op: "add", Path: path + "/" + escape(k), Value: v})
+			}
+			return nil
+		}
+	case []any:
+		if t, ok := t.([]any); ok && len(o) == len(t) {
+			for i := range o {
+				if err := diff(ops, path+"/"+strconv.Itoa(i), o[i], t[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	v, err := encode(t)
+	if err != nil {
+		return err
+	}
+	*ops = append(*ops, Operation{Op: "replace", Path: path, Value: v})
+	return nil
+}
+
+// Apply applies JSON Patch operations to doc in order, and returns the result.
+//
+// If an operation fails (including "test"), it returns error and doc is not changed.
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	d, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document: %w", err)
+	}
+	for i, op := range ops {
+		d, err = applyOp(d, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation[%d] (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return encode(d)
+}
+
+func applyOp(doc any, op Operation) (any, error) {
+	value := func() (any, error) {
+		if op.Value == nil {
+			return nil, fmt.Errorf(`required field missing: "value"`)
+		}
+		return decode(op.Value)
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "remove":
+		d, _, err := remove(doc, op.Path)
+		return d, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		d, _, err := remove(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return add(d, op.Path, v)
+	case "move":
+		if op.Path == op.From || strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move into itself: %q", op.From)
+		}
+		d, v, err := remove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(d, op.Path, v)
+	case "copy":
+		v, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, op.Path, v)
+	case "test":
+		want, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(got, want) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op: %q", op.Op)
+}
+
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("malformed pointer: %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func index(token string, length int, appendable bool) (int, error) {
+	if appendable && token == "-" {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("malformed index: %q", token)
+	}
+	max := length - 1
+	if appendable {
+		max = length
+	}
+	if max < i {
+		return 0, fmt.Errorf("index out of range: %d", i)
+	}
+	return i, nil
+}
+
+func get(doc any, pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]any:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("not found: %q", pointer)
+			}
+			cur = v
+		case []any:
+			i, err := index(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c[i]
+		default:
+			return nil, fmt.Errorf("not found: %q", pointer)
+		}
+	}
+	return cur, nil
+}
+
+// update replaces the value at tokens with f(value), copying containers on the way.
+func update(doc any, tokens []string, f func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return f(doc, tokens[0])
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		child, ok := c[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("not found: %q", tokens[0])
+		}
+		v, err := update(child, tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		c = maps.Clone(c)
+		c[tokens[0]] = v
+		return c, nil
+	case []any:
+		i, err := index(tokens[0], len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		v, err := update(c[i], tokens[1:], f)
+		if err != nil {
+			return nil, err
+		}
+		c = slices.Clone(c)
+		c[i] = v
+		return c, nil
+	}
+	return nil, fmt.Errorf("not a container at %q", tokens[0])
+}
+
+func add(doc any, pointer string, value any) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p = maps.Clone(p)
+			p[last] = value
+			return p, nil
+		case []any:
+			i, err := index(last, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(slices.Clone(p), i, value), nil
+		}
+		return nil, fmt.Errorf("not a container: %q", pointer)
+	})
+}
+
+func remove(doc any, pointer string) (any, any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	var removed any
+	d, err := update(doc, tokens, func(parent any, last string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			v, ok := p[last]
+			if !ok {
+				return nil, fmt.Errorf("not found: %q", pointer)
+			}
+			removed = v
+			p = maps.Clone(p)
+			delete(p, last)
+			return p, nil
+		case []any:
+			i, err := index(last, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = p[i]
+			return slices.Delete(slices.Clone(p), i, i+1), nil
+		}
+		return nil, fmt.Errorf("not a container: %q", pointer)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, removed, nil
+}
+
+// MergePatchOf returns the JSON Merge Patch which makes from to to, in their JSON representation.
+func MergePatchOf[T any](from, to T) ([]byte, error) {
+	a, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+	return CreateMergePatch(a, b)
+}
+
+// PatchOf returns JSON Patch operations which make from to to, in their JSON representation.
+func PatchOf[T any](from, to T) ([]Operation, error) {
+	a, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+	return CreatePatch(a, b)
+}
+
+// ApplyMergePatchTo applies the JSON Merge Patch to v in its JSON representation, and returns the result.
+func ApplyMergePatchTo[T any](v T, patch []byte) (T, error) {
+	return applyTo(v, func(doc []byte) ([]byte, error) { return ApplyMergePatch(doc, patch) })
+}
+
+// ApplyTo applies JSON Patch operations to v in its JSON representation, and returns the result.
+func ApplyTo[T any](v T, ops []Operation) (T, error) {
+	return applyTo(v, func(doc []byte) ([]byte, error) { return Apply(doc, ops) })
+}
+
+func applyTo[T any](v T, f func([]byte) ([]byte, error)) (T, error) {
+	var zero T
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return zero, err
+	}
+	patched, err := f(doc)
+	if err != nil {
+		return zero, err
+	}
+	var ret T
+	if err := json.Unmarshal(patched, &ret); err != nil {
+		return zero, err
+	}
+	return ret, nil
+}
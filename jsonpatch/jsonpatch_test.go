@@ -0,0 +1,172 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/jsonpatch"
+)
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("got %s, want %s", gb, wb)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// the example in RFC 7386, section 3.
+	original := `{
+		"title": "Goodbye!",
+		"author": {"givenName": "John", "familyName": "Doe"},
+		"tags": ["example", "sample"],
+		"content": "This will be unchanged"
+	}`
+	target := `{
+		"title": "Hello!",
+		"author": {"givenName": "John"},
+		"tags": ["example"],
+		"content": "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890"
+	}`
+
+	patch, err := jsonpatch.CreateMergePatch([]byte(original), []byte(target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, patch, `{
+		"title": "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null},
+		"tags": ["example"]
+	}`)
+
+	got, err := jsonpatch.ApplyMergePatch([]byte(original), patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, got, target)
+
+	// patches which are not objects replace the document.
+	got, err = jsonpatch.ApplyMergePatch([]byte(`{"a":"b"}`), []byte(`["c"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, got, `["c"]`)
+
+	if _, err := jsonpatch.ApplyMergePatch([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Error("malformed document is accepted")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	original := `{"a/b": 1, "list": [1, 2, 3], "obj": {"x": "y", "z": 0}, "short": [1]}`
+	target := `{"a/b": 2, "list": [1, 4, 3], "obj": {"x": "y"}, "short": [1, 2], "new~": null}`
+
+	ops, err := jsonpatch.CreatePatch([]byte(original), []byte(target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, b, `[
+		{"op": "replace", "path": "/a~1b", "value": 2},
+		{"op": "replace", "path": "/list/1", "value": 4},
+		{"op": "remove", "path": "/obj/z"},
+		{"op": "replace", "path": "/short", "value": [1, 2]},
+		{"op": "add", "path": "/new~0", "value": null}
+	]`)
+
+	got, err := jsonpatch.Apply([]byte(original), ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, got, target)
+}
+
+func TestApply(t *testing.T) {
+	doc := `{"foo": ["bar", "baz"], "obj": {"k": "v"}}`
+
+	for name, testcase := range map[string]struct {
+		ops  string
+		want string // empty if error
+	}{
+		"add to array": {
+			ops:  `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want: `{"foo": ["bar", "qux", "baz"], "obj": {"k": "v"}}`,
+		},
+		"append to array": {
+			ops:  `[{"op": "add", "path": "/foo/-", "value": "qux"}]`,
+			want: `{"foo": ["bar", "baz", "qux"], "obj": {"k": "v"}}`,
+		},
+		"remove": {
+			ops:  `[{"op": "remove", "path": "/foo/0"}]`,
+			want: `{"foo": ["baz"], "obj": {"k": "v"}}`,
+		},
+		"move": {
+			ops:  `[{"op": "move", "from": "/obj/k", "path": "/k"}]`,
+			want: `{"foo": ["bar", "baz"], "obj": {}, "k": "v"}`,
+		},
+		"copy": {
+			ops:  `[{"op": "copy", "from": "/foo/1", "path": "/obj/k2"}]`,
+			want: `{"foo": ["bar", "baz"], "obj": {"k": "v", "k2": "baz"}}`,
+		},
+		"test and replace": {
+			ops: `[
+				{"op": "test", "path": "/obj", "value": {"k": "v"}},
+				{"op": "replace", "path": "", "value": 1}
+			]`,
+			want: `1`,
+		},
+		"failed test": {
+			ops: `[{"op": "test", "path": "/foo/0", "value": "baz"}]`,
+		},
+		"remove missing": {
+			ops: `[{"op": "remove", "path": "/missing"}]`,
+		},
+		"index out of range": {
+			ops: `[{"op": "add", "path": "/foo/3", "value": 1}]`,
+		},
+		"leading zero": {
+			ops: `[{"op": "remove", "path": "/foo/01"}]`,
+		},
+		"move into itself": {
+			ops: `[{"op": "move", "from": "/obj", "path": "/obj/k"}]`,
+		},
+		"missing value": {
+			ops: `[{"op": "add", "path": "/x"}]`,
+		},
+		"unknown op": {
+			ops: `[{"op": "merge", "path": "/x", "value": 1}]`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ops := []jsonpatch.Operation{}
+			if err := json.Unmarshal([]byte(testcase.ops), &ops); err != nil {
+				t.Fatal(err)
+			}
+			got, err := jsonpatch.Apply([]byte(doc), ops)
+			if testcase.want == "" {
+				if err == nil {
+					t.Errorf("expected error, but got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, got, testcase.want)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Package knitid defines the identifier of Data.
+package knitid
+
+import (
+	"github.com/opst/knitfab-api-types/internal/ids"
+	"gopkg.in/yaml.v3"
+)
+
+// KnitId identifies Data.
+//
+// It is a UUID, and is also attached to the Data as the system tag "knit#id".
+type KnitId string
+
+// ParseKnitId parses s as KnitId.
+func ParseKnitId(s string) (KnitId, error) {
+	if err := ids.Validate("KnitId", s); err != nil {
+		return "", err
+	}
+	return KnitId(s), nil
+}
+
+func (id KnitId) String() string {
+	return string(id)
+}
+
+// IsZero returns true if id is empty.
+func (id KnitId) IsZero() bool {
+	return id == ""
+}
+
+// Parse parses s as KnitId, and sets it to id.
+func (id *KnitId) Parse(s string) error {
+	parsed, err := ParseKnitId(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id *KnitId) UnmarshalJSON(b []byte) error {
+	s, err := ids.UnmarshalJSON("KnitId", b)
+	if err != nil {
+		return err
+	}
+	*id = KnitId(s)
+	return nil
+}
+
+func (id *KnitId) UnmarshalYAML(node *yaml.Node) error {
+	s, err := ids.UnmarshalYAML("KnitId", node)
+	if err != nil {
+		return err
+	}
+	*id = KnitId(s)
+	return nil
+}
@@ -0,0 +1,77 @@
+package knitid_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"gopkg.in/yaml.v3"
+)
+
+func TestKnitId(t *testing.T) {
+	const id = "0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b"
+
+	t.Run("parse", func(t *testing.T) {
+		got, err := knitid.ParseKnitId(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != id || got.IsZero() {
+			t.Errorf("unexpected KnitId: %q", got)
+		}
+
+		for _, expr := range []string{
+			"knit-1",
+			"0190a1b2c3d47e5f8a9b0c1d2e3f4a5b",
+			"0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5",
+			"0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5g",
+			"0190a1b2_c3d4_7e5f_8a9b_0c1d2e3f4a5b",
+		} {
+			if _, err := knitid.ParseKnitId(expr); err == nil {
+				t.Errorf("malformed KnitId is accepted: %q", expr)
+			}
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		var got knitid.KnitId
+		if err := got.Parse(""); err != nil {
+			t.Fatal(err)
+		}
+		if !got.IsZero() {
+			t.Errorf("unexpected KnitId: %q", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var got knitid.KnitId
+		if err := json.Unmarshal([]byte(`"`+id+`"`), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Errorf("unexpected KnitId: %q", got)
+		}
+		if b, err := json.Marshal(got); err != nil {
+			t.Fatal(err)
+		} else if string(b) != `"`+id+`"` {
+			t.Errorf("unexpected JSON: %s", b)
+		}
+
+		if err := json.Unmarshal([]byte(`"knit-1"`), &got); err == nil {
+			t.Errorf("malformed KnitId is accepted: %q", got)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var got knitid.KnitId
+		if err := yaml.Unmarshal([]byte(id), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != id {
+			t.Errorf("unexpected KnitId: %q", got)
+		}
+		if err := yaml.Unmarshal([]byte(`knit-1`), &got); err == nil {
+			t.Errorf("malformed KnitId is accepted: %q", got)
+		}
+	})
+}
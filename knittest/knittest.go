@@ -0,0 +1,81 @@
+// Package knittest provides test helpers for packages using types of this module.
+package knittest
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Equaler is a type which can be compared with Equal method.
+type Equaler[T any] interface {
+	Equal(T) bool
+}
+
+// AssertRoundTrip asserts that value survives round trips of both JSON and YAML.
+//
+// See AssertJSONRoundTrip and AssertYAMLRoundTrip for details.
+func AssertRoundTrip[T Equaler[T]](t testing.TB, value T) {
+	t.Helper()
+	AssertJSONRoundTrip(t, value)
+	AssertYAMLRoundTrip(t, value)
+}
+
+// AssertJSONRoundTrip asserts that:
+//
+// - value can be marshalled into JSON and unmarshalled again,
+//
+// - the unmarshalled value is Equal to value, and
+//
+// - the unmarshalled value is marshalled into the same JSON (canonical form).
+func AssertJSONRoundTrip[T Equaler[T]](t testing.TB, value T) {
+	t.Helper()
+	roundTrip(t, "JSON", value, json.Marshal, json.Unmarshal)
+}
+
+// AssertYAMLRoundTrip asserts the same things as AssertJSONRoundTrip, but for YAML.
+func AssertYAMLRoundTrip[T Equaler[T]](t testing.TB, value T) {
+	t.Helper()
+	roundTrip(t, "YAML", value, yaml.Marshal, yaml.Unmarshal)
+}
+
+func roundTrip[T Equaler[T]](
+	t testing.TB, format string, value T,
+	marshal func(any) ([]byte, error),
+	unmarshal func([]byte, any) error,
+) {
+	t.Helper()
+
+	encoded, err := marshal(value)
+	if err != nil {
+		t.Errorf("%s: failed to marshal %+v: %v", format, value, err)
+		return
+	}
+
+	var decoded T
+	if err := unmarshal(encoded, &decoded); err != nil {
+		t.Errorf("%s: failed to unmarshal %s: %v", format, encoded, err)
+		return
+	}
+
+	if !decoded.Equal(value) {
+		t.Errorf(
+			"%s: round trip changes the value:\n=== original ===\n%+v\n=== encoded ===\n%s\n=== decoded ===\n%+v",
+			format, value, encoded, decoded,
+		)
+	}
+
+	reencoded, err := marshal(decoded)
+	if err != nil {
+		t.Errorf("%s: failed to marshal decoded value %+v: %v", format, decoded, err)
+		return
+	}
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf(
+			"%s: encoding is not canonical:\n=== first ===\n%s\n=== second ===\n%s",
+			format, encoded, reencoded,
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package knittest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestAssertRoundTrip(t *testing.T) {
+	active := false
+	knittest.AssertRoundTrip(t, plans.PlanSpec{
+		Annotations: plans.Annotations{{Key: "owner", Value: "team-a"}},
+		Image:       plans.Image{Repository: "registry.invalid/trainer", Tag: "v1"},
+		Inputs: []plans.Mountpoint{
+			{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}},
+		},
+		Outputs: []plans.Mountpoint{
+			{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+		},
+		Log:       &plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}},
+		OnNode:    &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "gpu", Value: "true"}}},
+		Resources: plans.Resources{"cpu": resource.MustParse("500m")},
+		Active:    &active,
+	})
+
+	knittest.AssertRoundTrip(t, tags.Tag{Key: "project", Value: "example"})
+}
+
+// lossy loses Dropped field on marshalling.
+type lossy struct {
+	Kept    string `json:"kept" yaml:"kept"`
+	Dropped string `json:"-" yaml:"-"`
+}
+
+func (l lossy) Equal(o lossy) bool {
+	return l.Kept == o.Kept && l.Dropped == o.Dropped
+}
+
+// recorder is testing.TB recording failures instead of failing.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertRoundTrip_detectsLoss(t *testing.T) {
+	r := &recorder{TB: t}
+	knittest.AssertRoundTrip(r, lossy{Kept: "a", Dropped: "b"})
+	if len(r.errors) != 2 {
+		t.Errorf("loss should be detected in both JSON and YAML: %v", r.errors)
+	}
+}
@@ -0,0 +1,245 @@
+// Package manifest reads and writes manifests: multi-document YAML streams of Knitfab resources.
+//
+// Each document is an envelope with "apiVersion" and "kind", holding the resource in "spec":
+//
+//	apiVersion: knitfab/v1
+//	kind: Plan
+//	spec:
+//	  image: "example.com/train:v1"
+//	  inputs:
+//	    - path: /in/dataset
+//	      tags: ["type:dataset"]
+//	---
+//	apiVersion: knitfab/v1
+//	kind: Plan
+//	spec:
+//	  ...
+//
+// Manifests are the canonical on-disk format to keep pipelines in version control.
+// Errors in a manifest are reported with the position of the document.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/strict"
+	"gopkg.in/yaml.v3"
+)
+
+// APIVersion is the version of manifests supported by this package.
+const APIVersion = meta.CurrentAPIVersion
+
+// Kinds of resources in manifests.
+//
+// They are same as kinds of meta.TypeMeta for the same types.
+const (
+	// KindPlan is the kind of documents holding plans.PlanSpec.
+	KindPlan = plans.KindPlanSpec
+)
+
+// Position is a position in the manifest. Line and Column are 1-origin.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Document is a document in a manifest.
+type Document struct {
+	// APIVersion is the version of the document.
+	APIVersion string
+
+	// Kind is the kind of Object, like "Plan".
+	Kind string
+
+	// Object is the resource in the document.
+	//
+	// Its type is decided by Kind: plans.PlanSpec for KindPlan.
+	Object any
+
+	// Index is the 0-origin index of the document in the manifest.
+	Index int
+
+	// Position is the position where the document starts.
+	Position Position
+}
+
+// DocumentError is the error found in a document of a manifest.
+type DocumentError struct {
+	// Index is the 0-origin index of the document in the manifest.
+	Index int
+
+	// Position is the position where the problem is found.
+	Position Position
+
+	Err error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document[%d] (at %s): %s", e.Index, e.Position, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+type envelope struct {
+	APIVersion string    `yaml:"apiVersion"`
+	Kind       string    `yaml:"kind"`
+	Spec       yaml.Node `yaml:"spec"`
+}
+
+// kinds are decoders of resources by kinds.
+var kinds = map[string]func(spec []byte) (any, error){
+	KindPlan: func(spec []byte) (any, error) {
+		ps, err := strict.YAML[plans.PlanSpec](spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := ps.Validate(); err != nil {
+			return nil, err
+		}
+		return ps, nil
+	},
+}
+
+// Read reads all documents in the manifest.
+//
+// Empty documents are skipped, but they are counted in Index.
+//
+// It returns *DocumentError for the first broken document:
+// with unknown apiVersion or kind, with unknown fields, or with invalid resource.
+func Read(r io.Reader) ([]Document, error) {
+	dec := yaml.NewDecoder(r)
+	docs := []Document{}
+	for index := 0; ; index++ {
+		node := yaml.Node{}
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, &DocumentError{Index: index, Err: err}
+		}
+		if node.Kind == yaml.DocumentNode && len(node.Content) == 1 {
+			node = *node.Content[0]
+		}
+		if node.Kind == 0 || (node.Kind == yaml.ScalarNode && node.Tag == "!!null") {
+			continue
+		}
+		doc, err := read(node)
+		if err != nil {
+			err.Index = index
+			return nil, err
+		}
+		doc.Index = index
+		docs = append(docs, doc)
+	}
+}
+
+func read(node yaml.Node) (Document, *DocumentError) {
+	pos := Position{Line: node.Line, Column: node.Column}
+	fail := func(p Position, format string, args ...any) (Document, *DocumentError) {
+		return Document{}, &DocumentError{Position: p, Err: fmt.Errorf(format, args...)}
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return fail(pos, "document should be a mapping")
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch k := node.Content[i]; k.Value {
+		case "apiVersion", "kind", "spec":
+		default:
+			return fail(Position{Line: k.Line, Column: k.Column}, "unknown field %q", k.Value)
+		}
+	}
+
+	env := envelope{}
+	if err := node.Decode(&env); err != nil {
+		return fail(pos, "%w", err)
+	}
+	if env.APIVersion != APIVersion {
+		return fail(pos, `"apiVersion" should be "%s": %q`, APIVersion, env.APIVersion)
+	}
+	decode, ok := kinds[env.Kind]
+	if !ok {
+		return fail(pos, "unknown kind: %q", env.Kind)
+	}
+	if env.Spec.Kind == 0 {
+		return fail(pos, `required field missing: "spec"`)
+	}
+
+	specPos := Position{Line: env.Spec.Line, Column: env.Spec.Column}
+	spec, err := yaml.Marshal(&env.Spec)
+	if err != nil {
+		return fail(specPos, "spec: %w", err)
+	}
+	obj, err := decode(spec)
+	if err != nil {
+		return fail(specPos, "spec: %w", err)
+	}
+
+	return Document{
+		APIVersion: env.APIVersion,
+		Kind:       env.Kind,
+		Object:     obj,
+		Position:   pos,
+	}, nil
+}
+
+// ReadPlans reads the manifest, and returns PlanSpecs in it.
+//
+// Documents of other kinds are ignored.
+func ReadPlans(r io.Reader) ([]plans.PlanSpec, error) {
+	docs, err := Read(r)
+	if err != nil {
+		return nil, err
+	}
+	specs := []plans.PlanSpec{}
+	for _, d := range docs {
+		if ps, ok := d.Object.(plans.PlanSpec); ok {
+			specs = append(specs, ps)
+		}
+	}
+	return specs, nil
+}
+
+// KindOf returns the kind of the object in manifests.
+//
+// If obj cannot be in manifests, it returns false.
+func KindOf(obj any) (string, bool) {
+	switch obj.(type) {
+	case plans.PlanSpec, *plans.PlanSpec:
+		return KindPlan, true
+	}
+	return "", false
+}
+
+// Write writes objects as a manifest, each in a document.
+//
+// Objects should be ones KindOf knows.
+func Write(w io.Writer, objects ...any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for i, obj := range objects {
+		kind, ok := KindOf(obj)
+		if !ok {
+			return fmt.Errorf("objects[%d]: unsupported type: %T", i, obj)
+		}
+		doc := struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Spec       any    `yaml:"spec"`
+		}{APIVersion: APIVersion, Kind: kind, Spec: obj}
+		if err := enc.Encode(doc); err != nil {
+			return fmt.Errorf("objects[%d]: %w", i, err)
+		}
+	}
+	return enc.Close()
+}
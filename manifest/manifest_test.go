@@ -0,0 +1,204 @@
+package manifest_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/manifest"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/strict"
+)
+
+func TestRead(t *testing.T) {
+	src := `
+apiVersion: knitfab/v1
+kind: Plan
+spec:
+  image: "example.com/train:v1"
+  inputs:
+    - path: /in/dataset
+      tags: ["type:dataset"]
+  outputs:
+    - path: /out/model
+      tags: ["type:model"]
+---
+# empty document
+---
+apiVersion: knitfab/v1
+kind: Plan
+spec:
+  image: "example.com/eval:v1"
+  inputs:
+    - path: /in/model
+      tags: ["type:model"]
+`
+	docs, err := manifest.Read(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("unexpected documents: %+v", docs)
+	}
+
+	train := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/dataset", "type:dataset").
+		AddOutput("/out/model", "type:model").
+		MustBuild()
+	if got, ok := docs[0].Object.(plans.PlanSpec); !ok || !got.Equal(train) {
+		t.Errorf("documents[0]: unexpected object: %+v", docs[0].Object)
+	}
+	if docs[0].Index != 0 || docs[0].Position != (manifest.Position{Line: 2, Column: 1}) || docs[0].Kind != manifest.KindPlan {
+		t.Errorf("documents[0]: unexpected document: %+v", docs[0])
+	}
+	if docs[1].Index != 2 || docs[1].Position != (manifest.Position{Line: 15, Column: 1}) {
+		t.Errorf("documents[1]: unexpected document: %+v", docs[1])
+	}
+
+	specs, err := manifest.ReadPlans(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 2 || !specs[0].Equal(train) {
+		t.Errorf("unexpected specs: %+v", specs)
+	}
+}
+
+func TestRead_error(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		src   string
+		index int
+		line  int
+	}{
+		"unknown apiVersion": {
+			src: `
+apiVersion: knitfab/v9
+kind: Plan
+spec: {}
+`,
+			index: 0, line: 2,
+		},
+		"unknown kind": {
+			src: `
+apiVersion: knitfab/v1
+kind: Plan
+spec:
+  image: "example.com/train:v1"
+  inputs: [{path: /in, tags: ["type:a"]}]
+---
+apiVersion: knitfab/v1
+kind: Pipeline
+spec: {}
+`,
+			index: 1, line: 8,
+		},
+		"unknown envelope field": {
+			src: `
+apiVersion: knitfab/v1
+kind: Plan
+metadata: {}
+spec: {}
+`,
+			index: 0, line: 4,
+		},
+		"missing spec": {
+			src: `
+apiVersion: knitfab/v1
+kind: Plan
+`,
+			index: 0, line: 2,
+		},
+		"invalid spec": {
+			src: `
+apiVersion: knitfab/v1
+kind: Plan
+spec:
+  image: "example.com/train:v1"
+`,
+			index: 0, line: 5,
+		},
+		"not a mapping": {
+			src:   `[]`,
+			index: 0, line: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := manifest.Read(strings.NewReader(testcase.src))
+			derr := &manifest.DocumentError{}
+			if !errors.As(err, &derr) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if derr.Index != testcase.index || derr.Position.Line != testcase.line {
+				t.Errorf("unexpected error: %v", derr)
+			}
+		})
+	}
+
+	t.Run("unknown spec field", func(t *testing.T) {
+		_, err := manifest.Read(strings.NewReader(`
+apiVersion: knitfab/v1
+kind: Plan
+spec:
+  image: "example.com/train:v1"
+  inputs: [{pth: /in}]
+`))
+		if uf := (strict.UnknownFieldError{}); !errors.As(err, &uf) || uf.Field != "pth" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	train := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/dataset", "type:dataset").
+		AddOutput("/out/model", "type:model").
+		MustBuild()
+	eval := plans.NewSpec().
+		WithImage("example.com/eval:v1").
+		AddInput("/in/model", "type:model").
+		MustBuild()
+
+	buf := &bytes.Buffer{}
+	if err := manifest.Write(buf, train, &eval); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "apiVersion: knitfab/v1\nkind: Plan\nspec:\n") {
+		t.Errorf("unexpected manifest:\n%s", buf)
+	}
+
+	specs, err := manifest.ReadPlans(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 2 || !specs[0].Equal(train) || !specs[1].Equal(eval) {
+		t.Errorf("unexpected specs: %+v", specs)
+	}
+
+	if err := manifest.Write(&bytes.Buffer{}, "not a resource"); err == nil {
+		t.Error("unsupported object is written")
+	}
+}
+
+func TestKindOf_sameAsTypeMeta(t *testing.T) {
+	scheme := meta.NewScheme()
+	if err := plans.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	ps := plans.NewSpec().WithImage("example.com/train:v1").AddInput("/in", "type:dataset").MustBuild()
+	kind, ok := manifest.KindOf(ps)
+	if !ok {
+		t.Fatal("PlanSpec is not in manifests")
+	}
+	tm, ok := scheme.KindOf(ps)
+	if !ok {
+		t.Fatal("PlanSpec is not in the Scheme")
+	}
+	if tm.Kind != kind || tm.APIVersion != manifest.APIVersion {
+		t.Errorf("manifest: %s %s, TypeMeta: %s", manifest.APIVersion, kind, tm)
+	}
+}
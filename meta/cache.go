@@ -0,0 +1,254 @@
+package meta
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// HTTP header names for caching and conditional requests.
+const (
+	HeaderLastModified    = "Last-Modified"
+	HeaderCacheControl    = "Cache-Control"
+	HeaderETag            = "ETag"
+	HeaderIfModifiedSince = "If-Modified-Since"
+	HeaderIfNoneMatch     = "If-None-Match"
+)
+
+// CacheInfo is caching metadata of a response.
+type CacheInfo struct {
+	// LastModified is the time when the resource was modified at last.
+	LastModified *rfctime.RFC3339 `json:"lastModified,omitempty"`
+
+	// ETag is the entity tag of the resource.
+	ETag string `json:"etag,omitempty"`
+
+	// MaxAge is how long the response can be reused without revalidation, in seconds.
+	//
+	// If nil, the response should be revalidated always.
+	MaxAge *int `json:"maxAge,omitempty"`
+
+	// NoStore means that the response should not be cached.
+	NoStore bool `json:"noStore,omitempty"`
+}
+
+func (c CacheInfo) Equal(o CacheInfo) bool {
+	return apicmp.PtrEqual(c.LastModified, o.LastModified) &&
+		apicmp.PtrEqEq(c.MaxAge, o.MaxAge) &&
+		c.ETag == o.ETag && c.NoStore == o.NoStore
+}
+
+// IsZero returns true if CacheInfo has no information.
+func (c CacheInfo) IsZero() bool {
+	return c.LastModified == nil && c.ETag == "" && c.MaxAge == nil && !c.NoStore
+}
+
+// Fresh returns true if the response fetched at fetchedAt can be reused at now
+// without revalidation.
+func (c CacheInfo) Fresh(fetchedAt, now time.Time) bool {
+	if c.NoStore || c.MaxAge == nil {
+		return false
+	}
+	return now.Before(fetchedAt.Add(time.Duration(*c.MaxAge) * time.Second))
+}
+
+// WriteHeader writes CacheInfo into HTTP response header h.
+func (c CacheInfo) WriteHeader(h http.Header) {
+	if c.LastModified != nil {
+		h.Set(HeaderLastModified, c.LastModified.Time().UTC().Format(http.TimeFormat))
+	}
+	if c.ETag != "" {
+		h.Set(HeaderETag, c.ETag)
+	}
+	directives := []string{}
+	if c.NoStore {
+		directives = append(directives, "no-store")
+	}
+	if c.MaxAge != nil {
+		directives = append(directives, "max-age="+strconv.Itoa(*c.MaxAge))
+	}
+	if 0 < len(directives) {
+		h.Set(HeaderCacheControl, strings.Join(directives, ", "))
+	}
+}
+
+// SetConditional writes conditional request headers into HTTP request header h,
+// to revalidate the response having this CacheInfo.
+func (c CacheInfo) SetConditional(h http.Header) {
+	if c.LastModified != nil {
+		h.Set(HeaderIfModifiedSince, c.LastModified.Time().UTC().Format(http.TimeFormat))
+	}
+	if c.ETag != "" {
+		h.Set(HeaderIfNoneMatch, c.ETag)
+	}
+}
+
+// NotModified returns true if the request with HTTP header h can be responded
+// with "304 Not Modified" for the resource having this CacheInfo.
+func (c CacheInfo) NotModified(h http.Header) bool {
+	if inm := h.Get(HeaderIfNoneMatch); inm != "" {
+		return c.ETag != "" && inm == c.ETag
+	}
+	ims := h.Get(HeaderIfModifiedSince)
+	if ims == "" || c.LastModified == nil {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP date has no sub-second part.
+	return !c.LastModified.Time().Truncate(time.Second).After(t)
+}
+
+// CaptureCacheInfo reads CacheInfo from HTTP response header h.
+//
+// Malformed values are ignored.
+func CaptureCacheInfo(h http.Header) CacheInfo {
+	c := CacheInfo{ETag: h.Get(HeaderETag)}
+	if lm := h.Get(HeaderLastModified); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			r := rfctime.RFC3339(t)
+			c.LastModified = &r
+		}
+	}
+	for _, d := range strings.Split(h.Get(HeaderCacheControl), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(d), "=")
+		switch strings.ToLower(k) {
+		case "no-store":
+			c.NoStore = true
+		case "max-age":
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxAge = &n
+			}
+		}
+	}
+	return c
+}
+
+// Cache is a small client-side cache of GET responses.
+//
+// It reuses fresh responses, and revalidates stale responses with
+// If-Modified-Since and If-None-Match.
+//
+// Responses are cached per URL and per Authorization and Accept headers of the request,
+// so a response is never reused for other credentials or other representations.
+//
+// Cache is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	now     func() time.Time
+}
+
+type cacheKey struct {
+	url           string
+	authorization string
+	accept        string
+}
+
+func cacheKeyOf(req *http.Request) cacheKey {
+	return cacheKey{
+		url:           req.URL.String(),
+		authorization: req.Header.Get("Authorization"),
+		accept:        req.Header.Get("Accept"),
+	}
+}
+
+type cacheEntry struct {
+	info      CacheInfo
+	fetchedAt time.Time
+	status    int
+	header    http.Header
+	body      []byte
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{entries: map[cacheKey]cacheEntry{}, now: time.Now}
+}
+
+// Do sends the request with the client, using cached responses when possible.
+//
+// Requests other than GET are sent as they are.
+// When the server responds "304 Not Modified", Do returns the cached response.
+func (c *Cache) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return client.Do(req)
+	}
+	key := cacheKeyOf(req)
+
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	c.mu.Unlock()
+
+	if cached {
+		if entry.info.Fresh(entry.fetchedAt, c.now()) {
+			return entry.response(req), nil
+		}
+		req = req.Clone(req.Context())
+		entry.info.SetConditional(req.Header)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry.fetchedAt = c.now()
+		if info := CaptureCacheInfo(resp.Header); !info.IsZero() {
+			entry.info = info
+		}
+		c.mu.Lock()
+		c.entries[key] = entry
+		c.mu.Unlock()
+		return entry.response(req), nil
+	}
+
+	info := CaptureCacheInfo(resp.Header)
+	if resp.StatusCode != http.StatusOK || info.NoStore ||
+		(info.LastModified == nil && info.ETag == "" && info.MaxAge == nil) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{
+		info:      info,
+		fetchedAt: c.now(),
+		status:    resp.StatusCode,
+		header:    resp.Header.Clone(),
+		body:      body,
+	}
+	c.mu.Unlock()
+	return resp, nil
+}
+
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,131 @@
+package meta_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+func TestCacheInfo_header(t *testing.T) {
+	lm := rfctime.RFC3339(time.Date(2024, 10, 11, 12, 13, 14, 0, time.UTC))
+	maxAge := 30
+	info := meta.CacheInfo{LastModified: &lm, ETag: `"v1"`, MaxAge: &maxAge}
+
+	h := http.Header{}
+	info.WriteHeader(h)
+	got := meta.CaptureCacheInfo(h)
+	if !got.Equal(info) {
+		t.Errorf("unexpected result: %+v", got)
+	}
+
+	req := http.Header{}
+	info.SetConditional(req)
+	if !info.NotModified(req) {
+		t.Errorf("conditional request should be not modified: %+v", req)
+	}
+
+	newer := rfctime.RFC3339(lm.Time().Add(time.Minute))
+	if (meta.CacheInfo{LastModified: &newer}).NotModified(http.Header{
+		meta.HeaderIfModifiedSince: req.Values(meta.HeaderIfModifiedSince),
+	}) {
+		t.Error("modified resource should not be not modified")
+	}
+}
+
+func TestCache_Do(t *testing.T) {
+	lm := time.Date(2024, 10, 11, 12, 13, 14, 0, time.UTC)
+	requests, notModified := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		info := meta.CacheInfo{LastModified: (*rfctime.RFC3339)(&lm)}
+		info.WriteHeader(w.Header())
+		if info.NotModified(r.Header) {
+			notModified += 1
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"planId":"p1"}`))
+	}))
+	defer srv.Close()
+
+	cache := meta.NewCache()
+	for i := range 3 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/plans/p1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := cache.Do(srv.Client(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || string(body) != `{"planId":"p1"}` {
+			t.Errorf("#%d: unexpected response: %d %s", i, resp.StatusCode, body)
+		}
+	}
+
+	if requests != 3 || notModified != 2 {
+		t.Errorf("unexpected requests: total = %d, not modified = %d", requests, notModified)
+	}
+}
+
+func TestCache_Do_perCredential(t *testing.T) {
+	maxAge := 60
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		info := meta.CacheInfo{ETag: `"v1"`, MaxAge: &maxAge}
+		info.WriteHeader(w.Header())
+		w.Write([]byte(r.Header.Get("Authorization") + ";" + r.Header.Get("Accept")))
+	}))
+	defer srv.Close()
+
+	cache := meta.NewCache()
+	get := func(authorization, accept string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/plans/p1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", authorization)
+		req.Header.Set("Accept", accept)
+		resp, err := cache.Do(srv.Client(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	for _, testcase := range []struct {
+		authorization, accept string
+		want                  string
+		wantRequests          int
+	}{
+		{"Bearer alice", "application/json", "Bearer alice;application/json", 1},
+		{"Bearer bob", "application/json", "Bearer bob;application/json", 2},
+		{"Bearer alice", "application/yaml", "Bearer alice;application/yaml", 3},
+		{"Bearer alice", "application/json", "Bearer alice;application/json", 3}, // fresh, cached
+		{"Bearer bob", "application/json", "Bearer bob;application/json", 3},     // fresh, cached
+	} {
+		if got := get(testcase.authorization, testcase.accept); got != testcase.want {
+			t.Errorf("%s, %s: got %q, want %q", testcase.authorization, testcase.accept, got, testcase.want)
+		}
+		if requests != testcase.wantRequests {
+			t.Errorf("%s, %s: requests = %d, want %d", testcase.authorization, testcase.accept, requests, testcase.wantRequests)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// HeaderDeprecation is the HTTP response header name which carries Deprecation.
+//
+// Each value of the header is a JSON expression of a Deprecation.
+// A response can have multiple values of the header.
+const HeaderDeprecation = "X-Knitfab-Deprecation"
+
+// DeprecationKind is the kind of deprecated item.
+type DeprecationKind string
+
+const (
+	// DeprecatedEndpoint means that the WebAPI endpoint is deprecated.
+	DeprecatedEndpoint DeprecationKind = "endpoint"
+
+	// DeprecatedField means that a field of request or response body is deprecated.
+	DeprecatedField DeprecationKind = "field"
+
+	// DeprecatedParameter means that a query parameter is deprecated.
+	DeprecatedParameter DeprecationKind = "parameter"
+)
+
+// Deprecation is a notice that something in WebAPI is going to be removed.
+type Deprecation struct {
+	// Kind is the kind of the deprecated item.
+	Kind DeprecationKind `json:"kind" yaml:"kind"`
+
+	// Target is the deprecated item.
+	//
+	// For endpoint, it is like "GET /api/plans/{planId}".
+	// For field, it is a path in the body, like "plans.Detail.name".
+	// For parameter, it is a name of query parameter.
+	Target string `json:"target" yaml:"target"`
+
+	// Sunset is the time when the item is removed.
+	//
+	// If nil, it is not scheduled yet.
+	Sunset *rfctime.RFC3339 `json:"sunset,omitempty" yaml:"sunset,omitempty"`
+
+	// Replacement is the item which should be used instead.
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+
+	// Message is the human readable description of the deprecation.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (d Deprecation) Equal(o Deprecation) bool {
+	return d.Kind == o.Kind &&
+		d.Target == o.Target &&
+		apicmp.PtrEqual(d.Sunset, o.Sunset) &&
+		d.Replacement == o.Replacement &&
+		d.Message == o.Message
+}
+
+func (d Deprecation) String() string {
+	msg := fmt.Sprintf("%s %s is deprecated", d.Kind, d.Target)
+	if d.Sunset != nil {
+		msg += fmt.Sprintf(" and will be removed at %s", d.Sunset)
+	}
+	if d.Replacement != "" {
+		msg += fmt.Sprintf(". use %s instead", d.Replacement)
+	}
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	return msg
+}
+
+// IsSunset returns true if the item has been removed at the time.
+func (d Deprecation) IsSunset(now time.Time) bool {
+	return d.Sunset != nil && !now.Before(d.Sunset.Time())
+}
+
+// SetHeader adds Deprecations to HTTP header h.
+func SetHeader(h http.Header, ds ...Deprecation) error {
+	for _, d := range ds {
+		b, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		h.Add(HeaderDeprecation, string(b))
+	}
+	return nil
+}
+
+// ParseHeader reads Deprecations from HTTP header h.
+func ParseHeader(h http.Header) ([]Deprecation, error) {
+	ds := []Deprecation{}
+	for _, v := range h.Values(HeaderDeprecation) {
+		d := Deprecation{}
+		if err := json.NewDecoder(strings.NewReader(v)).Decode(&d); err != nil {
+			return nil, fmt.Errorf("malformed %s header: %w", HeaderDeprecation, err)
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// DeprecationHandler is a callback to be notified Deprecations found in responses.
+type DeprecationHandler func(Deprecation)
+
+// Notify calls handler with each Deprecation in HTTP header h.
+//
+// Malformed header values are ignored, since deprecation notices should not break responses.
+func Notify(h http.Header, handler DeprecationHandler) {
+	ds, err := ParseHeader(h)
+	if err != nil {
+		return
+	}
+	for _, d := range ds {
+		handler(d)
+	}
+}
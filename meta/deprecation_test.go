@@ -0,0 +1,107 @@
+package meta_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+func TestDeprecationHeader(t *testing.T) {
+	sunset := rfctime.RFC3339(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	ds := []meta.Deprecation{
+		{
+			Kind:        meta.DeprecatedEndpoint,
+			Target:      "PUT /api/plans/{planId}/resources",
+			Sunset:      &sunset,
+			Replacement: "PUT /api/plans/{planId}",
+		},
+		{
+			Kind:   meta.DeprecatedField,
+			Target: "data.Summary.knitid",
+		},
+	}
+
+	h := http.Header{}
+	if err := meta.SetHeader(h, ds...); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := meta.ParseHeader(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !apicmp.SliceEqual(got, ds) {
+		t.Errorf("unexpected result: %+v", got)
+	}
+
+	notified := []meta.Deprecation{}
+	meta.Notify(h, func(d meta.Deprecation) { notified = append(notified, d) })
+	if !apicmp.SliceEqual(notified, ds) {
+		t.Errorf("unexpected notification: %+v", notified)
+	}
+
+	if !ds[0].IsSunset(sunset.Time()) {
+		t.Errorf("should be sunset at %s", sunset)
+	}
+	if ds[1].IsSunset(sunset.Time()) {
+		t.Errorf("should not be sunset without schedule")
+	}
+}
+
+func TestParseHeader_malformed(t *testing.T) {
+	h := http.Header{}
+	h.Add(meta.HeaderDeprecation, "not json")
+	if _, err := meta.ParseHeader(h); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	sunset := rfctime.RFC3339(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC))
+	for name, testcase := range map[string]meta.Deprecation{
+		"without sunset": {
+			Kind:   meta.DeprecatedParameter,
+			Target: "tag",
+		},
+		"with all fields": {
+			Kind:        meta.DeprecatedEndpoint,
+			Target:      "PUT /api/plans/{planId}/resources",
+			Sunset:      &sunset,
+			Replacement: "PUT /api/plans/{planId}",
+			Message:     "resources are part of plan",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			knittest.AssertRoundTrip(t, testcase)
+		})
+	}
+
+	t.Run("Equal compares Sunset", func(t *testing.T) {
+		later := rfctime.RFC3339(sunset.Time().Add(time.Hour))
+		base := meta.Deprecation{Kind: meta.DeprecatedField, Target: "data.Summary.knitid"}
+
+		for name, pair := range map[string]struct {
+			A, B *rfctime.RFC3339
+			Want bool
+		}{
+			"both nil":  {Want: true},
+			"same":      {A: &sunset, B: &sunset, Want: true},
+			"one nil":   {A: &sunset},
+			"different": {A: &sunset, B: &later},
+		} {
+			a, b := base, base
+			a.Sunset, b.Sunset = pair.A, pair.B
+			if got := a.Equal(b); got != pair.Want {
+				t.Errorf("%s: a.Equal(b): got %v, want %v", name, got, pair.Want)
+			}
+			if got := b.Equal(a); got != pair.Want {
+				t.Errorf("%s: b.Equal(a): got %v, want %v", name, got, pair.Want)
+			}
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package meta
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// HTTP header names which carry ResponseMeta.
+const (
+	HeaderRequestId    = "X-Request-Id"
+	HeaderServerTiming = "Server-Timing"
+	HeaderAPIVersion   = "X-Knitfab-Api-Version"
+)
+
+// Envelope wraps a payload with metadata of the response.
+//
+// WebAPIs respond bare payloads by default.
+// Envelope is an optional representation for clients which want to keep metadata with the payload.
+type Envelope[T any] struct {
+	// Meta is the metadata of the response.
+	Meta ResponseMeta `json:"meta"`
+
+	// Data is the payload of the response.
+	Data T `json:"data"`
+}
+
+// ResponseMeta is the metadata of a WebAPI response.
+type ResponseMeta struct {
+	// RequestId is the id assigned to the request by the server.
+	//
+	// Use this to correlate client failures with server logs.
+	RequestId string `json:"requestId,omitempty"`
+
+	// APIVersion is the version of WebAPI which handled the request.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// ServerTiming is the time spent on the server, broken down by phase.
+	ServerTiming []Timing `json:"serverTiming,omitempty"`
+
+	// Warnings are soft issues found while processing the request.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// Deprecations are deprecated items used in the request.
+	Deprecations []Deprecation `json:"deprecations,omitempty"`
+
+	// Cache is the caching metadata of the response.
+	Cache *CacheInfo `json:"cache,omitempty"`
+}
+
+func (m ResponseMeta) Equal(o ResponseMeta) bool {
+	return m.RequestId == o.RequestId &&
+		m.APIVersion == o.APIVersion &&
+		apicmp.SliceEqual(m.ServerTiming, o.ServerTiming) &&
+		apicmp.SliceEqual(m.Warnings, o.Warnings) &&
+		apicmp.SliceEqual(m.Deprecations, o.Deprecations) &&
+		apicmp.PtrEqual(m.Cache, o.Cache)
+}
+
+// WriteHeader writes ResponseMeta into HTTP header h.
+//
+// Warnings are not written, because they are carried in response bodies.
+func (m ResponseMeta) WriteHeader(h http.Header) error {
+	if m.RequestId != "" {
+		h.Set(HeaderRequestId, m.RequestId)
+	}
+	if m.APIVersion != "" {
+		h.Set(HeaderAPIVersion, m.APIVersion)
+	}
+	for _, t := range m.ServerTiming {
+		h.Add(HeaderServerTiming, t.String())
+	}
+	if m.Cache != nil {
+		m.Cache.WriteHeader(h)
+	}
+	return SetHeader(h, m.Deprecations...)
+}
+
+// CaptureResponseMeta reads ResponseMeta from HTTP header h.
+//
+// Malformed Server-Timing entries and Deprecation headers are ignored.
+func CaptureResponseMeta(h http.Header) ResponseMeta {
+	m := ResponseMeta{
+		RequestId:  h.Get(HeaderRequestId),
+		APIVersion: h.Get(HeaderAPIVersion),
+	}
+	for _, v := range h.Values(HeaderServerTiming) {
+		for _, expr := range strings.Split(v, ",") {
+			t := Timing{}
+			if err := t.Parse(expr); err != nil {
+				continue
+			}
+			m.ServerTiming = append(m.ServerTiming, t)
+		}
+	}
+	if ds, err := ParseHeader(h); err == nil && 0 < len(ds) {
+		m.Deprecations = ds
+	}
+	if c := CaptureCacheInfo(h); !c.IsZero() {
+		m.Cache = &c
+	}
+	return m
+}
+
+// Timing is a metric of Server-Timing header[^1].
+//
+// [^1]: https://www.w3.org/TR/server-timing/
+type Timing struct {
+	// Name is the name of the metric.
+	Name string `json:"name"`
+
+	// Duration is the time spent, in milliseconds.
+	Duration float64 `json:"dur,omitempty"`
+
+	// Description is the human readable description of the metric.
+	Description string `json:"desc,omitempty"`
+}
+
+func (t Timing) Equal(o Timing) bool {
+	return t.Name == o.Name && t.Duration == o.Duration && t.Description == o.Description
+}
+
+// String returns the expression of the metric in Server-Timing header.
+func (t Timing) String() string {
+	s := t.Name
+	if t.Duration != 0 {
+		s += ";dur=" + strconv.FormatFloat(t.Duration, 'f', -1, 64)
+	}
+	if t.Description != "" {
+		s += ";desc=" + strconv.Quote(t.Description)
+	}
+	return s
+}
+
+// Parse parses a metric of Server-Timing header, like `db;dur=12.5;desc="query"`.
+func (t *Timing) Parse(s string) error {
+	params := strings.Split(s, ";")
+	name := strings.TrimSpace(params[0])
+	if name == "" {
+		return fmt.Errorf("server-timing format error (no name): %s", s)
+	}
+
+	ret := Timing{Name: name}
+	for _, p := range params[1:] {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "dur":
+			d, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return fmt.Errorf("server-timing format error (bad dur): %s", s)
+			}
+			ret.Duration = d
+		case "desc":
+			v = strings.TrimSpace(v)
+			if uq, err := strconv.Unquote(v); err == nil {
+				v = uq
+			}
+			ret.Description = v
+		}
+	}
+
+	*t = ret
+	return nil
+}
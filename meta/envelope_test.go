@@ -0,0 +1,66 @@
+package meta_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/meta"
+)
+
+func TestResponseMeta_header(t *testing.T) {
+	m := meta.ResponseMeta{
+		RequestId:  "req-1",
+		APIVersion: "v1",
+		ServerTiming: []meta.Timing{
+			{Name: "db", Duration: 12.5, Description: "query plans"},
+			{Name: "total", Duration: 40},
+		},
+		Deprecations: []meta.Deprecation{
+			{Kind: meta.DeprecatedParameter, Target: "since"},
+		},
+	}
+
+	h := http.Header{}
+	if err := m.WriteHeader(h); err != nil {
+		t.Fatal(err)
+	}
+
+	got := meta.CaptureResponseMeta(h)
+	if !got.Equal(m) {
+		t.Errorf("unexpected result: %+v", got)
+	}
+}
+
+func TestTiming_Parse(t *testing.T) {
+	h := http.Header{}
+	h.Set(meta.HeaderServerTiming, `cache;desc="hit", app;dur=3.2, ;dur=1`)
+
+	got := meta.CaptureResponseMeta(h)
+	want := []meta.Timing{
+		{Name: "cache", Description: "hit"},
+		{Name: "app", Duration: 3.2},
+	}
+	if len(got.ServerTiming) != len(want) {
+		t.Fatalf("unexpected result: %+v", got.ServerTiming)
+	}
+	for i := range want {
+		if !got.ServerTiming[i].Equal(want[i]) {
+			t.Errorf("unexpected result[%d]: %+v", i, got.ServerTiming[i])
+		}
+	}
+}
+
+func TestEnvelope_marshal(t *testing.T) {
+	env := meta.Envelope[[]string]{
+		Meta: meta.ResponseMeta{RequestId: "req-1"},
+		Data: []string{"a", "b"},
+	}
+	b, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"meta":{"requestId":"req-1"},"data":["a","b"]}`; string(b) != want {
+		t.Errorf("unexpected result: %s", b)
+	}
+}
@@ -0,0 +1,49 @@
+package meta
+
+import "fmt"
+
+// ResourceKind is the kind of Knitfab resources.
+type ResourceKind string
+
+const (
+	KindPlan ResourceKind = "plan"
+	KindRun  ResourceKind = "run"
+	KindData ResourceKind = "data"
+)
+
+// Valid returns true if k is one of known kinds.
+func (k ResourceKind) Valid() bool {
+	switch k {
+	case KindPlan, KindRun, KindData:
+		return true
+	}
+	return false
+}
+
+// ResourceRef refers a resource by its kind and id.
+type ResourceRef struct {
+	// Kind is the kind of the resource.
+	Kind ResourceKind `json:"kind"`
+
+	// Id is the id of the resource: planId, runId or knitId.
+	Id string `json:"id"`
+}
+
+func (r ResourceRef) Equal(o ResourceRef) bool {
+	return r.Kind == o.Kind && r.Id == o.Id
+}
+
+func (r ResourceRef) String() string {
+	return fmt.Sprintf("%s/%s", r.Kind, r.Id)
+}
+
+// Validate checks the kind is known and the id is not empty.
+func (r ResourceRef) Validate() error {
+	if !r.Kind.Valid() {
+		return fmt.Errorf(`"kind" should be one of "%s", "%s" or "%s": %q`, KindPlan, KindRun, KindData, r.Kind)
+	}
+	if r.Id == "" {
+		return fmt.Errorf(`required field missing: "id"`)
+	}
+	return nil
+}
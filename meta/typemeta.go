@@ -0,0 +1,145 @@
+package meta
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// CurrentAPIVersion is the apiVersion of payloads defined in this module.
+const CurrentAPIVersion = "knitfab/v1"
+
+// TypeMeta tells the type of a payload and the version of its schema.
+//
+// It is optional: payloads may have "apiVersion" and "kind" at their top level,
+// in addition to their own fields, like
+//
+//	{"apiVersion": "knitfab/v1", "kind": "Plan", "image": "...", ...}
+//
+// Use Scheme.Encode to add them and Scheme.Decode to dispatch payloads by them.
+type TypeMeta struct {
+	// APIVersion is the version of the schema, like "knitfab/v1".
+	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+
+	// Kind is the type of the payload, like "Plan".
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+}
+
+func (t TypeMeta) Equal(o TypeMeta) bool {
+	return t.APIVersion == o.APIVersion && t.Kind == o.Kind
+}
+
+func (t TypeMeta) String() string {
+	return t.APIVersion + ", Kind=" + t.Kind
+}
+
+// UnknownKindError is the error for payloads with TypeMeta which is not registered in the Scheme.
+type UnknownKindError struct {
+	TypeMeta TypeMeta
+}
+
+func (e UnknownKindError) Error() string {
+	if e.TypeMeta.Kind == "" {
+		return `required field missing: "kind"`
+	}
+	return fmt.Sprintf("unknown kind: %s", e.TypeMeta)
+}
+
+// Scheme maps TypeMetas to types of payloads, for Decode and Encode.
+//
+// Build a Scheme with NewScheme and add kinds to it with Register
+// (or AddToScheme of packages of this module) before using it.
+// Once built, it is safe to use Decode, Encode and KindOf concurrently.
+type Scheme struct {
+	decode map[TypeMeta]func([]byte) (any, error)
+	types  map[reflect.Type]TypeMeta
+}
+
+// NewScheme returns an empty Scheme.
+func NewScheme() *Scheme {
+	return &Scheme{
+		decode: map[TypeMeta]func([]byte) (any, error){},
+		types:  map[reflect.Type]TypeMeta{},
+	}
+}
+
+// Register adds T as the kind in CurrentAPIVersion to the Scheme.
+//
+// It returns error if the kind or T is registered already.
+func Register[T any](s *Scheme, kind string) error {
+	tm := TypeMeta{APIVersion: CurrentAPIVersion, Kind: kind}
+	t := reflect.TypeFor[T]()
+	if _, ok := s.decode[tm]; ok {
+		return fmt.Errorf("kind is registered twice: %s", tm)
+	}
+	if other, ok := s.types[t]; ok {
+		return fmt.Errorf("type %s is registered already as %s", t, other)
+	}
+	s.decode[tm] = func(b []byte) (any, error) {
+		var v T
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	s.types[t] = tm
+	return nil
+}
+
+// TypeMetaOf returns TypeMeta of the JSON payload.
+//
+// If the payload has no "apiVersion" or "kind", they are empty.
+func TypeMetaOf(b []byte) (TypeMeta, error) {
+	tm := TypeMeta{}
+	if err := json.Unmarshal(b, &tm); err != nil {
+		return TypeMeta{}, err
+	}
+	return tm, nil
+}
+
+// KindOf returns TypeMeta registered for the type of v.
+func (s *Scheme) KindOf(v any) (TypeMeta, bool) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	tm, ok := s.types[t]
+	return tm, ok
+}
+
+// Decode decodes the JSON payload into the type registered for its TypeMeta.
+//
+// The returned value is not a pointer, like plans.PlanSpec.
+// If TypeMeta of the payload is not registered, it returns UnknownKindError.
+func (s *Scheme) Decode(b []byte) (any, error) {
+	tm, err := TypeMetaOf(b)
+	if err != nil {
+		return nil, err
+	}
+	decode, ok := s.decode[tm]
+	if !ok {
+		return nil, UnknownKindError{TypeMeta: tm}
+	}
+	return decode(b)
+}
+
+// Encode encodes v into JSON with its TypeMeta.
+//
+// v should be encoded as a JSON object, and its type should be registered.
+func (s *Scheme) Encode(v any) ([]byte, error) {
+	tm, ok := s.KindOf(v)
+	if !ok {
+		return nil, fmt.Errorf("unregistered type: %T", v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, fmt.Errorf("%T is not encoded as an object: %w", v, err)
+	}
+	obj["apiVersion"], _ = json.Marshal(tm.APIVersion)
+	obj["kind"], _ = json.Marshal(tm.Kind)
+	return json.Marshal(obj)
+}
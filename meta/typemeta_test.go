@@ -0,0 +1,117 @@
+package meta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func newScheme(t *testing.T) *meta.Scheme {
+	t.Helper()
+	s := meta.NewScheme()
+	for _, add := range []func(*meta.Scheme) error{
+		plans.AddToScheme, data.AddToScheme, runs.AddToScheme, tags.AddToScheme,
+	} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestScheme_Decode(t *testing.T) {
+	scheme := newScheme(t)
+	got, err := scheme.Decode([]byte(`{
+		"apiVersion": "knitfab/v1",
+		"kind": "Plan",
+		"image": "example.com/train:v1",
+		"inputs": [{"path": "/in", "tags": ["type:dataset"]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := plans.NewSpec().WithImage("example.com/train:v1").AddInput("/in", "type:dataset").MustBuild()
+	if ps, ok := got.(plans.PlanSpec); !ok || !ps.Equal(want) {
+		t.Errorf("unexpected payload: %#v", got)
+	}
+
+	got, err = scheme.Decode([]byte(`{"apiVersion": "knitfab/v1", "kind": "TagChange", "add": ["a:b"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := got.(tags.Change); !ok || len(c.AddTags) != 1 || c.AddTags[0].Key != "a" {
+		t.Errorf("unexpected payload: %#v", got)
+	}
+
+	for name, payload := range map[string]string{
+		"no kind":         `{"image": "example.com/train:v1"}`,
+		"unknown kind":    `{"apiVersion": "knitfab/v1", "kind": "Pipeline"}`,
+		"unknown version": `{"apiVersion": "knitfab/v9", "kind": "Plan"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := scheme.Decode([]byte(payload))
+			if uk := (meta.UnknownKindError{}); !errors.As(err, &uk) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestScheme_Encode(t *testing.T) {
+	scheme := newScheme(t)
+	ps := plans.NewSpec().WithImage("example.com/train:v1").AddInput("/in", "type:dataset").MustBuild()
+	b, err := scheme.Encode(&ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm, err := meta.TypeMetaOf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.Equal(meta.TypeMeta{APIVersion: meta.CurrentAPIVersion, Kind: plans.KindPlanSpec}) {
+		t.Errorf("unexpected TypeMeta: %s", tm)
+	}
+
+	got, err := scheme.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps2, ok := got.(plans.PlanSpec); !ok || !ps2.Equal(ps) {
+		t.Errorf("unexpected payload: %#v", got)
+	}
+
+	if _, err := scheme.Encode(map[string]string{}); err == nil {
+		t.Error("unregistered type is encoded")
+	}
+}
+
+func TestScheme_isolated(t *testing.T) {
+	empty := meta.NewScheme()
+	payload := []byte(`{"apiVersion": "knitfab/v1", "kind": "TagChange", "add": ["a:b"]}`)
+	if _, err := empty.Decode(payload); !errors.As(err, &meta.UnknownKindError{}) {
+		t.Errorf("empty Scheme decodes a payload: %v", err)
+	}
+	if _, err := newScheme(t).Decode(payload); err != nil {
+		t.Errorf("Scheme is affected by another: %v", err)
+	}
+
+	s := meta.NewScheme()
+	if err := tags.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := tags.AddToScheme(s); err == nil {
+		t.Error("kinds are registered twice")
+	}
+	if err := meta.Register[plans.PlanSpec](s, tags.KindChange); err == nil {
+		t.Error("registered kind is overwritten")
+	}
+	if err := meta.Register[tags.Change](s, "AnotherChange"); err == nil {
+		t.Error("registered type is registered as another kind")
+	}
+}
@@ -0,0 +1,143 @@
+package meta
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// Features which Knitfab servers can support.
+//
+// Clients should check Version.Supports before sending fields or calling APIs of the feature,
+// since older servers ignore unknown fields silently.
+const (
+	// FeatureAnnotations is "annotations" of PlanSpec and APIs to change them.
+	FeatureAnnotations = "annotations"
+
+	// FeatureEntrypoint is "entrypoint" and "args" of PlanSpec.
+	FeatureEntrypoint = "entrypoint"
+
+	// FeatureServiceAccount is "service_account" of PlanSpec.
+	FeatureServiceAccount = "service_account"
+
+	// FeatureSidecars is "sidecars" of PlanSpec.
+	FeatureSidecars = "sidecars"
+
+	// FeatureSchedule is "schedule" of PlanSpec.
+	FeatureSchedule = "schedule"
+
+	// FeaturePagination is pagination of list APIs (see package page).
+	FeaturePagination = "pagination"
+
+	// FeatureEvents is the event stream API (see package events).
+	FeatureEvents = "events"
+
+	// FeatureWebhooks is the webhook APIs (see package webhooks).
+	FeatureWebhooks = "webhooks"
+)
+
+// Version is the format for response body from Knitfab APIs below:
+//
+// - GET /api/version
+type Version struct {
+	// Server is the version of the Knitfab server, like "v1.5.0".
+	Server string `json:"server" yaml:"server"`
+
+	// APIVersions are versions of WebAPI the server serves, like "knitfab/v1".
+	APIVersions []string `json:"apiVersions" yaml:"apiVersions"`
+
+	// Features are the optional features the server supports. See Feature* constants.
+	Features []string `json:"features" yaml:"features"`
+}
+
+func (v Version) Equal(o Version) bool {
+	return v.Server == o.Server &&
+		apicmp.SliceEqEqUnordered(v.APIVersions, o.APIVersions) &&
+		apicmp.SliceEqEqUnordered(v.Features, o.Features)
+}
+
+// Supports returns true if the server supports the feature.
+func (v Version) Supports(feature string) bool {
+	return slices.Contains(v.Features, feature)
+}
+
+// SupportsAPIVersion returns true if the server serves the API version.
+func (v Version) SupportsAPIVersion(apiVersion string) bool {
+	return slices.Contains(v.APIVersions, apiVersion)
+}
+
+// HealthStatus is the status of the server or its components.
+type HealthStatus string
+
+const (
+	// HealthOk means that it works.
+	HealthOk HealthStatus = "ok"
+
+	// HealthDegraded means that it works, but some of its functions are unavailable or slow.
+	HealthDegraded HealthStatus = "degraded"
+
+	// HealthDown means that it does not work.
+	HealthDown HealthStatus = "down"
+)
+
+// severity returns how bad the status is. Unknown statuses are as bad as HealthDown.
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthOk:
+		return 0
+	case HealthDegraded:
+		return 1
+	}
+	return 2
+}
+
+// HTTPStatus returns the HTTP status code to respond the health with:
+// "503 Service Unavailable" for HealthDown (or unknown), and "200 OK" for others.
+func (s HealthStatus) HTTPStatus() int {
+	if s.severity() < HealthDown.severity() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+// ComponentHealth is the health of a component of the server, like the database or the storage.
+type ComponentHealth struct {
+	// Status is the status of the component.
+	Status HealthStatus `json:"status" yaml:"status"`
+
+	// Message is the human readable description of the status.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (c ComponentHealth) Equal(o ComponentHealth) bool {
+	return c.Status == o.Status && c.Message == o.Message
+}
+
+// Health is the format for response body from Knitfab APIs below:
+//
+// - GET /api/health
+type Health struct {
+	// Status is the overall status of the server.
+	Status HealthStatus `json:"status" yaml:"status"`
+
+	// Components are the health of components, by their names.
+	Components map[string]ComponentHealth `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+func (h Health) Equal(o Health) bool {
+	return h.Status == o.Status && apicmp.MapEqual(h.Components, o.Components)
+}
+
+// NewHealth makes Health from components. The overall status is the worst status of them.
+//
+// If there are no components, the status is HealthOk.
+func NewHealth(components map[string]ComponentHealth) Health {
+	status := HealthOk
+	for _, c := range components {
+		if status.severity() < c.Status.severity() {
+			status = c.Status
+		}
+	}
+	return Health{Status: status, Components: components}
+}
@@ -0,0 +1,64 @@
+package meta_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/meta"
+)
+
+func TestVersion(t *testing.T) {
+	v := meta.Version{
+		Server:      "v1.5.0",
+		APIVersions: []string{meta.CurrentAPIVersion},
+		Features:    []string{meta.FeatureAnnotations, meta.FeatureEntrypoint},
+	}
+	knittest.AssertRoundTrip(t, v)
+
+	if !v.Supports(meta.FeatureAnnotations) || v.Supports(meta.FeatureWebhooks) {
+		t.Error("unexpected feature support")
+	}
+	if !v.SupportsAPIVersion(meta.CurrentAPIVersion) || v.SupportsAPIVersion("knitfab/v2") {
+		t.Error("unexpected API version support")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	for name, tc := range map[string]struct {
+		components map[string]meta.ComponentHealth
+		want       meta.HealthStatus
+		code       int
+	}{
+		"no components": {want: meta.HealthOk, code: http.StatusOK},
+		"degraded": {
+			components: map[string]meta.ComponentHealth{
+				"database": {Status: meta.HealthOk},
+				"storage":  {Status: meta.HealthDegraded, Message: "slow"},
+			},
+			want: meta.HealthDegraded, code: http.StatusOK,
+		},
+		"down": {
+			components: map[string]meta.ComponentHealth{
+				"database": {Status: meta.HealthDown},
+				"storage":  {Status: meta.HealthDegraded},
+			},
+			want: meta.HealthDown, code: http.StatusServiceUnavailable,
+		},
+		"unknown": {
+			components: map[string]meta.ComponentHealth{"database": {Status: "exploded"}},
+			want:       "exploded", code: http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h := meta.NewHealth(tc.components)
+			if h.Status != tc.want {
+				t.Errorf("status: got %s, want %s", h.Status, tc.want)
+			}
+			if got := h.Status.HTTPStatus(); got != tc.code {
+				t.Errorf("HTTP status: got %d, want %d", got, tc.code)
+			}
+			knittest.AssertRoundTrip(t, h)
+		})
+	}
+}
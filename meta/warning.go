@@ -0,0 +1,29 @@
+package meta
+
+import "fmt"
+
+// Warning is a soft issue found while processing a request.
+//
+// Requests with Warnings are succeeded, but the result may not be as expected.
+// For example, "tag value truncated" or "resource rounded".
+type Warning struct {
+	// Code is the machine readable identifier of the kind of warning.
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+
+	// Field is the path to the field which causes the warning, like "on_node.must[0]".
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+
+	// Message is the human readable description of the warning.
+	Message string `json:"message" yaml:"message"`
+}
+
+func (w Warning) Equal(o Warning) bool {
+	return w.Code == o.Code && w.Field == o.Field && w.Message == o.Message
+}
+
+func (w Warning) String() string {
+	if w.Field == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Field, w.Message)
+}
@@ -0,0 +1,62 @@
+package meta_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/meta"
+)
+
+func TestWarning(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When       meta.Warning
+		WantString string
+		WantJSON   string
+	}{
+		"full": {
+			When:       meta.Warning{Code: "tag-truncated", Field: "tags[0]", Message: "value is truncated"},
+			WantString: "tags[0]: value is truncated",
+			WantJSON:   `{"code":"tag-truncated","field":"tags[0]","message":"value is truncated"}`,
+		},
+		"message only": {
+			When:       meta.Warning{Message: "resource is rounded"},
+			WantString: "resource is rounded",
+			WantJSON:   `{"message":"resource is rounded"}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.When.String(); got != testcase.WantString {
+				t.Errorf("String: got %q, want %q", got, testcase.WantString)
+			}
+			b, err := json.Marshal(testcase.When)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != testcase.WantJSON {
+				t.Errorf("JSON: got %s, want %s", b, testcase.WantJSON)
+			}
+			knittest.AssertRoundTrip(t, testcase.When)
+		})
+	}
+}
+
+func TestWarning_Equal(t *testing.T) {
+	base := meta.Warning{Code: "tag-truncated", Field: "tags[0]", Message: "value is truncated"}
+	for name, testcase := range map[string]struct {
+		When meta.Warning
+		Want bool
+	}{
+		"same":            {When: base, Want: true},
+		"code differs":    {When: meta.Warning{Code: "resource-rounded", Field: base.Field, Message: base.Message}},
+		"field differs":   {When: meta.Warning{Code: base.Code, Field: "tags[1]", Message: base.Message}},
+		"message differs": {When: meta.Warning{Code: base.Code, Field: base.Field, Message: "value is dropped"}},
+		"zero":            {When: meta.Warning{}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := base.Equal(testcase.When); got != testcase.Want {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package duration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Go-style duration, like "1h30m" or "90s".
+//
+// In JSON and YAML format, it is a string accepted by time.ParseDuration.
+// Units are "ns", "us" (or "µs"), "ms", "s", "m" and "h".
+type Duration time.Duration
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) Equal(other Duration) bool {
+	return d == other
+}
+
+// get string expression, like "1h30m0s".
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Parse string as Duration.
+func Parse(s string) (Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return Duration(d), nil
+}
+
+// implement encoding/json.Marshaller
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// implement encoding/json.Unmarshaller
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	ret, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*d = ret
+	return nil
+}
+
+// implement gopkg.in/yaml.v3.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// implement gopkg.in/yaml.v3.Unmarshaler
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: duration should be a scalar", node.Line)
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	ret, err := Parse(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+
+	*d = ret
+	return nil
+}
@@ -0,0 +1,82 @@
+package duration_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDuration(t *testing.T) {
+	t.Run("it should parse Go-style durations", func(t *testing.T) {
+		for s, want := range map[string]time.Duration{
+			"90s":    90 * time.Second,
+			"1h30m":  90 * time.Minute,
+			"1.5h":   90 * time.Minute,
+			"250ms":  250 * time.Millisecond,
+			"0s":     0,
+			"-1m10s": -70 * time.Second,
+		} {
+			got, err := duration.Parse(s)
+			if err != nil {
+				t.Errorf("%s: %v", s, err)
+				continue
+			}
+			if got.Duration() != want {
+				t.Errorf("%s: got %s, want %s", s, got, want)
+			}
+		}
+	})
+
+	t.Run("it should fail to parse malformed durations", func(t *testing.T) {
+		for _, s := range []string{"", "10", "1d", "one hour"} {
+			if _, err := duration.Parse(s); err == nil {
+				t.Errorf("%q: no error unexpectedly", s)
+			}
+		}
+	})
+
+	t.Run("it should be marshalled as strings", func(t *testing.T) {
+		type T struct {
+			Timeout *duration.Duration `json:"timeout" yaml:"timeout"`
+		}
+		d := duration.Duration(90 * time.Minute)
+		before := T{Timeout: &d}
+
+		j, err := json.Marshal(before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(j) != `{"timeout":"1h30m0s"}` {
+			t.Errorf("unexpected json: %s", j)
+		}
+		afterJ := T{}
+		if err := json.Unmarshal(j, &afterJ); err != nil {
+			t.Fatal(err)
+		}
+		if afterJ.Timeout == nil || !afterJ.Timeout.Equal(d) {
+			t.Errorf("unmatch: %v", afterJ.Timeout)
+		}
+
+		y, err := yaml.Marshal(before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		afterY := T{}
+		if err := yaml.Unmarshal(y, &afterY); err != nil {
+			t.Fatal(err)
+		}
+		if afterY.Timeout == nil || !afterY.Timeout.Equal(d) {
+			t.Errorf("unmatch: %v", afterY.Timeout)
+		}
+
+		if err := yaml.Unmarshal([]byte("timeout: [1h]"), &T{}); err == nil {
+			t.Error("no error unexpectedly")
+		}
+		if err := json.Unmarshal([]byte(`{"timeout": 3600}`), &T{}); err == nil {
+			t.Error("no error unexpectedly")
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package rfctime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Clock returns the current time. time.Now is a Clock.
+//
+// Inject a fixed Clock to resolve RelativeTime deterministically in tests.
+type Clock func() time.Time
+
+// Anchors of RelativeTime.
+const (
+	// AnchorNow is the current time.
+	AnchorNow = "now"
+
+	// AnchorToday is the start (00:00) of the current day.
+	AnchorToday = "today"
+
+	// AnchorYesterday is the start (00:00) of the previous day.
+	AnchorYesterday = "yesterday"
+)
+
+// RelativeTime is a time expression, either relative to the current time or absolute.
+//
+// Relative expressions are an anchor ("now", "today" or "yesterday")
+// optionally followed by an offset, like "now-1h", "today+9h" or "yesterday-1d12h".
+// Offsets are Go durations which can also have "w" (7 days) and "d" (24 hours) at their head.
+// Days of "today" and "yesterday" are in the location of the Clock.
+//
+// Other expressions are parsed by ParseLooseRFC3339, like "2024-10-11" or "2024-10-11T12:00:00+09:00".
+//
+// RelativeTime is marshalled as its expression.
+type RelativeTime struct {
+	expr string
+
+	// anchor is one of Anchor* constants, or empty for absolute times.
+	anchor   string
+	offset   time.Duration
+	absolute time.Time
+}
+
+var relativeExpr = regexp.MustCompile(`^(now|today|yesterday)(?:\s*([-+])\s*(?:(\d+)w)?(?:(\d+)d)?(\S*))?$`)
+
+// ParseRelativeTime parses a time expression. See RelativeTime for the syntax.
+func ParseRelativeTime(s string) (RelativeTime, error) {
+	expr := strings.TrimSpace(s)
+	m := relativeExpr.FindStringSubmatch(expr)
+	if m == nil {
+		t, err := ParseLooseRFC3339(expr)
+		if err != nil {
+			return RelativeTime{}, fmt.Errorf("time expression format error: %q", s)
+		}
+		return RelativeTime{expr: expr, absolute: t.Time()}, nil
+	}
+
+	ret := RelativeTime{expr: expr, anchor: m[1]}
+	if m[2] == "" {
+		return ret, nil
+	}
+	if m[3] == "" && m[4] == "" && m[5] == "" {
+		return RelativeTime{}, fmt.Errorf("time expression format error (no offset): %q", s)
+	}
+	var offset time.Duration
+	for _, u := range []struct {
+		value string
+		unit  time.Duration
+	}{
+		{value: m[3], unit: 7 * 24 * time.Hour},
+		{value: m[4], unit: 24 * time.Hour},
+	} {
+		if u.value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(u.value, 10, 32)
+		if err != nil {
+			return RelativeTime{}, fmt.Errorf("time expression format error (bad offset): %q", s)
+		}
+		offset += time.Duration(n) * u.unit
+	}
+	if m[5] != "" {
+		d, err := time.ParseDuration(m[5])
+		if err != nil || d < 0 {
+			return RelativeTime{}, fmt.Errorf("time expression format error (bad offset): %q", s)
+		}
+		offset += d
+	}
+	if m[2] == "-" {
+		offset = -offset
+	}
+	ret.offset = offset
+	return ret, nil
+}
+
+// IsRelative returns true if the expression depends on the current time.
+func (r RelativeTime) IsRelative() bool {
+	return r.anchor != ""
+}
+
+// IsZero returns true if r is the zero value, which is not parsed from any expression.
+func (r RelativeTime) IsZero() bool {
+	return r.expr == ""
+}
+
+// Resolve returns the time which r means, at the time clock tells.
+//
+// If clock is nil, time.Now is used. Absolute times are returned as they are.
+func (r RelativeTime) Resolve(clock Clock) RFC3339 {
+	if !r.IsRelative() {
+		return RFC3339(r.absolute)
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	now := clock()
+
+	base := now
+	switch r.anchor {
+	case AnchorToday, AnchorYesterday:
+		y, m, d := now.Date()
+		base = time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+		if r.anchor == AnchorYesterday {
+			base = base.AddDate(0, 0, -1)
+		}
+	}
+	return RFC3339(base.Add(r.offset))
+}
+
+// Equal returns true if r and o mean the same time, at any time.
+func (r RelativeTime) Equal(o RelativeTime) bool {
+	return r.anchor == o.anchor && r.offset == o.offset && r.absolute.Equal(o.absolute)
+}
+
+// String returns the expression.
+func (r RelativeTime) String() string {
+	return r.expr
+}
+
+// implement encoding/json.Marshaller
+func (r RelativeTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.expr)
+}
+
+// implement encoding/json.Unmarshaller
+func (r *RelativeTime) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	ret, err := ParseRelativeTime(s)
+	if err != nil {
+		return err
+	}
+
+	*r = ret
+
+	return nil
+}
+
+// implement gopkg.in/yaml.v3.Marshaler
+func (r RelativeTime) MarshalYAML() (interface{}, error) {
+	return r.expr, nil
+}
+
+// implement gopkg.in/yaml.v3.Unmarshaler
+func (r *RelativeTime) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: time expression should be a scalar", node.Line)
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	ret, err := ParseRelativeTime(node.Value)
+	if err != nil {
+		return err
+	}
+
+	*r = ret
+
+	return nil
+}
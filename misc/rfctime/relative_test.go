@@ -0,0 +1,79 @@
+package rfctime_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRelativeTime(t *testing.T) {
+	jst := time.FixedZone("+09:00", int((9 * time.Hour).Seconds()))
+	now := time.Date(2024, 10, 11, 15, 30, 0, 0, jst)
+	clock := func() time.Time { return now }
+
+	theory := func(expr string, relative bool, want time.Time) func(*testing.T) {
+		return func(t *testing.T) {
+			testee, err := rfctime.ParseRelativeTime(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if testee.IsRelative() != relative {
+				t.Errorf("IsRelative: (actual, expected) = (%v, %v)", testee.IsRelative(), relative)
+			}
+			if got := testee.Resolve(clock); !got.Time().Equal(want) {
+				t.Errorf("unmatch: (actual, expected) = (%s, %s)", got, rfctime.RFC3339(want))
+			}
+		}
+	}
+
+	t.Run("now", theory("now", true, now))
+	t.Run("now-1h", theory("now-1h", true, now.Add(-time.Hour)))
+	t.Run("now - 1h30m", theory("now - 1h30m", true, now.Add(-90*time.Minute)))
+	t.Run("now+2d", theory("now+2d", true, now.Add(48*time.Hour)))
+	t.Run("now-1w1d12h", theory("now-1w1d12h", true, now.Add(-(7*24+24+12)*time.Hour)))
+	t.Run("today", theory("today", true, time.Date(2024, 10, 11, 0, 0, 0, 0, jst)))
+	t.Run("today+9h", theory("today+9h", true, time.Date(2024, 10, 11, 9, 0, 0, 0, jst)))
+	t.Run("yesterday", theory("yesterday", true, time.Date(2024, 10, 10, 0, 0, 0, 0, jst)))
+	t.Run("absolute", theory("2024-10-01T12:00:00+09:00", false, time.Date(2024, 10, 1, 12, 0, 0, 0, jst)))
+
+	for _, expr := range []string{"", "now-", "now-1x", "now--1h", "tomorrow", "today+-1h", "now-1h1d"} {
+		t.Run("it should fail to parse "+expr, func(t *testing.T) {
+			if _, err := rfctime.ParseRelativeTime(expr); err == nil {
+				t.Error("no error unexpectedly")
+			}
+		})
+	}
+}
+
+func TestRelativeTime_marshal(t *testing.T) {
+	want, err := rfctime.ParseRelativeTime("now-1h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"now-1h"` {
+		t.Errorf("unmatch: json marshall: %s", b)
+	}
+	fromJSON := rfctime.RelativeTime{}
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !fromJSON.Equal(want) {
+		t.Errorf("unmatch: json unmarshall: %s", fromJSON)
+	}
+
+	fromYAML := rfctime.RelativeTime{}
+	if err := yaml.Unmarshal([]byte(`now - 60m`), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if !fromYAML.Equal(want) {
+		t.Errorf("unmatch: yaml unmarshall: %s", fromYAML)
+	}
+}
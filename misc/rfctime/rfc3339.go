@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Format string for date-time in RFC3339, disallowing Z as time-offset.
@@ -46,6 +48,10 @@ const (
 // this is known as a subset of ISO8601 extended format.
 //
 // This type is useful to interchange timestamps via network/file.
+//
+// RFC3339 holds nanoseconds, but is stringified (and marshalled) in milliseconds.
+// So, a value with sub-millisecond part is not Equal to itself after a round trip;
+// compare Truncate-d values, or use RFC3339Nano to keep nanoseconds on the wire.
 type RFC3339 time.Time
 
 func (rfctime RFC3339) Time() time.Time {
@@ -57,13 +63,27 @@ func (rfctime RFC3339) Equal(other RFC3339) bool {
 }
 
 // return true if this and other `.Time()` are equal.
-// If both this and other are nil, also return true.
+// If other is nil, also return true.
 //
 // otherwise, return false.
+//
+// Like Equal, this compares in nanoseconds, regardless of the precision of the type of other.
 func (rfctime RFC3339) Equiv(other interface{ Time() time.Time }) bool {
 	return other == nil || rfctime.Time().Equal(other.Time())
 }
 
+// Truncate returns the value truncated to milli second, the precision of String.
+//
+// Truncate-d values are Equal to themselves after a round trip.
+func (t RFC3339) Truncate() RFC3339 {
+	return RFC3339(time.Time(t).Truncate(time.Millisecond))
+}
+
+// Nano returns the value as RFC3339Nano, keeping nanoseconds.
+func (t RFC3339) Nano() RFC3339Nano {
+	return RFC3339Nano(t)
+}
+
 // get string expression.
 //
 // It formatted by RFC3339DateTimeFormat.
@@ -75,7 +95,8 @@ func (t RFC3339) String() string {
 
 // Parse string to ISO8601 time.
 //
-// It trancates resolution to milli second.
+// It keeps the resolution of s, up to nanoseconds.
+// Note that String (and marshalling) truncates resolution to milli second.
 func ParseRFC3339DateTime(s string) (RFC3339, error) {
 	t, err := time.Parse(RFC3339DateTimeFormatZ, s)
 	if err != nil {
@@ -149,3 +170,26 @@ func (t *RFC3339) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+// implement gopkg.in/yaml.v3.Marshaler
+func (t RFC3339) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+// implement gopkg.in/yaml.v3.Unmarshaler
+func (t *RFC3339) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: timestamp should be a scalar", node.Line)
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	ret, err := ParseRFC3339DateTime(node.Value)
+	if err != nil {
+		return err
+	}
+
+	*t = ret
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package rfctime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format string for date-time in RFC3339 with nanoseconds, disallowing Z as time-offset.
+const RFC3339NanoDateTimeFormat string = "2006-01-02T15:04:05.999999999-07:00"
+
+// RFC3339Nano is RFC3339, but stringified (and marshalled) in nanoseconds.
+//
+// Use this for timestamps from data sources with sub-millisecond precision,
+// so that values are Equal to themselves after a round trip.
+// Trailing zeros of fractional seconds are omitted, as RFC3339 does.
+type RFC3339Nano time.Time
+
+func (t RFC3339Nano) Time() time.Time {
+	return time.Time(t)
+}
+
+func (t RFC3339Nano) Equal(other RFC3339Nano) bool {
+	return t.Time().Equal(other.Time())
+}
+
+// return true if this and other `.Time()` are equal, in nanoseconds.
+// If other is nil, also return true.
+//
+// otherwise, return false.
+func (t RFC3339Nano) Equiv(other interface{ Time() time.Time }) bool {
+	return other == nil || t.Time().Equal(other.Time())
+}
+
+// Milli returns the value as RFC3339, which is stringified in milliseconds.
+func (t RFC3339Nano) Milli() RFC3339 {
+	return RFC3339(t)
+}
+
+// get string expression.
+//
+// It formatted by RFC3339NanoDateTimeFormat.
+func (t RFC3339Nano) String() string {
+	return time.Time(t).Format(RFC3339NanoDateTimeFormat)
+}
+
+// ParseRFC3339NanoDateTime parses string to RFC3339Nano.
+func ParseRFC3339NanoDateTime(s string) (RFC3339Nano, error) {
+	t, err := ParseRFC3339DateTime(s)
+	if err != nil {
+		return RFC3339Nano{}, err
+	}
+	return RFC3339Nano(t), nil
+}
+
+// implement encoding/json.Marshaller
+func (t RFC3339Nano) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%s"`, t)), nil
+}
+
+// implement encoding/json.Unmarshaller
+func (t *RFC3339Nano) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	ret, err := ParseRFC3339NanoDateTime(s)
+	if err != nil {
+		return err
+	}
+
+	*t = ret
+
+	return nil
+}
+
+// implement gopkg.in/yaml.v3.Marshaler
+func (t RFC3339Nano) MarshalYAML() (interface{}, error) {
+	return t.String(), nil
+}
+
+// implement gopkg.in/yaml.v3.Unmarshaler
+func (t *RFC3339Nano) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: timestamp should be a scalar", node.Line)
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	ret, err := ParseRFC3339NanoDateTime(node.Value)
+	if err != nil {
+		return err
+	}
+
+	*t = ret
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package rfctime_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRFC3339Nano(t *testing.T) {
+	s := "2021-10-22T12:34:56.987654321+07:00"
+	testee, err := rfctime.ParseRFC3339NanoDateTime(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := json.Marshal(testee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != `"`+s+`"` {
+		t.Errorf("unmatch: json marshall: (actual, expected) = (%s, %s)", actual, s)
+	}
+
+	milli, err := json.Marshal(testee.Milli())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(milli) != `"2021-10-22T12:34:56.987+07:00"` {
+		t.Errorf("unmatch: json marshall as RFC3339: %s", milli)
+	}
+
+	if !testee.Milli().Nano().Equal(testee) || testee.Milli().Truncate().Nano().Equal(testee) {
+		t.Error("conversion between RFC3339 and RFC3339Nano should keep nanoseconds")
+	}
+	if !testee.Equiv(testee.Milli()) || testee.Equiv(testee.Milli().Truncate()) {
+		t.Error("Equiv should compare in nanoseconds")
+	}
+}
+
+func assertRoundTrip[T interface {
+	Equal(T) bool
+	String() string
+}](t *testing.T, v T, want T) {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON := new(T)
+	if err := json.Unmarshal(b, fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !(*fromJSON).Equal(want) {
+		t.Errorf("json: (actual, expected) = (%s, %s)", *fromJSON, want)
+	}
+
+	y, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML := new(T)
+	if err := yaml.Unmarshal(y, fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if !(*fromYAML).Equal(want) {
+		t.Errorf("yaml: (actual, expected) = (%s, %s)", *fromYAML, want)
+	}
+}
+
+// timeOf makes a time in years 0001-9999, which RFC3339 can express.
+func timeOf(sec int64, nsec int64, offsetMin int16) time.Time {
+	const span = 9999 * 365 * 24 * 60 * 60
+	sec = (sec%span + span) % span
+	nsec = (nsec%1e9 + 1e9) % 1e9
+	offset := int(offsetMin) % (24 * 60)
+	return time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC).
+		Add(time.Duration(sec) * time.Second).
+		Add(time.Duration(nsec)).
+		In(time.FixedZone("", offset*60))
+}
+
+func FuzzRFC3339_roundTrip(f *testing.F) {
+	f.Add(int64(1634880896), int64(987654321), int16(420))
+	f.Add(int64(0), int64(0), int16(0))
+	f.Add(int64(-1), int64(1), int16(-570))
+	f.Fuzz(func(t *testing.T, sec int64, nsec int64, offsetMin int16) {
+		v := rfctime.RFC3339(timeOf(sec, nsec, offsetMin))
+		assertRoundTrip(t, v, v.Truncate())
+	})
+}
+
+func FuzzRFC3339Nano_roundTrip(f *testing.F) {
+	f.Add(int64(1634880896), int64(987654321), int16(420))
+	f.Add(int64(0), int64(0), int16(0))
+	f.Add(int64(-1), int64(1), int16(-570))
+	f.Fuzz(func(t *testing.T, sec int64, nsec int64, offsetMin int16) {
+		v := rfctime.RFC3339Nano(timeOf(sec, nsec, offsetMin))
+		assertRoundTrip(t, v, v)
+	})
+}
@@ -0,0 +1,116 @@
+package nodes
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Node is the format for response body from Knitfab APIs below:
+//
+// - GET /api/nodes/ (as list)
+//
+// - GET /api/nodes/{name}
+//
+// It describes a node of the cluster where Workers of Runs can be scheduled.
+type Node struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+
+	// Labels are the labels of the node.
+	//
+	// Plans can refer them with "on_node".
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are the taints of the node.
+	Taints []Taint `json:"taints,omitempty"`
+
+	// Allocatable is the resources of the node which can be used by Workers.
+	//
+	// Plans can refer its keys with "resources".
+	Allocatable map[string]resource.Quantity `json:"allocatable,omitempty"`
+
+	// GPUs are the GPUs attached to the node.
+	GPUs []GPU `json:"gpus,omitempty"`
+}
+
+func (n Node) Equal(o Node) bool {
+	return n.Name == o.Name &&
+		apicmp.MapEqualWith(n.Labels, o.Labels, func(a, b string) bool { return a == b }) &&
+		apicmp.SliceEqualUnordered(n.Taints, o.Taints) &&
+		apicmp.MapEqualWith(n.Allocatable, o.Allocatable, func(a, b resource.Quantity) bool { return a.Equal(b) }) &&
+		apicmp.SliceEqualUnordered(n.GPUs, o.GPUs)
+}
+
+// Taint is a taint of a node.
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+func (t Taint) Equal(o Taint) bool {
+	return t.Key == o.Key && t.Value == o.Value && t.Effect == o.Effect
+}
+
+// GPU is a kind of GPUs attached to a node.
+type GPU struct {
+	// Resource is the resource name of the GPU, like "nvidia.com/gpu".
+	Resource string `json:"resource"`
+
+	// Product is the product name of the GPU.
+	Product string `json:"product,omitempty"`
+
+	// Count is the number of the GPUs.
+	Count int `json:"count"`
+
+	// Memory is the memory size of each GPU.
+	Memory *resource.Quantity `json:"memory,omitempty"`
+}
+
+func (g GPU) Equal(o GPU) bool {
+	return g.Resource == o.Resource &&
+		g.Product == o.Product &&
+		g.Count == o.Count &&
+		apicmp.PtrEqual(g.Memory, o.Memory)
+}
+
+// List is the format for response body from Knitfab APIs below:
+//
+// - GET /api/nodes/
+type List []Node
+
+func (l List) Equal(o List) bool {
+	return apicmp.SliceEqualUnordered(l, o)
+}
+
+// Labels returns label values available in the nodes, by label key.
+//
+// Values are sorted and deduplicated.
+func (l List) Labels() map[string][]string {
+	labels := map[string][]string{}
+	for _, n := range l {
+		for k, v := range n.Labels {
+			if !slices.Contains(labels[k], v) {
+				labels[k] = append(labels[k], v)
+			}
+		}
+	}
+	for k := range labels {
+		slices.Sort(labels[k])
+	}
+	return labels
+}
+
+// ResourceNames returns sorted names of allocatable resources in the nodes.
+func (l List) ResourceNames() []string {
+	names := map[string]struct{}{}
+	for _, n := range l {
+		for k := range n.Allocatable {
+			names[k] = struct{}{}
+		}
+	}
+	return slices.Sorted(maps.Keys(names))
+}
@@ -0,0 +1,75 @@
+package nodes_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/nodes"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestList_Labels(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		List nodes.List
+		Want map[string][]string
+	}{
+		"nil list":   {List: nil, Want: map[string][]string{}},
+		"empty list": {List: nodes.List{}, Want: map[string][]string{}},
+		"nodes without labels": {
+			List: nodes.List{{Name: "node-1"}, {Name: "node-2"}},
+			Want: map[string][]string{},
+		},
+		"values are deduplicated and sorted": {
+			List: nodes.List{
+				{Name: "node-1", Labels: map[string]string{"zone": "b", "accelerator": "gpu"}},
+				{Name: "node-2", Labels: map[string]string{"zone": "a"}},
+				{Name: "node-3", Labels: map[string]string{"zone": "b", "accelerator": "gpu"}},
+				{Name: "node-4", Labels: map[string]string{"zone": "c", "dedicated": ""}},
+			},
+			Want: map[string][]string{
+				"zone":        {"a", "b", "c"},
+				"accelerator": {"gpu"},
+				"dedicated":   {""},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := testcase.List.Labels()
+			if !apicmp.MapEqualWith(got, testcase.Want, slices.Equal) {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
+
+func TestList_ResourceNames(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		List nodes.List
+		Want []string
+	}{
+		"nil list":   {List: nil, Want: []string{}},
+		"empty list": {List: nodes.List{}, Want: []string{}},
+		"names are deduplicated and sorted": {
+			List: nodes.List{
+				{Name: "node-1", Allocatable: map[string]resource.Quantity{
+					"memory": resource.MustParse("16Gi"),
+					"cpu":    resource.MustParse("4"),
+				}},
+				{Name: "node-2"},
+				{Name: "node-3", Allocatable: map[string]resource.Quantity{
+					"nvidia.com/gpu": resource.MustParse("1"),
+					"cpu":            resource.MustParse("8"),
+				}},
+			},
+			Want: []string{"cpu", "memory", "nvidia.com/gpu"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := testcase.List.ResourceNames()
+			if !slices.Equal(got, testcase.Want) {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
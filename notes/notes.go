@@ -0,0 +1,123 @@
+package notes
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Note is the format for response body from Knitfab APIs below:
+//
+// - GET  /api/notes/[?...] (as list)
+//
+// - POST /api/notes/
+//
+// - GET  /api/notes/{noteId}
+type Note struct {
+	// NoteId is the id of the Note.
+	NoteId string `json:"noteId"`
+
+	// Resource is the Run or Data which the Note is attached to.
+	Resource meta.ResourceRef `json:"resource"`
+
+	// Author is the user who wrote the Note.
+	Author string `json:"author"`
+
+	// Body is the content of the Note.
+	Body string `json:"body"`
+
+	// CreatedAt is the time when the Note was written.
+	CreatedAt rfctime.RFC3339 `json:"createdAt"`
+}
+
+func (n Note) Equal(o Note) bool {
+	return n.NoteId == o.NoteId &&
+		n.Resource.Equal(o.Resource) &&
+		n.Author == o.Author &&
+		n.Body == o.Body &&
+		n.CreatedAt.Equal(o.CreatedAt)
+}
+
+// Create is the format for request body to Knitfab APIs below:
+//
+// - POST /api/notes/
+//
+// The author of the Note is the requester.
+type Create struct {
+	// Resource is the Run or Data which the Note is attached to.
+	Resource meta.ResourceRef `json:"resource"`
+
+	// Body is the content of the Note.
+	Body string `json:"body"`
+}
+
+// Validate checks the request refers a Run or a Data and has non-blank body.
+func (c Create) Validate() error {
+	if err := c.Resource.Validate(); err != nil {
+		return err
+	}
+	if c.Resource.Kind != meta.KindRun && c.Resource.Kind != meta.KindData {
+		return fmt.Errorf(`notes can be attached only to "%s" and "%s": %q`, meta.KindRun, meta.KindData, c.Resource.Kind)
+	}
+	if strings.TrimSpace(c.Body) == "" {
+		return fmt.Errorf(`"body" should not be blank`)
+	}
+	return nil
+}
+
+// Query parameter names for ListQuery.
+const (
+	ParamKind   = "kind"
+	ParamId     = "id"
+	ParamAuthor = "author"
+)
+
+// ListQuery is the query parameters for Knitfab APIs below:
+//
+// - GET /api/notes/[?...]
+//
+// Empty fields are not used to filter.
+//
+// Other Note related WebAPI do not take query parameters.
+//
+// - DELETE /api/notes/{noteId}: empty response ("204 No Content" on success)
+type ListQuery struct {
+	// Kind filters Notes by the kind of resource attached to.
+	Kind meta.ResourceKind
+
+	// Id filters Notes by the id of resource attached to.
+	Id string
+
+	// Author filters Notes by the author.
+	Author string
+}
+
+// Encode returns the query as url.Values.
+func (q ListQuery) Encode() url.Values {
+	v := url.Values{}
+	if q.Kind != "" {
+		v.Set(ParamKind, string(q.Kind))
+	}
+	if q.Id != "" {
+		v.Set(ParamId, q.Id)
+	}
+	if q.Author != "" {
+		v.Set(ParamAuthor, q.Author)
+	}
+	return v
+}
+
+// Decode reads the query from url.Values.
+func (q *ListQuery) Decode(v url.Values) error {
+	kind := meta.ResourceKind(v.Get(ParamKind))
+	if kind != "" && !kind.Valid() {
+		return fmt.Errorf(`query parameter "%s" is invalid: %q`, ParamKind, kind)
+	}
+	q.Kind = kind
+	q.Id = v.Get(ParamId)
+	q.Author = v.Get(ParamAuthor)
+	return nil
+}
@@ -0,0 +1,111 @@
+package notes_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/notes"
+)
+
+func TestListQuery(t *testing.T) {
+	for name, testcase := range map[string]notes.ListQuery{
+		"empty": {},
+		"kind only": {
+			Kind: meta.KindData,
+		},
+		"all fields": {
+			Kind:   meta.KindRun,
+			Id:     "0190a1b2-0000-7000-8000-000000000301",
+			Author: "alice",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			v := testcase.Encode()
+			got := notes.ListQuery{}
+			if err := got.Decode(v); err != nil {
+				t.Fatal(err)
+			}
+			if got != testcase {
+				t.Errorf("round trip: got %+v, want %+v", got, testcase)
+			}
+		})
+	}
+
+	t.Run("empty query is encoded as no parameters", func(t *testing.T) {
+		if v := (notes.ListQuery{}).Encode(); len(v) != 0 {
+			t.Errorf("got %v, want empty", v)
+		}
+	})
+
+	t.Run("invalid kind is rejected", func(t *testing.T) {
+		got := notes.ListQuery{}
+		if err := got.Decode(url.Values{notes.ParamKind: {"pipeline"}}); err == nil {
+			t.Errorf("invalid kind is accepted: %+v", got)
+		}
+	})
+}
+
+func TestCreate_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    notes.Create
+		WantErr bool
+	}{
+		"note on run": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindRun, Id: "0190a1b2-0000-7000-8000-000000000301"},
+				Body:     "failed by OOM",
+			},
+		},
+		"note on data": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindData, Id: "0190a1b2-0000-7000-8000-000000000302"},
+				Body:     "checked by hand",
+			},
+		},
+		"note on plan": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindPlan, Id: "0190a1b2-0000-7000-8000-000000000303"},
+				Body:     "plans cannot have notes",
+			},
+			WantErr: true,
+		},
+		"unknown kind": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: "pipeline", Id: "0190a1b2-0000-7000-8000-000000000304"},
+				Body:     "unknown",
+			},
+			WantErr: true,
+		},
+		"missing id": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindRun},
+				Body:     "no id",
+			},
+			WantErr: true,
+		},
+		"empty body": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindRun, Id: "0190a1b2-0000-7000-8000-000000000305"},
+			},
+			WantErr: true,
+		},
+		"blank body": {
+			When: notes.Create{
+				Resource: meta.ResourceRef{Kind: meta.KindRun, Id: "0190a1b2-0000-7000-8000-000000000306"},
+				Body:     " \t\n",
+			},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+// Package openapi describes types of this module as OpenAPI 3.1 components.
+//
+// Schemas are derived from Go types by their json tags.
+// Types with custom encodings are described by their wire formats:
+// timestamps as "date-time" strings, resources as quantity strings,
+// tags, labels, annotations and images as patterned strings.
+//
+// Struct types become components named "<package>.<Type>", like "plans.Detail",
+// and are referred with "$ref".
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/audit"
+	"github.com/opst/knitfab-api-types/auth"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/errors"
+	"github.com/opst/knitfab-api-types/events"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+	"github.com/opst/knitfab-api-types/webhooks"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Version is the OpenAPI version of documents in this package.
+const Version = "3.1.0"
+
+// Patterns of strings in the wire format.
+const (
+	// PatternTag is the pattern of tags, "key:value".
+	PatternTag = `^[^:]+:.*$`
+
+	// PatternLabel is the pattern of node labels, "key=value".
+	PatternLabel = `^[^=]+=.*$`
+
+	// PatternAnnotation is the pattern of annotations, "key=value".
+	PatternAnnotation = `^[^=]+=.*$`
+
+	// PatternEnv is the pattern of environment variables, "NAME=value".
+	PatternEnv = `^[-._a-zA-Z][-._a-zA-Z0-9]*=.*$`
+
+	// PatternDuration is the pattern of Go-style durations, like "1h30m" or "90s".
+	PatternDuration = `^[-+]?(\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h)((\d+(\.\d*)?|\.\d+)(ns|us|µs|ms|s|m|h))*$|^0$`
+
+	// PatternQuantity is the pattern of Kubernetes resource quantities, like "500m" or "1Gi".
+	PatternQuantity = `^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+|[numkMGTPE]|[KMGTPE]i)?$`
+)
+
+// Schema is a Schema Object of OpenAPI 3.1.
+//
+// Only keywords used in this package are defined.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+}
+
+// Document is a minimal OpenAPI document holding components only.
+type Document struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       Info              `json:"info"`
+	Components DocumentComponent `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type DocumentComponent struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Types are the root types exposed as components.
+//
+// Types referred from them are also exposed.
+var Types = []reflect.Type{
+	reflect.TypeFor[plans.Summary](),
+	reflect.TypeFor[plans.Detail](),
+	reflect.TypeFor[plans.PlanSpec](),
+	reflect.TypeFor[plans.ResourceLimitChange](),
+	reflect.TypeFor[plans.SetServiceAccount](),
+	reflect.TypeFor[plans.AnnotationChange](),
+	reflect.TypeFor[plans.Graph](),
+	reflect.TypeFor[runs.Summary](),
+	reflect.TypeFor[runs.Detail](),
+	reflect.TypeFor[runs.History](),
+	reflect.TypeFor[runs.Spec](),
+	reflect.TypeFor[runs.Metrics](),
+	reflect.TypeFor[data.Summary](),
+	reflect.TypeFor[data.Detail](),
+	reflect.TypeFor[data.Lineage](),
+	reflect.TypeFor[data.Deps](),
+	reflect.TypeFor[data.ImportSpec](),
+	reflect.TypeFor[data.DownloadRef](),
+	reflect.TypeFor[data.RetentionPolicy](),
+	reflect.TypeFor[data.PurgeRequest](),
+	reflect.TypeFor[data.PurgeResult](),
+	reflect.TypeFor[data.RestoreRequest](),
+	reflect.TypeFor[tags.Change](),
+	reflect.TypeFor[events.Event](),
+	reflect.TypeFor[webhooks.Subscription](),
+	reflect.TypeFor[webhooks.Payload](),
+	reflect.TypeFor[errors.ErrorResponse](),
+	reflect.TypeFor[projects.Project](),
+	reflect.TypeFor[projects.Detail](),
+	reflect.TypeFor[meta.Version](),
+	reflect.TypeFor[meta.Health](),
+	reflect.TypeFor[auth.TokenRequest](),
+	reflect.TypeFor[auth.Token](),
+	reflect.TypeFor[auth.RegistryCredential](),
+	reflect.TypeFor[audit.Entry](),
+	reflect.TypeFor[plans.Page](),
+	reflect.TypeFor[runs.Page](),
+	reflect.TypeFor[data.Page](),
+}
+
+// Components returns schemas of Types and types referred from them, by component names.
+func Components() map[string]*Schema {
+	g := generator{components: map[string]*Schema{}}
+	for _, t := range Types {
+		g.schema(t)
+	}
+	return g.components
+}
+
+// NewDocument returns an OpenAPI document with Components.
+func NewDocument(title, version string) Document {
+	return Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Components: DocumentComponent{Schemas: Components()},
+	}
+}
+
+// Name returns the component name for t, like "plans.Detail".
+//
+// Type arguments of generic types are also named so, like "page.Page[plans.Detail]".
+func Name(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	if generic {
+		targs := strings.Split(strings.TrimSuffix(args, "]"), ",")
+		for i, a := range targs {
+			targs[i] = path.Base(a)
+		}
+		name += "[" + strings.Join(targs, ",") + "]"
+	}
+	return path.Base(t.PkgPath()) + "." + name
+}
+
+// Ref returns the reference to the component for t, like "#/components/schemas/plans.Detail".
+func Ref(t reflect.Type) string {
+	return "#/components/schemas/" + Name(t)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// overrides are schemas of types with custom encodings.
+var overrides = map[reflect.Type]func() *Schema{
+	reflect.TypeFor[rfctime.RFC3339](): func() *Schema {
+		return &Schema{Type: "string", Format: "date-time"}
+	},
+	reflect.TypeFor[json.RawMessage](): func() *Schema {
+		return &Schema{Description: "any JSON value"}
+	},
+	reflect.TypeFor[rfctime.RFC3339Nano](): func() *Schema {
+		return &Schema{Type: "string", Format: "date-time"}
+	},
+	reflect.TypeFor[duration.Duration](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternDuration, Description: `duration, like "1h30m" or "90s"`}
+	},
+	reflect.TypeFor[resource.Quantity](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternQuantity}
+	},
+	reflect.TypeFor[tags.Tag](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternTag, Description: `tag in the form "key:value"`}
+	},
+	reflect.TypeFor[tags.UserTag](): func() *Schema {
+		return &Schema{
+			Description: `tag not starting with "` + tags.SystemTagPrefix + `"`,
+			OneOf: []*Schema{
+				{Type: "string", Pattern: PatternTag},
+				{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"key":   {Type: "string"},
+						"value": {Type: "string"},
+					},
+					Required: []string{"key", "value"},
+				},
+			},
+		}
+	},
+	reflect.TypeFor[plans.Image](): func() *Schema {
+		return &Schema{Type: "string", Description: `container image, like "repository:tag" or "repository@sha256:..."`}
+	},
+	reflect.TypeFor[plans.Annotation](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternAnnotation, Description: `annotation in the form "key=value"`}
+	},
+	reflect.TypeFor[plans.EnvVar](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternEnv, Description: `environment variable in the form "NAME=value"`}
+	},
+	reflect.TypeFor[plans.OnSpecLabel](): func() *Schema {
+		return &Schema{Type: "string", Pattern: PatternLabel, Description: `node label in the form "key=value"`}
+	},
+	reflect.TypeFor[knitid.KnitId](): func() *Schema {
+		return &Schema{Type: "string", Format: "uuid"}
+	},
+	reflect.TypeFor[plans.PlanId](): func() *Schema {
+		return &Schema{Type: "string", Format: "uuid"}
+	},
+	reflect.TypeFor[runs.RunId](): func() *Schema {
+		return &Schema{Type: "string", Format: "uuid"}
+	},
+	reflect.TypeFor[plans.Priority](): func() *Schema {
+		s := &Schema{Type: "string"}
+		for _, p := range plans.Priorities() {
+			s.Enum = append(s.Enum, p.String())
+		}
+		return s
+	},
+	reflect.TypeFor[plans.CachePolicy](): func() *Schema {
+		s := &Schema{Type: "string"}
+		for _, c := range plans.CachePolicies() {
+			s.Enum = append(s.Enum, c.String())
+		}
+		return s
+	},
+	reflect.TypeFor[runs.Status](): func() *Schema {
+		s := &Schema{Type: "string"}
+		for _, st := range runs.Statuses() {
+			s.Enum = append(s.Enum, st.String())
+		}
+		return s
+	},
+}
+
+type generator struct {
+	components map[string]*Schema
+}
+
+func (g generator) schema(t reflect.Type) *Schema {
+	if o, ok := overrides[t]; ok {
+		return o()
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := &Schema{Type: "integer", Minimum: ptr(0.0)}
+		if t.Kind() == reflect.Uint8 {
+			s.Maximum = ptr(255.0)
+		}
+		return s
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		name := Name(t)
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // placeholder for recursive types
+			g.components[name] = g.object(t)
+		}
+		return &Schema{Ref: Ref(t)}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type: %s", t))
+}
+
+// object describes a struct type by its fields.
+func (g generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.fields(s, t)
+	slices.Sort(s.Required)
+	return s
+}
+
+func (g generator) fields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if _, custom := overrides[ft]; !custom {
+					g.fields(s, ft)
+					continue
+				}
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := g.schema(f.Type)
+		omitempty := slices.Contains(strings.Split(opts, ","), "omitempty")
+		switch {
+		case f.Type.Kind() == reflect.Pointer && !omitempty:
+			// nil is encoded as null.
+			s.Properties[name] = &Schema{OneOf: []*Schema{fs, {Type: "null"}}}
+			s.Required = append(s.Required, name)
+		case omitempty || f.Type.Kind() == reflect.Pointer:
+			s.Properties[name] = fs
+		default:
+			s.Properties[name] = fs
+			s.Required = append(s.Required, name)
+		}
+	}
+}
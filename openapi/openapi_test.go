@@ -0,0 +1,87 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/openapi"
+)
+
+func TestComponents(t *testing.T) {
+	components := openapi.Components()
+
+	// every reference is resolvable.
+	var walk func(at string, s *openapi.Schema)
+	walk = func(at string, s *openapi.Schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+			if _, ok := components[name]; !ok {
+				t.Errorf("%s: unresolved reference %s", at, s.Ref)
+			}
+		}
+		walk(at+".items", s.Items)
+		walk(at+".additionalProperties", s.AdditionalProperties)
+		for k, p := range s.Properties {
+			walk(at+"."+k, p)
+		}
+		for _, o := range s.OneOf {
+			walk(at+".oneOf", o)
+		}
+	}
+	for name, s := range components {
+		walk(name, s)
+	}
+
+	runSummary := components["runs.Summary"]
+	if runSummary == nil {
+		t.Fatal("runs.Summary is missing")
+	}
+	if got := runSummary.Properties["updatedAt"]; got.Type != "string" || got.Format != "date-time" {
+		t.Errorf("updatedAt: %+v", got)
+	}
+	if got := runSummary.Properties["status"]; !slices.Contains(got.Enum, "done") {
+		t.Errorf("status: %+v", got)
+	}
+	if !slices.Equal(runSummary.Required, []string{"plan", "runId", "status", "updatedAt"}) {
+		t.Errorf("required: %v", runSummary.Required)
+	}
+
+	// embedded structs are flattened.
+	planDetail := components["plans.Detail"]
+	for _, name := range []string{"planId", "image", "inputs", "resources"} {
+		if _, ok := planDetail.Properties[name]; !ok {
+			t.Errorf("plans.Detail.%s is missing", name)
+		}
+	}
+	if got := planDetail.Properties["resources"].AdditionalProperties; got.Pattern != openapi.PatternQuantity {
+		t.Errorf("resources: %+v", got)
+	}
+
+	mountpoint := components["plans.Mountpoint"]
+	if got := mountpoint.Properties["tags"].Items; got.Pattern != openapi.PatternTag {
+		t.Errorf("tags: %+v", got)
+	}
+
+	// nil pointer without omitempty is null.
+	if got := components["runs.Detail"].Properties["log"]; len(got.OneOf) != 2 || got.OneOf[1].Type != "null" {
+		t.Errorf("runs.Detail.log: %+v", got)
+	}
+
+	// generic types are named with short type arguments.
+	if got := components["page.Page[plans.Detail]"]; got == nil || got.Properties["items"].Items.Ref != "#/components/schemas/plans.Detail" {
+		t.Errorf("page.Page[plans.Detail]: %+v", got)
+	}
+
+	doc, err := json.Marshal(openapi.NewDocument("Knitfab", "v1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc), `"openapi":"3.1.0"`) {
+		t.Errorf("unexpected document: %s", doc)
+	}
+}
@@ -0,0 +1,136 @@
+package orphans
+
+import (
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+// Kind is the kind of orphaned resource.
+type Kind string
+
+const (
+	// DataWithoutUpstream is a Data which has no Run created it.
+	DataWithoutUpstream Kind = "data_without_upstream"
+
+	// RunWithPurgedData is a Run which refers Data already purged.
+	RunWithPurgedData Kind = "run_with_purged_data"
+
+	// PlanWithMissingImage is a Plan whose image is not found in the registry.
+	PlanWithMissingImage Kind = "plan_with_missing_image"
+)
+
+// Severity is how serious a Finding is.
+type Severity string
+
+const (
+	// Info is a Finding which can be left as it is.
+	Info Severity = "info"
+
+	// Warning is a Finding which should be looked into.
+	Warning Severity = "warning"
+
+	// Critical is a Finding which breaks lineage or blocks Runs.
+	Critical Severity = "critical"
+)
+
+// rank returns the order of severity. Unknown severities are ranked lowest.
+func (s Severity) rank() int {
+	switch s {
+	case Info:
+		return 1
+	case Warning:
+		return 2
+	case Critical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// AtLeast returns true if s is as serious as or more serious than o.
+func (s Severity) AtLeast(o Severity) bool {
+	return o.rank() <= s.rank()
+}
+
+// Action is an action suggested to resolve a Finding.
+type Action string
+
+const (
+	// Inspect means that the resource should be inspected by human.
+	Inspect Action = "inspect"
+
+	// Delete means that the resource can be deleted.
+	Delete Action = "delete"
+
+	// Invalidate means that the Run should be invalidated.
+	Invalidate Action = "invalidate"
+
+	// Deactivate means that the Plan should be deactivated.
+	Deactivate Action = "deactivate"
+)
+
+// Report is the format for response body from Knitfab APIs below:
+//
+// - GET /api/orphans
+type Report struct {
+	// GeneratedAt is the time when the report is generated.
+	GeneratedAt rfctime.RFC3339 `json:"generatedAt"`
+
+	// Findings are the orphaned resources found.
+	Findings []Finding `json:"findings"`
+}
+
+func (r Report) Equal(o Report) bool {
+	return r.GeneratedAt.Equal(o.GeneratedAt) &&
+		apicmp.SliceEqualUnordered(r.Findings, o.Findings)
+}
+
+// Filter returns Findings as serious as or more serious than min.
+func (r Report) Filter(min Severity) []Finding {
+	found := []Finding{}
+	for _, f := range r.Findings {
+		if f.Severity.AtLeast(min) {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// Finding is an orphaned resource.
+//
+// One of KnitId, Run or Plan is set depending on Kind.
+type Finding struct {
+	// Kind is the kind of the orphaned resource.
+	Kind Kind `json:"kind"`
+
+	// Severity is how serious the Finding is.
+	Severity Severity `json:"severity"`
+
+	// SuggestedAction is the action suggested to resolve the Finding.
+	SuggestedAction Action `json:"suggestedAction"`
+
+	// Message is the human readable description of the Finding.
+	Message string `json:"message,omitempty"`
+
+	// KnitId is the id of the orphaned Data.
+	KnitId knitid.KnitId `json:"knitId,omitempty"`
+
+	// Run is the orphaned Run.
+	Run *runs.Summary `json:"run,omitempty"`
+
+	// Plan is the orphaned Plan.
+	Plan *plans.Summary `json:"plan,omitempty"`
+}
+
+func (f Finding) Equal(o Finding) bool {
+	return f.Kind == o.Kind &&
+		f.Severity == o.Severity &&
+		f.SuggestedAction == o.SuggestedAction &&
+		f.Message == o.Message &&
+		f.KnitId == o.KnitId &&
+		apicmp.PtrEqual(f.Run, o.Run) &&
+		apicmp.PtrEqual(f.Plan, o.Plan)
+}
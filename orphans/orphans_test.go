@@ -0,0 +1,64 @@
+package orphans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/orphans"
+)
+
+func TestSeverity_AtLeast(t *testing.T) {
+	unknown := orphans.Severity("fatal")
+
+	for name, testcase := range map[string]struct {
+		S, Min orphans.Severity
+		Want   bool
+	}{
+		"info, at least info":         {S: orphans.Info, Min: orphans.Info, Want: true},
+		"info, at least warning":      {S: orphans.Info, Min: orphans.Warning, Want: false},
+		"warning, at least info":      {S: orphans.Warning, Min: orphans.Info, Want: true},
+		"warning, at least critical":  {S: orphans.Warning, Min: orphans.Critical, Want: false},
+		"critical, at least warning":  {S: orphans.Critical, Min: orphans.Warning, Want: true},
+		"critical, at least critical": {S: orphans.Critical, Min: orphans.Critical, Want: true},
+		"unknown, at least info":      {S: unknown, Min: orphans.Info, Want: false},
+		"unknown, at least unknown":   {S: unknown, Min: unknown, Want: true},
+		"info, at least unknown":      {S: orphans.Info, Min: unknown, Want: true},
+		"empty, at least info":        {S: "", Min: orphans.Info, Want: false},
+		"info, at least empty":        {S: orphans.Info, Min: "", Want: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := testcase.S.AtLeast(testcase.Min); got != testcase.Want {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+		})
+	}
+}
+
+func TestReport_Filter(t *testing.T) {
+	info := orphans.Finding{Kind: orphans.DataWithoutUpstream, Severity: orphans.Info, KnitId: "0190a1b2-0000-7000-8000-000000000301"}
+	warning := orphans.Finding{Kind: orphans.PlanWithMissingImage, Severity: orphans.Warning, SuggestedAction: orphans.Deactivate}
+	critical := orphans.Finding{Kind: orphans.RunWithPurgedData, Severity: orphans.Critical, SuggestedAction: orphans.Invalidate}
+	unknown := orphans.Finding{Kind: orphans.DataWithoutUpstream, Severity: "fatal"}
+
+	report := orphans.Report{Findings: []orphans.Finding{info, warning, critical, unknown}}
+
+	for name, testcase := range map[string]struct {
+		Min  orphans.Severity
+		Want []orphans.Finding
+	}{
+		"info":     {Min: orphans.Info, Want: []orphans.Finding{info, warning, critical}},
+		"warning":  {Min: orphans.Warning, Want: []orphans.Finding{warning, critical}},
+		"critical": {Min: orphans.Critical, Want: []orphans.Finding{critical}},
+		"empty":    {Min: "", Want: []orphans.Finding{info, warning, critical, unknown}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := report.Filter(testcase.Min); !apicmp.SliceEqual(got, testcase.Want) {
+				t.Errorf("got %+v, want %+v", got, testcase.Want)
+			}
+		})
+	}
+
+	if got := (orphans.Report{}).Filter(orphans.Info); got == nil || len(got) != 0 {
+		t.Errorf("empty report: got %#v, want empty slice", got)
+	}
+}
@@ -0,0 +1,136 @@
+// Package page defines the envelope of paginated list responses, and its query parameters.
+//
+// Servers return a Page for a request with Query. Clients request the next Page
+// with the NextCursor of the last Page, until it becomes empty:
+//
+//	q := page.Query{Limit: 100}
+//	for {
+//		// GET /api/plans/?limit=100&cursor=... as page.Page[plans.Detail]
+//		p := ...
+//		if !p.HasNext() {
+//			break
+//		}
+//		q = p.Next(q)
+//	}
+package page
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// Query parameter names for Query.
+const (
+	ParamLimit  = "limit"
+	ParamCursor = "cursor"
+)
+
+// Query is the query parameters for paginated list APIs.
+//
+// It can be encoded into (or decoded from) the same url.Values with other query parameters,
+// like runs.FindQuery, since their parameter names are not overlapped.
+type Query struct {
+	// Limit is the max number of items in a Page.
+	//
+	// If 0, the server default is used.
+	Limit int
+
+	// Cursor is the position to start the Page, taken from Page.NextCursor.
+	//
+	// If empty, the first Page is requested.
+	Cursor string
+}
+
+func (q Query) Equal(o Query) bool {
+	return q.Limit == o.Limit && q.Cursor == o.Cursor
+}
+
+// Encode returns the query as url.Values.
+func (q Query) Encode() url.Values {
+	v := url.Values{}
+	if q.Limit != 0 {
+		v.Set(ParamLimit, strconv.Itoa(q.Limit))
+	}
+	if q.Cursor != "" {
+		v.Set(ParamCursor, q.Cursor)
+	}
+	return v
+}
+
+// Decode reads the query from url.Values. Other parameters are ignored.
+func (q *Query) Decode(v url.Values) error {
+	ret := Query{}
+	if l := v.Get(ParamLimit); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 0 {
+			return fmt.Errorf(`query parameter "%s" should be a non-negative integer: %q`, ParamLimit, l)
+		}
+		ret.Limit = limit
+	}
+	ret.Cursor = v.Get(ParamCursor)
+	*q = ret
+	return nil
+}
+
+// Page is the envelope of paginated list responses.
+//
+// Cursors are opaque for clients; they should not be parsed nor built by clients.
+type Page[T interface{ Equal(T) bool }] struct {
+	// Items are the items in the Page.
+	Items []T `json:"items"`
+
+	// NextCursor is the cursor to request the next Page.
+	//
+	// If empty, this is the last Page.
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// Total is the total number of items across all Pages.
+	//
+	// If nil, it is not reported, since counting can be expensive.
+	Total *int `json:"total,omitempty"`
+}
+
+func (p Page[T]) Equal(o Page[T]) bool {
+	return p.NextCursor == o.NextCursor &&
+		apicmp.PtrEqEq(p.Total, o.Total) &&
+		apicmp.SliceEqual(p.Items, o.Items)
+}
+
+// HasNext returns true if there is the next Page.
+func (p Page[T]) HasNext() bool {
+	return p.NextCursor != ""
+}
+
+// Next returns the Query for the next Page, keeping the Limit of q.
+func (p Page[T]) Next(q Query) Query {
+	return Query{Limit: q.Limit, Cursor: p.NextCursor}
+}
+
+// Of makes the Page of items for q, taking the cursor as the offset in items.
+//
+// This is a reference implementation for servers and test doubles,
+// whose cursors are decimal offsets. If limit is 0, all items are in the Page.
+func Of[T interface{ Equal(T) bool }](items []T, q Query) (Page[T], error) {
+	offset := 0
+	if q.Cursor != "" {
+		o, err := strconv.Atoi(q.Cursor)
+		if err != nil || o < 0 || len(items) < o {
+			return Page[T]{}, fmt.Errorf("malformed cursor: %q", q.Cursor)
+		}
+		offset = o
+	}
+	end := len(items)
+	if q.Limit != 0 && offset+q.Limit < end {
+		end = offset + q.Limit
+	}
+
+	total := len(items)
+	ret := Page[T]{Items: append([]T{}, items[offset:end]...), Total: &total}
+	if end < len(items) {
+		ret.NextCursor = strconv.Itoa(end)
+	}
+	return ret, nil
+}
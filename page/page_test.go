@@ -0,0 +1,79 @@
+package page_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/page"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+type item int
+
+func (i item) Equal(o item) bool { return i == o }
+
+func TestQuery(t *testing.T) {
+	q := page.Query{Limit: 50, Cursor: "opaque"}
+	v := (runs.FindQuery{Statuses: []runs.Status{runs.Running}}).Encode()
+	for k, vs := range q.Encode() {
+		v[k] = vs
+	}
+
+	got := page.Query{}
+	if err := got.Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(q) {
+		t.Errorf("round trip: got %+v, want %+v", got, q)
+	}
+	fq := runs.FindQuery{}
+	if err := fq.Decode(v); err != nil || len(fq.Statuses) != 1 {
+		t.Errorf("find query is broken: %+v, %v", fq, err)
+	}
+
+	if err := got.Decode(url.Values{page.ParamLimit: {"-1"}}); err == nil {
+		t.Errorf("negative limit is accepted: %+v", got)
+	}
+}
+
+func TestPage(t *testing.T) {
+	items := []item{1, 2, 3, 4, 5}
+
+	all := []item{}
+	q := page.Query{Limit: 2}
+	for n := 0; ; n++ {
+		if 3 < n {
+			t.Fatal("too many pages")
+		}
+		p, err := page.Of(items, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Total == nil || *p.Total != len(items) {
+			t.Errorf("total: %v", p.Total)
+		}
+		all = append(all, p.Items...)
+		if !p.HasNext() {
+			break
+		}
+		q = p.Next(q)
+	}
+	if len(all) != len(items) {
+		t.Errorf("items: got %v, want %v", all, items)
+	}
+
+	if _, err := page.Of(items, page.Query{Cursor: "9"}); err == nil {
+		t.Error("cursor out of range is accepted")
+	}
+
+	total := 1
+	knittest.AssertJSONRoundTrip(t, plans.Page{
+		Items: []plans.Detail{{
+			Summary: plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101", Name: "train"},
+		}},
+		NextCursor: "next",
+		Total:      &total,
+	})
+}
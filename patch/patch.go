@@ -0,0 +1,271 @@
+// Package patch provides types for partial update payloads.
+//
+// Fields with `omitempty` cannot tell "leave this field as is" from "clear this field".
+// Option distinguishes three states of a field in JSON/YAML:
+//
+// - absent: the key is not in the document. The field is left as is.
+//
+// - null: the value is null. The field is cleared (reset to its default).
+//
+// - some: the value is given. The field is set to the value.
+//
+// Since encoding/json and gopkg.in/yaml.v3 do not omit struct-typed fields,
+// and gopkg.in/yaml.v3 does not call UnmarshalYAML for null, types having Options
+// should encode and decode themselves with MarshalJSON, UnmarshalJSON, MarshalYAML and UnmarshalYAML in this package.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+type state int
+
+const (
+	absent state = iota
+	null
+	some
+)
+
+// Option is a tri-state field of patch payloads: absent, null or some value.
+//
+// The zero value is absent.
+type Option[T any] struct {
+	state state
+	value T
+}
+
+// Absent returns the Option which is absent.
+func Absent[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// Null returns the Option which is null.
+func Null[T any]() Option[T] {
+	return Option[T]{state: null}
+}
+
+// Some returns the Option with v.
+func Some[T any](v T) Option[T] {
+	return Option[T]{state: some, value: v}
+}
+
+func (o Option[T]) IsAbsent() bool {
+	return o.state == absent
+}
+
+func (o Option[T]) IsNull() bool {
+	return o.state == null
+}
+
+func (o Option[T]) IsSome() bool {
+	return o.state == some
+}
+
+// Get returns the value and true if the Option has some value.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.state == some
+}
+
+// Or returns the value if the Option has some value, or def otherwise.
+func (o Option[T]) Or(def T) T {
+	if o.state == some {
+		return o.value
+	}
+	return def
+}
+
+// EqualWith returns true if o and other are in the same state,
+// and their values are equal by eq if they have some values.
+func (o Option[T]) EqualWith(other Option[T], eq func(a, b T) bool) bool {
+	return o.state == other.state && (o.state != some || eq(o.value, other.value))
+}
+
+func (o Option[T]) String() string {
+	switch o.state {
+	case null:
+		return "null"
+	case some:
+		return fmt.Sprintf("%v", o.value)
+	}
+	return "(absent)"
+}
+
+func (o *Option[T]) setNull() {
+	*o = Null[T]()
+}
+
+// MarshalJSON encodes the value, or null if the Option is null or absent.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if o.state != some {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Option[T]) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// MarshalYAML encodes the value, or null if the Option is null or absent.
+func (o Option[T]) MarshalYAML() (interface{}, error) {
+	if o.state != some {
+		return nil, nil
+	}
+	return o.value, nil
+}
+
+func (o *Option[T]) UnmarshalYAML(node *yaml.Node) error {
+	if isNull(node) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null"
+}
+
+// Field is a pointer to Option.
+type Field interface {
+	IsAbsent() bool
+	json.Marshaler
+	json.Unmarshaler
+	yaml.Marshaler
+	yaml.Unmarshaler
+	setNull()
+}
+
+// Entry is a key in the document and the Option for it.
+type Entry struct {
+	Key   string
+	Field Field
+}
+
+// MarshalJSON encodes entries as a JSON object, in the order of entries.
+// Absent entries are omitted.
+func MarshalJSON(entries []Entry) ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	first := true
+	for _, e := range entries {
+		if e.Field.IsAbsent() {
+			continue
+		}
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := e.Field.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Key, err)
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object into entries.
+//
+// Keys not in the object are left absent. Keys not in entries are rejected.
+func UnmarshalJSON(b []byte, entries []Entry) error {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	for key := range raw {
+		if find(entries, key) == nil {
+			return fmt.Errorf("unknown field: %q", key)
+		}
+	}
+	for _, e := range entries {
+		v, ok := raw[e.Key]
+		if !ok {
+			continue
+		}
+		if err := e.Field.UnmarshalJSON(v); err != nil {
+			return fmt.Errorf("%s: %w", e.Key, err)
+		}
+	}
+	return nil
+}
+
+// MarshalYAML encodes entries as a YAML mapping, in the order of entries.
+// Absent entries are omitted.
+func MarshalYAML(entries []Entry) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, e := range entries {
+		if e.Field.IsAbsent() {
+			continue
+		}
+		v, err := e.Field.MarshalYAML()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Key, err)
+		}
+		vn := &yaml.Node{}
+		if err := vn.Encode(v); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Key, err)
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: e.Key}, vn)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML decodes a YAML mapping into entries.
+//
+// Keys not in the mapping are left absent. Keys not in entries are rejected.
+func UnmarshalYAML(node *yaml.Node, entries []Entry) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: should be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		e := find(entries, k.Value)
+		if e == nil {
+			return fmt.Errorf("line %d: unknown field: %q", k.Line, k.Value)
+		}
+		if isNull(v) {
+			// yaml.v3 does not call UnmarshalYAML for null.
+			e.Field.setNull()
+			continue
+		}
+		if err := v.Decode(e.Field); err != nil {
+			return fmt.Errorf("line %d: %s: %w", k.Line, k.Value, err)
+		}
+	}
+	return nil
+}
+
+func find(entries []Entry, key string) *Entry {
+	for i := range entries {
+		if entries[i].Key == key {
+			return &entries[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/patch"
+	"gopkg.in/yaml.v3"
+)
+
+type payload struct {
+	Name  patch.Option[string]
+	Count patch.Option[int]
+	Tags  patch.Option[[]string]
+}
+
+func (p *payload) entries() []patch.Entry {
+	return []patch.Entry{
+		{Key: "name", Field: &p.Name},
+		{Key: "count", Field: &p.Count},
+		{Key: "tags", Field: &p.Tags},
+	}
+}
+
+func (p payload) MarshalJSON() ([]byte, error) { return patch.MarshalJSON(p.entries()) }
+
+func (p payload) MarshalYAML() (interface{}, error) { return patch.MarshalYAML(p.entries()) }
+
+func (p *payload) UnmarshalJSON(b []byte) error { return patch.UnmarshalJSON(b, p.entries()) }
+
+func (p *payload) UnmarshalYAML(node *yaml.Node) error { return patch.UnmarshalYAML(node, p.entries()) }
+
+func (p payload) equal(o payload) bool {
+	return p.Name.EqualWith(o.Name, func(a, b string) bool { return a == b }) &&
+		p.Count.EqualWith(o.Count, func(a, b int) bool { return a == b }) &&
+		p.Tags.EqualWith(o.Tags, slices.Equal)
+}
+
+func TestOption(t *testing.T) {
+	if o := patch.Absent[int](); !o.IsAbsent() || o.IsNull() || o.IsSome() || o.Or(3) != 3 {
+		t.Errorf("unexpected absent: %v", o)
+	}
+	if o := patch.Null[int](); o.IsAbsent() || !o.IsNull() || o.IsSome() || o.Or(3) != 3 {
+		t.Errorf("unexpected null: %v", o)
+	}
+	if o := patch.Some(1); o.IsAbsent() || o.IsNull() || !o.IsSome() || o.Or(3) != 1 {
+		t.Errorf("unexpected some: %v", o)
+	}
+	if v, ok := patch.Some(0).Get(); !ok || v != 0 {
+		t.Error("some zero value should have the value")
+	}
+	if (patch.Option[int]{}) != patch.Absent[int]() {
+		t.Error("zero value should be absent")
+	}
+}
+
+func TestOption_document(t *testing.T) {
+	for name, tc := range map[string]struct {
+		json string
+		yaml string
+		want payload
+	}{
+		"absent": {
+			json: `{}`,
+			yaml: `{}`,
+			want: payload{},
+		},
+		"null": {
+			json: `{"name": null, "tags": null}`,
+			yaml: "name: null\ntags: ~\n",
+			want: payload{Name: patch.Null[string](), Tags: patch.Null[[]string]()},
+		},
+		"some": {
+			json: `{"name": "", "count": 0, "tags": []}`,
+			yaml: "name: \"\"\ncount: 0\ntags: []\n",
+			want: payload{Name: patch.Some(""), Count: patch.Some(0), Tags: patch.Some([]string{})},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fromJSON := payload{}
+			if err := json.Unmarshal([]byte(tc.json), &fromJSON); err != nil {
+				t.Fatal(err)
+			}
+			if !fromJSON.equal(tc.want) {
+				t.Errorf("json: got %+v, want %+v", fromJSON, tc.want)
+			}
+
+			fromYAML := payload{}
+			if err := yaml.Unmarshal([]byte(tc.yaml), &fromYAML); err != nil {
+				t.Fatal(err)
+			}
+			if !fromYAML.equal(tc.want) {
+				t.Errorf("yaml: got %+v, want %+v", fromYAML, tc.want)
+			}
+
+			// round trip
+			b, err := json.Marshal(tc.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again := payload{}
+			if err := json.Unmarshal(b, &again); err != nil || !again.equal(tc.want) {
+				t.Errorf("json round trip: %s -> %+v (%v)", b, again, err)
+			}
+			y, err := yaml.Marshal(tc.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again = payload{}
+			if err := yaml.Unmarshal(y, &again); err != nil || !again.equal(tc.want) {
+				t.Errorf("yaml round trip: %s -> %+v (%v)", y, again, err)
+			}
+		})
+	}
+
+	if err := json.Unmarshal([]byte(`{"nmae": "x"}`), &payload{}); err == nil {
+		t.Error("unknown field is accepted")
+	}
+	if err := yaml.Unmarshal([]byte("nmae: x\n"), &payload{}); err == nil {
+		t.Error("unknown field is accepted")
+	}
+	if err := json.Unmarshal([]byte(`{"count": "many"}`), &payload{}); err == nil {
+		t.Error("malformed value is accepted")
+	}
+}
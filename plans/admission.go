@@ -0,0 +1,87 @@
+package plans
+
+import (
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/nodes"
+)
+
+// Admission is the format for response body from Knitfab APIs below:
+//
+// - POST /api/plans/admission (with PlanSpec as request body)
+//
+// It tells whether Runs of the Plan can be scheduled on the cluster at the moment,
+// without registering the Plan.
+type Admission struct {
+	// Schedulable is true if some nodes satisfy both OnNode and Resources of the Plan.
+	Schedulable bool `json:"schedulable"`
+
+	// Nodes are the names of nodes satisfying the Plan.
+	Nodes []string `json:"nodes,omitempty"`
+
+	// ExpectedQueueSeconds is the expected time for a new Run to wait until it starts, in seconds.
+	//
+	// If nil, it is unknown.
+	ExpectedQueueSeconds *float64 `json:"expected_queue_seconds,omitempty"`
+
+	// Blocking are the constraints which prevent Runs from being scheduled.
+	Blocking []Warning `json:"blocking,omitempty"`
+
+	// Advisory are the soft issues which do not prevent scheduling.
+	Advisory []Warning `json:"advisory,omitempty"`
+}
+
+func (a Admission) Equal(o Admission) bool {
+	return a.Schedulable == o.Schedulable &&
+		apicmp.SliceEqEqUnordered(a.Nodes, o.Nodes) &&
+		apicmp.PtrEqEq(a.ExpectedQueueSeconds, o.ExpectedQueueSeconds) &&
+		apicmp.SliceEqualUnordered(a.Blocking, o.Blocking) &&
+		apicmp.SliceEqualUnordered(a.Advisory, o.Advisory)
+}
+
+// Admit checks the spec against the node inventory, and makes Admission.
+//
+// A node satisfies the spec when it has all "must" labels and enough allocatable for all resources.
+// Warnings about "may" and "prefer" (WarnNoTaint and WarnNoPreferredLabel) are advisory,
+// and others are blocking.
+//
+// ExpectedQueueSeconds is left nil, since it depends on the load of the cluster.
+func Admit(spec PlanSpec, ns []nodes.Node) Admission {
+	a := Admission{}
+	for _, n := range ns {
+		if satisfies(spec, n) {
+			a.Nodes = append(a.Nodes, n.Name)
+		}
+	}
+	a.Schedulable = 0 < len(a.Nodes)
+
+	for _, w := range ValidateAgainstNodes(spec, ns) {
+		switch w.Code {
+		case WarnNoTaint, WarnNoPreferredLabel:
+			a.Advisory = append(a.Advisory, w)
+		default:
+			a.Blocking = append(a.Blocking, w)
+		}
+	}
+	if !a.Schedulable && len(a.Blocking) == 0 {
+		a.Blocking = append(a.Blocking, Warning{
+			Code:    WarnUnschedulable,
+			Message: "no node satisfies both on_node and resources at once",
+		})
+	}
+	return a
+}
+
+func satisfies(spec PlanSpec, n nodes.Node) bool {
+	if spec.OnNode != nil && slices.ContainsFunc(spec.OnNode.Must, func(l OnSpecLabel) bool { return !hasLabel(n, l) }) {
+		return false
+	}
+	for name, req := range spec.Resources {
+		alloc, ok := n.Allocatable[name]
+		if !ok || 0 < req.Cmp(alloc) {
+			return false
+		}
+	}
+	return true
+}
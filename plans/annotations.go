@@ -0,0 +1,88 @@
+package plans
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/patch"
+)
+
+// Get returns the value of the annotation with the key.
+//
+// If there are annotations with the same key, the last one wins, as Knitfab does.
+func (ans Annotations) Get(key string) (string, bool) {
+	for i := len(ans) - 1; 0 <= i; i-- {
+		if ans[i].Key == key {
+			return ans[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Set sets the value of the annotation with the key.
+//
+// Existing annotations with the key are replaced.
+func (ans *Annotations) Set(key, value string) {
+	ans.Delete(key)
+	*ans = append(*ans, Annotation{Key: key, Value: value})
+}
+
+// Delete removes all annotations with the key.
+func (ans *Annotations) Delete(key string) {
+	*ans = slices.DeleteFunc(*ans, func(an Annotation) bool { return an.Key == key })
+}
+
+// Keys returns keys of the annotations, sorted and deduplicated.
+func (ans Annotations) Keys() []string {
+	keys := make([]string, 0, len(ans))
+	for _, an := range ans {
+		keys = append(keys, an.Key)
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// AsMap returns the annotations as a map from key to value.
+//
+// If there are annotations with the same key, the last one wins.
+func (ans Annotations) AsMap() map[string]string {
+	m := make(map[string]string, len(ans))
+	for _, an := range ans {
+		m[an.Key] = an.Value
+	}
+	return m
+}
+
+// ChangeTo returns the AnnotationChange which makes ans to target.
+func (ans Annotations) ChangeTo(target Annotations) AnnotationChange {
+	current, want := ans.AsMap(), target.AsMap()
+
+	change := AnnotationChange{}
+	for _, key := range target.Keys() {
+		if v, ok := current[key]; !ok || v != want[key] {
+			change.Add = append(change.Add, Annotation{Key: key, Value: want[key]})
+		}
+	}
+	for _, key := range ans.Keys() {
+		if _, ok := want[key]; !ok {
+			change.RemoveKey = append(change.RemoveKey, key)
+		}
+	}
+	return change
+}
+
+// AnnotationChangeOf returns the AnnotationChange from options by keys:
+// keys with some values are added (or updated), and null keys are removed.
+// Absent keys are ignored.
+func AnnotationChangeOf(options map[string]patch.Option[string]) AnnotationChange {
+	change := AnnotationChange{}
+	for _, key := range slices.Sorted(maps.Keys(options)) {
+		o := options[key]
+		if v, ok := o.Get(); ok {
+			change.Add = append(change.Add, Annotation{Key: key, Value: v})
+		} else if o.IsNull() {
+			change.RemoveKey = append(change.RemoveKey, key)
+		}
+	}
+	return change
+}
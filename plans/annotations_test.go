@@ -0,0 +1,77 @@
+package plans_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/patch"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+func TestAnnotations_mapLike(t *testing.T) {
+	ans := plans.Annotations{
+		{Key: "owner", Value: "alice"},
+		{Key: "team", Value: "ml"},
+		{Key: "owner", Value: "bob"},
+	}
+
+	if v, ok := ans.Get("owner"); !ok || v != "bob" {
+		t.Errorf("Get: got (%q, %v)", v, ok)
+	}
+	if v, ok := ans.Get("missing"); ok {
+		t.Errorf("Get: got (%q, %v)", v, ok)
+	}
+	if got := ans.Keys(); !slices.Equal(got, []string{"owner", "team"}) {
+		t.Errorf("Keys: got %v", got)
+	}
+	if got := ans.AsMap(); !maps.Equal(got, map[string]string{"owner": "bob", "team": "ml"}) {
+		t.Errorf("AsMap: got %v", got)
+	}
+
+	ans.Set("owner", "carol")
+	if !ans.Equal(plans.Annotations{{Key: "team", Value: "ml"}, {Key: "owner", Value: "carol"}}) {
+		t.Errorf("Set: got %v", ans)
+	}
+
+	ans.Delete("team")
+	if !ans.Equal(plans.Annotations{{Key: "owner", Value: "carol"}}) {
+		t.Errorf("Delete: got %v", ans)
+	}
+}
+
+func TestAnnotations_ChangeTo(t *testing.T) {
+	current := plans.Annotations{
+		{Key: "owner", Value: "alice"},
+		{Key: "team", Value: "ml"},
+		{Key: "stale", Value: "yes"},
+	}
+	target := plans.Annotations{
+		{Key: "owner", Value: "bob"},
+		{Key: "team", Value: "ml"},
+		{Key: "new", Value: "1"},
+	}
+
+	got := current.ChangeTo(target)
+	want := plans.AnnotationChange{
+		Add:       plans.Annotations{{Key: "new", Value: "1"}, {Key: "owner", Value: "bob"}},
+		RemoveKey: []string{"stale"},
+	}
+	if !got.Add.Equal(want.Add) || !slices.Equal(got.RemoveKey, want.RemoveKey) || len(got.Remove) != 0 {
+		t.Errorf("ChangeTo: got %+v", got)
+	}
+}
+
+func TestAnnotationChangeOf(t *testing.T) {
+	change := plans.AnnotationChangeOf(map[string]patch.Option[string]{
+		"owner": patch.Some("ml-team"),
+		"memo":  patch.Null[string](),
+		"other": patch.Absent[string](),
+	})
+	if len(change.Add) != 1 || change.Add[0] != (plans.Annotation{Key: "owner", Value: "ml-team"}) {
+		t.Errorf("unexpected add: %v", change.Add)
+	}
+	if !slices.Equal(change.RemoveKey, []string{"memo"}) || len(change.Remove) != 0 {
+		t.Errorf("unexpected remove: %v, %v", change.RemoveKey, change.Remove)
+	}
+}
@@ -0,0 +1,350 @@
+package plans
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SpecBuilder builds PlanSpec fluently.
+//
+// Each method validates its arguments. Once an error is found,
+// the following methods do nothing, and Build returns the error.
+//
+// Example:
+//
+//	spec, err := plans.NewSpec().
+//		WithImage("example.com/train:v1").
+//		AddInput("/in/dataset", "type:dataset").
+//		AddOutput("/out/model", "type:model").
+//		WithLog("type:log").
+//		Build()
+type SpecBuilder struct {
+	spec PlanSpec
+	err  error
+}
+
+// NewSpec starts building a PlanSpec.
+func NewSpec() *SpecBuilder {
+	return &SpecBuilder{}
+}
+
+func (b *SpecBuilder) fail(format string, args ...any) *SpecBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+	return b
+}
+
+// WithImage sets the image, like "repository:tag".
+func (b *SpecBuilder) WithImage(image string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := b.spec.Image.Parse(image); err != nil {
+		return b.fail("image: %w", err)
+	}
+	return b
+}
+
+// WithEntrypoint sets the entrypoint.
+func (b *SpecBuilder) WithEntrypoint(entrypoint ...string) *SpecBuilder {
+	b.spec.Entrypoint = entrypoint
+	return b
+}
+
+// WithArgs sets the arguments.
+func (b *SpecBuilder) WithArgs(args ...string) *SpecBuilder {
+	b.spec.Args = args
+	return b
+}
+
+// WithEnv appends an environment variable.
+func (b *SpecBuilder) WithEnv(name string, value string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	e := EnvVar{Name: name, Value: value}
+	if err := e.Validate(); err != nil {
+		return b.fail("env: %w", err)
+	}
+	b.spec.Env = append(b.spec.Env, e)
+	return b
+}
+
+// WithWorkingDir sets the working directory. It should be absolute.
+func (b *SpecBuilder) WithWorkingDir(dir string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if !path.IsAbs(dir) {
+		return b.fail(`working_dir: path should be absolute: "%s"`, dir)
+	}
+	b.spec.WorkingDir = dir
+	return b
+}
+
+// WithRunAs sets the UID and GID to run the container.
+func (b *SpecBuilder) WithRunAs(uid, gid int64) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if uid < 0 || gid < 0 {
+		return b.fail("run_as: should not be negative: %d:%d", uid, gid)
+	}
+	b.spec.RunAsUser = &uid
+	b.spec.RunAsGroup = &gid
+	return b
+}
+
+// AddSecretFile mounts the Secret (or its key, if given) at path.
+func (b *SpecBuilder) AddSecretFile(name string, key string, path string) *SpecBuilder {
+	return b.addSecret(SecretMount{Name: name, Key: key, Path: path})
+}
+
+// AddSecretEnv sets the value of the key in the Secret to the environment variable env.
+func (b *SpecBuilder) AddSecretEnv(name string, key string, env string) *SpecBuilder {
+	return b.addSecret(SecretMount{Name: name, Key: key, Env: env})
+}
+
+func (b *SpecBuilder) addSecret(s SecretMount) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := s.Validate(); err != nil {
+		return b.fail("secrets[%d]: %w", len(b.spec.Secrets), err)
+	}
+	b.spec.Secrets = append(b.spec.Secrets, s)
+	return b
+}
+
+// AddSidecar adds a sidecar container.
+func (b *SpecBuilder) AddSidecar(c Container) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := c.Validate(); err != nil {
+		return b.fail("sidecars[%d]: %w", len(b.spec.Sidecars), err)
+	}
+	b.spec.Sidecars = append(b.spec.Sidecars, c)
+	return b
+}
+
+// AddInput adds an input mountpoint. Tags are in the form "key:value".
+func (b *SpecBuilder) AddInput(path string, tags ...string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	field := fmt.Sprintf("inputs[%d]", len(b.spec.Inputs))
+	mp, err := mountpoint(path, tags)
+	if err != nil {
+		return b.fail("%s: %w", field, err)
+	}
+	b.spec.Inputs = append(b.spec.Inputs, mp)
+	return b
+}
+
+// AddOutput adds an output mountpoint. Tags are in the form "key:value".
+func (b *SpecBuilder) AddOutput(path string, tags ...string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	field := fmt.Sprintf("outputs[%d]", len(b.spec.Outputs))
+	mp, err := mountpoint(path, tags)
+	if err != nil {
+		return b.fail("%s: %w", field, err)
+	}
+	if err := noSystemTags(mp.Tags); err != nil {
+		return b.fail("%s: %w", field, err)
+	}
+	b.spec.Outputs = append(b.spec.Outputs, mp)
+	return b
+}
+
+// WithLog sets the log point. Tags are in the form "key:value".
+func (b *SpecBuilder) WithLog(tags ...string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	ts, err := parseTags(tags)
+	if err != nil {
+		return b.fail("log: %w", err)
+	}
+	if err := noSystemTags(ts); err != nil {
+		return b.fail("log: %w", err)
+	}
+	b.spec.Log = &LogPoint{Tags: ts}
+	return b
+}
+
+// WithAnnotation adds an annotation.
+func (b *SpecBuilder) WithAnnotation(key, value string) *SpecBuilder {
+	b.spec.Annotations = append(b.spec.Annotations, Annotation{Key: key, Value: value})
+	return b
+}
+
+// OnNodeMay adds a label in the form "key=value" to OnNode.May.
+func (b *SpecBuilder) OnNodeMay(label string) *SpecBuilder {
+	return b.onNode("may", label, func(on *OnNode, l OnSpecLabel) { on.May = append(on.May, l) })
+}
+
+// OnNodePrefer adds a label in the form "key=value" to OnNode.Prefer.
+func (b *SpecBuilder) OnNodePrefer(label string) *SpecBuilder {
+	return b.onNode("prefer", label, func(on *OnNode, l OnSpecLabel) { on.Prefer = append(on.Prefer, l) })
+}
+
+// OnNodeMust adds a label in the form "key=value" to OnNode.Must.
+func (b *SpecBuilder) OnNodeMust(label string) *SpecBuilder {
+	return b.onNode("must", label, func(on *OnNode, l OnSpecLabel) { on.Must = append(on.Must, l) })
+}
+
+func (b *SpecBuilder) onNode(field string, label string, add func(*OnNode, OnSpecLabel)) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	l := OnSpecLabel{}
+	if err := l.Parse(label); err != nil {
+		return b.fail("on_node.%s: %w", field, err)
+	}
+	if b.spec.OnNode == nil {
+		b.spec.OnNode = &OnNode{}
+	}
+	add(b.spec.OnNode, l)
+	return b
+}
+
+// WithResource sets a resource, like ("cpu", "500m") or ("memory", "1Gi").
+func (b *SpecBuilder) WithResource(name string, quantity string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	q, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return b.fail("resources.%s: %w", name, err)
+	}
+	if q.Sign() <= 0 {
+		return b.fail("resources.%s: should be positive: %s", name, quantity)
+	}
+	if b.spec.Resources == nil {
+		b.spec.Resources = Resources{}
+	}
+	b.spec.Resources[name] = q
+	return b
+}
+
+// WithTimeout sets the maximum duration of Runs, from their start.
+func (b *SpecBuilder) WithTimeout(timeout time.Duration) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if timeout <= 0 {
+		return b.fail("timeout: should be positive: %s", timeout)
+	}
+	d := duration.Duration(timeout)
+	b.spec.Timeout = &d
+	return b
+}
+
+// WithDeadline sets the maximum duration of Runs, from their creation.
+func (b *SpecBuilder) WithDeadline(deadline time.Duration) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if deadline <= 0 {
+		return b.fail("deadline: should be positive: %s", deadline)
+	}
+	d := duration.Duration(deadline)
+	b.spec.Deadline = &d
+	return b
+}
+
+// WithPriority sets the priority class, like "high".
+func (b *SpecBuilder) WithPriority(priority string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := b.spec.Priority.Parse(priority); err != nil {
+		return b.fail("priority: %w", err)
+	}
+	return b
+}
+
+// WithSchedule sets the schedule, like ("0 3 * * *", "Asia/Tokyo").
+func (b *SpecBuilder) WithSchedule(cron string, timezone string) *SpecBuilder {
+	if b.err != nil {
+		return b
+	}
+	s := Schedule{Cron: cron, Timezone: timezone}
+	if err := s.Validate(); err != nil {
+		return b.fail("schedule: %w", err)
+	}
+	b.spec.Schedule = &s
+	return b
+}
+
+// WithServiceAccount sets the service account.
+func (b *SpecBuilder) WithServiceAccount(serviceAccount string) *SpecBuilder {
+	b.spec.ServiceAccount = serviceAccount
+	return b
+}
+
+// WithActive sets the activeness.
+func (b *SpecBuilder) WithActive(active bool) *SpecBuilder {
+	b.spec.Active = &active
+	return b
+}
+
+// WithProject sets the project.
+func (b *SpecBuilder) WithProject(project string) *SpecBuilder {
+	b.spec.Project = project
+	return b
+}
+
+// Build returns the PlanSpec.
+//
+// It returns the first error found while building, or the error of PlanSpec.Validate.
+func (b *SpecBuilder) Build() (PlanSpec, error) {
+	if b.err != nil {
+		return PlanSpec{}, b.err
+	}
+	if err := b.spec.Validate(); err != nil {
+		return PlanSpec{}, err
+	}
+	return b.spec, nil
+}
+
+// MustBuild is Build, but panics on error. This is useful in tests.
+func (b *SpecBuilder) MustBuild() PlanSpec {
+	spec, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func mountpoint(path string, tags []string) (Mountpoint, error) {
+	if err := validatePath(path); err != nil {
+		return Mountpoint{}, err
+	}
+	ts, err := parseTags(tags)
+	if err != nil {
+		return Mountpoint{}, err
+	}
+	return Mountpoint{Path: path, Tags: ts}, nil
+}
+
+func parseTags(exprs []string) ([]tags.Tag, error) {
+	ts := make([]tags.Tag, 0, len(exprs))
+	for _, expr := range exprs {
+		t := tags.Tag{}
+		if err := t.Parse(expr); err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
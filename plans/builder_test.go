@@ -0,0 +1,106 @@
+package plans_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSpecBuilder(t *testing.T) {
+	got, err := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		WithEntrypoint("python", "train.py").
+		WithArgs("--epochs", "10").
+		AddInput("/in/dataset", "type:dataset", "format:csv").
+		AddOutput("/out/model", "type:model").
+		WithLog("type:log").
+		WithAnnotation("owner", "ml-team").
+		OnNodeMust("accelerator=gpu").
+		OnNodePrefer("zone=a").
+		WithResource("cpu", "500m").
+		WithTimeout(2 * time.Hour).
+		WithDeadline(6 * time.Hour).
+		WithServiceAccount("trainer").
+		WithActive(true).
+		WithProject("demo").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active := true
+	timeout := duration.Duration(2 * time.Hour)
+	deadline := duration.Duration(6 * time.Hour)
+	want := plans.PlanSpec{
+		Image:      plans.Image{Repository: "example.com/train", Tag: "v1"},
+		Entrypoint: []string{"python", "train.py"},
+		Args:       []string{"--epochs", "10"},
+		Inputs: []plans.Mountpoint{
+			{Path: "/in/dataset", Tags: []tags.Tag{{Key: "type", Value: "dataset"}, {Key: "format", Value: "csv"}}},
+		},
+		Outputs:     []plans.Mountpoint{{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}}},
+		Log:         &plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}},
+		Annotations: plans.Annotations{{Key: "owner", Value: "ml-team"}},
+		OnNode: &plans.OnNode{
+			Must:   []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}},
+			Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+		},
+		Resources:      plans.Resources{"cpu": resource.MustParse("500m")},
+		Timeout:        &timeout,
+		Deadline:       &deadline,
+		ServiceAccount: "trainer",
+		Active:         &active,
+		Project:        "demo",
+	}
+	if diffs := got.Diff(want); diffs != nil {
+		t.Errorf("unexpected spec: %v", diffs)
+	}
+
+	for name, b := range map[string]*plans.SpecBuilder{
+		"malformed image": plans.NewSpec().WithImage("UPPER CASE").AddInput("/in", "type:x"),
+		"malformed tag":   plans.NewSpec().WithImage("repo:v1").AddInput("/in", "no-colon"),
+		"relative path":   plans.NewSpec().WithImage("repo:v1").AddInput("in", "type:x"),
+		"system tag on output": plans.NewSpec().WithImage("repo:v1").AddInput("/in", "type:x").
+			AddOutput("/out", "knit#id:x"),
+		"malformed label":    plans.NewSpec().WithImage("repo:v1").AddInput("/in", "type:x").OnNodeMust("no-equal"),
+		"malformed resource": plans.NewSpec().WithImage("repo:v1").AddInput("/in", "type:x").WithResource("cpu", "a lot"),
+		"no inputs":          plans.NewSpec().WithImage("repo:v1"),
+		"zero timeout":       plans.NewSpec().WithImage("repo:v1").AddInput("/in", "type:x").WithTimeout(0),
+		"negative deadline":  plans.NewSpec().WithImage("repo:v1").AddInput("/in", "type:x").WithDeadline(-time.Minute),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if spec, err := b.Build(); err == nil {
+				t.Errorf("invalid spec is built: %+v", spec)
+			}
+		})
+	}
+}
+
+func TestPlanSpec_timeout(t *testing.T) {
+	spec := plans.NewSpec().
+		WithImage("repo:v1").
+		AddInput("/in", "type:x").
+		WithTimeout(90 * time.Minute).
+		MustBuild()
+	knittest.AssertRoundTrip(t, spec)
+
+	other := spec.Clone()
+	*other.Timeout = duration.Duration(time.Hour)
+	if spec.Equal(other) || spec.Hash() == other.Hash() {
+		t.Errorf("timeout is not taken into account")
+	}
+	if *spec.Timeout != duration.Duration(90*time.Minute) {
+		t.Errorf("clone shares timeout")
+	}
+
+	zero := duration.Duration(0)
+	other.Deadline = &zero
+	if err := other.Validate(); err == nil {
+		t.Errorf("zero deadline is accepted")
+	}
+}
@@ -0,0 +1,83 @@
+package plans
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CachePolicy tells whether Knitfab may reuse existing Data for an output,
+// instead of running the Plan again (memoization).
+//
+// The zero value ("") means "unspecified", and is treated as CacheNever.
+type CachePolicy string
+
+const (
+	// CacheNever: new Data is always produced by a new Run.
+	CacheNever CachePolicy = "never"
+
+	// CacheReuse: if a Run of the same Plan has been done with identical input Data,
+	// Knitfab may reuse its output Data instead of starting a new Run.
+	CacheReuse CachePolicy = "reuse"
+)
+
+// CachePolicies returns all known CachePolicies.
+func CachePolicies() []CachePolicy {
+	return []CachePolicy{CacheNever, CacheReuse}
+}
+
+func (c CachePolicy) String() string {
+	return string(c)
+}
+
+// Valid returns true if c is one of known CachePolicies or unspecified.
+func (c CachePolicy) Valid() bool {
+	switch c {
+	case "", CacheNever, CacheReuse:
+		return true
+	}
+	return false
+}
+
+// Effective returns c, or CacheNever if c is unspecified.
+func (c CachePolicy) Effective() CachePolicy {
+	if c == "" {
+		return CacheNever
+	}
+	return c
+}
+
+// Parse parses v as CachePolicy, and returns error if it is not known.
+func (c *CachePolicy) Parse(v string) error {
+	cp := CachePolicy(v)
+	if !cp.Valid() {
+		return fmt.Errorf("unknown cache policy: %q", v)
+	}
+	*c = cp
+	return nil
+}
+
+func (c CachePolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(c))
+}
+
+func (c *CachePolicy) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return c.Parse(v)
+}
+
+func (c CachePolicy) MarshalYAML() (interface{}, error) {
+	return string(c), nil
+}
+
+func (c *CachePolicy) UnmarshalYAML(node *yaml.Node) error {
+	var v string
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	return c.Parse(v)
+}
@@ -0,0 +1,82 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestCachePolicy(t *testing.T) {
+	for _, c := range plans.CachePolicies() {
+		if !c.Valid() {
+			t.Errorf("%s: should be valid", c)
+		}
+		var got plans.CachePolicy
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &got); err != nil || got != c {
+			t.Errorf("%s: JSON round trip: got %s (%v)", c, got, err)
+		}
+	}
+	if plans.CachePolicy("").Effective() != plans.CacheNever {
+		t.Error("unspecified cache policy should be never")
+	}
+	var c plans.CachePolicy
+	if err := json.Unmarshal([]byte(`"sometimes"`), &c); err == nil {
+		t.Errorf("unknown cache policy is accepted: %s", c)
+	}
+}
+
+func TestPlanSpec_cachePolicy(t *testing.T) {
+	spec := plans.PlanSpec{
+		Image:  plans.Image{Repository: "repo", Tag: "v1"},
+		Inputs: []plans.Mountpoint{{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+		Outputs: []plans.Mountpoint{
+			{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}, CachePolicy: plans.CacheReuse},
+			{Path: "/out/report", Tags: []tags.Tag{{Key: "type", Value: "report"}}},
+		},
+	}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	b, err := json.Marshal(spec.Outputs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"cache_policy":"reuse"`) {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	never := spec.Clone()
+	never.Outputs[1].CachePolicy = plans.CacheNever
+	if spec.Equal(never) {
+		t.Errorf("cache policies are not compared")
+	}
+	if spec.Hash() != never.Hash() {
+		t.Errorf("unspecified and never should have the same hash")
+	}
+	noReuse := spec.Clone()
+	noReuse.Outputs[0].CachePolicy = ""
+	if spec.Hash() == noReuse.Hash() {
+		t.Errorf("cache policy is not taken into account")
+	}
+
+	for name, mod := range map[string]func(*plans.PlanSpec){
+		"on input": func(ps *plans.PlanSpec) { ps.Inputs[0].CachePolicy = plans.CacheReuse },
+		"unknown":  func(ps *plans.PlanSpec) { ps.Outputs[0].CachePolicy = "sometimes" },
+	} {
+		ps := spec.Clone()
+		mod(&ps)
+		if err := ps.Validate(); err == nil {
+			t.Errorf("%s: invalid spec is accepted", name)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package plans
+
+import (
+	"slices"
+
+	"github.com/opst/knitfab-api-types/internal/clone"
+)
+
+// Clone returns a deep copy of the Summary.
+func (s Summary) Clone() Summary {
+	return Summary{
+		PlanId:      s.PlanId,
+		Image:       clone.Ptr(s.Image),
+		Entrypoint:  slices.Clone(s.Entrypoint),
+		Args:        slices.Clone(s.Args),
+		Name:        s.Name,
+		Annotations: slices.Clone(s.Annotations),
+		Project:     s.Project,
+	}
+}
+
+// Clone returns a deep copy of the Detail.
+func (d Detail) Clone() Detail {
+	return Detail{
+		Summary:        d.Summary.Clone(),
+		Env:            slices.Clone(d.Env),
+		WorkingDir:     d.WorkingDir,
+		RunAsUser:      clone.Ptr(d.RunAsUser),
+		RunAsGroup:     clone.Ptr(d.RunAsGroup),
+		Secrets:        slices.Clone(d.Secrets),
+		Sidecars:       clone.SliceWith(d.Sidecars, Container.Clone),
+		Inputs:         clone.SliceWith(d.Inputs, Input.Clone),
+		Outputs:        clone.SliceWith(d.Outputs, Output.Clone),
+		Log:            clone.PtrWith(d.Log, Log.Clone),
+		Active:         d.Active,
+		OnNode:         clone.PtrWith(d.OnNode, OnNode.Clone),
+		Resources:      d.Resources.Clone(),
+		Timeout:        clone.Ptr(d.Timeout),
+		Deadline:       clone.Ptr(d.Deadline),
+		Priority:       d.Priority,
+		Schedule:       clone.Ptr(d.Schedule),
+		ServiceAccount: d.ServiceAccount,
+		Warnings:       slices.Clone(d.Warnings),
+		Extensions:     d.Extensions.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the PlanSpec.
+func (ps PlanSpec) Clone() PlanSpec {
+	return PlanSpec{
+		Annotations:    slices.Clone(ps.Annotations),
+		Image:          ps.Image,
+		Entrypoint:     slices.Clone(ps.Entrypoint),
+		Args:           slices.Clone(ps.Args),
+		Env:            slices.Clone(ps.Env),
+		WorkingDir:     ps.WorkingDir,
+		RunAsUser:      clone.Ptr(ps.RunAsUser),
+		RunAsGroup:     clone.Ptr(ps.RunAsGroup),
+		Secrets:        slices.Clone(ps.Secrets),
+		Sidecars:       clone.SliceWith(ps.Sidecars, Container.Clone),
+		Inputs:         clone.SliceWith(ps.Inputs, Mountpoint.Clone),
+		Outputs:        clone.SliceWith(ps.Outputs, Mountpoint.Clone),
+		Log:            clone.PtrWith(ps.Log, LogPoint.Clone),
+		OnNode:         clone.PtrWith(ps.OnNode, OnNode.Clone),
+		Resources:      ps.Resources.Clone(),
+		Timeout:        clone.Ptr(ps.Timeout),
+		Deadline:       clone.Ptr(ps.Deadline),
+		Priority:       ps.Priority,
+		Schedule:       clone.Ptr(ps.Schedule),
+		ServiceAccount: ps.ServiceAccount,
+		Active:         clone.Ptr(ps.Active),
+		Project:        ps.Project,
+	}
+}
+
+// Clone returns a deep copy of the Mountpoint.
+func (m Mountpoint) Clone() Mountpoint {
+	return Mountpoint{
+		Path:        m.Path,
+		Tags:        slices.Clone(m.Tags),
+		ReadOnly:    m.ReadOnly,
+		SubPath:     m.SubPath,
+		CachePolicy: m.CachePolicy,
+	}
+}
+
+// Clone returns a deep copy of the LogPoint.
+func (lp LogPoint) Clone() LogPoint {
+	return LogPoint{Tags: slices.Clone(lp.Tags)}
+}
+
+// Clone returns a deep copy of the Upstream.
+func (d Upstream) Clone() Upstream {
+	return Upstream{
+		Plan:       d.Plan.Clone(),
+		Mountpoint: clone.PtrWith(d.Mountpoint, Mountpoint.Clone),
+		Log:        clone.PtrWith(d.Log, LogPoint.Clone),
+	}
+}
+
+// Clone returns a deep copy of the Downstream.
+func (d Downstream) Clone() Downstream {
+	return Downstream{Plan: d.Plan.Clone(), Mountpoint: d.Mountpoint.Clone()}
+}
+
+// Clone returns a deep copy of the Input.
+func (i Input) Clone() Input {
+	return Input{
+		Mountpoint: i.Mountpoint.Clone(),
+		Upstreams:  clone.SliceWith(i.Upstreams, Upstream.Clone),
+	}
+}
+
+// Clone returns a deep copy of the Output.
+func (o Output) Clone() Output {
+	return Output{
+		Mountpoint:  o.Mountpoint.Clone(),
+		Downstreams: clone.SliceWith(o.Downstreams, Downstream.Clone),
+	}
+}
+
+// Clone returns a deep copy of the Log.
+func (l Log) Clone() Log {
+	return Log{
+		LogPoint:    l.LogPoint.Clone(),
+		Downstreams: clone.SliceWith(l.Downstreams, Downstream.Clone),
+	}
+}
+
+// Clone returns a deep copy of the OnNode.
+func (o OnNode) Clone() OnNode {
+	return OnNode{
+		May:    slices.Clone(o.May),
+		Prefer: slices.Clone(o.Prefer),
+		Must:   slices.Clone(o.Must),
+	}
+}
+
+// Clone returns a deep copy of the Resources.
+func (r Resources) Clone() Resources {
+	if r == nil {
+		return nil
+	}
+	ret := make(Resources, len(r))
+	for k, v := range r {
+		ret[k] = v.DeepCopy()
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ResourceLimitChange.
+func (r ResourceLimitChange) Clone() ResourceLimitChange {
+	return ResourceLimitChange{Set: r.Set.Clone(), Unset: slices.Clone(r.Unset)}
+}
+
+// Clone returns a deep copy of the AnnotationChange.
+func (a AnnotationChange) Clone() AnnotationChange {
+	return AnnotationChange{
+		Add:       slices.Clone(a.Add),
+		Remove:    slices.Clone(a.Remove),
+		RemoveKey: slices.Clone(a.RemoveKey),
+	}
+}
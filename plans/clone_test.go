@@ -0,0 +1,126 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDetail_Clone(t *testing.T) {
+	upstream := plans.Summary{PlanId: "up", Image: &plans.Image{Repository: "repo", Tag: "v1"}}
+	original := plans.Detail{
+		Summary: plans.Summary{
+			PlanId:      "plan-1",
+			Image:       &plans.Image{Repository: "repo", Tag: "v2"},
+			Entrypoint:  []string{"python"},
+			Annotations: plans.Annotations{{Key: "owner", Value: "ml-team"}},
+		},
+		Inputs: []plans.Input{
+			{
+				Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}},
+				Upstreams: []plans.Upstream{
+					{Plan: upstream, Mountpoint: &plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+				},
+			},
+		},
+		Log:       &plans.Log{LogPoint: plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}}},
+		OnNode:    &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "zone", Value: "a"}}},
+		Resources: plans.Resources{"cpu": resource.MustParse("1")},
+	}
+	snapshot := original.Clone()
+
+	cloned := original.Clone()
+	if !cloned.Equal(original) {
+		t.Fatalf("clone is not equal: %+v", cloned)
+	}
+
+	cloned.Image.Tag = "mutated"
+	cloned.Entrypoint[0] = "mutated"
+	cloned.Annotations[0].Value = "mutated"
+	cloned.Inputs[0].Tags[0].Value = "mutated"
+	cloned.Inputs[0].Upstreams[0].Plan.Image.Tag = "mutated"
+	cloned.Inputs[0].Upstreams[0].Mountpoint.Path = "/mutated"
+	cloned.Log.Tags[0].Value = "mutated"
+	cloned.OnNode.Must[0].Value = "mutated"
+	cloned.Resources["cpu"] = resource.MustParse("2")
+
+	if !original.Equal(snapshot) {
+		t.Errorf("original is modified through clone: %v", original.Diff(snapshot))
+	}
+}
+
+func TestPlanSpec_Clone(t *testing.T) {
+	active := true
+	original := plans.NewSpec().
+		WithImage("repo:v1").
+		AddInput("/in", "type:dataset").
+		WithLog("type:log").
+		WithResource("memory", "1Gi").
+		WithActive(active).
+		MustBuild()
+	snapshot := original.Clone()
+
+	cloned := original.Clone()
+	cloned.Inputs[0].Tags[0].Value = "mutated"
+	cloned.Log.Tags[0].Value = "mutated"
+	cloned.Resources["memory"] = resource.MustParse("2Gi")
+	*cloned.Active = false
+
+	if !original.Equal(snapshot) {
+		t.Errorf("original is modified through clone: %v", original.Diff(snapshot))
+	}
+}
+
+func TestDetail_ToSpec(t *testing.T) {
+	detail := plans.Detail{
+		Summary: plans.Summary{
+			PlanId:      "plan-1",
+			Image:       &plans.Image{Repository: "repo", Tag: "v1"},
+			Entrypoint:  []string{"python"},
+			Args:        []string{"main.py"},
+			Annotations: plans.Annotations{{Key: "owner", Value: "ml-team"}},
+		},
+		Inputs: []plans.Input{
+			{
+				Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}},
+				Upstreams:  []plans.Upstream{{Plan: plans.Summary{PlanId: "up"}}},
+			},
+		},
+		Outputs: []plans.Output{
+			{
+				Mountpoint:  plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+				Downstreams: []plans.Downstream{{Plan: plans.Summary{PlanId: "down"}}},
+			},
+		},
+		Log:            &plans.Log{LogPoint: plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}}},
+		Active:         false,
+		OnNode:         &plans.OnNode{Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}}},
+		Resources:      plans.Resources{"cpu": resource.MustParse("1")},
+		ServiceAccount: "trainer",
+	}
+
+	got := detail.ToSpec()
+	want := plans.NewSpec().
+		WithImage("repo:v1").
+		WithEntrypoint("python").
+		WithArgs("main.py").
+		WithAnnotation("owner", "ml-team").
+		AddInput("/in", "type:dataset").
+		AddOutput("/out", "type:model").
+		WithLog("type:log").
+		OnNodePrefer("zone=a").
+		WithResource("cpu", "1").
+		WithServiceAccount("trainer").
+		WithActive(false).
+		MustBuild()
+	if diffs := got.Diff(want); diffs != nil {
+		t.Errorf("unexpected spec: %v", diffs)
+	}
+
+	got.Inputs[0].Tags[0].Value = "mutated"
+	if detail.Inputs[0].Tags[0].Value != "dataset" {
+		t.Error("spec shares tags with detail")
+	}
+}
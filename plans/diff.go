@@ -0,0 +1,23 @@
+package plans
+
+import "github.com/opst/knitfab-api-types/apidiff"
+
+// Diff returns differences from o, field by field.
+//
+// If d.Equal(o), it returns nil even if their JSON differ, for example, in order of Inputs.
+func (d Detail) Diff(o Detail) []apidiff.FieldDiff {
+	if d.Equal(o) {
+		return nil
+	}
+	return apidiff.Of(d, o)
+}
+
+// Diff returns differences from o, field by field.
+//
+// If ps.Equal(o), it returns nil even if their JSON differ, for example, in order of Inputs.
+func (ps PlanSpec) Diff(o PlanSpec) []apidiff.FieldDiff {
+	if ps.Equal(o) {
+		return nil
+	}
+	return apidiff.Of(ps, o)
+}
@@ -0,0 +1,76 @@
+package plans
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// EnvVar is an environment variable of the container of the Plan.
+//
+// In JSON and YAML format, it is a string in the form of "NAME=value".
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+func (e EnvVar) String() string {
+	return e.Name + "=" + e.Value
+}
+
+func (e EnvVar) Equal(o EnvVar) bool {
+	return e.Name == o.Name && e.Value == o.Value
+}
+
+// Parse parses s in the form of "NAME=value" as EnvVar.
+//
+// The value can contain "=". Whitespaces around the value are preserved.
+func (e *EnvVar) Parse(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("env format error (should be NAME=value): %s", s)
+	}
+	*e = EnvVar{Name: strings.TrimSpace(name), Value: value}
+	return nil
+}
+
+// Validate checks the name of the environment variable.
+//
+// The name should be non-empty and consist of alphanumerics, '-', '_' or '.', not starting with a digit.
+func (e EnvVar) Validate() error {
+	if errs := validation.IsEnvVarName(e.Name); len(errs) != 0 {
+		return fmt.Errorf("env name %q is invalid: %s", e.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (e EnvVar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.String())
+}
+
+func (e EnvVar) MarshalYAML() (interface{}, error) {
+	return yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Value: e.String(),
+		Style: yaml.DoubleQuotedStyle,
+	}, nil
+}
+
+func (e *EnvVar) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	return e.Parse(s)
+}
+
+func (e *EnvVar) UnmarshalYAML(node *yaml.Node) error {
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return err
+	}
+	return e.Parse(s)
+}
@@ -0,0 +1,73 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestEnvVar_Parse(t *testing.T) {
+	for expr, want := range map[string]plans.EnvVar{
+		"DEBUG=1":        {Name: "DEBUG", Value: "1"},
+		"EMPTY=":         {Name: "EMPTY", Value: ""},
+		"OPTS=a=b c=d":   {Name: "OPTS", Value: "a=b c=d"},
+		" PADDED = v ":   {Name: "PADDED", Value: " v "},
+		"dotted.name=x":  {Name: "dotted.name", Value: "x"},
+		"with-dash=true": {Name: "with-dash", Value: "true"},
+	} {
+		t.Run(expr, func(t *testing.T) {
+			got := plans.EnvVar{}
+			if err := got.Parse(expr); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("valid env is rejected: %v", err)
+			}
+		})
+	}
+
+	if err := new(plans.EnvVar).Parse("NO_VALUE"); err == nil {
+		t.Error("expected error does not occur")
+	}
+	for _, name := range []string{"", "1ST", "has space", "A=B"} {
+		if err := (plans.EnvVar{Name: name, Value: "x"}).Validate(); err == nil {
+			t.Errorf("invalid name is accepted: %q", name)
+		}
+	}
+}
+
+func TestPlanSpec_env(t *testing.T) {
+	spec, err := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/data", "type:dataset").
+		WithEnv("DEBUG", "1").
+		WithEnv("OPTS", "a=b").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []plans.EnvVar{{Name: "DEBUG", Value: "1"}, {Name: "OPTS", Value: "a=b"}}
+	if len(spec.Env) != len(want) || !spec.Env[0].Equal(want[0]) || !spec.Env[1].Equal(want[1]) {
+		t.Errorf("unexpected env: %v", spec.Env)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	detail := plans.Detail{
+		Summary: plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101"},
+		Env:     spec.Env,
+		Inputs:  []plans.Input{{Mountpoint: plans.Mountpoint{Path: "/in/data", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}}},
+	}
+	knittest.AssertRoundTrip(t, detail)
+	if got := detail.ToSpec(); len(got.Env) != 2 || !got.Env[1].Equal(want[1]) {
+		t.Errorf("env is not carried to spec: %v", got.Env)
+	}
+
+	if _, err := plans.NewSpec().WithEnv("1BAD", "x").Build(); err == nil {
+		t.Error("expected error does not occur")
+	}
+}
@@ -0,0 +1,99 @@
+package plans
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// EstimateRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/plans/estimate
+//
+// It asks cost impact of registering the Plan, before registration.
+type EstimateRequest struct {
+	// Spec is the Plan to be estimated.
+	Spec PlanSpec `json:"spec" yaml:"spec"`
+
+	// ExpectedRuns is the number of Runs expected in the estimation period.
+	//
+	// This and Schedule are mutually exclusive.
+	ExpectedRuns int `json:"expected_runs,omitempty" yaml:"expected_runs,omitempty"`
+
+	// Schedule is the cron expression when Runs are expected to be started.
+	// The syntax is same as Schedule.Cron, evaluated in UTC.
+	//
+	// This and ExpectedRuns are mutually exclusive.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// PeriodDays is the length of the estimation period, in days.
+	//
+	// If 0, the server default (typically 30 days) is used.
+	PeriodDays int `json:"period_days,omitempty" yaml:"period_days,omitempty"`
+}
+
+func (e EstimateRequest) Equal(o EstimateRequest) bool {
+	return e.Spec.Equal(o.Spec) &&
+		e.ExpectedRuns == o.ExpectedRuns &&
+		e.Schedule == o.Schedule &&
+		e.PeriodDays == o.PeriodDays
+}
+
+// Validate checks the request is well-formed.
+func (e EstimateRequest) Validate() error {
+	if err := e.Spec.Validate(); err != nil {
+		return fmt.Errorf("spec: %w", err)
+	}
+	if e.ExpectedRuns < 0 {
+		return fmt.Errorf(`"expected_runs" should not be negative: %d`, e.ExpectedRuns)
+	}
+	if e.PeriodDays < 0 {
+		return fmt.Errorf(`"period_days" should not be negative: %d`, e.PeriodDays)
+	}
+	if e.ExpectedRuns != 0 && e.Schedule != "" {
+		return fmt.Errorf(`"expected_runs" and "schedule" are mutually exclusive`)
+	}
+	if e.Schedule != "" {
+		if err := (Schedule{Cron: e.Schedule}).Validate(); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// EstimateResult is the format for response body from Knitfab APIs below:
+//
+// - POST /api/plans/estimate
+//
+// The values are projected from historical Runs of similar Plans.
+type EstimateResult struct {
+	// Runs is the projected number of Runs in the period.
+	Runs int `json:"runs"`
+
+	// PeriodDays is the length of the estimation period, in days.
+	PeriodDays int `json:"period_days"`
+
+	// AverageRunSeconds is the projected average duration of a Run, in seconds.
+	AverageRunSeconds float64 `json:"average_run_seconds"`
+
+	// ResourceHours is the projected resource usage in the period by resource type,
+	// in the unit of the resource times hours (e.g. core-hours for "cpu", GiB-hours for "memory").
+	ResourceHours map[string]float64 `json:"resource_hours"`
+
+	// StorageGrowthBytes is the projected size of Data created in the period, in bytes.
+	StorageGrowthBytes int64 `json:"storage_growth_bytes"`
+
+	// BasedOn are the Plans whose Runs are used for the estimation.
+	//
+	// If empty, the estimation is based only on the Spec and has low confidence.
+	BasedOn []Summary `json:"based_on,omitempty"`
+}
+
+func (e EstimateResult) Equal(o EstimateResult) bool {
+	return e.Runs == o.Runs &&
+		e.PeriodDays == o.PeriodDays &&
+		e.AverageRunSeconds == o.AverageRunSeconds &&
+		apicmp.MapEqualWith(e.ResourceHours, o.ResourceHours, func(a, b float64) bool { return a == b }) &&
+		e.StorageGrowthBytes == o.StorageGrowthBytes &&
+		apicmp.SliceEqualUnordered(e.BasedOn, o.BasedOn)
+}
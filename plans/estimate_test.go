@@ -0,0 +1,66 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+func TestEstimateRequest_Validate(t *testing.T) {
+	spec := plans.NewSpec().
+		WithImage("repo:v1").
+		AddInput("/in", "type:x").
+		MustBuild()
+
+	for name, testcase := range map[string]struct {
+		When    plans.EstimateRequest
+		WantErr bool
+	}{
+		"spec only": {
+			When: plans.EstimateRequest{Spec: spec},
+		},
+		"expected runs": {
+			When: plans.EstimateRequest{Spec: spec, ExpectedRuns: 10, PeriodDays: 7},
+		},
+		"schedule": {
+			When: plans.EstimateRequest{Spec: spec, Schedule: "0 */6 * * *", PeriodDays: 7},
+		},
+		"schedule with descriptor": {
+			When: plans.EstimateRequest{Spec: spec, Schedule: "@daily"},
+		},
+		"invalid spec": {
+			When:    plans.EstimateRequest{Spec: plans.PlanSpec{}},
+			WantErr: true,
+		},
+		"negative expected runs": {
+			When:    plans.EstimateRequest{Spec: spec, ExpectedRuns: -1},
+			WantErr: true,
+		},
+		"negative period": {
+			When:    plans.EstimateRequest{Spec: spec, PeriodDays: -1},
+			WantErr: true,
+		},
+		"expected runs and schedule": {
+			When:    plans.EstimateRequest{Spec: spec, ExpectedRuns: 10, Schedule: "@daily"},
+			WantErr: true,
+		},
+		"too few cron fields": {
+			When:    plans.EstimateRequest{Spec: spec, Schedule: "* *"},
+			WantErr: true,
+		},
+		"malformed cron field": {
+			When:    plans.EstimateRequest{Spec: spec, Schedule: "0 9 * * someday"},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package plans
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/internal/render"
+)
+
+// Graph is the format for response body from Knitfab APIs below:
+//
+// - GET /api/plans/{planId}/graph
+//
+// It is the dependency graph of Plans around the Plan.
+//
+// Nodes and Edges are flat lists and refer each other by PlanId,
+// so Graph can represent cyclic dependencies.
+type Graph struct {
+	// Root is the id of the Plan which the graph is queried for.
+	Root PlanId `json:"root"`
+
+	// Nodes are Plans in the graph.
+	Nodes []Summary `json:"nodes"`
+
+	// Edges are dependencies between Plans in the graph.
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Equal returns true if g and o have the same root, nodes and edges, ignoring their order.
+func (g Graph) Equal(o Graph) bool {
+	return g.Root == o.Root &&
+		apicmp.SliceEqualUnordered(g.Nodes, o.Nodes) &&
+		apicmp.SliceEqualUnordered(g.Edges, o.Edges)
+}
+
+// MarshalJSON marshals the graph with nodes sorted by PlanId and edges sorted by their ends,
+// so that the same graph is always marshalled into the same bytes.
+func (g Graph) MarshalJSON() ([]byte, error) {
+	type graph Graph
+	sorted := graph{
+		Root:  g.Root,
+		Nodes: slices.Clone(g.Nodes),
+		Edges: slices.Clone(g.Edges),
+	}
+	if sorted.Nodes == nil {
+		sorted.Nodes = []Summary{}
+	}
+	if sorted.Edges == nil {
+		sorted.Edges = []GraphEdge{}
+	}
+	slices.SortStableFunc(sorted.Nodes, func(a, b Summary) int {
+		return cmp.Compare(a.PlanId, b.PlanId)
+	})
+	slices.SortStableFunc(sorted.Edges, compareEdges)
+	return json.Marshal(sorted)
+}
+
+// Node returns the node with planId.
+func (g Graph) Node(planId PlanId) (Summary, bool) {
+	for _, n := range g.Nodes {
+		if n.PlanId == planId {
+			return n, true
+		}
+	}
+	return Summary{}, false
+}
+
+// Upstreams returns edges coming into the Plan with planId.
+func (g Graph) Upstreams(planId PlanId) []GraphEdge {
+	ret := []GraphEdge{}
+	for _, e := range g.Edges {
+		if e.To == planId {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// Downstreams returns edges going out of the Plan with planId.
+func (g Graph) Downstreams(planId PlanId) []GraphEdge {
+	ret := []GraphEdge{}
+	for _, e := range g.Edges {
+		if e.From == planId {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// GraphEdge is a dependency between Plans: Data from an output (or log) of a Plan
+// can be assigned to an input of another Plan.
+type GraphEdge struct {
+	// From is the id of the upstream Plan.
+	From PlanId `json:"from"`
+
+	// To is the id of the downstream Plan.
+	To PlanId `json:"to"`
+
+	// Output is the output mountpoint of the upstream Plan.
+	//
+	// Output and Log are mutually exclusive.
+	Output *Mountpoint `json:"output,omitempty"`
+
+	// Log is the log point of the upstream Plan.
+	//
+	// Output and Log are mutually exclusive.
+	Log *LogPoint `json:"log,omitempty"`
+
+	// Input is the input mountpoint of the downstream Plan.
+	Input Mountpoint `json:"input"`
+}
+
+func (e GraphEdge) Equal(o GraphEdge) bool {
+	return e.From == o.From &&
+		e.To == o.To &&
+		apicmp.PtrEqual(e.Output, o.Output) &&
+		apicmp.PtrEqual(e.Log, o.Log) &&
+		e.Input.Equal(o.Input)
+}
+
+func compareEdges(a, b GraphEdge) int {
+	if c := cmp.Compare(a.From, b.From); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.To, b.To); c != 0 {
+		return c
+	}
+	outPath := func(e GraphEdge) string {
+		if e.Output == nil {
+			return "" // log comes first
+		}
+		return e.Output.Path
+	}
+	if c := strings.Compare(outPath(a), outPath(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Input.Path, b.Input.Path)
+}
+
+// GraphFromDetails builds a Graph from Upstreams and Downstreams of Plans.
+//
+// Plans which are only referred as upstream or downstream are also included as nodes.
+func GraphFromDetails(root PlanId, ds []Detail) Graph {
+	g := Graph{Root: root, Nodes: []Summary{}, Edges: []GraphEdge{}}
+
+	addNode := func(s Summary) {
+		if _, ok := g.Node(s.PlanId); !ok {
+			g.Nodes = append(g.Nodes, s)
+		}
+	}
+	addEdge := func(e GraphEdge) {
+		if !slices.ContainsFunc(g.Edges, e.Equal) {
+			g.Edges = append(g.Edges, e)
+		}
+	}
+
+	// Summaries from Details take precedence over ones in Upstreams/Downstreams.
+	for _, d := range ds {
+		addNode(d.Summary)
+	}
+
+	for _, d := range ds {
+		for _, in := range d.Inputs {
+			for _, up := range in.Upstreams {
+				addNode(up.Plan)
+				addEdge(GraphEdge{
+					From: up.Plan.PlanId, To: d.PlanId,
+					Output: up.Mountpoint, Log: up.Log,
+					Input: in.Mountpoint,
+				})
+			}
+		}
+		for _, out := range d.Outputs {
+			for _, down := range out.Downstreams {
+				addNode(down.Plan)
+				addEdge(GraphEdge{
+					From: d.PlanId, To: down.Plan.PlanId,
+					Output: &out.Mountpoint,
+					Input:  down.Mountpoint,
+				})
+			}
+		}
+		if d.Log != nil {
+			for _, down := range d.Log.Downstreams {
+				addNode(down.Plan)
+				addEdge(GraphEdge{
+					From: d.PlanId, To: down.Plan.PlanId,
+					Log:   &d.Log.LogPoint,
+					Input: down.Mountpoint,
+				})
+			}
+		}
+	}
+
+	return g
+}
+
+// WriteDOT writes the graph in DOT language of Graphviz.
+//
+// Nodes and edges are written in sorted order.
+func (g Graph) WriteDOT(w io.Writer) error {
+	return render.DOT(w, g.render())
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+//
+// Nodes and edges are written in sorted order.
+func (g Graph) WriteMermaid(w io.Writer) error {
+	return render.Mermaid(w, g.render())
+}
+
+func (g Graph) render() render.Graph {
+	r := render.Graph{}
+	for _, n := range g.Nodes {
+		label := n.Name
+		if n.Image != nil {
+			label = n.Image.String()
+		}
+		r.Nodes = append(r.Nodes, render.Node{
+			Id:    n.PlanId.String(),
+			Label: n.PlanId.String() + "\n" + label,
+			Shape: render.Box,
+		})
+	}
+	for _, e := range g.Edges {
+		from := "(log)"
+		if e.Output != nil {
+			from = e.Output.Path
+		}
+		r.Edges = append(r.Edges, render.Edge{
+			From:  e.From.String(),
+			To:    e.To.String(),
+			Label: from + " -> " + e.Input.Path,
+		})
+	}
+	return r
+}
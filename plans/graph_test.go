@@ -0,0 +1,88 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestGraphFromDetails(t *testing.T) {
+	const trainId plans.PlanId = "0190a1b2-0000-7000-8000-000000000002"
+	const evaluateId plans.PlanId = "0190a1b2-0000-7000-8000-000000000001"
+	train := plans.Summary{PlanId: trainId, Image: &plans.Image{Repository: "example.com/train", Tag: "v1"}}
+	evaluate := plans.Summary{PlanId: evaluateId, Image: &plans.Image{Repository: "example.com/evaluate", Tag: "v1"}}
+
+	model := plans.Mountpoint{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}}
+	modelIn := plans.Mountpoint{Path: "/in/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}}
+	log := plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "eval-log"}}}
+	feedback := plans.Mountpoint{Path: "/in/feedback", Tags: []tags.Tag{{Key: "type", Value: "eval-log"}}}
+
+	// train -> evaluate -(log)-> train: a cycle.
+	ds := []plans.Detail{
+		{
+			Summary: train,
+			Inputs: []plans.Input{
+				{Mountpoint: feedback, Upstreams: []plans.Upstream{{Plan: evaluate, Log: &log}}},
+			},
+			Outputs: []plans.Output{
+				{Mountpoint: model, Downstreams: []plans.Downstream{{Plan: evaluate, Mountpoint: modelIn}}},
+			},
+		},
+		{
+			Summary: evaluate,
+			Inputs: []plans.Input{
+				{Mountpoint: modelIn, Upstreams: []plans.Upstream{{Plan: train, Mountpoint: &model}}},
+			},
+			Log: &plans.Log{
+				LogPoint:    log,
+				Downstreams: []plans.Downstream{{Plan: train, Mountpoint: feedback}},
+			},
+		},
+	}
+
+	got := plans.GraphFromDetails(trainId, ds)
+	want := plans.Graph{
+		Root:  trainId,
+		Nodes: []plans.Summary{evaluate, train},
+		Edges: []plans.GraphEdge{
+			{From: trainId, To: evaluateId, Output: &model, Input: modelIn},
+			{From: evaluateId, To: trainId, Log: &log, Input: feedback},
+		},
+	}
+	if !got.Equal(want) {
+		t.Errorf("unexpected graph:\n%+v", got)
+	}
+
+	if ups := got.Upstreams(evaluateId); len(ups) != 1 || ups[0].From != trainId {
+		t.Errorf("unexpected upstreams: %+v", ups)
+	}
+	if downs := got.Downstreams(evaluateId); len(downs) != 1 || downs[0].Log == nil {
+		t.Errorf("unexpected downstreams: %+v", downs)
+	}
+
+	// marshalling is independent from the order of nodes and edges.
+	reversed := plans.GraphFromDetails(trainId, []plans.Detail{ds[1], ds[0]})
+	slices.Reverse(reversed.Edges)
+	a, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Errorf("unstable marshalling:\n%s\n%s", a, b)
+	}
+
+	unmarshalled := plans.Graph{}
+	if err := json.Unmarshal(a, &unmarshalled); err != nil {
+		t.Fatal(err)
+	}
+	if !unmarshalled.Equal(got) {
+		t.Errorf("JSON round trip: got %+v", unmarshalled)
+	}
+}
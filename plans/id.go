@@ -0,0 +1,54 @@
+package plans
+
+import (
+	"github.com/opst/knitfab-api-types/internal/ids"
+	"gopkg.in/yaml.v3"
+)
+
+// PlanId identifies Plans.
+type PlanId string
+
+// ParsePlanId parses s as PlanId.
+func ParsePlanId(s string) (PlanId, error) {
+	if err := ids.Validate("PlanId", s); err != nil {
+		return "", err
+	}
+	return PlanId(s), nil
+}
+
+func (id PlanId) String() string {
+	return string(id)
+}
+
+// IsZero returns true if id is empty.
+func (id PlanId) IsZero() bool {
+	return id == ""
+}
+
+// Parse parses s as PlanId, and sets it to id.
+func (id *PlanId) Parse(s string) error {
+	parsed, err := ParsePlanId(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id *PlanId) UnmarshalJSON(b []byte) error {
+	s, err := ids.UnmarshalJSON("PlanId", b)
+	if err != nil {
+		return err
+	}
+	*id = PlanId(s)
+	return nil
+}
+
+func (id *PlanId) UnmarshalYAML(node *yaml.Node) error {
+	s, err := ids.UnmarshalYAML("PlanId", node)
+	if err != nil {
+		return err
+	}
+	*id = PlanId(s)
+	return nil
+}
@@ -0,0 +1,47 @@
+package plans
+
+import "github.com/opst/knitfab-api-types/jsonpatch"
+
+// MergePatch returns the JSON Merge Patch (RFC 7386) which makes d to target.
+//
+// Arrays, like inputs, are replaced as a whole if they differ.
+func (d Detail) MergePatch(target Detail) ([]byte, error) {
+	return jsonpatch.MergePatchOf(d, target)
+}
+
+// JSONPatch returns JSON Patch (RFC 6902) operations which make d to target.
+func (d Detail) JSONPatch(target Detail) ([]jsonpatch.Operation, error) {
+	return jsonpatch.PatchOf(d, target)
+}
+
+// ApplyMergePatch returns a Detail with the JSON Merge Patch (RFC 7386) applied. d is not modified.
+func (d Detail) ApplyMergePatch(patch []byte) (Detail, error) {
+	return jsonpatch.ApplyMergePatchTo(d, patch)
+}
+
+// ApplyJSONPatch returns a Detail with JSON Patch (RFC 6902) operations applied. d is not modified.
+func (d Detail) ApplyJSONPatch(ops []jsonpatch.Operation) (Detail, error) {
+	return jsonpatch.ApplyTo(d, ops)
+}
+
+// MergePatch returns the JSON Merge Patch (RFC 7386) which makes ps to target.
+//
+// Arrays, like inputs, are replaced as a whole if they differ.
+func (ps PlanSpec) MergePatch(target PlanSpec) ([]byte, error) {
+	return jsonpatch.MergePatchOf(ps, target)
+}
+
+// JSONPatch returns JSON Patch (RFC 6902) operations which make ps to target.
+func (ps PlanSpec) JSONPatch(target PlanSpec) ([]jsonpatch.Operation, error) {
+	return jsonpatch.PatchOf(ps, target)
+}
+
+// ApplyMergePatch returns a PlanSpec with the JSON Merge Patch (RFC 7386) applied. ps is not modified.
+func (ps PlanSpec) ApplyMergePatch(patch []byte) (PlanSpec, error) {
+	return jsonpatch.ApplyMergePatchTo(ps, patch)
+}
+
+// ApplyJSONPatch returns a PlanSpec with JSON Patch (RFC 6902) operations applied. ps is not modified.
+func (ps PlanSpec) ApplyJSONPatch(ops []jsonpatch.Operation) (PlanSpec, error) {
+	return jsonpatch.ApplyTo(ps, ops)
+}
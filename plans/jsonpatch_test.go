@@ -0,0 +1,52 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+func TestDetail_MergePatch(t *testing.T) {
+	actual := plans.Detail{
+		Summary: plans.Summary{
+			PlanId: "0190a1b2-0000-7000-8000-000000000101",
+			Image:  &plans.Image{Repository: "example.com/train", Tag: "v1"},
+		},
+		Inputs:         []plans.Input{{Mountpoint: plans.Mountpoint{Path: "/in"}}},
+		ServiceAccount: "trainer",
+	}
+	desired := actual.Clone()
+	desired.Image = &plans.Image{Repository: "example.com/train", Tag: "v2"}
+	desired.ServiceAccount = ""
+	desired.Outputs = []plans.Output{{Mountpoint: plans.Mountpoint{Path: "/out"}}}
+
+	patch, err := actual.MergePatch(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(patch) != `{"image":"example.com/train:v2","outputs":[{"downstreams":null,"path":"/out","tags":null}],"service_account":null}` {
+		t.Errorf("unexpected patch: %s", patch)
+	}
+	got, err := actual.ApplyMergePatch(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := got.Diff(desired); diffs != nil {
+		t.Errorf("unexpected result: %v", diffs)
+	}
+
+	ops, err := actual.JSONPatch(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = actual.ApplyJSONPatch(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := got.Diff(desired); diffs != nil {
+		t.Errorf("unexpected result: %v", diffs)
+	}
+	if actual.ServiceAccount != "trainer" {
+		t.Error("original is modified")
+	}
+}
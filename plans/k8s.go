@@ -0,0 +1,147 @@
+package plans
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ToNodeAffinity returns the node affinity of Runs of the Plan.
+//
+// If OnNode has neither "prefer" nor "must" labels, it returns nil.
+func (o OnNode) ToNodeAffinity() *corev1.NodeAffinity {
+	if len(o.Prefer) == 0 && len(o.Must) == 0 {
+		return nil
+	}
+
+	affinity := &corev1.NodeAffinity{}
+	if 0 < len(o.Must) {
+		term := corev1.NodeSelectorTerm{}
+		for _, l := range o.Must {
+			term.MatchExpressions = append(term.MatchExpressions, l.toRequirement())
+		}
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{term},
+		}
+	}
+	for _, l := range o.Prefer {
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.PreferredSchedulingTerm{
+				Weight: 1,
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{l.toRequirement()},
+				},
+			},
+		)
+	}
+	return affinity
+}
+
+// ToTolerations returns tolerations of Runs of the Plan.
+func (o OnNode) ToTolerations() []corev1.Toleration {
+	tolerations := []corev1.Toleration{}
+	for _, l := range o.May {
+		tolerations = append(tolerations, l.toToleration(corev1.TaintEffectNoSchedule))
+	}
+	for _, l := range slices.Concat(o.Prefer, o.Must) {
+		tolerations = append(
+			tolerations,
+			l.toToleration(corev1.TaintEffectNoSchedule),
+			l.toToleration(corev1.TaintEffectPreferNoSchedule),
+		)
+	}
+	return tolerations
+}
+
+// OnNodeFromK8s makes OnNode from the node affinity and tolerations,
+// as the reverse of OnNode.ToNodeAffinity and OnNode.ToTolerations.
+//
+// Labels in the affinity take precedence over tolerations;
+// tolerations for labels which are in the affinity are not translated as "may".
+//
+// # Args
+//
+// - affinity: node affinity. It can be nil.
+//
+// - tolerations: tolerations.
+//
+// # Returns
+//
+// - OnNode: translated OnNode.
+//
+// - error: if affinity or tolerations cannot be expressed as OnNode.
+// Affinities should be "In" operator with a single value, and "must" labels should be in a single term.
+// Tolerations should be "Equal" operator with a key.
+func OnNodeFromK8s(affinity *corev1.NodeAffinity, tolerations []corev1.Toleration) (OnNode, error) {
+	ret := OnNode{}
+
+	if affinity != nil {
+		if req := affinity.RequiredDuringSchedulingIgnoredDuringExecution; req != nil {
+			switch len(req.NodeSelectorTerms) {
+			case 0:
+			case 1:
+				for _, r := range req.NodeSelectorTerms[0].MatchExpressions {
+					l, err := fromRequirement(r)
+					if err != nil {
+						return OnNode{}, fmt.Errorf("required affinity: %w", err)
+					}
+					ret.Must = append(ret.Must, l)
+				}
+			default:
+				return OnNode{}, fmt.Errorf("required affinity: multiple terms are not supported")
+			}
+		}
+		for _, p := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			for _, r := range p.Preference.MatchExpressions {
+				l, err := fromRequirement(r)
+				if err != nil {
+					return OnNode{}, fmt.Errorf("preferred affinity: %w", err)
+				}
+				ret.Prefer = append(ret.Prefer, l)
+			}
+		}
+	}
+
+	for _, t := range tolerations {
+		if t.Operator != corev1.TolerationOpEqual || t.Key == "" {
+			return OnNode{}, fmt.Errorf(
+				"toleration: unsupported: %s %s %s (should be %s with a key)", t.Key, t.Operator, t.Value, corev1.TolerationOpEqual,
+			)
+		}
+		l := OnSpecLabel{Key: t.Key, Value: t.Value}
+		if slices.ContainsFunc(slices.Concat(ret.Prefer, ret.Must, ret.May), l.Equal) {
+			continue
+		}
+		ret.May = append(ret.May, l)
+	}
+
+	return ret, nil
+}
+
+func (l OnSpecLabel) toRequirement() corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{
+		Key:      l.Key,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{l.Value},
+	}
+}
+
+func (l OnSpecLabel) toToleration(effect corev1.TaintEffect) corev1.Toleration {
+	return corev1.Toleration{
+		Key:      l.Key,
+		Operator: corev1.TolerationOpEqual,
+		Value:    l.Value,
+		Effect:   effect,
+	}
+}
+
+func fromRequirement(r corev1.NodeSelectorRequirement) (OnSpecLabel, error) {
+	if r.Operator != corev1.NodeSelectorOpIn || len(r.Values) != 1 {
+		return OnSpecLabel{}, fmt.Errorf(
+			"unsupported: %s %s %v (should be %s with a single value)", r.Key, r.Operator, r.Values, corev1.NodeSelectorOpIn,
+		)
+	}
+	return OnSpecLabel{Key: r.Key, Value: r.Values[0]}, nil
+}
@@ -0,0 +1,91 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestOnNode_k8s(t *testing.T) {
+	on := plans.OnNode{
+		May:    []plans.OnSpecLabel{{Key: "accelerator", Value: "tpu"}},
+		Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+		Must: []plans.OnSpecLabel{
+			{Key: "gpu", Value: "true"},
+			{Key: "arch", Value: "amd64"},
+		},
+	}
+
+	affinity := on.ToNodeAffinity()
+	if affinity == nil {
+		t.Fatal("affinity is nil")
+	}
+	req := affinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if req == nil || len(req.NodeSelectorTerms) != 1 || len(req.NodeSelectorTerms[0].MatchExpressions) != 2 {
+		t.Errorf("unexpected required affinity: %+v", req)
+	} else if e := req.NodeSelectorTerms[0].MatchExpressions[0]; e.Key != "gpu" ||
+		e.Operator != corev1.NodeSelectorOpIn || len(e.Values) != 1 || e.Values[0] != "true" {
+		t.Errorf("unexpected match expression: %+v", e)
+	}
+	if pref := affinity.PreferredDuringSchedulingIgnoredDuringExecution; len(pref) != 1 ||
+		pref[0].Preference.MatchExpressions[0].Key != "zone" {
+		t.Errorf("unexpected preferred affinity: %+v", pref)
+	}
+
+	tolerations := on.ToTolerations()
+	if len(tolerations) != 1+2*3 {
+		t.Errorf("unexpected tolerations: %+v", tolerations)
+	}
+	if tl := tolerations[0]; tl.Key != "accelerator" || tl.Value != "tpu" ||
+		tl.Operator != corev1.TolerationOpEqual || tl.Effect != corev1.TaintEffectNoSchedule {
+		t.Errorf("unexpected toleration for may: %+v", tl)
+	}
+
+	got, err := plans.OnNodeFromK8s(affinity, tolerations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(on) {
+		t.Errorf("reverse: got %+v", got)
+	}
+
+	if a := (plans.OnNode{May: on.May}).ToNodeAffinity(); a != nil {
+		t.Errorf("affinity for may only: %+v", a)
+	}
+}
+
+func TestOnNodeFromK8s_unsupported(t *testing.T) {
+	for name, tc := range map[string]struct {
+		affinity    *corev1.NodeAffinity
+		tolerations []corev1.Toleration
+	}{
+		"NotIn": {
+			affinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "gpu", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"true"}},
+						},
+					}},
+				},
+			},
+		},
+		"multiple terms": {
+			affinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{}, {}},
+				},
+			},
+		},
+		"toleration Exists": {
+			tolerations: []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got, err := plans.OnNodeFromK8s(tc.affinity, tc.tolerations); err == nil {
+				t.Errorf("unsupported input is accepted: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package plans
+
+import (
+	"errors"
+
+	"github.com/opst/knitfab-api-types/meta"
+)
+
+// Kinds of payloads in this package, for meta.TypeMeta.
+//
+// KindPlanSpec is also the kind of PlanSpecs in manifests (see package manifest).
+const (
+	KindPlanSpec   = "Plan"
+	KindPlanDetail = "PlanDetail"
+)
+
+// AddToScheme registers kinds of this package to s.
+func AddToScheme(s *meta.Scheme) error {
+	return errors.Join(
+		meta.Register[PlanSpec](s, KindPlanSpec),
+		meta.Register[Detail](s, KindPlanDetail),
+	)
+}
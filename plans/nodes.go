@@ -0,0 +1,139 @@
+package plans
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/nodes"
+)
+
+// Warning is a soft issue of a PlanSpec.
+//
+// Plans with Warnings can be registered, but may not work as expected.
+type Warning = meta.Warning
+
+// Codes of Warnings reported by ValidateAgainstNodes and Admit.
+const (
+	// WarnNoTaint is for a "may" label which matches taints of no node.
+	WarnNoTaint = "no-node-with-taint"
+
+	// WarnNoPreferredLabel is for a "prefer" label which matches labels of no node.
+	WarnNoPreferredLabel = "no-node-with-preferred-label"
+
+	// WarnNoRequiredLabel is for a "must" label which matches labels of no node.
+	WarnNoRequiredLabel = "no-node-with-required-label"
+
+	// WarnRequiredLabelsApart is for "must" labels which no single node has at once.
+	WarnRequiredLabelsApart = "required-labels-apart"
+
+	// WarnResourceExceeds is for a resource which exceeds allocatable of every node.
+	WarnResourceExceeds = "resource-exceeds-allocatable"
+
+	// WarnUnschedulable is for a spec which no node satisfies in total.
+	WarnUnschedulable = "unschedulable"
+)
+
+// ValidateAgainstNodes checks that Runs of the spec can be scheduled on some of the nodes.
+//
+// It reports:
+//
+// - "may" labels which match taints of no node.
+//
+// - "prefer" and "must" labels which match labels of no node.
+//
+// - "must" labels which are not satisfied by any single node at once.
+//
+// - resources which exceed allocatable of every node.
+//
+// # Args
+//
+// - spec: PlanSpec to be checked.
+//
+// - ns: nodes of the cluster, typically from GET /api/nodes/ .
+//
+// # Returns
+//
+// - []Warning: found issues. If there are no issues, it is empty.
+func ValidateAgainstNodes(spec PlanSpec, ns []nodes.Node) []Warning {
+	warnings := []Warning{}
+
+	if on := spec.OnNode; on != nil {
+		for i, l := range on.May {
+			if !slices.ContainsFunc(ns, func(n nodes.Node) bool { return hasTaint(n, l) }) {
+				warnings = append(warnings, Warning{
+					Code:    WarnNoTaint,
+					Field:   fmt.Sprintf("on_node.may[%d]", i),
+					Message: fmt.Sprintf("no node has taint %s", l),
+				})
+			}
+		}
+		for i, l := range on.Prefer {
+			if !slices.ContainsFunc(ns, func(n nodes.Node) bool { return hasLabel(n, l) }) {
+				warnings = append(warnings, Warning{
+					Code:    WarnNoPreferredLabel,
+					Field:   fmt.Sprintf("on_node.prefer[%d]", i),
+					Message: fmt.Sprintf("no node has label %s", l),
+				})
+			}
+		}
+
+		mustOk := true
+		for i, l := range on.Must {
+			if !slices.ContainsFunc(ns, func(n nodes.Node) bool { return hasLabel(n, l) }) {
+				mustOk = false
+				warnings = append(warnings, Warning{
+					Code:    WarnNoRequiredLabel,
+					Field:   fmt.Sprintf("on_node.must[%d]", i),
+					Message: fmt.Sprintf("no node has label %s. Runs will never be scheduled", l),
+				})
+			}
+		}
+		if mustOk && 1 < len(on.Must) {
+			satisfied := slices.ContainsFunc(ns, func(n nodes.Node) bool {
+				for _, l := range on.Must {
+					if !hasLabel(n, l) {
+						return false
+					}
+				}
+				return true
+			})
+			if !satisfied {
+				warnings = append(warnings, Warning{
+					Code:    WarnRequiredLabelsApart,
+					Field:   "on_node.must",
+					Message: "no node has all labels at once. Runs will never be scheduled",
+				})
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(spec.Resources)) {
+		req := spec.Resources[name]
+		fits := slices.ContainsFunc(ns, func(n nodes.Node) bool {
+			alloc, ok := n.Allocatable[name]
+			return ok && req.Cmp(alloc) <= 0
+		})
+		if !fits {
+			warnings = append(warnings, Warning{
+				Code:    WarnResourceExceeds,
+				Field:   "resources." + name,
+				Message: fmt.Sprintf("%s exceeds allocatable of every node", req.String()),
+			})
+		}
+	}
+
+	return warnings
+}
+
+func hasLabel(n nodes.Node, l OnSpecLabel) bool {
+	v, ok := n.Labels[l.Key]
+	return ok && v == l.Value
+}
+
+func hasTaint(n nodes.Node, l OnSpecLabel) bool {
+	return slices.ContainsFunc(n.Taints, func(t nodes.Taint) bool {
+		return t.Key == l.Key && t.Value == l.Value
+	})
+}
@@ -0,0 +1,185 @@
+package plans_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/nodes"
+	"github.com/opst/knitfab-api-types/plans"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestValidateAgainstNodes(t *testing.T) {
+	ns := []nodes.Node{
+		{
+			Name:   "node-a",
+			Labels: map[string]string{"accelerator": "gpu", "zone": "a"},
+			Taints: []nodes.Taint{{Key: "dedicated", Value: "ml", Effect: "NoSchedule"}},
+			Allocatable: map[string]resource.Quantity{
+				"cpu":    resource.MustParse("4"),
+				"memory": resource.MustParse("16Gi"),
+			},
+		},
+		{
+			Name:   "node-b",
+			Labels: map[string]string{"zone": "b"},
+			Allocatable: map[string]resource.Quantity{
+				"cpu":    resource.MustParse("8"),
+				"memory": resource.MustParse("8Gi"),
+			},
+		},
+	}
+
+	type When struct {
+		Spec plans.PlanSpec
+	}
+	type Then struct {
+		Fields []string
+		Codes  []string
+	}
+
+	theory := func(when When, then Then) func(*testing.T) {
+		return func(t *testing.T) {
+			got := plans.ValidateAgainstNodes(when.Spec, ns)
+			if len(got) != len(then.Fields) {
+				t.Fatalf("unexpected warnings: %+v", got)
+			}
+			for i := range got {
+				if got[i].Field != then.Fields[i] {
+					t.Errorf("unexpected warning[%d]: %s (want field %s)", i, got[i], then.Fields[i])
+				}
+				if got[i].Code != then.Codes[i] {
+					t.Errorf("unexpected warning[%d]: code %s (want %s)", i, got[i].Code, then.Codes[i])
+				}
+			}
+		}
+	}
+
+	t.Run("schedulable spec", theory(
+		When{
+			Spec: plans.PlanSpec{
+				OnNode: &plans.OnNode{
+					May:    []plans.OnSpecLabel{{Key: "dedicated", Value: "ml"}},
+					Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "b"}},
+					Must:   []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}, {Key: "zone", Value: "a"}},
+				},
+				Resources: plans.Resources{
+					"cpu":    resource.MustParse("2"),
+					"memory": resource.MustParse("12Gi"),
+				},
+			},
+		},
+		Then{Fields: []string{}, Codes: []string{}},
+	))
+
+	t.Run("unknown labels", theory(
+		When{
+			Spec: plans.PlanSpec{
+				OnNode: &plans.OnNode{
+					May:    []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+					Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "c"}},
+					Must:   []plans.OnSpecLabel{{Key: "accelerator", Value: "tpu"}},
+				},
+			},
+		},
+		Then{
+			Fields: []string{"on_node.may[0]", "on_node.prefer[0]", "on_node.must[0]"},
+			Codes:  []string{plans.WarnNoTaint, plans.WarnNoPreferredLabel, plans.WarnNoRequiredLabel},
+		},
+	))
+
+	t.Run("must labels not on a single node", theory(
+		When{
+			Spec: plans.PlanSpec{
+				OnNode: &plans.OnNode{
+					Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}, {Key: "zone", Value: "b"}},
+				},
+			},
+		},
+		Then{Fields: []string{"on_node.must"}, Codes: []string{plans.WarnRequiredLabelsApart}},
+	))
+
+	t.Run("too large resources", theory(
+		When{
+			Spec: plans.PlanSpec{
+				Resources: plans.Resources{
+					"cpu":            resource.MustParse("16"),
+					"memory":         resource.MustParse("16Gi"),
+					"nvidia.com/gpu": resource.MustParse("1"),
+				},
+			},
+		},
+		Then{
+			Fields: []string{"resources.cpu", "resources.nvidia.com/gpu"},
+			Codes:  []string{plans.WarnResourceExceeds, plans.WarnResourceExceeds},
+		},
+	))
+}
+
+func TestAdmit(t *testing.T) {
+	ns := []nodes.Node{
+		{
+			Name:        "gpu-node",
+			Labels:      map[string]string{"accelerator": "gpu"},
+			Allocatable: map[string]resource.Quantity{"cpu": resource.MustParse("4")},
+		},
+		{
+			Name:        "cpu-node",
+			Allocatable: map[string]resource.Quantity{"cpu": resource.MustParse("16")},
+		},
+	}
+
+	t.Run("schedulable", func(t *testing.T) {
+		got := plans.Admit(plans.PlanSpec{
+			OnNode: &plans.OnNode{
+				Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+			},
+			Resources: plans.Resources{"cpu": resource.MustParse("8")},
+		}, ns)
+		if !got.Schedulable || len(got.Nodes) != 1 || got.Nodes[0] != "cpu-node" {
+			t.Errorf("unexpected admission: %+v", got)
+		}
+		if len(got.Blocking) != 0 || len(got.Advisory) != 1 {
+			t.Errorf("unexpected warnings: %+v", got)
+		}
+	})
+
+	t.Run("not schedulable: no node satisfies all at once", func(t *testing.T) {
+		got := plans.Admit(plans.PlanSpec{
+			OnNode:    &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}}},
+			Resources: plans.Resources{"cpu": resource.MustParse("8")},
+		}, ns)
+		if got.Schedulable || len(got.Nodes) != 0 || len(got.Blocking) != 1 {
+			t.Errorf("unexpected admission: %+v", got)
+		}
+		if got.Blocking[0].Code != plans.WarnUnschedulable {
+			t.Errorf("unexpected blocking: %+v", got.Blocking)
+		}
+	})
+
+	t.Run("classified by code", func(t *testing.T) {
+		got := plans.Admit(plans.PlanSpec{
+			OnNode: &plans.OnNode{
+				May:    []plans.OnSpecLabel{{Key: "dedicated", Value: "ml"}},
+				Prefer: []plans.OnSpecLabel{{Key: "zone", Value: "a"}},
+				Must:   []plans.OnSpecLabel{{Key: "accelerator", Value: "tpu"}},
+			},
+			Resources: plans.Resources{"memory": resource.MustParse("1Gi")},
+		}, ns)
+
+		advisory := []string{}
+		for _, w := range got.Advisory {
+			advisory = append(advisory, w.Code)
+		}
+		blocking := []string{}
+		for _, w := range got.Blocking {
+			blocking = append(blocking, w.Code)
+		}
+		if want := []string{plans.WarnNoTaint, plans.WarnNoPreferredLabel}; !slices.Equal(advisory, want) {
+			t.Errorf("advisory: got %v, want %v", advisory, want)
+		}
+		if want := []string{plans.WarnNoRequiredLabel, plans.WarnResourceExceeds}; !slices.Equal(blocking, want) {
+			t.Errorf("blocking: got %v, want %v", blocking, want)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package plans
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Normalize returns the canonical form of the PlanSpec.
+//
+// It trims whitespaces around paths (including the working directory), tags, labels, annotations and names (including names of env),
+// sorts and deduplicates tags, labels and annotations, and sorts mountpoints by their paths,
+// secrets by their names, keys and targets, and sidecars by their names.
+// Timestamp tags ("knit#timestamp") are reformatted in UTC.
+// For annotations with the same key, the last one is kept.
+// The order of env is kept, since it is meaningful.
+// Cache policy "never" is normalized to unspecified, since they are same.
+// Whitespaces in the cron expression of the schedule are squashed.
+//
+// Equivalent PlanSpecs have the same normalized form. ps is not modified.
+func (ps PlanSpec) Normalize() PlanSpec {
+	n := PlanSpec{
+		Image:          ps.Image,
+		Entrypoint:     slices.Clone(ps.Entrypoint),
+		Args:           slices.Clone(ps.Args),
+		Env:            normalizeEnv(ps.Env),
+		WorkingDir:     strings.TrimSpace(ps.WorkingDir),
+		RunAsUser:      clone.Ptr(ps.RunAsUser),
+		RunAsGroup:     clone.Ptr(ps.RunAsGroup),
+		Secrets:        normalizeSecrets(ps.Secrets),
+		Sidecars:       normalizeSidecars(ps.Sidecars),
+		Inputs:         normalizeMountpoints(ps.Inputs),
+		Outputs:        normalizeMountpoints(ps.Outputs),
+		Timeout:        clone.Ptr(ps.Timeout),
+		Deadline:       clone.Ptr(ps.Deadline),
+		Priority:       ps.Priority,
+		ServiceAccount: strings.TrimSpace(ps.ServiceAccount),
+		Project:        strings.TrimSpace(ps.Project),
+	}
+
+	if 0 < len(ps.Annotations) {
+		last := map[string]Annotation{}
+		for _, an := range ps.Annotations {
+			an = Annotation{Key: strings.TrimSpace(an.Key), Value: strings.TrimSpace(an.Value)}
+			last[an.Key] = an
+		}
+		for _, an := range last {
+			n.Annotations = append(n.Annotations, an)
+		}
+		n.Annotations = n.Annotations.marshal()
+	}
+
+	if ps.Log != nil {
+		n.Log = &LogPoint{Tags: normalizeTags(ps.Log.Tags)}
+	}
+
+	if ps.OnNode != nil {
+		n.OnNode = &OnNode{
+			May:    normalizeLabels(ps.OnNode.May),
+			Prefer: normalizeLabels(ps.OnNode.Prefer),
+			Must:   normalizeLabels(ps.OnNode.Must),
+		}
+	}
+
+	if ps.Resources != nil {
+		n.Resources = Resources{}
+		for k, v := range ps.Resources {
+			n.Resources[strings.TrimSpace(k)] = v.DeepCopy()
+		}
+	}
+
+	if ps.Schedule != nil {
+		n.Schedule = &Schedule{
+			Cron:     strings.Join(strings.Fields(ps.Schedule.Cron), " "),
+			Timezone: strings.TrimSpace(ps.Schedule.Timezone),
+		}
+	}
+
+	if ps.Active != nil {
+		active := *ps.Active
+		n.Active = &active
+	}
+
+	return n
+}
+
+// Hash returns the digest of the canonical form of the PlanSpec, like "sha256:...".
+//
+// Equivalent PlanSpecs have the same hash.
+// Active is not taken into account, because it is the state of the Plan, not its content.
+func (ps PlanSpec) Hash() string {
+	n := ps.Normalize()
+	n.Active = nil
+	b, err := json.Marshal(n)
+	if err != nil {
+		// PlanSpec consists of marshallable types only.
+		panic(err)
+	}
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func normalizeMountpoints(mps []Mountpoint) []Mountpoint {
+	if mps == nil {
+		return nil
+	}
+	ret := make([]Mountpoint, 0, len(mps))
+	for _, mp := range mps {
+		n := Mountpoint{
+			Path:        strings.TrimSpace(mp.Path),
+			Tags:        normalizeTags(mp.Tags),
+			ReadOnly:    mp.ReadOnly,
+			SubPath:     strings.TrimSpace(mp.SubPath),
+			CachePolicy: mp.CachePolicy,
+		}
+		if n.CachePolicy == CacheNever {
+			// same as unspecified.
+			n.CachePolicy = ""
+		}
+		ret = append(ret, n)
+	}
+	slices.SortStableFunc(ret, func(a, b Mountpoint) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return ret
+}
+
+func normalizeTags(ts []tags.Tag) []tags.Tag {
+	if ts == nil {
+		return nil
+	}
+	ret := make([]tags.Tag, 0, len(ts))
+	for _, t := range ts {
+		t = tags.Tag{Key: strings.TrimSpace(t.Key), Value: strings.TrimSpace(t.Value)}
+		if t.Key == tags.KeyKnitTimestamp {
+			if at, err := rfctime.ParseRFC3339DateTime(t.Value); err == nil {
+				t = tags.Timestamp(at.Time().UTC())
+			}
+		}
+		ret = append(ret, t)
+	}
+	return tags.NewSet(ret...).Slice()
+}
+
+func normalizeEnv(env []EnvVar) []EnvVar {
+	if env == nil {
+		return nil
+	}
+	ret := make([]EnvVar, 0, len(env))
+	for _, e := range env {
+		ret = append(ret, EnvVar{Name: strings.TrimSpace(e.Name), Value: e.Value})
+	}
+	return ret
+}
+
+func normalizeSecrets(ss []SecretMount) []SecretMount {
+	if ss == nil {
+		return nil
+	}
+	ret := make([]SecretMount, 0, len(ss))
+	for _, s := range ss {
+		ret = append(ret, SecretMount{
+			Name: strings.TrimSpace(s.Name),
+			Key:  strings.TrimSpace(s.Key),
+			Path: strings.TrimSpace(s.Path),
+			Env:  strings.TrimSpace(s.Env),
+		})
+	}
+	slices.SortFunc(ret, func(a, b SecretMount) int {
+		return cmp.Or(
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Key, b.Key),
+			strings.Compare(a.Path, b.Path),
+			strings.Compare(a.Env, b.Env),
+		)
+	})
+	return slices.Compact(ret)
+}
+
+func normalizeLabels(ls []OnSpecLabel) []OnSpecLabel {
+	if ls == nil {
+		return nil
+	}
+	ret := make([]OnSpecLabel, 0, len(ls))
+	for _, l := range ls {
+		ret = append(ret, OnSpecLabel{Key: strings.TrimSpace(l.Key), Value: strings.TrimSpace(l.Value)})
+	}
+	slices.SortFunc(ret, func(a, b OnSpecLabel) int {
+		if c := strings.Compare(a.Key, b.Key); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return slices.Compact(ret)
+}
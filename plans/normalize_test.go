@@ -0,0 +1,125 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPlanSpec_Normalize(t *testing.T) {
+	active := false
+	ps := plans.PlanSpec{
+		Image: plans.Image{Repository: "example.com/train", Tag: "v1"},
+		Annotations: plans.Annotations{
+			{Key: "owner ", Value: "someone"},
+			{Key: "memo", Value: " note "},
+			{Key: "owner", Value: "ml-team"},
+		},
+		Inputs: []plans.Mountpoint{
+			{Path: "/in/b", Tags: []tags.Tag{{Key: "type", Value: "b"}}},
+			{Path: " /in/a", Tags: []tags.Tag{
+				{Key: "type", Value: "a"},
+				{Key: tags.KeyKnitTimestamp, Value: "2024-01-01T09:00:00+09:00"},
+				{Key: "project ", Value: " demo"},
+				{Key: "type", Value: "a"},
+			}},
+		},
+		Outputs: []plans.Mountpoint{{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}}},
+		OnNode: &plans.OnNode{
+			Must: []plans.OnSpecLabel{{Key: "zone", Value: "a"}, {Key: "accelerator", Value: "gpu"}},
+		},
+		Resources: plans.Resources{"cpu": resource.MustParse("1")},
+		Active:    &active,
+	}
+	before := ps.Inputs[1].Tags[0]
+
+	got := ps.Normalize()
+	want := plans.PlanSpec{
+		Image: plans.Image{Repository: "example.com/train", Tag: "v1"},
+		Annotations: plans.Annotations{
+			{Key: "memo", Value: "note"},
+			{Key: "owner", Value: "ml-team"},
+		},
+		Inputs: []plans.Mountpoint{
+			{Path: "/in/a", Tags: []tags.Tag{
+				{Key: tags.KeyKnitTimestamp, Value: "2024-01-01T00:00:00+00:00"},
+				{Key: "project", Value: "demo"},
+				{Key: "type", Value: "a"},
+			}},
+			{Path: "/in/b", Tags: []tags.Tag{{Key: "type", Value: "b"}}},
+		},
+		Outputs: []plans.Mountpoint{{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}}},
+		OnNode: &plans.OnNode{
+			Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}, {Key: "zone", Value: "a"}},
+		},
+		Resources: plans.Resources{"cpu": resource.MustParse("1")},
+		Active:    &active,
+	}
+	if diffs := got.Diff(want); diffs != nil {
+		t.Errorf("unexpected normalized form: %v", diffs)
+	}
+	if got.Inputs[0].Tags[0].Key != tags.KeyKnitTimestamp || got.Inputs[0].Tags[0].Value != want.Inputs[0].Tags[0].Value {
+		t.Errorf("tags are not sorted: %v", got.Inputs[0].Tags)
+	}
+	if ps.Inputs[1].Tags[0] != before {
+		t.Errorf("original is modified: %v", ps.Inputs[1].Tags)
+	}
+
+	// equivalent specs have the same hash.
+	if ps.Hash() != want.Hash() {
+		t.Errorf("hashes differ: %s, %s", ps.Hash(), want.Hash())
+	}
+	activated := true
+	want.Active = &activated
+	if ps.Hash() != want.Hash() {
+		t.Errorf("hash depends on activeness")
+	}
+	want.Args = []string{"--epochs", "10"}
+	if ps.Hash() == want.Hash() {
+		t.Errorf("different specs have the same hash")
+	}
+}
+
+func TestPlanSpec_runAs(t *testing.T) {
+	spec, err := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/data", "type:dataset").
+		WithWorkingDir("/work").
+		WithRunAs(1000, 100).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.WorkingDir != "/work" || *spec.RunAsUser != 1000 || *spec.RunAsGroup != 100 {
+		t.Errorf("unexpected spec: %+v", spec)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	other := spec.Clone()
+	*other.RunAsGroup = 0
+	if spec.Equal(other) || spec.Hash() == other.Hash() {
+		t.Errorf("RunAsGroup is not taken into account")
+	}
+	other = spec.Clone()
+	other.RunAsUser = nil
+	if spec.Equal(other) || spec.Hash() == other.Hash() {
+		t.Errorf("RunAsUser is not taken into account")
+	}
+	other = spec.Clone()
+	other.WorkingDir = " /work "
+	if !spec.Normalize().Equal(other.Normalize()) || spec.Hash() != other.Hash() {
+		t.Errorf("WorkingDir is not normalized")
+	}
+
+	for name, b := range map[string]*plans.SpecBuilder{
+		"relative working dir": plans.NewSpec().WithWorkingDir("work"),
+		"negative uid":         plans.NewSpec().WithRunAs(-1, 0),
+	} {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error does not occur", name)
+		}
+	}
+}
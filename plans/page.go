@@ -0,0 +1,10 @@
+package plans
+
+import "github.com/opst/knitfab-api-types/page"
+
+// Page is the format for response body from Knitfab APIs below, when paginated with page.Query:
+//
+// - GET /api/plans/?limit=...&cursor=...
+//
+// Without page.Query, the response is a bare list of Detail.
+type Page = page.Page[Detail]
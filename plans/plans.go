@@ -8,83 +8,145 @@ import (
 	"strings"
 
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/duration"
 	"github.com/opst/knitfab-api-types/tags"
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type Summary struct {
 	// PlanId is the id of the Plan.
-	PlanId string `json:"planId"`
+	PlanId PlanId `json:"planId" yaml:"planId"`
 
 	// Image is the container image of the Plan.
 	//
 	// This is exclusive with Name.
-	Image *Image `json:"image,omitempty"`
+	Image *Image `json:"image,omitempty" yaml:"image,omitempty"`
 
 	// Entrypoint is the entrypoint of the container of the Plan.
-	Entrypoint []string `json:"entrypoint,omitempty"`
+	Entrypoint []string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
 
 	// Args are the arguments of the container of the Plan.
-	Args []string `json:"args,omitempty"`
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
 
 	// Name is the name of the Plan.
 	//
 	// This is exclusive with Image, and used only for the system-builtin Plan with no image.
-	Name string `json:"name,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
 	// Annotations are the annotations of the Plan.
 	//
 	// In JSON format, it is a list of strings in the form of "key=value".
-	Annotations Annotations `json:"annotations,omitempty"`
+	Annotations Annotations `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+
+	// Project is the name of the Project which the Plan belongs to.
+	//
+	// If empty, the Plan is not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
 }
 
 func (s Summary) Equal(o Summary) bool {
 	return s.PlanId == o.PlanId &&
 		s.Image.Equal(o.Image) &&
-		cmp.SliceEqEq(s.Entrypoint, o.Entrypoint) &&
-		cmp.SliceEqEq(s.Args, o.Args) &&
+		apicmp.SliceEqEq(s.Entrypoint, o.Entrypoint) &&
+		apicmp.SliceEqEq(s.Args, o.Args) &&
 		s.Name == o.Name &&
-		s.Annotations.Equal(o.Annotations)
+		s.Annotations.Equal(o.Annotations) &&
+		s.Project == o.Project
 }
 
+// Image is a container image, like "repository:tag", "repository@sha256:..." or "repository:tag@sha256:...".
 type Image struct {
 	Repository string
 	Tag        string
+
+	// Digest is the content digest of the image, like "sha256:...".
+	//
+	// If empty, the image is referred only by Tag.
+	Digest string
 }
 
+// Equal returns true if i and o refer the same image.
+//
+// When both of i and o have digests, they are compared by Repository and Digest; Tags are ignored.
+// Otherwise, all of Repository, Tag and Digest should be same.
 func (i *Image) Equal(o *Image) bool {
 	if (i == nil) || (o == nil) {
 		return (i == nil) && (o == nil)
 	}
+	if i.Digest != "" && o.Digest != "" {
+		return i.Repository == o.Repository &&
+			i.Digest == o.Digest
+	}
 	return i.Repository == o.Repository &&
-		i.Tag == o.Tag
+		i.Tag == o.Tag &&
+		i.Digest == o.Digest
 }
 
 // parse string as Image Tag, and upgate itself.
 //
 // this spec is based on docker image tag spec[^1].
+// In addition, the image can be pinned by digest, as "<name>@<digest>" or "<name>:<tag>@<digest>".
 //
 // [^1]: https://docs.docker.com/engine/reference/commandline/tag/#description
 func (i *Image) Parse(s string) error {
-	// [<repository>[:<port>]/]<name>:<tag>
+	// [<repository>[:<port>]/]<name>[:<tag>][@<digest>]
+
+	base, digest, hasDigest := strings.Cut(s, "@")
+	if !hasDigest {
+		ref, err := name.NewTag(s, name.WithDefaultRegistry(""))
+		if err != nil {
+			return err
+		}
 
-	ref, err := name.NewTag(s, name.WithDefaultRegistry(""))
+		i.Repository = ref.Repository.Name()
+		i.Tag = ref.TagStr()
+		i.Digest = ""
+		return nil
+	}
+
+	if _, err := name.NewDigest("image@"+digest, name.WithDefaultRegistry("")); err != nil {
+		return fmt.Errorf("image digest is malformed: %s: %w", s, err)
+	}
+
+	tag := ""
+	if c := strings.LastIndex(base, ":"); strings.LastIndex(base, "/") < c {
+		tag = base[c+1:]
+		base = base[:c]
+	}
+	repo, err := name.NewRepository(base, name.WithDefaultRegistry(""))
 	if err != nil {
 		return err
 	}
+	if tag != "" {
+		if _, err := name.NewTag(repo.Name()+":"+tag, name.WithDefaultRegistry("")); err != nil {
+			return err
+		}
+	}
 
-	i.Repository = ref.Repository.Name()
-	i.Tag = ref.TagStr()
+	i.Repository = repo.Name()
+	i.Tag = tag
+	i.Digest = digest
 	return nil
 }
 
 func (i *Image) marshal() string {
-	if i.Repository == "" && i.Tag == "" {
+	if i.Repository == "" && i.Tag == "" && i.Digest == "" {
 		return ""
 	}
-	return fmt.Sprintf(`%s:%s`, i.Repository, i.Tag)
+	s := i.Repository
+	if i.Tag != "" || i.Digest == "" {
+		s += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		s += "@" + i.Digest
+	}
+	return s
 }
 
 func (i Image) MarshalJSON() ([]byte, error) {
@@ -128,7 +190,7 @@ func (i *Image) String() string {
 type Annotations []Annotation
 
 func (ans Annotations) Equal(o Annotations) bool {
-	return cmp.SliceEqualUnordered(ans, o)
+	return apicmp.SliceEqualUnordered(ans, o)
 }
 
 func (ans Annotations) marshal() []Annotation {
@@ -214,51 +276,147 @@ func (an *Annotation) UnmarshalYAML(node *yaml.Node) error {
 //
 // - PUT  /api/plans/{planId}/resources
 type Detail struct {
-	Summary
+	Summary `yaml:",inline"`
+
+	// Env are the environment variables of the container of the Plan.
+	Env []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// WorkingDir is the working directory of the container of the Plan.
+	WorkingDir string `json:"working_dir,omitempty" yaml:"working_dir,omitempty"`
+
+	// RunAsUser is the UID to run the container of the Plan. If nil, the default of the image is used.
+	RunAsUser *int64 `json:"run_as_user,omitempty" yaml:"run_as_user,omitempty"`
+
+	// RunAsGroup is the GID to run the container of the Plan. If nil, the default of the image is used.
+	RunAsGroup *int64 `json:"run_as_group,omitempty" yaml:"run_as_group,omitempty"`
+
+	// Secrets are the Kubernetes Secrets consumed by the container of the Plan.
+	Secrets []SecretMount `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// Sidecars are the auxiliary containers running next to the main container of the Plan.
+	Sidecars []Container `json:"sidecars,omitempty" yaml:"sidecars,omitempty"`
 
 	// Inputs are the input mountpoints of the plan.
-	Inputs []Input `json:"inputs"`
+	Inputs []Input `json:"inputs" yaml:"inputs"`
 
 	// Outputs are the output mountpoints of the plan.
-	Outputs []Output `json:"outputs"`
+	Outputs []Output `json:"outputs" yaml:"outputs"`
 
 	// Log is the log point of the plan.
 	//
 	// If nil, the plan does not record logs.
-	Log *Log `json:"log,omitempty"`
+	Log *Log `json:"log,omitempty" yaml:"log,omitempty"`
 
 	// Active shows Plan's activeness.
 	//
 	// It is true if the plan is active and new runs can be created.
-	Active bool `json:"active"`
+	Active bool `json:"active" yaml:"active"`
 
 	// OnNode is the node affinity/torelance of the plan.
 	//
 	// If nil, the plan does not have node affinity/torelance.
-	OnNode *OnNode `json:"on_node,omitempty"`
+	OnNode *OnNode `json:"on_node,omitempty" yaml:"on_node,omitempty"`
 
 	// Resources is the resource limits and requiremnts of the plan.
-	Resources Resources `json:"resources,omitempty"`
+	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// Timeout is the maximum duration of a Run of the Plan, from its start.
+	Timeout *duration.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Priority is the priority class of Runs of the Plan.
+	Priority Priority `json:"priority,omitempty" yaml:"priority,omitempty"`
+
+	// Schedule is the time-based trigger of the Plan.
+	Schedule *Schedule `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// Deadline is the maximum duration of a Run of the Plan, from its creation.
+	Deadline *duration.Duration `json:"deadline,omitempty" yaml:"deadline,omitempty"`
 
 	// ServiceAccount is the ServiceAccount name of the plan.
 	//
 	// Workers of the Run based this Plan will run with this ServiceAccount.
-	ServiceAccount string `json:"service_account,omitempty"`
+	ServiceAccount string `json:"service_account,omitempty" yaml:"service_account,omitempty"`
+
+	// Warnings are soft issues found while registering or updating the Plan.
+	//
+	// This is set only in responses of mutating WebAPIs.
+	Warnings []meta.Warning `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// Extensions are custom fields of the Plan, set by plugins or experimental features of Knitfab.
+	//
+	// Clients should keep ones they do not know as they are.
+	Extensions extensions.Extensions `json:"x-ext,omitempty" yaml:"x-ext,omitempty"`
 }
 
 func (d Detail) Equal(o Detail) bool {
-	logEq := d.Log == nil && o.Log == nil ||
-		(d.Log != nil && o.Log != nil && d.Log.Equal(*o.Log))
-	onnodeEq := d.OnNode == nil && o.OnNode == nil ||
-		(d.OnNode != nil && o.OnNode != nil && d.OnNode.Equal(*o.OnNode))
-
 	return d.Summary.Equal(o.Summary) &&
+		apicmp.SliceEqual(d.Env, o.Env) &&
+		d.WorkingDir == o.WorkingDir &&
+		apicmp.PtrEqEq(d.RunAsUser, o.RunAsUser) &&
+		apicmp.PtrEqEq(d.RunAsGroup, o.RunAsGroup) &&
+		apicmp.SliceEqualUnordered(d.Secrets, o.Secrets) &&
+		apicmp.SliceEqualUnordered(d.Sidecars, o.Sidecars) &&
 		d.Active == o.Active &&
 		d.ServiceAccount == o.ServiceAccount &&
-		logEq && onnodeEq &&
-		cmp.MapEqual(d.Resources, o.Resources) &&
-		cmp.SliceEqualUnordered(d.Inputs, o.Inputs) &&
-		cmp.SliceEqualUnordered(d.Outputs, o.Outputs)
+		apicmp.PtrEqual(d.Log, o.Log) &&
+		apicmp.PtrEqual(d.OnNode, o.OnNode) &&
+		apicmp.MapEqual(d.Resources, o.Resources) &&
+		apicmp.PtrEqEq(d.Timeout, o.Timeout) &&
+		apicmp.PtrEqEq(d.Deadline, o.Deadline) &&
+		apicmp.PtrEqEq(d.Schedule, o.Schedule) &&
+		d.Priority == o.Priority &&
+		apicmp.SliceEqualUnordered(d.Inputs, o.Inputs) &&
+		apicmp.SliceEqualUnordered(d.Outputs, o.Outputs) &&
+		apicmp.SliceEqualUnordered(d.Warnings, o.Warnings) &&
+		d.Extensions.Equal(o.Extensions)
+}
+
+// ToSpec returns the PlanSpec to register the same Plan, for example, on another Knitfab instance.
+//
+// Server-populated fields (PlanId, Upstreams, Downstreams and Warnings) are dropped.
+// The returned PlanSpec shares nothing with d.
+func (d Detail) ToSpec() PlanSpec {
+	spec := PlanSpec{
+		Annotations:    slices.Clone(d.Annotations),
+		Entrypoint:     slices.Clone(d.Entrypoint),
+		Args:           slices.Clone(d.Args),
+		Env:            slices.Clone(d.Env),
+		WorkingDir:     d.WorkingDir,
+		RunAsUser:      clone.Ptr(d.RunAsUser),
+		RunAsGroup:     clone.Ptr(d.RunAsGroup),
+		Secrets:        slices.Clone(d.Secrets),
+		Sidecars:       clone.SliceWith(d.Sidecars, Container.Clone),
+		Inputs:         []Mountpoint{},
+		Outputs:        []Mountpoint{},
+		OnNode:         clone.PtrWith(d.OnNode, OnNode.Clone),
+		Resources:      d.Resources.Clone(),
+		Timeout:        clone.Ptr(d.Timeout),
+		Deadline:       clone.Ptr(d.Deadline),
+		Priority:       d.Priority,
+		Schedule:       clone.Ptr(d.Schedule),
+		ServiceAccount: d.ServiceAccount,
+		Active:         clone.Ptr(&d.Active),
+		Project:        d.Project,
+	}
+	if d.Image != nil {
+		spec.Image = *d.Image
+	}
+	for _, in := range d.Inputs {
+		spec.Inputs = append(spec.Inputs, in.Mountpoint.Clone())
+	}
+	for _, out := range d.Outputs {
+		spec.Outputs = append(spec.Outputs, out.Mountpoint.Clone())
+	}
+	if d.Log != nil {
+		lp := d.Log.LogPoint.Clone()
+		spec.Log = &lp
+	}
+	return spec
+}
+
+// ToSummary returns the Summary of the Plan, sharing nothing with d.
+func (d Detail) ToSummary() Summary {
+	return d.Summary.Clone()
 }
 
 // Mountpoint is the format for input/output mountpoints of a Plan.
@@ -267,7 +425,7 @@ type Mountpoint struct {
 	//
 	// This is the path in the container where the Data will be mounted
 	// when the Run starts.
-	Path string `json:"path"`
+	Path string `json:"path" yaml:"path"`
 
 	// Tags are the tags of the mountpoint.
 	//
@@ -275,27 +433,46 @@ type Mountpoint struct {
 	// The Data with these all tags will be mounted to the Path when the Run starts.
 	//
 	// For output mountpoints, these are the tags to be attached to the Data mounted.
-	Tags []tags.Tag `json:"tags"`
+	Tags []tags.Tag `json:"tags" yaml:"tags"`
+
+	// ReadOnly makes the mountpoint read-only.
+	//
+	// This is for input mountpoints only.
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+
+	// SubPath is the relative path in the Data to be mounted, instead of the whole Data.
+	//
+	// If empty, the whole Data is mounted. This is for input mountpoints only.
+	SubPath string `json:"sub_path,omitempty" yaml:"sub_path,omitempty"`
+
+	// CachePolicy tells whether Knitfab may reuse existing Data for the output.
+	//
+	// If empty, CacheNever is used. This is for output mountpoints only.
+	CachePolicy CachePolicy `json:"cache_policy,omitempty" yaml:"cache_policy,omitempty"`
 }
 
 func (m Mountpoint) Equal(o Mountpoint) bool {
-	return m.Path == o.Path && cmp.SliceEqualUnordered(m.Tags, o.Tags)
+	return m.Path == o.Path &&
+		m.ReadOnly == o.ReadOnly &&
+		m.SubPath == o.SubPath &&
+		m.CachePolicy == o.CachePolicy &&
+		apicmp.SliceEqualUnordered(m.Tags, o.Tags)
 }
 
 // Upstream is the format for input dependencies of a Plan.
 type Upstream struct {
 	// Plan is the upstream Plan.
-	Plan Summary `json:"plan"`
+	Plan Summary `json:"plan" yaml:"plan"`
 
 	// Mountpoint represents the Output which is directt upstream.
 	//
 	// Log and Mountpoint are mutually exclusive.
-	Mountpoint *Mountpoint `json:"mountpoint,omitempty"`
+	Mountpoint *Mountpoint `json:"mountpoint,omitempty" yaml:"mountpoint,omitempty"`
 
 	// Log represents the Log which is direct upstream.
 	//
 	// Log and Mountpoint are mutually exclusive.
-	Log *LogPoint `json:"log,omitempty"`
+	Log *LogPoint `json:"log,omitempty" yaml:"log,omitempty"`
 }
 
 func (d Upstream) Equal(o Upstream) bool {
@@ -318,25 +495,25 @@ func (d Upstream) Equal(o Upstream) bool {
 
 // Input is the format for input mountpoints of a Plan.
 type Input struct {
-	Mountpoint
+	Mountpoint `yaml:",inline"`
 
 	// Upstreams are the upstream Plans and their output mountpoints
 	// whose output Data can be mounted to this input mountpoint.
-	Upstreams []Upstream `json:"upstreams"`
+	Upstreams []Upstream `json:"upstreams" yaml:"upstreams"`
 }
 
 func (i Input) Equal(o Input) bool {
 	return i.Mountpoint.Equal(o.Mountpoint) &&
-		cmp.SliceEqualUnordered(i.Upstreams, o.Upstreams)
+		apicmp.SliceEqualUnordered(i.Upstreams, o.Upstreams)
 }
 
 // Downstream is the format for output dependencies of a Plan.
 type Downstream struct {
 	// Plan is the downstream Plan.
-	Plan Summary `json:"plan"`
+	Plan Summary `json:"plan" yaml:"plan"`
 
 	// Mountpoint represents the Input which is direct downstream.
-	Mountpoint Mountpoint `json:"mountpoint"`
+	Mountpoint Mountpoint `json:"mountpoint" yaml:"mountpoint"`
 }
 
 func (d Downstream) Equal(o Downstream) bool {
@@ -345,29 +522,29 @@ func (d Downstream) Equal(o Downstream) bool {
 
 // Output is the format for output mountpoints of a Plan.
 type Output struct {
-	Mountpoint
+	Mountpoint `yaml:",inline"`
 
 	// Downstreams are the downstream Plans and their input mountpoints
 	// can be assigned with Data from this output.
-	Downstreams []Downstream `json:"downstreams"`
+	Downstreams []Downstream `json:"downstreams" yaml:"downstreams"`
 }
 
 func (o Output) Equal(oo Output) bool {
 	return o.Mountpoint.Equal(oo.Mountpoint) &&
-		cmp.SliceEqualUnordered(o.Downstreams, oo.Downstreams)
+		apicmp.SliceEqualUnordered(o.Downstreams, oo.Downstreams)
 }
 
 type Log struct {
-	LogPoint
+	LogPoint `yaml:",inline"`
 
 	// Downstreams are the downstream Plans and their input mountpoints
 	// can be assigned with Data from this output.
-	Downstreams []Downstream `json:"downstreams"`
+	Downstreams []Downstream `json:"downstreams" yaml:"downstreams"`
 }
 
 func (l Log) Equal(ol Log) bool {
 	return l.LogPoint.Equal(ol.LogPoint) &&
-		cmp.SliceEqualUnordered(l.Downstreams, ol.Downstreams)
+		apicmp.SliceEqualUnordered(l.Downstreams, ol.Downstreams)
 }
 
 func (l Log) String() string {
@@ -375,17 +552,30 @@ func (l Log) String() string {
 }
 
 type LogPoint struct {
-	Tags []tags.Tag `json:"tags"`
+	Tags []tags.Tag `json:"tags" yaml:"tags"`
 }
 
 func (lp LogPoint) Equal(o LogPoint) bool {
-	return cmp.SliceEqualUnordered(lp.Tags, o.Tags)
+	return apicmp.SliceEqualUnordered(lp.Tags, o.Tags)
 }
 
 func (lp LogPoint) String() string {
 	return fmt.Sprintf("{Tags: %+v}", lp.Tags)
 }
 
+// OnNode is the node affinity/toleration of the Plan.
+//
+// For each label "key=value", Runs of the Plan are scheduled as below:
+//
+// - May: Runs tolerate the taint "key=value:NoSchedule".
+//
+// - Prefer: Runs tolerate the taints "key=value:NoSchedule" and "key=value:PreferNoSchedule",
+// and prefer nodes labeled "key=value".
+//
+// - Must: Runs tolerate the taints "key=value:NoSchedule" and "key=value:PreferNoSchedule",
+// and are scheduled only on nodes labeled with all of "must" labels.
+//
+// See ToNodeAffinity and ToTolerations for the Kubernetes translation.
 type OnNode struct {
 	May    []OnSpecLabel `json:"may,omitempty" yaml:"may,omitempty"`
 	Prefer []OnSpecLabel `json:"prefer,omitempty" yaml:"prefer,omitempty"`
@@ -393,9 +583,9 @@ type OnNode struct {
 }
 
 func (o OnNode) Equal(oo OnNode) bool {
-	return cmp.SliceEqualUnordered(o.May, oo.May) &&
-		cmp.SliceEqualUnordered(o.Prefer, oo.Prefer) &&
-		cmp.SliceEqualUnordered(o.Must, oo.Must)
+	return apicmp.SliceEqualUnordered(o.May, oo.May) &&
+		apicmp.SliceEqualUnordered(o.Prefer, oo.Prefer) &&
+		apicmp.SliceEqualUnordered(o.Must, oo.Must)
 }
 
 type OnSpecLabel struct {
@@ -411,15 +601,45 @@ func (l OnSpecLabel) Equal(o OnSpecLabel) bool {
 	return l.Key == o.Key && l.Value == o.Value
 }
 
+// Parse parses s in the form "key=value" as OnSpecLabel,
+// and validates it with OnSpecLabel.Validate.
 func (l *OnSpecLabel) Parse(s string) error {
+	parsed := OnSpecLabel{}
+	if err := parsed.ParseLax(s); err != nil {
+		return err
+	}
+	if err := parsed.Validate(); err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
 
+// ParseLax parses s in the form "key=value" as OnSpecLabel, without validation.
+//
+// Use this for labels which the server may accept but this package does not know.
+func (l *OnSpecLabel) ParseLax(s string) error {
 	k, v, ok := strings.Cut(s, "=")
 	if !ok {
 		return fmt.Errorf("label format error (should be key=value): %s", s)
 	}
+	*l = OnSpecLabel{Key: k, Value: v}
+	return nil
+}
 
-	l.Key = k
-	l.Value = v
+// Validate checks the label against the Kubernetes label syntax.
+//
+// Key should be a qualified name, "[prefix/]name", where
+// the prefix is a DNS subdomain (up to 253 characters) and
+// the name is up to 63 characters of alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric.
+// Value should be empty or follow the same rules as the name.
+func (l OnSpecLabel) Validate() error {
+	if errs := validation.IsQualifiedName(l.Key); len(errs) != 0 {
+		return fmt.Errorf("label key %q is invalid: %s", l.Key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(l.Value); len(errs) != 0 {
+		return fmt.Errorf("label value %q (for key %q) is invalid: %s", l.Value, l.Key, strings.Join(errs, "; "))
+	}
 	return nil
 }
 
@@ -439,28 +659,32 @@ func (l OnSpecLabel) MarshalYAML() (interface{}, error) {
 	return n, nil
 }
 
+// UnmarshalJSON decodes "key=value" without validation, for forward compatibility.
+// Labels are validated by PlanSpec.Validate.
 func (l *OnSpecLabel) UnmarshalJSON(value []byte) error {
 	expr := new(string)
 	err := json.Unmarshal(value, expr)
 	if err != nil {
 		return err
 	}
-	return l.Parse(*expr)
+	return l.ParseLax(*expr)
 }
 
+// UnmarshalYAML decodes "key=value" without validation, for forward compatibility.
+// Labels are validated by PlanSpec.Validate.
 func (l *OnSpecLabel) UnmarshalYAML(node *yaml.Node) error {
 	expr := new(string)
 	err := node.Decode(expr)
 	if err != nil {
 		return err
 	}
-	return l.Parse(*expr)
+	return l.ParseLax(*expr)
 }
 
 type Resources map[string]resource.Quantity
 
 func (r Resources) Equal(o Resources) bool {
-	return cmp.MapEqual(r, o)
+	return apicmp.MapEqual(r, o)
 }
 
 func (r Resources) MarshalJSON() ([]byte, error) {
@@ -527,6 +751,37 @@ type PlanSpec struct {
 	// Args are the arguments of the container of the Plan.
 	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
 
+	// Env are the environment variables of the container of the Plan.
+	//
+	// In JSON format, it is a list of strings in the form of "NAME=value".
+	// The order is kept, since later variables can refer earlier ones.
+	Env []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// WorkingDir is the working directory of the container of the Plan.
+	//
+	// If empty, the default of the image is used.
+	WorkingDir string `json:"working_dir,omitempty" yaml:"working_dir,omitempty"`
+
+	// RunAsUser is the UID to run the container of the Plan.
+	//
+	// If nil, the default of the image is used.
+	RunAsUser *int64 `json:"run_as_user,omitempty" yaml:"run_as_user,omitempty"`
+
+	// RunAsGroup is the GID to run the container of the Plan.
+	//
+	// If nil, the default of the image is used.
+	RunAsGroup *int64 `json:"run_as_group,omitempty" yaml:"run_as_group,omitempty"`
+
+	// Secrets are the Kubernetes Secrets consumed by the container of the Plan.
+	//
+	// Each of them is mounted as files, or set as an environment variable.
+	Secrets []SecretMount `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+
+	// Sidecars are the auxiliary containers running next to the main container of the Plan.
+	//
+	// The order is not meaningful; sidecars are identified by their names.
+	Sidecars []Container `json:"sidecars,omitempty" yaml:"sidecars,omitempty"`
+
 	// Inputs are the input mountpoints of the plan.
 	//
 	// These describes "where should input Data be mounted to the container" and
@@ -559,6 +814,27 @@ type PlanSpec struct {
 	// Resources is the conputational resource limits and requiremnts of the plan.
 	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
 
+	// Timeout is the maximum duration of a Run of the Plan, from its start.
+	//
+	// Runs running longer than this are aborted. If nil, Runs are not timed out.
+	Timeout *duration.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// Priority is the priority class of Runs of the Plan.
+	//
+	// If empty, PriorityNormal is used.
+	Priority Priority `json:"priority,omitempty" yaml:"priority,omitempty"`
+
+	// Schedule is the time-based trigger of the Plan.
+	//
+	// If nil, Runs are started only when new Data for inputs are found.
+	Schedule *Schedule `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+
+	// Deadline is the maximum duration of a Run of the Plan, from its creation.
+	//
+	// Unlike Timeout, this includes the time waiting for starting.
+	// Runs not finished until then are aborted. If nil, Runs have no deadline.
+	Deadline *duration.Duration `json:"deadline,omitempty" yaml:"deadline,omitempty"`
+
 	// ServiceAccount is the Kubernetes ServiceAccount name of the plan.
 	ServiceAccount string `json:"service_account,omitempty" yaml:"service_account,omitempty"`
 
@@ -568,24 +844,36 @@ type PlanSpec struct {
 	//
 	// If false, the Plan is inactive and new Runs based the Plan are created but suspended to start.
 	Active *bool `json:"active" yaml:"active,omitempty"`
+
+	// Project is the name of the Project which the Plan belongs to.
+	//
+	// If empty, the Plan is not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
 }
 
 func (ps PlanSpec) Equal(o PlanSpec) bool {
-	logEq := ps.Log == nil && o.Log == nil || (ps.Log != nil && o.Log != nil && ps.Log.Equal(*o.Log))
-	onNodeEq := ps.OnNode == nil && o.OnNode == nil || (ps.OnNode != nil && o.OnNode != nil && ps.OnNode.Equal(*o.OnNode))
-	activeEq := ps.Active == nil && o.Active == nil || (ps.Active != nil && o.Active != nil && *ps.Active == *o.Active)
-
 	return ps.Annotations.Equal(o.Annotations) &&
 		ps.Image.Equal(&o.Image) &&
-		cmp.SliceEqEq(ps.Entrypoint, o.Entrypoint) &&
-		cmp.SliceEqEq(ps.Args, o.Args) &&
-		cmp.SliceEqualUnordered(ps.Inputs, o.Inputs) &&
-		cmp.SliceEqualUnordered(ps.Outputs, o.Outputs) &&
-		logEq &&
-		onNodeEq &&
-		cmp.MapEqual(ps.Resources, o.Resources) &&
+		apicmp.SliceEqEq(ps.Entrypoint, o.Entrypoint) &&
+		apicmp.SliceEqEq(ps.Args, o.Args) &&
+		apicmp.SliceEqual(ps.Env, o.Env) &&
+		ps.WorkingDir == o.WorkingDir &&
+		apicmp.PtrEqEq(ps.RunAsUser, o.RunAsUser) &&
+		apicmp.PtrEqEq(ps.RunAsGroup, o.RunAsGroup) &&
+		apicmp.SliceEqualUnordered(ps.Secrets, o.Secrets) &&
+		apicmp.SliceEqualUnordered(ps.Sidecars, o.Sidecars) &&
+		apicmp.SliceEqualUnordered(ps.Inputs, o.Inputs) &&
+		apicmp.SliceEqualUnordered(ps.Outputs, o.Outputs) &&
+		apicmp.PtrEqual(ps.Log, o.Log) &&
+		apicmp.PtrEqual(ps.OnNode, o.OnNode) &&
+		apicmp.MapEqual(ps.Resources, o.Resources) &&
+		apicmp.PtrEqEq(ps.Timeout, o.Timeout) &&
+		apicmp.PtrEqEq(ps.Deadline, o.Deadline) &&
+		apicmp.PtrEqEq(ps.Schedule, o.Schedule) &&
+		ps.Priority == o.Priority &&
 		ps.ServiceAccount == o.ServiceAccount &&
-		activeEq
+		apicmp.PtrEqEq(ps.Active, o.Active) &&
+		ps.Project == o.Project
 }
 
 // ResourceLimitChange is a change of resource limit of plan.
@@ -601,10 +889,38 @@ type ResourceLimitChange struct {
 }
 
 // SetServiceccount declares new ServiceAccount name of a Plan.
+//
+// It is validated on unmarshalling. See SetServiceAccount.Validate.
 type SetServiceAccount struct {
 	ServiceAccount string `json:"service_account" yaml:"service_account"`
 }
 
+func (s *SetServiceAccount) UnmarshalJSON(b []byte) error {
+	type setServiceAccount SetServiceAccount
+	v := setServiceAccount{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := SetServiceAccount(v).Validate(); err != nil {
+		return err
+	}
+	*s = SetServiceAccount(v)
+	return nil
+}
+
+func (s *SetServiceAccount) UnmarshalYAML(node *yaml.Node) error {
+	type setServiceAccount SetServiceAccount
+	v := setServiceAccount{}
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	if err := SetServiceAccount(v).Validate(); err != nil {
+		return err
+	}
+	*s = SetServiceAccount(v)
+	return nil
+}
+
 // AnnotationChange is a changeset of Annotations of a Plan.
 //
 // Knitfab WebAPI applies Remove first, then Add.
@@ -2,10 +2,14 @@ package plans_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/meta"
 	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -66,6 +70,60 @@ func TestImage(t *testing.T) {
 		Repository: "registry.invalid:5000/repo",
 		Tag:        "tag",
 	}))
+
+	t.Run("repository and digest", theory("repo@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", plans.Image{
+		Repository: "repo",
+		Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}))
+
+	t.Run("registry /w port, repository, tag and digest", theory("registry.invalid:5000/repo:tag@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", plans.Image{
+		Repository: "registry.invalid:5000/repo",
+		Tag:        "tag",
+		Digest:     "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	}))
+
+	t.Run("malformed digest", func(t *testing.T) {
+		for _, expr := range []string{"repo@sha256:xyz", "repo@", "repo:tag@latest"} {
+			if err := new(plans.Image).Parse(expr); err == nil {
+				t.Errorf("malformed image is accepted: %s", expr)
+			}
+		}
+	})
+}
+
+func TestImage_Equal(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	other := "sha256:" + strings.Repeat("f", 64)
+
+	for name, tc := range map[string]struct {
+		a, b plans.Image
+		want bool
+	}{
+		"same tag": {
+			a: plans.Image{Repository: "repo", Tag: "v1"}, b: plans.Image{Repository: "repo", Tag: "v1"}, want: true,
+		},
+		"same digest, different tags": {
+			a: plans.Image{Repository: "repo", Tag: "v1", Digest: digest}, b: plans.Image{Repository: "repo", Tag: "latest", Digest: digest}, want: true,
+		},
+		"same digest, one without tag": {
+			a: plans.Image{Repository: "repo", Digest: digest}, b: plans.Image{Repository: "repo", Tag: "v1", Digest: digest}, want: true,
+		},
+		"different digests, same tag": {
+			a: plans.Image{Repository: "repo", Tag: "v1", Digest: digest}, b: plans.Image{Repository: "repo", Tag: "v1", Digest: other}, want: false,
+		},
+		"one without digest": {
+			a: plans.Image{Repository: "repo", Tag: "v1", Digest: digest}, b: plans.Image{Repository: "repo", Tag: "v1"}, want: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.a.Equal(&tc.b); got != tc.want {
+				t.Errorf("%+v.Equal(%+v): got %v", tc.a, tc.b, got)
+			}
+			if got := tc.b.Equal(&tc.a); got != tc.want {
+				t.Errorf("%+v.Equal(%+v): got %v", tc.b, tc.a, got)
+			}
+		})
+	}
 }
 
 func TestResources(t *testing.T) {
@@ -84,7 +142,7 @@ func TestResources(t *testing.T) {
 				if err := json.Unmarshal([]byte(expr.Json), &unmarshalled); err != nil {
 					t.Fatal(err)
 				}
-				if !cmp.MapEqual(unmarshalled.Resources, resources) {
+				if !apicmp.MapEqual(unmarshalled.Resources, resources) {
 					t.Errorf("unexpected result: json.Unmarshal(%s) --> %#v", expr.Json, unmarshalled)
 				}
 
@@ -97,7 +155,7 @@ func TestResources(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				if !cmp.MapEqual(reunmarshalled.Resources, resources) {
+				if !apicmp.MapEqual(reunmarshalled.Resources, resources) {
 					t.Errorf("unexpected result: json.Marshal(%#v) --> %s", resources, marshalled)
 				}
 			}
@@ -111,7 +169,7 @@ func TestResources(t *testing.T) {
 				if err := yaml.Unmarshal([]byte(expr.Yaml), &unmarshalled); err != nil {
 					t.Fatal(err)
 				}
-				if !cmp.MapEqual(unmarshalled.Resources, resources) {
+				if !apicmp.MapEqual(unmarshalled.Resources, resources) {
 					t.Errorf("unexpected result: yaml.Unmarshal(%s) --> %#v", expr.Yaml, unmarshalled)
 				}
 
@@ -124,7 +182,7 @@ func TestResources(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				if !cmp.MapEqual(reunmarshalled.Resources, resources) {
+				if !apicmp.MapEqual(reunmarshalled.Resources, resources) {
 					t.Errorf("unexpected result: yaml.Marshal(%#v) --> %s", resources, marshalled)
 				}
 			}
@@ -183,7 +241,7 @@ func TestAnnotations_marshalling(t *testing.T) {
 					t.Fatalf("unexpected error: %v", err)
 				}
 
-				if !cmp.SliceEqualUnordered(got, when.Annotations) {
+				if !apicmp.SliceEqualUnordered(got, when.Annotations) {
 					t.Errorf("unexpected result: json.Marshal(%#v) --> %s", when.Annotations, got)
 				}
 			}
@@ -267,7 +325,7 @@ func TestAnnotation_unmarshal_json(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if !cmp.SliceEqualUnordered(got, then.want) {
+			if !apicmp.SliceEqualUnordered(got, then.want) {
 				t.Errorf("unexpected result: json.Unmarshal(%s) --> %v", when.source, got)
 			}
 		}
@@ -321,7 +379,7 @@ func TestAnnotation_unmarshal_yaml(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if !cmp.SliceEqualUnordered(got, then.want) {
+			if !apicmp.SliceEqualUnordered(got, then.want) {
 				t.Errorf("unexpected result: json.Unmarshal(%s) --> %v", when.source, got)
 			}
 		}
@@ -351,3 +409,110 @@ func TestAnnotation_unmarshal_yaml(t *testing.T) {
 		Then{wantError: true},
 	))
 }
+
+func TestMountpoint_options(t *testing.T) {
+	mp := plans.Mountpoint{
+		Path:     "/in/dataset",
+		Tags:     []tags.Tag{{Key: "type", Value: "dataset"}},
+		ReadOnly: true,
+		SubPath:  "train",
+	}
+	knittest.AssertRoundTrip(t, mp)
+
+	b, err := json.Marshal(mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"read_only":true`) || !strings.Contains(string(b), `"sub_path":"train"`) {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	// they are omitted when not set, as before.
+	plain := plans.Mountpoint{Path: "/in/dataset", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}
+	b, err = json.Marshal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"path":"/in/dataset","tags":["type:dataset"]}` {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	for name, other := range map[string]plans.Mountpoint{
+		"writable":      {Path: mp.Path, Tags: mp.Tags, SubPath: mp.SubPath},
+		"whole of data": {Path: mp.Path, Tags: mp.Tags, ReadOnly: true},
+	} {
+		if mp.Equal(other) {
+			t.Errorf("%s: should not be equal", name)
+		}
+	}
+
+	spec := plans.PlanSpec{
+		Image:  plans.Image{Repository: "repo", Tag: "v1"},
+		Inputs: []plans.Mountpoint{mp},
+	}
+	if err := spec.Validate(); err != nil {
+		t.Errorf("valid spec is rejected: %v", err)
+	}
+	if cloned := spec.Clone(); !cloned.Inputs[0].Equal(mp) {
+		t.Errorf("options are not cloned: %+v", cloned.Inputs[0])
+	}
+}
+
+func TestDetail_warnings(t *testing.T) {
+	detail := func(ws ...meta.Warning) plans.Detail {
+		return plans.Detail{
+			Summary: plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101", Image: &plans.Image{Repository: "repo", Tag: "v1"}},
+			Inputs: []plans.Input{
+				{Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+			},
+			Outputs:  []plans.Output{},
+			Warnings: ws,
+		}
+	}
+	truncated := meta.Warning{Code: "no-node-with-taint", Field: "on_node.may[0]", Message: "no node has taint dedicated=ml"}
+	rounded := meta.Warning{Field: "resources.cpu", Message: "resource is rounded"}
+
+	t.Run("marshalled", func(t *testing.T) {
+		d := detail(truncated, rounded)
+		knittest.AssertRoundTrip(t, d)
+
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), `"warnings":[{"code":"no-node-with-taint","field":"on_node.may[0]","message":"no node has taint dedicated=ml"},{"field":"resources.cpu","message":"resource is rounded"}]`) {
+			t.Errorf("warnings are not marshalled: %s", b)
+		}
+	})
+
+	t.Run("omitted when empty", func(t *testing.T) {
+		for name, d := range map[string]plans.Detail{"nil": detail(), "empty": detail([]meta.Warning{}...)} {
+			b, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), "warnings") {
+				t.Errorf("%s: warnings in JSON: %s", name, b)
+			}
+			y, err := yaml.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(y), "warnings") {
+				t.Errorf("%s: warnings in YAML: %s", name, y)
+			}
+		}
+	})
+
+	t.Run("compared by Equal", func(t *testing.T) {
+		if !detail(truncated, rounded).Equal(detail(rounded, truncated)) {
+			t.Error("order of warnings should not matter")
+		}
+		if detail(truncated).Equal(detail(rounded)) {
+			t.Error("different warnings should not be equal")
+		}
+		if detail(truncated).Equal(detail()) {
+			t.Error("detail with warnings should not be equal to one without warnings")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package plans
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Priority is the priority class of Runs of the Plan.
+//
+// Runs with higher priority are scheduled earlier,
+// and can preempt Runs with lower priority.
+//
+// The zero value ("") means "unspecified", and is treated as PriorityNormal.
+type Priority string
+
+const (
+	// PriorityLow: Runs are scheduled after others, and can be preempted by Runs of any other priority.
+	PriorityLow Priority = "low"
+
+	// PriorityNormal: the default priority.
+	PriorityNormal Priority = "normal"
+
+	// PriorityHigh: Runs are scheduled before normal ones.
+	PriorityHigh Priority = "high"
+
+	// PriorityUrgent: Runs are scheduled first, and can preempt Runs of other priorities.
+	PriorityUrgent Priority = "urgent"
+)
+
+// Priorities returns all known Priorities, from the lowest to the highest.
+func Priorities() []Priority {
+	return []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+}
+
+func (p Priority) String() string {
+	return string(p)
+}
+
+// Valid returns true if p is one of known Priorities or unspecified.
+func (p Priority) Valid() bool {
+	switch p {
+	case "", PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+// Effective returns p, or PriorityNormal if p is unspecified.
+func (p Priority) Effective() Priority {
+	if p == "" {
+		return PriorityNormal
+	}
+	return p
+}
+
+func (p Priority) rank() int {
+	switch p.Effective() {
+	case PriorityLow:
+		return 0
+	case PriorityNormal:
+		return 1
+	case PriorityHigh:
+		return 2
+	case PriorityUrgent:
+		return 3
+	}
+	return -1
+}
+
+// Compare returns -1 if p is lower than o, +1 if p is higher than o, and 0 otherwise.
+//
+// Unspecified priority is compared as PriorityNormal. Unknown priorities are lower than any known ones.
+func (p Priority) Compare(o Priority) int {
+	return cmp.Compare(p.rank(), o.rank())
+}
+
+// Parse parses v as Priority, and returns error if it is not known.
+func (p *Priority) Parse(v string) error {
+	pr := Priority(v)
+	if !pr.Valid() {
+		return fmt.Errorf("unknown priority: %q", v)
+	}
+	*p = pr
+	return nil
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+func (p *Priority) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return p.Parse(v)
+}
+
+func (p Priority) MarshalYAML() (interface{}, error) {
+	return string(p), nil
+}
+
+func (p *Priority) UnmarshalYAML(node *yaml.Node) error {
+	var v string
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	return p.Parse(v)
+}
@@ -0,0 +1,78 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPriority(t *testing.T) {
+	ps := plans.Priorities()
+	for i, p := range ps {
+		if !p.Valid() {
+			t.Errorf("%s: should be valid", p)
+		}
+		if 0 < i && p.Compare(ps[i-1]) <= 0 {
+			t.Errorf("%s should be higher than %s", p, ps[i-1])
+		}
+
+		b, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON plans.Priority
+		if err := json.Unmarshal(b, &fromJSON); err != nil || fromJSON != p {
+			t.Errorf("%s: JSON round trip: got %s (%v)", p, fromJSON, err)
+		}
+
+		y, err := yaml.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromYAML plans.Priority
+		if err := yaml.Unmarshal(y, &fromYAML); err != nil || fromYAML != p {
+			t.Errorf("%s: YAML round trip: got %s (%v)", p, fromYAML, err)
+		}
+	}
+
+	unspecified := plans.Priority("")
+	if unspecified.Effective() != plans.PriorityNormal || unspecified.Compare(plans.PriorityNormal) != 0 {
+		t.Error("unspecified priority should be normal")
+	}
+
+	var p plans.Priority
+	if err := json.Unmarshal([]byte(`"asap"`), &p); err == nil {
+		t.Errorf("unknown priority is accepted: %s", p)
+	}
+	if plans.Priority("asap").Compare(plans.PriorityLow) >= 0 {
+		t.Error("unknown priority should be lower than any known ones")
+	}
+}
+
+func TestPlanSpec_priority(t *testing.T) {
+	spec := plans.NewSpec().
+		WithImage("repo:v1").
+		AddInput("/in", "type:x").
+		WithPriority("urgent").
+		MustBuild()
+	if spec.Priority != plans.PriorityUrgent {
+		t.Errorf("unexpected priority: %s", spec.Priority)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	other := spec.Clone()
+	other.Priority = plans.PriorityLow
+	if spec.Equal(other) || spec.Hash() == other.Hash() {
+		t.Errorf("priority is not taken into account")
+	}
+	other.Priority = "asap"
+	if err := other.Validate(); err == nil {
+		t.Errorf("unknown priority is accepted")
+	}
+	if _, err := plans.NewSpec().WithPriority("asap").Build(); err == nil {
+		t.Errorf("expected error does not occur")
+	}
+}
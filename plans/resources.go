@@ -0,0 +1,130 @@
+package plans
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/patch"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Well-known resource names.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+
+	// ResourceGPU is the resource name of NVIDIA GPUs, provided by its device plugin.
+	ResourceGPU = "nvidia.com/gpu"
+)
+
+// Get returns the quantity of the resource.
+//
+// If the resource is not in r, it returns zero.
+func (r Resources) Get(name string) resource.Quantity {
+	return r[name]
+}
+
+// CPU returns the quantity of "cpu".
+func (r Resources) CPU() resource.Quantity {
+	return r.Get(ResourceCPU)
+}
+
+// Memory returns the quantity of "memory".
+func (r Resources) Memory() resource.Quantity {
+	return r.Get(ResourceMemory)
+}
+
+// GPU returns the quantity of "nvidia.com/gpu".
+func (r Resources) GPU() resource.Quantity {
+	return r.Get(ResourceGPU)
+}
+
+// Add returns the sum of r and o, by resource name.
+//
+// Neither r nor o is modified.
+func (r Resources) Add(o Resources) Resources {
+	ret := Resources{}
+	for name, q := range r {
+		ret[name] = q.DeepCopy()
+	}
+	for name, q := range o {
+		sum := ret[name]
+		sum.Add(q)
+		ret[name] = sum
+	}
+	return ret
+}
+
+// Max returns the larger quantities of r and o, by resource name.
+//
+// Neither r nor o is modified.
+func (r Resources) Max(o Resources) Resources {
+	ret := Resources{}
+	for name, q := range r {
+		ret[name] = q.DeepCopy()
+	}
+	for name, q := range o {
+		if cur, ok := ret[name]; !ok || cur.Cmp(q) < 0 {
+			ret[name] = q.DeepCopy()
+		}
+	}
+	return ret
+}
+
+// HumanString returns the resources like "cpu=500m, memory=1Gi", sorted by name.
+func (r Resources) HumanString() string {
+	terms := make([]string, 0, len(r))
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		q := r[name]
+		terms = append(terms, name+"="+q.String())
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Apply returns new Resources, which are r changed by c.
+//
+// Resources in Unset are removed even if they are also in Set. r is not modified.
+func (c ResourceLimitChange) Apply(r Resources) Resources {
+	ret := Resources{}
+	maps.Copy(ret, r)
+	maps.Copy(ret, c.Set)
+	for _, name := range c.Unset {
+		delete(ret, name)
+	}
+	return ret
+}
+
+// ResourceLimitChangeOf returns the ResourceLimitChange from options by resource names:
+// resources with some quantities are set, and null resources are unset.
+// Absent resources are ignored.
+func ResourceLimitChangeOf(options map[string]patch.Option[resource.Quantity]) ResourceLimitChange {
+	change := ResourceLimitChange{}
+	for _, name := range slices.Sorted(maps.Keys(options)) {
+		o := options[name]
+		if q, ok := o.Get(); ok {
+			if change.Set == nil {
+				change.Set = Resources{}
+			}
+			change.Set[name] = q.DeepCopy()
+		} else if o.IsNull() {
+			change.Unset = append(change.Unset, name)
+		}
+	}
+	return change
+}
+
+// Options returns the change as options by resource names,
+// as the reverse of ResourceLimitChangeOf.
+//
+// Resources both in Set and Unset are null, since Unset wins.
+func (c ResourceLimitChange) Options() map[string]patch.Option[resource.Quantity] {
+	options := map[string]patch.Option[resource.Quantity]{}
+	for name, q := range c.Set {
+		options[name] = patch.Some(q.DeepCopy())
+	}
+	for _, name := range c.Unset {
+		options[name] = patch.Null[resource.Quantity]()
+	}
+	return options
+}
@@ -0,0 +1,139 @@
+package plans_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/patch"
+	"github.com/opst/knitfab-api-types/plans"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResourceLimitChange_Apply(t *testing.T) {
+	base := plans.Resources{
+		"cpu":    resource.MustParse("1"),
+		"memory": resource.MustParse("1Gi"),
+	}
+	change := plans.ResourceLimitChange{
+		Set: plans.Resources{
+			"memory": resource.MustParse("2Gi"),
+			"gpu":    resource.MustParse("1"),
+		},
+		Unset: []string{"cpu", "gpu"},
+	}
+
+	got := change.Apply(base)
+	want := plans.Resources{"memory": resource.MustParse("2Gi")}
+	if !got.Equal(want) {
+		t.Errorf("Apply: got %v", got)
+	}
+	if len(base) != 2 || !base["memory"].Equal(resource.MustParse("1Gi")) {
+		t.Errorf("Apply modifies the base: %v", base)
+	}
+}
+
+func TestResourceLimitChange_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		change plans.ResourceLimitChange
+		valid  bool
+	}{
+		"empty": {valid: true},
+		"set and unset": {
+			change: plans.ResourceLimitChange{
+				Set:   plans.Resources{"cpu": resource.MustParse("500m")},
+				Unset: []string{"gpu"},
+			},
+			valid: true,
+		},
+		"empty name to set": {
+			change: plans.ResourceLimitChange{Set: plans.Resources{"": resource.MustParse("1")}},
+		},
+		"zero quantity": {
+			change: plans.ResourceLimitChange{Set: plans.Resources{"cpu": resource.MustParse("0")}},
+		},
+		"negative quantity": {
+			change: plans.ResourceLimitChange{Set: plans.Resources{"memory": resource.MustParse("-1Gi")}},
+		},
+		"empty name to unset": {
+			change: plans.ResourceLimitChange{Unset: []string{""}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.change.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if !tc.valid && err == nil {
+				t.Error("invalid change is accepted")
+			}
+		})
+	}
+}
+
+func TestResources_arithmetic(t *testing.T) {
+	a := plans.Resources{
+		plans.ResourceCPU:    resource.MustParse("500m"),
+		plans.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	b := plans.Resources{
+		plans.ResourceCPU: resource.MustParse("2"),
+		plans.ResourceGPU: resource.MustParse("1"),
+	}
+
+	if cpu := a.CPU(); !cpu.Equal(resource.MustParse("0.5")) {
+		t.Errorf("CPU: got %s", cpu.String())
+	}
+	if mem := a.Memory(); !mem.Equal(resource.MustParse("1024Mi")) {
+		t.Errorf("Memory: got %s", mem.String())
+	}
+	if gpu := a.GPU(); !gpu.IsZero() {
+		t.Errorf("GPU: got %s", gpu.String())
+	}
+
+	sum := a.Add(b)
+	want := plans.Resources{
+		plans.ResourceCPU:    resource.MustParse("2500m"),
+		plans.ResourceMemory: resource.MustParse("1Gi"),
+		plans.ResourceGPU:    resource.MustParse("1"),
+	}
+	if !sum.Equal(want) {
+		t.Errorf("Add: got %s", sum.HumanString())
+	}
+	if cpu := a.CPU(); !cpu.Equal(resource.MustParse("500m")) {
+		t.Errorf("Add modifies the receiver: %s", a.HumanString())
+	}
+
+	max := a.Max(b)
+	want = plans.Resources{
+		plans.ResourceCPU:    resource.MustParse("2"),
+		plans.ResourceMemory: resource.MustParse("1Gi"),
+		plans.ResourceGPU:    resource.MustParse("1"),
+	}
+	if !max.Equal(want) {
+		t.Errorf("Max: got %s", max.HumanString())
+	}
+
+	if got := a.HumanString(); got != "cpu=500m, memory=1Gi" {
+		t.Errorf("HumanString: got %q", got)
+	}
+}
+
+func TestResourceLimitChangeOf(t *testing.T) {
+	options := map[string]patch.Option[resource.Quantity]{
+		"cpu":    patch.Some(resource.MustParse("2")),
+		"memory": patch.Null[resource.Quantity](),
+		"gpu":    patch.Absent[resource.Quantity](),
+	}
+	change := plans.ResourceLimitChangeOf(options)
+	want := plans.ResourceLimitChange{
+		Set:   plans.Resources{"cpu": resource.MustParse("2")},
+		Unset: []string{"memory"},
+	}
+	if !change.Set.Equal(want.Set) || !slices.Equal(change.Unset, want.Unset) {
+		t.Errorf("unexpected change: %+v", change)
+	}
+
+	back := change.Options()
+	if len(back) != 2 || !back["cpu"].IsSome() || !back["memory"].IsNull() {
+		t.Errorf("unexpected options: %v", back)
+	}
+}
@@ -0,0 +1,275 @@
+package plans
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schedule is a time-based trigger of the Plan.
+//
+// Runs of the Plan are started at times matching Cron, in the Timezone,
+// with the latest Data for each input.
+type Schedule struct {
+	// Cron is the cron expression, with 5 fields: "minute hour day-of-month month day-of-week".
+	//
+	// Each field is "*", a number, a range "a-b", a list "a,b,c", or one of them with a step "/n".
+	// Months and days of week can be names, like "jan" or "mon". Both 0 and 7 are Sunday.
+	//
+	// Descriptors "@yearly" (or "@annually"), "@monthly", "@weekly", "@daily" (or "@midnight") and "@hourly" are also accepted.
+	//
+	// As with cron(8), if both of day-of-month and day-of-week are restricted,
+	// a time matching either of them matches.
+	Cron string `json:"cron" yaml:"cron"`
+
+	// Timezone is the IANA timezone name where Cron is evaluated, like "Asia/Tokyo".
+	//
+	// If empty, UTC is used.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+func (s Schedule) Equal(o Schedule) bool {
+	return s.Cron == o.Cron && s.Timezone == o.Timezone
+}
+
+// Validate checks the cron expression and the timezone.
+func (s Schedule) Validate() error {
+	if _, err := parseCron(s.Cron); err != nil {
+		return fmt.Errorf("cron: %w", err)
+	}
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	return nil
+}
+
+func (s Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// NextAfter returns the first time matching the Schedule strictly after t.
+//
+// The returned time is in the Timezone of the Schedule.
+// If the Schedule is invalid or never matches (like "0 0 30 2 *"), it returns false.
+func (s Schedule) NextAfter(t time.Time) (time.Time, bool) {
+	c, err := parseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return c.nextAfter(t.In(loc))
+}
+
+type schedule Schedule
+
+func (s *Schedule) UnmarshalJSON(b []byte) error {
+	ret := schedule{}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return err
+	}
+	if err := Schedule(ret).Validate(); err != nil {
+		return err
+	}
+	*s = Schedule(ret)
+	return nil
+}
+
+func (s *Schedule) UnmarshalYAML(node *yaml.Node) error {
+	ret := schedule{}
+	if err := node.Decode(&ret); err != nil {
+		return err
+	}
+	if err := Schedule(ret).Validate(); err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*s = Schedule(ret)
+	return nil
+}
+
+// cron is a parsed cron expression. Each field is a bitset of matching values.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny are true if the field is "*" (without steps).
+	domAny, dowAny bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseCron(expr string) (cron, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		d, ok := cronDescriptors[strings.ToLower(expr)]
+		if !ok {
+			return cron{}, fmt.Errorf("unknown descriptor: %q", expr)
+		}
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cron{}, fmt.Errorf("should have 5 fields, but %d: %q", len(fields), expr)
+	}
+
+	c := cron{}
+	var err error
+	if c.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return cron{}, fmt.Errorf("minute: %w", err)
+	}
+	if c.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return cron{}, fmt.Errorf("hour: %w", err)
+	}
+	if c.dom, c.domAny, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return cron{}, fmt.Errorf("day of month: %w", err)
+	}
+	if c.month, _, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return cron{}, fmt.Errorf("month: %w", err)
+	}
+	if c.dow, c.dowAny, err = parseCronField(fields[4], 0, 7, cronWeekdays); err != nil {
+		return cron{}, fmt.Errorf("day of week: %w", err)
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is also Sunday
+	}
+	return c, nil
+}
+
+// parseCronField parses a field of cron expression as a bitset.
+//
+// It also returns true if the field is "*".
+func parseCronField(field string, min, max int, names map[string]int) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepExpr)
+			if err != nil || s <= 0 {
+				return 0, false, fmt.Errorf("malformed step: %q", part)
+			}
+			step = s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+			if field == "*" {
+				return bits | span(min, max, 1), true, nil
+			}
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = cronValue(a, min, max, names); err != nil {
+				return 0, false, err
+			}
+			if hi, err = cronValue(b, min, max, names); err != nil {
+				return 0, false, err
+			}
+			if hi < lo {
+				return 0, false, fmt.Errorf("reversed range: %q", part)
+			}
+		default:
+			v, err := cronValue(rng, min, max, names)
+			if err != nil {
+				return 0, false, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		bits |= span(lo, hi, step)
+	}
+	return bits, false, nil
+}
+
+func cronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("malformed value: %q", s)
+	}
+	if v < min || max < v {
+		return 0, fmt.Errorf("out of range [%d, %d]: %d", min, max, v)
+	}
+	return v, nil
+}
+
+func span(lo, hi, step int) uint64 {
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << v
+	}
+	return bits
+}
+
+func (c cron) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// nextAfter finds the next matching time, within 5 years from t.
+func (c cron) nextAfter(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<t.Hour()) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// the next hour in the wall clock can be earlier, when the clock is set back by DST.
+				next = t.Add(time.Hour).Truncate(time.Minute)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,108 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchedule_NextAfter(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("tzdata is not available:", err)
+	}
+	// Monday.
+	base := time.Date(2024, 1, 15, 10, 20, 30, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		schedule plans.Schedule
+		want     time.Time
+	}{
+		"every 15 minutes": {
+			schedule: plans.Schedule{Cron: "*/15 * * * *"},
+			want:     time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		},
+		"daily": {
+			schedule: plans.Schedule{Cron: "@daily"},
+			want:     time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+		},
+		"daily in timezone": {
+			schedule: plans.Schedule{Cron: "0 3 * * *", Timezone: "Asia/Tokyo"},
+			want:     time.Date(2024, 1, 16, 3, 0, 0, 0, tokyo),
+		},
+		"weekdays by names": {
+			schedule: plans.Schedule{Cron: "0 9 * * sat,sun"},
+			want:     time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC),
+		},
+		"sunday as 7": {
+			schedule: plans.Schedule{Cron: "0 0 * * 7"},
+			want:     time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+		},
+		"list and range": {
+			schedule: plans.Schedule{Cron: "5,10 8-9 * * *"},
+			want:     time.Date(2024, 1, 16, 8, 5, 0, 0, time.UTC),
+		},
+		"day of month or day of week": {
+			schedule: plans.Schedule{Cron: "0 0 1 * fri"},
+			want:     time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+		},
+		"leap day": {
+			schedule: plans.Schedule{Cron: "0 0 29 feb *"},
+			want:     time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, ok := tc.schedule.NextAfter(base)
+			if !ok {
+				t.Fatal("no next time")
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if got, ok := (plans.Schedule{Cron: "0 0 30 2 *"}).NextAfter(base); ok {
+		t.Errorf("never matching schedule matches: %s", got)
+	}
+	if _, ok := (plans.Schedule{Cron: "* * * * *"}).NextAfter(base.Truncate(time.Minute)); !ok {
+		t.Errorf("no next time")
+	}
+}
+
+func TestSchedule_unmarshal(t *testing.T) {
+	want := plans.Schedule{Cron: "0 3 * * mon-fri", Timezone: "UTC"}
+
+	j := plans.Schedule{}
+	if err := json.Unmarshal([]byte(`{"cron": "0 3 * * mon-fri", "timezone": "UTC"}`), &j); err != nil {
+		t.Fatal(err)
+	}
+	if !j.Equal(want) {
+		t.Errorf("unexpected: %+v", j)
+	}
+
+	y := plans.Schedule{}
+	if err := yaml.Unmarshal([]byte("cron: 0 3 * * mon-fri\ntimezone: UTC\n"), &y); err != nil {
+		t.Fatal(err)
+	}
+	if !y.Equal(want) {
+		t.Errorf("unexpected: %+v", y)
+	}
+
+	for _, doc := range []string{
+		`{"cron": "0 3 * *"}`,
+		`{"cron": "60 * * * *"}`,
+		`{"cron": "* * * * funday"}`,
+		`{"cron": "*/0 * * * *"}`,
+		`{"cron": "10-5 * * * *"}`,
+		`{"cron": "@fortnightly"}`,
+		`{"cron": "0 3 * * *", "timezone": "Mars/Olympus_Mons"}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &plans.Schedule{}); err == nil {
+			t.Errorf("invalid schedule is accepted: %s", doc)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package plans
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SecretMount is a reference to a Kubernetes Secret consumed by the container of the Plan.
+//
+// A Secret is exposed either as files at Path, or as an environment variable Env.
+// Exactly one of Path and Env should be set.
+type SecretMount struct {
+	// Name is the name of the Secret.
+	Name string `json:"name" yaml:"name"`
+
+	// Key is the key in the Secret.
+	//
+	// For Path, if Key is empty, all keys in the Secret are mounted as files in the directory Path.
+	// Otherwise, the value of Key is mounted as the file Path.
+	//
+	// For Env, Key is required.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// Path is the absolute path in the container where the Secret is mounted.
+	//
+	// It should not overlap with input/output mountpoints.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Env is the name of the environment variable to be set with the value of Key.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+func (s SecretMount) Equal(o SecretMount) bool {
+	return s.Name == o.Name && s.Key == o.Key && s.Path == o.Path && s.Env == o.Env
+}
+
+// Validate checks the SecretMount by itself.
+//
+// Collisions with other mountpoints or env are checked by PlanSpec.Validate.
+func (s SecretMount) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	if errs := validation.IsDNS1123Subdomain(s.Name); len(errs) != 0 {
+		return fmt.Errorf("secret name %q is invalid: %s", s.Name, strings.Join(errs, "; "))
+	}
+
+	switch {
+	case s.Path != "" && s.Env != "":
+		return fmt.Errorf(`"path" and "env" are mutually exclusive`)
+	case s.Path != "":
+		if err := validatePath(s.Path); err != nil {
+			return err
+		}
+	case s.Env != "":
+		if s.Key == "" {
+			return fmt.Errorf(`required field missing: "key" (for "env")`)
+		}
+		if err := (EnvVar{Name: s.Env}).Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf(`either "path" or "env" is required`)
+	}
+	return nil
+}
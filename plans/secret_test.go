@@ -0,0 +1,54 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+func TestPlanSpec_secrets(t *testing.T) {
+	spec, err := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/data", "type:dataset").
+		AddOutput("/out/model", "type:model").
+		WithEnv("DEBUG", "1").
+		AddSecretFile("registry-auth", "", "/secrets/registry").
+		AddSecretEnv("s3-credentials", "access-key", "AWS_ACCESS_KEY_ID").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	reordered := spec.Clone()
+	reordered.Secrets[0], reordered.Secrets[1] = reordered.Secrets[1], reordered.Secrets[0]
+	if !spec.Equal(reordered) || spec.Hash() != reordered.Hash() {
+		t.Errorf("order of secrets should not be meaningful")
+	}
+
+	for name, mod := range map[string]func(*plans.PlanSpec){
+		"no name":          func(ps *plans.PlanSpec) { ps.Secrets[0].Name = "" },
+		"bad name":         func(ps *plans.PlanSpec) { ps.Secrets[0].Name = "Registry Auth" },
+		"no target":        func(ps *plans.PlanSpec) { ps.Secrets[0].Path = "" },
+		"both targets":     func(ps *plans.PlanSpec) { ps.Secrets[0].Env = "AUTH" },
+		"relative path":    func(ps *plans.PlanSpec) { ps.Secrets[0].Path = "secrets/registry" },
+		"env without key":  func(ps *plans.PlanSpec) { ps.Secrets[1].Key = "" },
+		"bad env":          func(ps *plans.PlanSpec) { ps.Secrets[1].Env = "1KEY" },
+		"same as input":    func(ps *plans.PlanSpec) { ps.Secrets[0].Path = "/in/data" },
+		"inside of output": func(ps *plans.PlanSpec) { ps.Secrets[0].Path = "/out/model/auth" },
+		"containing input": func(ps *plans.PlanSpec) { ps.Secrets[0].Path = "/in" },
+		"env collision":    func(ps *plans.PlanSpec) { ps.Secrets[1].Env = "DEBUG" },
+		"secrets collision": func(ps *plans.PlanSpec) {
+			ps.Secrets = append(ps.Secrets, plans.SecretMount{Name: "other", Key: "key", Env: "AWS_ACCESS_KEY_ID"})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ps := spec.Clone()
+			mod(&ps)
+			if err := ps.Validate(); err == nil {
+				t.Errorf("invalid spec is accepted: %+v", ps.Secrets)
+			}
+		})
+	}
+}
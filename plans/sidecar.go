@@ -0,0 +1,108 @@
+package plans
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Container is an auxiliary container running next to the main container of the Plan,
+// like TensorBoard or a metrics exporter.
+//
+// Sidecars do not have mountpoints; they share nothing with the main container
+// other than the network of the worker.
+type Container struct {
+	// Name is the name of the container.
+	//
+	// It should be a DNS label (RFC 1123), unique in the Plan.
+	Name string `json:"name" yaml:"name"`
+
+	// Image is the container image.
+	Image Image `json:"image" yaml:"image"`
+
+	// Entrypoint is the entrypoint of the container.
+	Entrypoint []string `json:"entrypoint,omitempty" yaml:"entrypoint,omitempty"`
+
+	// Args are the arguments of the container.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+
+	// Env are the environment variables of the container.
+	Env []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Resources is the computational resource limits and requirements of the container.
+	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+func (c Container) Equal(o Container) bool {
+	return c.Name == o.Name &&
+		c.Image.Equal(&o.Image) &&
+		apicmp.SliceEqEq(c.Entrypoint, o.Entrypoint) &&
+		apicmp.SliceEqEq(c.Args, o.Args) &&
+		apicmp.SliceEqual(c.Env, o.Env) &&
+		apicmp.MapEqual(c.Resources, o.Resources)
+}
+
+// Clone returns a deep copy of the Container.
+func (c Container) Clone() Container {
+	return Container{
+		Name:       c.Name,
+		Image:      c.Image,
+		Entrypoint: slices.Clone(c.Entrypoint),
+		Args:       slices.Clone(c.Args),
+		Env:        slices.Clone(c.Env),
+		Resources:  c.Resources.Clone(),
+	}
+}
+
+// Validate checks the name is a DNS label, the image is specified,
+// and env and resources are valid.
+func (c Container) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	if errs := validation.IsDNS1123Label(c.Name); len(errs) != 0 {
+		return fmt.Errorf("container name %q is invalid: %s", c.Name, strings.Join(errs, "; "))
+	}
+	if c.Image.Repository == "" {
+		return fmt.Errorf(`required field missing: "image"`)
+	}
+	for i, e := range c.Env {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("env[%d]: %w", i, err)
+		}
+	}
+	if err := c.Resources.Validate(); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	return nil
+}
+
+func normalizeSidecars(cs []Container) []Container {
+	if cs == nil {
+		return nil
+	}
+	ret := make([]Container, 0, len(cs))
+	for _, c := range cs {
+		n := Container{
+			Name:       strings.TrimSpace(c.Name),
+			Image:      c.Image,
+			Entrypoint: slices.Clone(c.Entrypoint),
+			Args:       slices.Clone(c.Args),
+			Env:        normalizeEnv(c.Env),
+		}
+		if c.Resources != nil {
+			n.Resources = Resources{}
+			for k, v := range c.Resources {
+				n.Resources[strings.TrimSpace(k)] = v.DeepCopy()
+			}
+		}
+		ret = append(ret, n)
+	}
+	slices.SortStableFunc(ret, func(a, b Container) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ret
+}
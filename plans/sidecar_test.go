@@ -0,0 +1,66 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPlanSpec_sidecars(t *testing.T) {
+	tensorboard := plans.Container{
+		Name:      "tensorboard",
+		Image:     plans.Image{Repository: "example.com/tensorboard", Tag: "v2"},
+		Args:      []string{"--logdir", "/out/model"},
+		Env:       []plans.EnvVar{{Name: "PORT", Value: "6006"}},
+		Resources: plans.Resources{plans.ResourceCPU: resource.MustParse("100m")},
+	}
+	exporter := plans.Container{
+		Name:  "exporter",
+		Image: plans.Image{Repository: "example.com/exporter", Tag: "v1"},
+	}
+
+	spec, err := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		AddInput("/in/data", "type:dataset").
+		AddSidecar(tensorboard).
+		AddSidecar(exporter).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	reordered := spec.Clone()
+	reordered.Sidecars[0], reordered.Sidecars[1] = reordered.Sidecars[1], reordered.Sidecars[0]
+	if !spec.Equal(reordered) || spec.Hash() != reordered.Hash() {
+		t.Errorf("order of sidecars should not be meaningful")
+	}
+
+	cloned := spec.Clone()
+	cloned.Sidecars[0].Env[0].Value = "8080"
+	if spec.Sidecars[0].Env[0].Value != "6006" {
+		t.Errorf("clone shares env of sidecars")
+	}
+	if spec.Equal(cloned) {
+		t.Errorf("different sidecars are equal")
+	}
+
+	for name, mod := range map[string]func(*plans.PlanSpec){
+		"no name":        func(ps *plans.PlanSpec) { ps.Sidecars[0].Name = "" },
+		"bad name":       func(ps *plans.PlanSpec) { ps.Sidecars[0].Name = "Tensor_Board" },
+		"no image":       func(ps *plans.PlanSpec) { ps.Sidecars[0].Image = plans.Image{} },
+		"duplicated":     func(ps *plans.PlanSpec) { ps.Sidecars[1].Name = ps.Sidecars[0].Name },
+		"bad env":        func(ps *plans.PlanSpec) { ps.Sidecars[0].Env[0].Name = "1PORT" },
+		"zero resources": func(ps *plans.PlanSpec) { ps.Sidecars[0].Resources[plans.ResourceCPU] = resource.MustParse("0") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			ps := spec.Clone()
+			mod(&ps)
+			if err := ps.Validate(); err == nil {
+				t.Errorf("invalid spec is accepted: %+v", ps.Sidecars)
+			}
+		})
+	}
+}
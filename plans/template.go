@@ -0,0 +1,215 @@
+package plans
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// Template is a PlanSpec with parameters.
+//
+// Strings in Spec (tags, args, image, paths, ...) can have placeholders "${NAME}",
+// which are replaced with values of parameters by Render. "$$" is an escaped "$".
+//
+// Example (in YAML):
+//
+//	parameters:
+//	  - name: dataset
+//	  - name: version
+//	    default: "v1"
+//	spec:
+//	  image: "example.com/train:${version}"
+//	  inputs:
+//	    - path: /in/dataset
+//	      tags: ["type:dataset", "name:${dataset}"]
+type Template struct {
+	// Parameters are the declared parameters of the Template.
+	Parameters []TemplateParameter `json:"parameters" yaml:"parameters"`
+
+	// Spec is the PlanSpec with placeholders, as a JSON/YAML document.
+	//
+	// It is kept as decoded from JSON/YAML (maps, slices, strings, numbers and booleans),
+	// since it may not be a valid PlanSpec until rendered.
+	Spec map[string]any `json:"spec" yaml:"spec"`
+}
+
+// TemplateParameter is a parameter of Template.
+type TemplateParameter struct {
+	// Name is the name of the parameter, referred as "${NAME}".
+	//
+	// It should consist of alphanumerics and '_', not starting with a digit.
+	Name string `json:"name" yaml:"name"`
+
+	// Description is a human readable description of the parameter.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Default is the value used when the parameter is not given.
+	//
+	// If nil, the parameter is required.
+	Default *string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+func (p TemplateParameter) Equal(o TemplateParameter) bool {
+	return p.Name == o.Name &&
+		p.Description == o.Description &&
+		apicmp.PtrEqEq(p.Default, o.Default)
+}
+
+func (t Template) Equal(o Template) bool {
+	if !apicmp.SliceEqual(t.Parameters, o.Parameters) {
+		return false
+	}
+	a, errA := json.Marshal(t.Spec)
+	b, errB := json.Marshal(o.Spec)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+var (
+	parameterName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	placeholder   = regexp.MustCompile(`\$\$|\$\{[^}]*\}|\$\{`)
+)
+
+// Variables returns names of parameters referred in Spec, sorted.
+//
+// It returns error if Spec has malformed placeholders.
+func (t Template) Variables() ([]string, error) {
+	found := map[string]struct{}{}
+	_, err := substitute(t.Spec, func(name string) (string, error) {
+		found[name] = struct{}{}
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(found)), nil
+}
+
+// Validate checks that parameters are well-formed and unique,
+// and all placeholders in Spec refer declared parameters.
+func (t Template) Validate() error {
+	declared := map[string]bool{}
+	for i, p := range t.Parameters {
+		if !parameterName.MatchString(p.Name) {
+			return fmt.Errorf("parameters[%d]: malformed name: %q", i, p.Name)
+		}
+		if declared[p.Name] {
+			return fmt.Errorf(`parameters[%d]: duplicated name: "%s"`, i, p.Name)
+		}
+		declared[p.Name] = true
+	}
+
+	vars, err := t.Variables()
+	if err != nil {
+		return fmt.Errorf("spec: %w", err)
+	}
+	for _, v := range vars {
+		if !declared[v] {
+			return fmt.Errorf(`spec: unbound variable: "${%s}"`, v)
+		}
+	}
+	return nil
+}
+
+// Render replaces placeholders in Spec with params (or defaults), and returns the PlanSpec.
+//
+// It returns error if the Template is invalid, params have undeclared names,
+// required parameters are not given, or the rendered PlanSpec is invalid.
+func (t Template) Render(params map[string]string) (PlanSpec, error) {
+	if err := t.Validate(); err != nil {
+		return PlanSpec{}, err
+	}
+
+	values := map[string]string{}
+	for _, p := range t.Parameters {
+		if p.Default != nil {
+			values[p.Name] = *p.Default
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		if !slices.ContainsFunc(t.Parameters, func(p TemplateParameter) bool { return p.Name == name }) {
+			return PlanSpec{}, fmt.Errorf(`unknown parameter: "%s"`, name)
+		}
+		values[name] = params[name]
+	}
+
+	rendered, err := substitute(t.Spec, func(name string) (string, error) {
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf(`required parameter is not given: "%s"`, name)
+		}
+		return v, nil
+	})
+	if err != nil {
+		return PlanSpec{}, err
+	}
+
+	b, err := json.Marshal(rendered)
+	if err != nil {
+		return PlanSpec{}, err
+	}
+	spec := PlanSpec{}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return PlanSpec{}, fmt.Errorf("rendered spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return PlanSpec{}, fmt.Errorf("rendered spec: %w", err)
+	}
+	return spec, nil
+}
+
+// substitute replaces placeholders in strings in v with values from lookup, and returns the copy of v.
+//
+// Map keys are not substituted.
+func substitute(v any, lookup func(name string) (string, error)) (any, error) {
+	switch v := v.(type) {
+	case string:
+		var err error
+		s := placeholder.ReplaceAllStringFunc(v, func(m string) string {
+			if m == "$$" || err != nil {
+				return "$"
+			}
+			if m == "${" {
+				err = fmt.Errorf("unterminated placeholder: %q", v)
+				return ""
+			}
+			name := m[2 : len(m)-1]
+			if !parameterName.MatchString(name) {
+				err = fmt.Errorf("malformed placeholder: %q", m)
+				return ""
+			}
+			var value string
+			value, err = lookup(name)
+			return value
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, e := range v {
+			s, err := substitute(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			ret[k] = s
+		}
+		return ret, nil
+	case []any:
+		ret := make([]any, 0, len(v))
+		for _, e := range v {
+			s, err := substitute(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, s)
+		}
+		return ret, nil
+	}
+	return v, nil
+}
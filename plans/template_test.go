@@ -0,0 +1,104 @@
+package plans_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+const trainTemplate = `
+parameters:
+  - name: dataset
+    description: name of the dataset
+  - name: version
+    default: v1
+spec:
+  image: "example.com/train:${version}"
+  args: ["--name", "${dataset}", "--price", "$$10"]
+  inputs:
+    - path: /in/dataset
+      tags: ["type:dataset", "name:${dataset}"]
+  outputs:
+    - path: /out/model
+      tags: ["type:model", "name:${dataset}"]
+  active: true
+`
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl := plans.Template{}
+	if err := yaml.Unmarshal([]byte(trainTemplate), &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, tmpl)
+
+	if vars, err := tmpl.Variables(); err != nil || len(vars) != 2 || vars[0] != "dataset" || vars[1] != "version" {
+		t.Errorf("unexpected variables: %v (%v)", vars, err)
+	}
+
+	got, err := tmpl.Render(map[string]string{"dataset": "mnist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	active := true
+	want := plans.PlanSpec{
+		Image: plans.Image{Repository: "example.com/train", Tag: "v1"},
+		Args:  []string{"--name", "mnist", "--price", "$10"},
+		Inputs: []plans.Mountpoint{
+			{Path: "/in/dataset", Tags: []tags.Tag{{Key: "type", Value: "dataset"}, {Key: "name", Value: "mnist"}}},
+		},
+		Outputs: []plans.Mountpoint{
+			{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}, {Key: "name", Value: "mnist"}}},
+		},
+		Active: &active,
+	}
+	if diffs := got.Diff(want); diffs != nil {
+		t.Errorf("unexpected spec: %v", diffs)
+	}
+
+	got, err = tmpl.Render(map[string]string{"dataset": "cifar", "version": "v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Image.Tag != "v2" || got.Inputs[0].Tags[1].Value != "cifar" {
+		t.Errorf("unexpected spec: %+v", got)
+	}
+
+	for name, params := range map[string]map[string]string{
+		"missing required":  {},
+		"unknown parameter": {"dataset": "mnist", "epochs": "10"},
+		"invalid result":    {"dataset": "mnist", "version": "NOT A TAG"},
+	} {
+		if _, err := tmpl.Render(params); err == nil {
+			t.Errorf("%s: expected error does not occur", name)
+		}
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	for name, tmpl := range map[string]plans.Template{
+		"unbound variable": {
+			Spec: map[string]any{"args": []any{"${epochs}"}},
+		},
+		"malformed placeholder": {
+			Parameters: []plans.TemplateParameter{{Name: "epochs"}},
+			Spec:       map[string]any{"args": []any{"${epochs-1}"}},
+		},
+		"unterminated placeholder": {
+			Parameters: []plans.TemplateParameter{{Name: "epochs"}},
+			Spec:       map[string]any{"args": []any{"${epochs"}},
+		},
+		"malformed name": {
+			Parameters: []plans.TemplateParameter{{Name: "1st"}},
+		},
+		"duplicated name": {
+			Parameters: []plans.TemplateParameter{{Name: "epochs"}, {Name: "epochs"}},
+		},
+	} {
+		if err := tmpl.Validate(); err == nil {
+			t.Errorf("%s: invalid template is accepted", name)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package plans
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/patch"
+	"gopkg.in/yaml.v3"
+)
+
+// Update is a revision of a Plan.
+//
+// This is the format of the request body of:
+//
+// - PUT /api/plans/{planId}
+//
+// Each field is a patch.Option: absent keys leave the field as is, null resets it to the default,
+// and other values set it. Unknown keys are rejected on unmarshalling.
+//
+// Secrets and sidecars are replaced as a whole.
+// Activeness, annotations and mountpoints are not changed by Update.
+type Update struct {
+	Image          patch.Option[Image]
+	Entrypoint     patch.Option[[]string]
+	Args           patch.Option[[]string]
+	Env            patch.Option[[]EnvVar]
+	WorkingDir     patch.Option[string]
+	RunAsUser      patch.Option[int64]
+	RunAsGroup     patch.Option[int64]
+	Secrets        patch.Option[[]SecretMount]
+	Sidecars       patch.Option[[]Container]
+	OnNode         patch.Option[OnNode]
+	Resources      patch.Option[Resources]
+	ServiceAccount patch.Option[string]
+	Timeout        patch.Option[duration.Duration]
+	Deadline       patch.Option[duration.Duration]
+	Priority       patch.Option[Priority]
+	Schedule       patch.Option[Schedule]
+}
+
+// entries returns fields of Update with their keys, in the order of the wire format.
+func (u *Update) entries() []patch.Entry {
+	return []patch.Entry{
+		{Key: "image", Field: &u.Image},
+		{Key: "entrypoint", Field: &u.Entrypoint},
+		{Key: "args", Field: &u.Args},
+		{Key: "env", Field: &u.Env},
+		{Key: "working_dir", Field: &u.WorkingDir},
+		{Key: "run_as_user", Field: &u.RunAsUser},
+		{Key: "run_as_group", Field: &u.RunAsGroup},
+		{Key: "secrets", Field: &u.Secrets},
+		{Key: "sidecars", Field: &u.Sidecars},
+		{Key: "on_node", Field: &u.OnNode},
+		{Key: "resources", Field: &u.Resources},
+		{Key: "service_account", Field: &u.ServiceAccount},
+		{Key: "timeout", Field: &u.Timeout},
+		{Key: "deadline", Field: &u.Deadline},
+		{Key: "priority", Field: &u.Priority},
+		{Key: "schedule", Field: &u.Schedule},
+	}
+}
+
+func (u Update) Equal(o Update) bool {
+	return u.Image.EqualWith(o.Image, func(a, b Image) bool { return a.Equal(&b) }) &&
+		u.Entrypoint.EqualWith(o.Entrypoint, slices.Equal) &&
+		u.Args.EqualWith(o.Args, slices.Equal) &&
+		u.Env.EqualWith(o.Env, slices.Equal) &&
+		u.WorkingDir.EqualWith(o.WorkingDir, eqeq) &&
+		u.RunAsUser.EqualWith(o.RunAsUser, eqeq) &&
+		u.RunAsGroup.EqualWith(o.RunAsGroup, eqeq) &&
+		u.Secrets.EqualWith(o.Secrets, apicmp.SliceEqualUnordered) &&
+		u.Sidecars.EqualWith(o.Sidecars, apicmp.SliceEqualUnordered) &&
+		u.OnNode.EqualWith(o.OnNode, OnNode.Equal) &&
+		u.Resources.EqualWith(o.Resources, Resources.Equal) &&
+		u.ServiceAccount.EqualWith(o.ServiceAccount, eqeq) &&
+		u.Timeout.EqualWith(o.Timeout, duration.Duration.Equal) &&
+		u.Deadline.EqualWith(o.Deadline, duration.Duration.Equal) &&
+		u.Priority.EqualWith(o.Priority, eqeq) &&
+		u.Schedule.EqualWith(o.Schedule, Schedule.Equal)
+}
+
+func eqeq[T comparable](a, b T) bool {
+	return a == b
+}
+
+// Validate checks the Update.
+//
+// Image cannot be unset, and values to be set should be valid as ones of PlanSpec.
+// Collisions of secrets with env or mountpoints of the PlanSpec are not checked here;
+// validate the PlanSpec after Apply for them.
+func (u Update) Validate() error {
+	if u.Image.IsNull() {
+		return fmt.Errorf(`image: should not be unset`)
+	}
+	if img, ok := u.Image.Get(); ok && img.Repository == "" {
+		return fmt.Errorf(`image: required field missing: "repository"`)
+	}
+	if env, ok := u.Env.Get(); ok {
+		for i, e := range env {
+			if err := e.Validate(); err != nil {
+				return fmt.Errorf("env[%d]: %w", i, err)
+			}
+		}
+	}
+	if dir, ok := u.WorkingDir.Get(); ok {
+		if err := validatePath(dir); err != nil {
+			return fmt.Errorf("working_dir: %w", err)
+		}
+	}
+	for _, id := range []struct {
+		field string
+		f     patch.Option[int64]
+	}{{"run_as_user", u.RunAsUser}, {"run_as_group", u.RunAsGroup}} {
+		if v, ok := id.f.Get(); ok && v < 0 {
+			return fmt.Errorf("%s: should not be negative: %d", id.field, v)
+		}
+	}
+	if secrets, ok := u.Secrets.Get(); ok {
+		envs := map[string]string{}
+		for i, sec := range secrets {
+			field := fmt.Sprintf("secrets[%d]", i)
+			if err := sec.Validate(); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			if sec.Env == "" {
+				continue
+			}
+			if other, ok := envs[sec.Env]; ok {
+				return fmt.Errorf(`%s and %s: env should not be duplicated: "%s"`, other, field, sec.Env)
+			}
+			envs[sec.Env] = field
+		}
+	}
+	if sidecars, ok := u.Sidecars.Get(); ok {
+		names := map[string]bool{}
+		for i, c := range sidecars {
+			if err := c.Validate(); err != nil {
+				return fmt.Errorf("sidecars[%d]: %w", i, err)
+			}
+			if names[c.Name] {
+				return fmt.Errorf(`sidecars[%d]: duplicated name: "%s"`, i, c.Name)
+			}
+			names[c.Name] = true
+		}
+	}
+	if res, ok := u.Resources.Get(); ok {
+		if err := res.Validate(); err != nil {
+			return fmt.Errorf("resources: %w", err)
+		}
+	}
+	if sa, ok := u.ServiceAccount.Get(); ok {
+		if err := ValidateServiceAccount(sa); err != nil {
+			return fmt.Errorf("service_account: %w", err)
+		}
+	}
+	for _, d := range []struct {
+		field string
+		f     patch.Option[duration.Duration]
+	}{{"timeout", u.Timeout}, {"deadline", u.Deadline}} {
+		if v, ok := d.f.Get(); ok && v <= 0 {
+			return fmt.Errorf("%s: should be positive: %s", d.field, v)
+		}
+	}
+	if p, ok := u.Priority.Get(); ok && !p.Valid() {
+		return fmt.Errorf("priority: unknown priority: %q", p)
+	}
+	if s, ok := u.Schedule.Get(); ok {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// Apply returns the PlanSpec updated by u. ps is not modified.
+func (u Update) Apply(ps PlanSpec) PlanSpec {
+	ret := ps.Clone()
+	ret.Image = apply(u.Image, ret.Image, same)
+	ret.Entrypoint = apply(u.Entrypoint, ret.Entrypoint, slices.Clone)
+	ret.Args = apply(u.Args, ret.Args, slices.Clone)
+	ret.Env = apply(u.Env, ret.Env, slices.Clone)
+	ret.WorkingDir = apply(u.WorkingDir, ret.WorkingDir, same)
+	ret.RunAsUser = applyPtr(u.RunAsUser, ret.RunAsUser, same)
+	ret.RunAsGroup = applyPtr(u.RunAsGroup, ret.RunAsGroup, same)
+	ret.Secrets = apply(u.Secrets, ret.Secrets, slices.Clone)
+	ret.Sidecars = apply(u.Sidecars, ret.Sidecars, func(cs []Container) []Container {
+		return clone.SliceWith(cs, Container.Clone)
+	})
+	ret.Resources = apply(u.Resources, ret.Resources, Resources.Clone)
+	ret.ServiceAccount = apply(u.ServiceAccount, ret.ServiceAccount, same)
+	ret.Timeout = applyPtr(u.Timeout, ret.Timeout, same)
+	ret.Deadline = applyPtr(u.Deadline, ret.Deadline, same)
+	ret.Priority = apply(u.Priority, ret.Priority, same)
+	ret.Schedule = applyPtr(u.Schedule, ret.Schedule, same)
+	ret.OnNode = applyPtr(u.OnNode, ret.OnNode, OnNode.Clone)
+	return ret
+}
+
+func same[T any](v T) T {
+	return v
+}
+
+// apply returns the value after the change. Values in current are shared.
+func apply[T any](o patch.Option[T], current T, cloneValue func(T) T) T {
+	if v, ok := o.Get(); ok {
+		return cloneValue(v)
+	}
+	if o.IsNull() {
+		var zero T
+		return zero
+	}
+	return current
+}
+
+// applyPtr is apply for optional fields of PlanSpec.
+func applyPtr[T any](o patch.Option[T], current *T, cloneValue func(T) T) *T {
+	if v, ok := o.Get(); ok {
+		v = cloneValue(v)
+		return &v
+	}
+	if o.IsNull() {
+		return nil
+	}
+	return current
+}
+
+func (u Update) MarshalJSON() ([]byte, error) {
+	return patch.MarshalJSON(u.entries())
+}
+
+func (u Update) MarshalYAML() (interface{}, error) {
+	return patch.MarshalYAML(u.entries())
+}
+
+func (u *Update) UnmarshalJSON(b []byte) error {
+	ret := Update{}
+	if err := patch.UnmarshalJSON(b, ret.entries()); err != nil {
+		return err
+	}
+	*u = ret
+	return nil
+}
+
+func (u *Update) UnmarshalYAML(node *yaml.Node) error {
+	ret := Update{}
+	if err := patch.UnmarshalYAML(node, ret.entries()); err != nil {
+		return err
+	}
+	*u = ret
+	return nil
+}
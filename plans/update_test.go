@@ -0,0 +1,171 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/duration"
+	"github.com/opst/knitfab-api-types/patch"
+	"github.com/opst/knitfab-api-types/plans"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestUpdate_unmarshal(t *testing.T) {
+	want := plans.Update{
+		Image:      patch.Some(plans.Image{Repository: "example.com/train", Tag: "v2"}),
+		Args:       patch.Some([]string{"--epochs", "20"}),
+		Entrypoint: patch.Null[[]string](),
+		Timeout:    patch.Some(duration.Duration(time.Hour)),
+		Schedule:   patch.Null[plans.Schedule](),
+		Secrets:    patch.Some([]plans.SecretMount{{Name: "registry", Path: "/secrets/registry"}}),
+		Sidecars:   patch.Null[[]plans.Container](),
+	}
+
+	fromJSON := plans.Update{}
+	if err := json.Unmarshal([]byte(`{
+		"image": "example.com/train:v2",
+		"args": ["--epochs", "20"],
+		"entrypoint": null,
+		"timeout": "1h",
+		"schedule": null,
+		"secrets": [{"name": "registry", "path": "/secrets/registry"}],
+		"sidecars": null
+	}`), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !fromJSON.Equal(want) {
+		t.Errorf("unexpected update: %+v", fromJSON)
+	}
+
+	fromYAML := plans.Update{}
+	if err := yaml.Unmarshal([]byte(`
+image: example.com/train:v2
+args: ["--epochs", "20"]
+entrypoint: null
+timeout: 1h
+schedule: ~
+secrets:
+  - name: registry
+    path: /secrets/registry
+sidecars: null
+`), &fromYAML); err != nil {
+		t.Fatal(err)
+	}
+	if !fromYAML.Equal(want) {
+		t.Errorf("unexpected update: %+v", fromYAML)
+	}
+
+	knittest.AssertRoundTrip(t, want)
+
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"image":"example.com/train:v2","entrypoint":null,"args":["--epochs","20"],"secrets":[{"name":"registry","path":"/secrets/registry"}],"sidecars":null,"timeout":"1h0m0s","schedule":null}` {
+		t.Errorf("unexpected json: %s", b)
+	}
+
+	for _, doc := range []string{
+		`{"imgae": "example.com/train:v2"}`,
+		`{"active": false}`,
+		`{"timeout": 3600}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &plans.Update{}); err == nil {
+			t.Errorf("invalid update is accepted: %s", doc)
+		}
+	}
+	if err := yaml.Unmarshal([]byte("imgae: example.com/train:v2\n"), &plans.Update{}); err == nil {
+		t.Error("unknown field is accepted")
+	}
+}
+
+func TestUpdate_Apply(t *testing.T) {
+	uid := int64(1000)
+	timeout := duration.Duration(time.Hour)
+	base := plans.NewSpec().
+		WithImage("example.com/train:v1").
+		WithEntrypoint("python", "train.py").
+		WithArgs("--epochs", "10").
+		AddInput("/in/data", "type:dataset").
+		WithResource("cpu", "1").
+		WithServiceAccount("trainer").
+		MustBuild()
+	base.RunAsUser = &uid
+	base.Timeout = &timeout
+
+	update := plans.Update{
+		Image:      patch.Some(plans.Image{Repository: "example.com/train", Tag: "v2"}),
+		Args:       patch.Some([]string{"--epochs", "20"}),
+		RunAsUser:  patch.Null[int64](),
+		Resources:  patch.Some(plans.Resources{"cpu": resource.MustParse("2")}),
+		OnNode:     patch.Some(plans.OnNode{Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}}}),
+		Priority:   patch.Some(plans.PriorityHigh),
+		WorkingDir: patch.Some("/work"),
+		Secrets:    patch.Some([]plans.SecretMount{{Name: "registry", Key: "token", Env: "REGISTRY_TOKEN"}}),
+		Sidecars: patch.Some([]plans.Container{
+			{Name: "tensorboard", Image: plans.Image{Repository: "example.com/tensorboard", Tag: "v1"}},
+		}),
+	}
+	if err := update.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := update.Apply(base)
+	want := base.Clone()
+	want.Image = plans.Image{Repository: "example.com/train", Tag: "v2"}
+	want.Args = []string{"--epochs", "20"}
+	want.RunAsUser = nil
+	want.Resources = plans.Resources{"cpu": resource.MustParse("2")}
+	want.OnNode = &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "accelerator", Value: "gpu"}}}
+	want.Priority = plans.PriorityHigh
+	want.WorkingDir = "/work"
+	want.Secrets = []plans.SecretMount{{Name: "registry", Key: "token", Env: "REGISTRY_TOKEN"}}
+	want.Sidecars = []plans.Container{
+		{Name: "tensorboard", Image: plans.Image{Repository: "example.com/tensorboard", Tag: "v1"}},
+	}
+	if diffs := got.Diff(want); diffs != nil {
+		t.Errorf("unexpected spec: %v", diffs)
+	}
+	if *base.RunAsUser != 1000 || base.Args[1] != "10" {
+		t.Errorf("original is modified: %+v", base)
+	}
+
+	if got := (plans.Update{}).Apply(base); !got.Equal(base) {
+		t.Errorf("empty update changes the spec: %+v", got)
+	}
+
+	for name, u := range map[string]plans.Update{
+		"unset image":      {Image: patch.Null[plans.Image]()},
+		"relative dir":     {WorkingDir: patch.Some("work")},
+		"negative gid":     {RunAsGroup: patch.Some(int64(-1))},
+		"zero deadline":    {Deadline: patch.Some(duration.Duration(0))},
+		"bad account":      {ServiceAccount: patch.Some("Not Valid")},
+		"unknown priority": {Priority: patch.Some(plans.Priority("asap"))},
+		"bad secret":       {Secrets: patch.Some([]plans.SecretMount{{Name: "registry"}})},
+		"duplicated secret env": {Secrets: patch.Some([]plans.SecretMount{
+			{Name: "a", Key: "token", Env: "TOKEN"},
+			{Name: "b", Key: "token", Env: "TOKEN"},
+		})},
+		"bad sidecar": {Sidecars: patch.Some([]plans.Container{{Name: "tensorboard"}})},
+		"duplicated sidecar name": {Sidecars: patch.Some([]plans.Container{
+			{Name: "tensorboard", Image: plans.Image{Repository: "example.com/tensorboard"}},
+			{Name: "tensorboard", Image: plans.Image{Repository: "example.com/exporter"}},
+		})},
+	} {
+		if err := u.Validate(); err == nil {
+			t.Errorf("%s: invalid update is accepted", name)
+		}
+	}
+
+	withSidecar := update.Apply(base)
+	if got := (plans.Update{Secrets: patch.Null[[]plans.SecretMount](), Sidecars: patch.Null[[]plans.Container]()}).Apply(withSidecar); len(got.Secrets) != 0 || len(got.Sidecars) != 0 {
+		t.Errorf("null does not clear secrets and sidecars: %+v", got)
+	}
+	withSidecar.Sidecars[0].Args = append(withSidecar.Sidecars[0].Args, "--logdir=/logs")
+	if s, _ := update.Sidecars.Get(); len(s[0].Args) != 0 {
+		t.Errorf("Apply shares sidecars with the update: %+v", s)
+	}
+}
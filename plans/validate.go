@@ -0,0 +1,320 @@
+package plans
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/tags"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Validate checks the PlanSpec before registering it, and returns the first problem found.
+//
+// It checks that:
+//
+// - the image is specified,
+//
+// - there is at least one input,
+//
+// - all mountpoint paths (including ones of secrets) are absolute, and no one of them contains another,
+//
+// - inputs have no system tags other than "knit#id" and "knit#timestamp",
+//
+// - sub_paths of inputs are relative and do not go up (".."), and inputs have no cache policy,
+//
+// - outputs and log have no system tags, and outputs are neither read-only nor with sub_path,
+//
+// - cache policies of outputs are known,
+//
+// - on_node labels follow the Kubernetes label syntax (unless ValidateOptions.LaxLabels is true),
+//
+// - env names are valid,
+//
+// - secrets are exposed as either files or env, and their env do not collide with others,
+//
+// - the working directory is absolute, and UID/GID are not negative, if specified,
+//
+// - sidecars are valid, and their names are unique,
+//
+// - the service account name is a DNS subdomain (RFC 1123),
+//
+// - resources are positive,
+//
+// - timeout and deadline are positive, if specified,
+//
+// - the priority is known, if specified,
+//
+// - the schedule has a valid cron expression and timezone, if specified, and
+//
+// - the project name is well-formed.
+//
+// Passing this does not guarantee that Knitfab accepts the PlanSpec;
+// for example, it does not check that the image exists.
+func (ps PlanSpec) Validate() error {
+	return ps.ValidateWith(ValidateOptions{})
+}
+
+// ValidateOptions relaxes checks of PlanSpec.ValidateWith.
+//
+// The zero value means no relaxation, same as PlanSpec.Validate.
+type ValidateOptions struct {
+	// LaxLabels skips checking on_node labels against the Kubernetes label syntax.
+	//
+	// Set this true when the server accepts labels which this package does not know.
+	LaxLabels bool
+}
+
+// ValidateWith is PlanSpec.Validate, with checks relaxed by opts.
+func (ps PlanSpec) ValidateWith(opts ValidateOptions) error {
+	if ps.Image.Repository == "" {
+		return fmt.Errorf(`required field missing: "image"`)
+	}
+	if len(ps.Inputs) == 0 {
+		return fmt.Errorf(`"inputs" should have at least one mountpoint`)
+	}
+
+	type pathOf struct {
+		field string
+		path  string
+	}
+	paths := []pathOf{}
+
+	for i, in := range ps.Inputs {
+		field := fmt.Sprintf("inputs[%d]", i)
+		if err := validatePath(in.Path); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		for _, t := range in.Tags {
+			switch {
+			case !strings.HasPrefix(t.Key, tags.SystemTagPrefix):
+			case t.Key == tags.KeyKnitId, t.Key == tags.KeyKnitTimestamp:
+			default:
+				return fmt.Errorf(`%s: system tag is not allowed: "%s"`, field, t)
+			}
+		}
+		if err := validateSubPath(in.SubPath); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if in.CachePolicy != "" {
+			return fmt.Errorf("%s: input should not have cache_policy", field)
+		}
+		paths = append(paths, pathOf{field: field, path: in.Path})
+	}
+
+	for i, out := range ps.Outputs {
+		field := fmt.Sprintf("outputs[%d]", i)
+		if err := validatePath(out.Path); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if err := noSystemTags(out.Tags); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if out.ReadOnly {
+			return fmt.Errorf("%s: output should not be read-only", field)
+		}
+		if out.SubPath != "" {
+			return fmt.Errorf("%s: output should not have sub_path", field)
+		}
+		if !out.CachePolicy.Valid() {
+			return fmt.Errorf("%s: unknown cache policy: %q", field, out.CachePolicy)
+		}
+		paths = append(paths, pathOf{field: field, path: out.Path})
+	}
+
+	if ps.Log != nil {
+		if err := noSystemTags(ps.Log.Tags); err != nil {
+			return fmt.Errorf("log: %w", err)
+		}
+	}
+
+	envs := map[string]string{}
+	for i, e := range ps.Env {
+		envs[e.Name] = fmt.Sprintf("env[%d]", i)
+	}
+	for i, sec := range ps.Secrets {
+		field := fmt.Sprintf("secrets[%d]", i)
+		if err := sec.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if sec.Path != "" {
+			paths = append(paths, pathOf{field: field, path: sec.Path})
+			continue
+		}
+		if other, ok := envs[sec.Env]; ok {
+			return fmt.Errorf(`%s and %s: env should not be duplicated: "%s"`, other, field, sec.Env)
+		}
+		envs[sec.Env] = field
+	}
+
+	for i, a := range paths {
+		for _, b := range paths[i+1:] {
+			if overlaps(a.path, b.path) {
+				return fmt.Errorf(`%s and %s: paths should not overlap: "%s", "%s"`, a.field, b.field, a.path, b.path)
+			}
+		}
+	}
+
+	if on := ps.OnNode; on != nil && !opts.LaxLabels {
+		for _, ls := range []struct {
+			field  string
+			labels []OnSpecLabel
+		}{
+			{field: "may", labels: on.May},
+			{field: "prefer", labels: on.Prefer},
+			{field: "must", labels: on.Must},
+		} {
+			for i, l := range ls.labels {
+				if err := l.Validate(); err != nil {
+					return fmt.Errorf("on_node.%s[%d]: %w", ls.field, i, err)
+				}
+			}
+		}
+	}
+
+	for i, e := range ps.Env {
+		if err := e.Validate(); err != nil {
+			return fmt.Errorf("env[%d]: %w", i, err)
+		}
+	}
+
+	if ps.WorkingDir != "" && !path.IsAbs(ps.WorkingDir) {
+		return fmt.Errorf(`working_dir: path should be absolute: "%s"`, ps.WorkingDir)
+	}
+	if ps.RunAsUser != nil && *ps.RunAsUser < 0 {
+		return fmt.Errorf("run_as_user: should not be negative: %d", *ps.RunAsUser)
+	}
+	if ps.RunAsGroup != nil && *ps.RunAsGroup < 0 {
+		return fmt.Errorf("run_as_group: should not be negative: %d", *ps.RunAsGroup)
+	}
+
+	sidecars := map[string]bool{}
+	for i, c := range ps.Sidecars {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("sidecars[%d]: %w", i, err)
+		}
+		if sidecars[c.Name] {
+			return fmt.Errorf(`sidecars[%d]: duplicated name: "%s"`, i, c.Name)
+		}
+		sidecars[c.Name] = true
+	}
+
+	if ps.ServiceAccount != "" {
+		if err := ValidateServiceAccount(ps.ServiceAccount); err != nil {
+			return fmt.Errorf("service_account: %w", err)
+		}
+	}
+
+	if err := ps.Resources.Validate(); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+
+	if ps.Timeout != nil && *ps.Timeout <= 0 {
+		return fmt.Errorf("timeout: should be positive: %s", ps.Timeout)
+	}
+	if ps.Deadline != nil && *ps.Deadline <= 0 {
+		return fmt.Errorf("deadline: should be positive: %s", ps.Deadline)
+	}
+
+	if !ps.Priority.Valid() {
+		return fmt.Errorf("priority: unknown priority: %q", ps.Priority)
+	}
+	if ps.Schedule != nil {
+		if err := ps.Schedule.Validate(); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+	}
+
+	return projects.ValidateName(ps.Project)
+}
+
+// Validate checks resource names are not empty and quantities are positive.
+func (r Resources) Validate() error {
+	for _, name := range slices.Sorted(maps.Keys(r)) {
+		if name == "" {
+			return fmt.Errorf(`resource name should not be empty`)
+		}
+		if q := r[name]; q.Sign() <= 0 {
+			return fmt.Errorf(`"%s" should be positive: %s`, name, q.String())
+		}
+	}
+	return nil
+}
+
+// ValidateServiceAccount checks name is a valid Kubernetes ServiceAccount name,
+// that is, a DNS subdomain defined in RFC 1123:
+// up to 253 characters of lower case alphanumerics, '-' or '.', starting and ending with an alphanumeric.
+func ValidateServiceAccount(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return fmt.Errorf("service account name %q is invalid: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Validate checks the service account name is given and valid.
+func (s SetServiceAccount) Validate() error {
+	if s.ServiceAccount == "" {
+		return fmt.Errorf(`required field missing: "service_account"`)
+	}
+	return ValidateServiceAccount(s.ServiceAccount)
+}
+
+// Validate checks the change is well-formed.
+//
+// Resources to be set should be valid as Resources, and names to be unset should not be empty.
+func (c ResourceLimitChange) Validate() error {
+	if err := c.Set.Validate(); err != nil {
+		return fmt.Errorf("set: %w", err)
+	}
+	for _, name := range c.Unset {
+		if name == "" {
+			return fmt.Errorf(`unset: resource name should not be empty`)
+		}
+	}
+	return nil
+}
+
+func validatePath(p string) error {
+	if p == "" {
+		return fmt.Errorf(`required field missing: "path"`)
+	}
+	if !path.IsAbs(p) {
+		return fmt.Errorf(`path should be absolute: "%s"`, p)
+	}
+	if p == "/" {
+		return fmt.Errorf(`path should not be root`)
+	}
+	return nil
+}
+
+func validateSubPath(p string) error {
+	if p == "" {
+		return nil
+	}
+	if path.IsAbs(p) {
+		return fmt.Errorf(`sub_path should be relative: "%s"`, p)
+	}
+	if c := path.Clean(p); c == ".." || strings.HasPrefix(c, "../") {
+		return fmt.Errorf(`sub_path should not go up: "%s"`, p)
+	}
+	return nil
+}
+
+func noSystemTags(ts []tags.Tag) error {
+	for _, t := range ts {
+		if strings.HasPrefix(t.Key, tags.SystemTagPrefix) {
+			return fmt.Errorf(`system tag is not allowed: "%s"`, t)
+		}
+	}
+	return nil
+}
+
+// overlaps returns true if a and b are same, or one of them is in the other.
+func overlaps(a, b string) bool {
+	a = path.Clean(a)
+	b = path.Clean(b)
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
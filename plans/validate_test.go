@@ -0,0 +1,163 @@
+package plans_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPlanSpec_Validate(t *testing.T) {
+	valid := func() plans.PlanSpec {
+		return plans.PlanSpec{
+			Image: plans.Image{Repository: "example.com/train", Tag: "v1"},
+			Inputs: []plans.Mountpoint{
+				{Path: "/in/data", Tags: []tags.Tag{{Key: "type", Value: "dataset"}, {Key: tags.KeyKnitId, Value: "some-id"}}},
+			},
+			Outputs: []plans.Mountpoint{
+				{Path: "/out/model", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+			},
+			Log:       &plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}},
+			Resources: plans.Resources{"cpu": resource.MustParse("1")},
+		}
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid spec is rejected: %v", err)
+	}
+
+	for name, mod := range map[string]func(*plans.PlanSpec){
+		"no image":      func(ps *plans.PlanSpec) { ps.Image = plans.Image{} },
+		"no inputs":     func(ps *plans.PlanSpec) { ps.Inputs = nil },
+		"relative path": func(ps *plans.PlanSpec) { ps.Outputs[0].Path = "out/model" },
+		"root path":     func(ps *plans.PlanSpec) { ps.Inputs[0].Path = "/" },
+		"same paths":    func(ps *plans.PlanSpec) { ps.Outputs[0].Path = "/in/data/" },
+		"nested paths":  func(ps *plans.PlanSpec) { ps.Outputs[0].Path = "/in/data/model" },
+		"transient on input": func(ps *plans.PlanSpec) {
+			ps.Inputs[0].Tags = append(ps.Inputs[0].Tags, tags.Tag{Key: tags.KeyKnitTransient, Value: tags.ValueKnitTransientFailed})
+		},
+		"system tag on output": func(ps *plans.PlanSpec) {
+			ps.Outputs[0].Tags = append(ps.Outputs[0].Tags, tags.Tag{Key: tags.KeyKnitId, Value: "some-id"})
+		},
+		"system tag on log": func(ps *plans.PlanSpec) {
+			ps.Log.Tags = append(ps.Log.Tags, tags.Tag{Key: tags.KeyKnitTimestamp, Value: "2024-01-01T00:00:00Z"})
+		},
+		"zero resource":        func(ps *plans.PlanSpec) { ps.Resources["memory"] = resource.MustParse("0") },
+		"bad project":          func(ps *plans.PlanSpec) { ps.Project = "Not A Project" },
+		"relative working dir": func(ps *plans.PlanSpec) { ps.WorkingDir = "work" },
+		"negative uid":         func(ps *plans.PlanSpec) { uid := int64(-1); ps.RunAsUser = &uid },
+		"absolute sub_path":    func(ps *plans.PlanSpec) { ps.Inputs[0].SubPath = "/part" },
+		"going up sub_path":    func(ps *plans.PlanSpec) { ps.Inputs[0].SubPath = "part/../../other" },
+		"read-only output":     func(ps *plans.PlanSpec) { ps.Outputs[0].ReadOnly = true },
+		"output with sub_path": func(ps *plans.PlanSpec) { ps.Outputs[0].SubPath = "part" },
+		"bad env":              func(ps *plans.PlanSpec) { ps.Env = []plans.EnvVar{{Name: "has space", Value: "x"}} },
+		"bad label": func(ps *plans.PlanSpec) {
+			ps.OnNode = &plans.OnNode{Must: []plans.OnSpecLabel{{Key: "has space", Value: "x"}}}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ps := valid()
+			mod(&ps)
+			if err := ps.Validate(); err == nil {
+				t.Errorf("invalid spec is accepted: %+v", ps)
+			}
+		})
+	}
+}
+
+func TestPlanSpec_Validate_siblingPaths(t *testing.T) {
+	ps := plans.PlanSpec{
+		Image:   plans.Image{Repository: "example.com/train"},
+		Inputs:  []plans.Mountpoint{{Path: "/in/data"}},
+		Outputs: []plans.Mountpoint{{Path: "/in/data2"}},
+	}
+	if err := ps.Validate(); err != nil {
+		t.Errorf("sibling paths are rejected: %v", err)
+	}
+}
+
+func TestOnSpecLabel_Parse(t *testing.T) {
+	for _, expr := range []string{
+		"zone=a",
+		"example.com/accelerator=nvidia-a100",
+		"dedicated=",
+		"a.b_c-d=A.b_c-1",
+	} {
+		l := plans.OnSpecLabel{}
+		if err := l.Parse(expr); err != nil {
+			t.Errorf("valid label %q is rejected: %v", expr, err)
+		}
+	}
+
+	for _, expr := range []string{
+		"=a",
+		"has space=a",
+		"-zone=a",
+		"Example.com/zone=a",
+		"zone=" + strings.Repeat("a", 64),
+		"zone=a/b",
+		"zone=-a",
+	} {
+		l := plans.OnSpecLabel{}
+		if err := l.Parse(expr); err == nil {
+			t.Errorf("invalid label %q is accepted: %+v", expr, l)
+		}
+	}
+
+	l := plans.OnSpecLabel{}
+	if err := l.ParseLax("has space=a/b"); err != nil {
+		t.Errorf("ParseLax: label is rejected: %v", err)
+	}
+	if err := l.ParseLax("no-equal"); err == nil {
+		t.Errorf("ParseLax: label without \"=\" is accepted: %+v", l)
+	}
+}
+
+func TestPlanSpec_ValidateWith_laxLabels(t *testing.T) {
+	var spec plans.PlanSpec
+	doc := `{"image": "example.com/train:v1", "inputs": [{"path": "/in", "tags": ["type:dataset"]}], "on_node": {"must": ["has space=a/b"]}}`
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		t.Fatalf("unmarshalling should not validate labels: %v", err)
+	}
+	if err := spec.Validate(); err == nil {
+		t.Error("invalid label is accepted by Validate")
+	}
+	if err := spec.ValidateWith(plans.ValidateOptions{LaxLabels: true}); err != nil {
+		t.Errorf("invalid label is rejected with LaxLabels: %v", err)
+	}
+}
+
+func TestSetServiceAccount(t *testing.T) {
+	for _, name := range []string{"default", "knit-worker", "sa.example.com"} {
+		if err := (plans.SetServiceAccount{ServiceAccount: name}).Validate(); err != nil {
+			t.Errorf("valid name %q is rejected: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "Upper", "has space", "-leading", "trailing.", strings.Repeat("a", 254)} {
+		if err := (plans.SetServiceAccount{ServiceAccount: name}).Validate(); err == nil {
+			t.Errorf("invalid name %q is accepted", name)
+		}
+
+		s := plans.SetServiceAccount{}
+		if err := json.Unmarshal([]byte(`{"service_account": "`+name+`"}`), &s); err == nil {
+			t.Errorf("invalid name %q is unmarshalled from JSON", name)
+		}
+		if err := yaml.Unmarshal([]byte(`service_account: "`+name+`"`), &s); err == nil {
+			t.Errorf("invalid name %q is unmarshalled from YAML", name)
+		}
+	}
+
+	ps := plans.PlanSpec{
+		Image:          plans.Image{Repository: "example.com/train"},
+		Inputs:         []plans.Mountpoint{{Path: "/in"}},
+		ServiceAccount: "Not_Valid",
+	}
+	if err := ps.Validate(); err == nil {
+		t.Errorf("PlanSpec with invalid service account is accepted")
+	}
+}
@@ -0,0 +1,217 @@
+package policies
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Permission is an operation on resources.
+type Permission string
+
+const (
+	Read  Permission = "read"
+	Write Permission = "write"
+)
+
+// Effect is what a Policy does for matched requests.
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Subject is who the Policy is applied to.
+type Subject struct {
+	// Users are the names of users.
+	Users []string `json:"users,omitempty"`
+
+	// Groups are the names of groups.
+	Groups []string `json:"groups,omitempty"`
+}
+
+func (s Subject) Equal(o Subject) bool {
+	return apicmp.SliceEqEqUnordered(s.Users, o.Users) &&
+		apicmp.SliceEqEqUnordered(s.Groups, o.Groups)
+}
+
+// Policy grants or denies permissions on Data and Plans selected by tags.
+//
+// Policy is the format for request and response body for Knitfab APIs below:
+//
+// - GET  /api/policies/ (as list)
+//
+// - PUT  /api/policies/{name}
+//
+// - GET  /api/policies/{name}
+type Policy struct {
+	// Name is the name of the Policy.
+	Name string `json:"name"`
+
+	// Effect is whether the Policy allows or denies.
+	//
+	// When both allowing and denying Policies match a request, the request is denied.
+	Effect Effect `json:"effect"`
+
+	// Subject is who the Policy is applied to.
+	Subject Subject `json:"subject"`
+
+	// Permissions are the operations the Policy is applied to.
+	Permissions []Permission `json:"permissions"`
+
+	// Kinds are the kinds of resources the Policy is applied to.
+	//
+	// Only meta.KindData and meta.KindPlan are available.
+	Kinds []meta.ResourceKind `json:"kinds"`
+
+	// Tags selects resources. Resources with all of these tags are selected.
+	//
+	// For Plans, tags of their inputs and outputs are used.
+	// If empty, all resources are selected.
+	Tags []tags.Tag `json:"tags,omitempty"`
+}
+
+func (p Policy) Equal(o Policy) bool {
+	return p.Name == o.Name &&
+		p.Effect == o.Effect &&
+		p.Subject.Equal(o.Subject) &&
+		apicmp.SliceEqEqUnordered(p.Permissions, o.Permissions) &&
+		apicmp.SliceEqEqUnordered(p.Kinds, o.Kinds) &&
+		apicmp.SliceEqualUnordered(p.Tags, o.Tags)
+}
+
+// Validate checks the Policy is well-formed.
+func (p Policy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	if p.Effect != Allow && p.Effect != Deny {
+		return fmt.Errorf(`"effect" should be "%s" or "%s": %q`, Allow, Deny, p.Effect)
+	}
+	if len(p.Subject.Users) == 0 && len(p.Subject.Groups) == 0 {
+		return fmt.Errorf(`"subject" should have users or groups`)
+	}
+	if len(p.Permissions) == 0 {
+		return fmt.Errorf(`required field missing: "permissions"`)
+	}
+	for _, perm := range p.Permissions {
+		if perm != Read && perm != Write {
+			return fmt.Errorf(`"permissions" should be "%s" or "%s": %q`, Read, Write, perm)
+		}
+	}
+	if len(p.Kinds) == 0 {
+		return fmt.Errorf(`required field missing: "kinds"`)
+	}
+	for _, k := range p.Kinds {
+		if k != meta.KindData && k != meta.KindPlan {
+			return fmt.Errorf(`"kinds" should be "%s" or "%s": %q`, meta.KindData, meta.KindPlan, k)
+		}
+	}
+	return nil
+}
+
+// match returns reason why the Policy matches the request, or empty string if not matched.
+func (p Policy) match(r Request) string {
+	who := ""
+	if slices.Contains(p.Subject.Users, r.User) {
+		who = "user " + r.User
+	} else if i := slices.IndexFunc(r.Groups, func(g string) bool {
+		return slices.Contains(p.Subject.Groups, g)
+	}); 0 <= i {
+		who = "group " + r.Groups[i]
+	} else {
+		return ""
+	}
+
+	if !slices.Contains(p.Permissions, r.Permission) || !slices.Contains(p.Kinds, r.Resource.Kind) {
+		return ""
+	}
+	for _, t := range p.Tags {
+		if !slices.ContainsFunc(r.Tags, t.Equal) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: %s %s %s on %s for %s", p.Name, p.Effect, r.Permission, r.Resource.Kind, r.Resource, who)
+}
+
+// Request is the format for request body to Knitfab APIs below:
+//
+// - POST /api/policies/decide
+//
+// It asks whether the user can do the operation on the resource.
+type Request struct {
+	// User is the name of the user.
+	User string `json:"user"`
+
+	// Groups are the groups the user belongs to.
+	Groups []string `json:"groups,omitempty"`
+
+	// Permission is the operation.
+	Permission Permission `json:"permission"`
+
+	// Resource is the resource to be operated.
+	Resource meta.ResourceRef `json:"resource"`
+
+	// Tags are the tags of the resource.
+	Tags []tags.Tag `json:"tags,omitempty"`
+}
+
+// Decision is the format for response body from Knitfab APIs below:
+//
+// - POST /api/policies/decide
+type Decision struct {
+	// Allowed is true if the operation is allowed.
+	Allowed bool `json:"allowed"`
+
+	// Allowing are the names of Policies which allow the operation.
+	Allowing []string `json:"allowing,omitempty"`
+
+	// Denying are the names of Policies which deny the operation.
+	Denying []string `json:"denying,omitempty"`
+
+	// Explanation describes how the decision is made, for debugging policies.
+	Explanation []string `json:"explanation"`
+}
+
+func (d Decision) Equal(o Decision) bool {
+	return d.Allowed == o.Allowed &&
+		apicmp.SliceEqEq(d.Allowing, o.Allowing) &&
+		apicmp.SliceEqEq(d.Denying, o.Denying) &&
+		apicmp.SliceEqEq(d.Explanation, o.Explanation)
+}
+
+// Decide makes the Decision for the request under the policies.
+//
+// The operation is allowed when some allowing Policies match and no denying Policies match.
+// If no Policies match, it is denied.
+func Decide(policies []Policy, r Request) Decision {
+	d := Decision{Explanation: []string{}}
+	for _, p := range policies {
+		reason := p.match(r)
+		if reason == "" {
+			continue
+		}
+		switch p.Effect {
+		case Allow:
+			d.Allowing = append(d.Allowing, p.Name)
+		case Deny:
+			d.Denying = append(d.Denying, p.Name)
+		}
+		d.Explanation = append(d.Explanation, reason)
+	}
+
+	switch {
+	case 0 < len(d.Denying):
+		d.Explanation = append(d.Explanation, "denied: denying policies matched")
+	case 0 < len(d.Allowing):
+		d.Allowed = true
+		d.Explanation = append(d.Explanation, "allowed")
+	default:
+		d.Explanation = append(d.Explanation, "denied: no policies matched")
+	}
+	return d
+}
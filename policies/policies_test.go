@@ -0,0 +1,90 @@
+package policies_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/policies"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestDecide(t *testing.T) {
+	ps := []policies.Policy{
+		{
+			Name:        "team-x-reads-x",
+			Effect:      policies.Allow,
+			Subject:     policies.Subject{Groups: []string{"team-x"}},
+			Permissions: []policies.Permission{policies.Read},
+			Kinds:       []meta.ResourceKind{meta.KindData},
+			Tags:        []tags.Tag{{Key: "team", Value: "x"}},
+		},
+		{
+			Name:        "no-secrets",
+			Effect:      policies.Deny,
+			Subject:     policies.Subject{Users: []string{"intern"}},
+			Permissions: []policies.Permission{policies.Read, policies.Write},
+			Kinds:       []meta.ResourceKind{meta.KindData, meta.KindPlan},
+			Tags:        []tags.Tag{{Key: "secret", Value: "true"}},
+		},
+	}
+	for _, p := range ps {
+		if err := p.Validate(); err != nil {
+			t.Fatalf("policy %s is invalid: %v", p.Name, err)
+		}
+	}
+
+	type Then struct {
+		Allowed  bool
+		Allowing []string
+		Denying  []string
+	}
+	theory := func(r policies.Request, then Then) func(*testing.T) {
+		return func(t *testing.T) {
+			d := policies.Decide(ps, r)
+			if d.Allowed != then.Allowed ||
+				!apicmp.SliceEqEq(d.Allowing, then.Allowing) ||
+				!apicmp.SliceEqEq(d.Denying, then.Denying) {
+				t.Errorf("unexpected decision: %+v", d)
+			}
+			if len(d.Explanation) == 0 {
+				t.Error("decision should be explained")
+			}
+		}
+	}
+
+	data := meta.ResourceRef{Kind: meta.KindData, Id: "knit-1"}
+
+	t.Run("member reads team data", theory(
+		policies.Request{
+			User: "alice", Groups: []string{"team-x"},
+			Permission: policies.Read, Resource: data,
+			Tags: []tags.Tag{{Key: "team", Value: "x"}, {Key: "type", Value: "csv"}},
+		},
+		Then{Allowed: true, Allowing: []string{"team-x-reads-x"}},
+	))
+	t.Run("member writes team data", theory(
+		policies.Request{
+			User: "alice", Groups: []string{"team-x"},
+			Permission: policies.Write, Resource: data,
+			Tags: []tags.Tag{{Key: "team", Value: "x"}},
+		},
+		Then{Allowed: false},
+	))
+	t.Run("non-member reads team data", theory(
+		policies.Request{
+			User: "bob", Groups: []string{"team-y"},
+			Permission: policies.Read, Resource: data,
+			Tags: []tags.Tag{{Key: "team", Value: "x"}},
+		},
+		Then{Allowed: false},
+	))
+	t.Run("deny wins", theory(
+		policies.Request{
+			User: "intern", Groups: []string{"team-x"},
+			Permission: policies.Read, Resource: data,
+			Tags: []tags.Tag{{Key: "team", Value: "x"}, {Key: "secret", Value: "true"}},
+		},
+		Then{Allowed: false, Allowing: []string{"team-x-reads-x"}, Denying: []string{"no-secrets"}},
+	))
+}
@@ -0,0 +1,111 @@
+package projects
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// QueryParam is the name of query parameter to scope list WebAPIs to a Project.
+const QueryParam = "project"
+
+// Project is a scope of resources for a team sharing a Knitfab cluster.
+//
+// Project is the format for request and response body for Knitfab APIs below:
+//
+// - GET /api/projects/ (as list)
+//
+// - PUT /api/projects/{name}
+type Project struct {
+	// Name is the name of the Project.
+	//
+	// It should be a DNS label: lower case alphanumerics and "-", up to 63 characters.
+	Name string `json:"name" yaml:"name"`
+
+	// Description is the human readable description of the Project.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// QuotaRef is the name of the resource quota applied to the Project.
+	//
+	// If empty, the Project has no quota.
+	QuotaRef string `json:"quotaRef,omitempty" yaml:"quotaRef,omitempty"`
+
+	// DefaultTags are the tags attached to Data registered in the Project,
+	// in addition to tags specified in requests.
+	DefaultTags []tags.UserTag `json:"defaultTags,omitempty" yaml:"defaultTags,omitempty"`
+}
+
+func (p Project) Equal(o Project) bool {
+	return p.Name == o.Name &&
+		p.Description == o.Description &&
+		p.QuotaRef == o.QuotaRef &&
+		apicmp.SliceEqualUnordered(p.DefaultTags, o.DefaultTags)
+}
+
+// Detail is a Project with the number of resources in it.
+//
+// Detail is the format for response body for Knitfab APIs below:
+//
+// - GET /api/projects/{name}
+type Detail struct {
+	Project `yaml:",inline"`
+
+	// PlanCount is the number of Plans in the Project.
+	PlanCount int `json:"planCount" yaml:"planCount"`
+
+	// RunCount is the number of Runs in the Project.
+	RunCount int `json:"runCount" yaml:"runCount"`
+
+	// DataCount is the number of Data in the Project.
+	DataCount int `json:"dataCount" yaml:"dataCount"`
+}
+
+func (d Detail) Equal(o Detail) bool {
+	return d.Project.Equal(o.Project) &&
+		d.PlanCount == o.PlanCount &&
+		d.RunCount == o.RunCount &&
+		d.DataCount == o.DataCount
+}
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// ValidateName checks the name is valid as a Project name.
+//
+// Empty name is valid, and means "no Project" (cluster-wide scope).
+func ValidateName(name string) error {
+	if name == "" || dnsLabel.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf(
+		"project name %q is invalid: it should be lower case alphanumerics and \"-\", up to 63 characters, and start and end with an alphanumeric",
+		name,
+	)
+}
+
+// Validate checks the Project is well-formed.
+func (p Project) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	return ValidateName(p.Name)
+}
+
+// WithDefaultTags returns ts with DefaultTags which ts does not have.
+func (p Project) WithDefaultTags(ts []tags.UserTag) []tags.UserTag {
+	ret := append([]tags.UserTag{}, ts...)
+	for _, dt := range p.DefaultTags {
+		has := false
+		for _, t := range ts {
+			if t.Equal(dt) {
+				has = true
+				break
+			}
+		}
+		if !has {
+			ret = append(ret, dt)
+		}
+	}
+	return ret
+}
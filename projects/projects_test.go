@@ -0,0 +1,137 @@
+package projects_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetail_roundTrip(t *testing.T) {
+	detail := projects.Detail{
+		Project: projects.Project{
+			Name:        "team-a",
+			Description: "models of team A",
+			QuotaRef:    "team-a-quota",
+			DefaultTags: []tags.UserTag{{Key: "team", Value: "a"}},
+		},
+		PlanCount: 3,
+		RunCount:  12,
+		DataCount: 20,
+	}
+	knittest.AssertRoundTrip(t, detail)
+
+	// Project is inlined.
+	b, err := yaml.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"name: team-a", "quotaRef: team-a-quota", "planCount: 3", "runCount: 12", "dataCount: 20"} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("missing %q in:\n%s", key, b)
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for name, ok := range map[string]bool{
+		"":                      true,
+		"team-a":                true,
+		"a":                     true,
+		"Team-A":                false,
+		"-team":                 false,
+		"team-":                 false,
+		"team_a":                false,
+		strings.Repeat("a", 63): true,
+		strings.Repeat("a", 64): false,
+	} {
+		if err := projects.ValidateName(name); (err == nil) != ok {
+			t.Errorf("ValidateName(%q) = %v, want ok=%v", name, err, ok)
+		}
+	}
+}
+
+func TestProject_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    projects.Project
+		WantErr bool
+	}{
+		"valid": {
+			When: projects.Project{Name: "team-a", DefaultTags: []tags.UserTag{{Key: "team", Value: "a"}}},
+		},
+		"empty name": {
+			When:    projects.Project{Description: "no name"},
+			WantErr: true,
+		},
+		"invalid name": {
+			When:    projects.Project{Name: "Team_A"},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid project is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid project is rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestProject_WithDefaultTags(t *testing.T) {
+	team := tags.UserTag{Key: "team", Value: "a"}
+	stage := tags.UserTag{Key: "stage", Value: "dev"}
+	typ := tags.UserTag{Key: "type", Value: "csv"}
+
+	for name, testcase := range map[string]struct {
+		Defaults []tags.UserTag
+		When     []tags.UserTag
+		Want     []tags.UserTag
+	}{
+		"nil default tags": {
+			Defaults: nil,
+			When:     []tags.UserTag{typ},
+			Want:     []tags.UserTag{typ},
+		},
+		"nil default tags and nil tags": {
+			Defaults: nil,
+			When:     nil,
+			Want:     []tags.UserTag{},
+		},
+		"default tags are appended": {
+			Defaults: []tags.UserTag{team, stage},
+			When:     []tags.UserTag{typ},
+			Want:     []tags.UserTag{typ, team, stage},
+		},
+		"existing tags are not duplicated": {
+			Defaults: []tags.UserTag{team, stage},
+			When:     []tags.UserTag{stage, typ},
+			Want:     []tags.UserTag{stage, typ, team},
+		},
+		"same key with other value is kept": {
+			Defaults: []tags.UserTag{stage},
+			When:     []tags.UserTag{{Key: "stage", Value: "prod"}},
+			Want:     []tags.UserTag{{Key: "stage", Value: "prod"}, stage},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := projects.Project{Name: "team-a", DefaultTags: testcase.Defaults}
+			given := slices.Clone(testcase.When)
+
+			got := p.WithDefaultTags(testcase.When)
+			if !apicmp.SliceEqual(got, testcase.Want) {
+				t.Errorf("got %v, want %v", got, testcase.Want)
+			}
+			if !apicmp.SliceEqual(testcase.When, given) {
+				t.Errorf("argument is modified: %v", testcase.When)
+			}
+		})
+	}
+}
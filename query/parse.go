@@ -0,0 +1,232 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Parse parses the text form of a query.
+//
+// The grammar is:
+//
+//	expr   := term ( "or" term )*
+//	term   := factor ( "and" factor )*
+//	factor := "not" factor | "(" expr ")" | "any" | predicate
+//
+// and predicates are:
+//
+//	tag:KEY:VALUE    Data has the tag (Tag)
+//	key:KEY          Data has a tag with the key (HasKey)
+//	since:TIME       Data is created at TIME or later (TimeRange)
+//	until:TIME       Data is created before TIME (TimeRange)
+//	plan:PLANID      Data is created by the Plan (Upstream)
+//	image:REPO:TAG   Data is created by a Plan with the image (Upstream)
+//	name:NAME        Data is created by a Plan with the name (Upstream)
+//
+// Values containing spaces, parentheses or quotes should be double-quoted, like tag:"project:my data".
+// TIME accepts abbreviated forms of RFC3339 (see rfctime.ParseLooseRFC3339).
+//
+// Keywords "and", "or", "not" and "any" are case-insensitive.
+func Parse(s string) (Expr, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("query: unexpected token: %s", p.toks[p.pos])
+	}
+	return e, nil
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) peek() string {
+	if len(p.toks) <= p.pos {
+		return ""
+	}
+	return p.toks[p.pos]
+}
+
+func (p *parser) keyword(kw string) bool {
+	if strings.EqualFold(p.peek(), kw) {
+		p.pos += 1
+		return true
+	}
+	return false
+}
+
+func (p *parser) expr() (Expr, error) {
+	first, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	args := []Expr{first}
+	for p.keyword("or") {
+		e, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, e)
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return Or{Args: args}, nil
+}
+
+func (p *parser) term() (Expr, error) {
+	first, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	args := []Expr{first}
+	for p.keyword("and") {
+		e, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, e)
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return And{Args: args}, nil
+}
+
+func (p *parser) factor() (Expr, error) {
+	switch {
+	case p.keyword("not"):
+		e, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Arg: e}, nil
+	case p.keyword("any"):
+		return And{}, nil
+	case p.peek() == "(":
+		p.pos += 1
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf(`query: ")" is expected`)
+		}
+		p.pos += 1
+		return e, nil
+	case p.peek() == "":
+		return nil, fmt.Errorf("query: unexpected end of query")
+	}
+
+	tok := p.toks[p.pos]
+	p.pos += 1
+	return predicate(tok)
+}
+
+func predicate(tok string) (Expr, error) {
+	field, raw, ok := strings.Cut(tok, ":")
+	if !ok {
+		return nil, fmt.Errorf("query: predicate format error (should be field:value): %s", tok)
+	}
+	value := raw
+	if strings.HasPrefix(raw, `"`) {
+		v, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("query: bad quoted value: %s", tok)
+		}
+		value = v
+	}
+
+	switch field {
+	case "tag":
+		t := tags.Tag{}
+		if err := t.Parse(value); err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+		return Tag{Tag: t}, nil
+	case "key":
+		return HasKey{Key: value}, nil
+	case "since", "until":
+		t, err := rfctime.ParseLooseRFC3339(value)
+		if err != nil {
+			return nil, fmt.Errorf("query: %s: %w", field, err)
+		}
+		if field == "since" {
+			return TimeRange{Since: &t}, nil
+		}
+		return TimeRange{Until: &t}, nil
+	case "plan":
+		id, err := plans.ParsePlanId(value)
+		if err != nil {
+			return nil, fmt.Errorf("query: %s: %w", field, err)
+		}
+		return Upstream{PlanId: id}, nil
+	case "image":
+		return Upstream{Image: value}, nil
+	case "name":
+		return Upstream{Name: value}, nil
+	default:
+		return nil, fmt.Errorf("query: unknown field: %q", field)
+	}
+}
+
+func tokenize(s string) ([]string, error) {
+	toks := []string{}
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		switch r := rs[i]; {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			i += 1
+		case r == '(' || r == ')':
+			toks = append(toks, string(r))
+			i += 1
+		default:
+			b := new(strings.Builder)
+		WORD:
+			for i < len(rs) {
+				switch rs[i] {
+				case ' ', '\t', '\r', '\n', '(', ')':
+					break WORD
+				case '"':
+					b.WriteRune('"')
+					i += 1
+					closed := false
+					for i < len(rs) {
+						b.WriteRune(rs[i])
+						if rs[i] == '\\' && i+1 < len(rs) {
+							b.WriteRune(rs[i+1])
+							i += 2
+							continue
+						}
+						i += 1
+						if rs[i-1] == '"' {
+							closed = true
+							break
+						}
+					}
+					if !closed {
+						return nil, fmt.Errorf("query: unterminated quoted string")
+					}
+				default:
+					b.WriteRune(rs[i])
+					i += 1
+				}
+			}
+			toks = append(toks, b.String())
+		}
+	}
+	return toks, nil
+}
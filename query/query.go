@@ -0,0 +1,414 @@
+// Package query defines a structured query for advanced Data search.
+//
+// A query is an expression tree (AST) of predicates over Data combined with "and", "or" and "not".
+// It has the text form (see Parse) and the JSON form (see Query),
+// and can be evaluated against data.Detail on client-side (see Expr.Eval).
+//
+// Sort specifies the order of results of list queries, for Runs and Data.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Expr is a node of query AST.
+type Expr interface {
+	// Eval returns true if the Data matches this expression.
+	Eval(d data.Detail) bool
+
+	// String returns the text form of this expression, which can be read by Parse.
+	String() string
+
+	json.Marshaler
+}
+
+// Tag matches Data having the tag.
+type Tag struct {
+	Tag tags.Tag
+}
+
+func (t Tag) Eval(d data.Detail) bool {
+	for _, dt := range d.Tags {
+		if dt.Equal(t.Tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Tag) String() string {
+	return "tag:" + quote(t.Tag.String())
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]tags.Tag{"tag": t.Tag})
+}
+
+// HasKey matches Data having a tag with the key.
+type HasKey struct {
+	Key string
+}
+
+func (k HasKey) Eval(d data.Detail) bool {
+	for _, dt := range d.Tags {
+		if dt.Key == k.Key {
+			return true
+		}
+	}
+	return false
+}
+
+func (k HasKey) String() string {
+	return "key:" + quote(k.Key)
+}
+
+func (k HasKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"key": k.Key})
+}
+
+// TimeRange matches Data whose "knit#timestamp" is in the range.
+//
+// Since is inclusive, and Until is exclusive. Nil means unbounded.
+type TimeRange struct {
+	Since *rfctime.RFC3339 `json:"since,omitempty"`
+	Until *rfctime.RFC3339 `json:"until,omitempty"`
+}
+
+func (r TimeRange) Eval(d data.Detail) bool {
+	ts, ok := tags.FindTimestamp(d.Tags)
+	return ok && r.contains(ts.Time())
+}
+
+func (r TimeRange) contains(t time.Time) bool {
+	if r.Since != nil && t.Before(r.Since.Time()) {
+		return false
+	}
+	if r.Until != nil && !t.Before(r.Until.Time()) {
+		return false
+	}
+	return true
+}
+
+func (r TimeRange) String() string {
+	terms := []string{}
+	if r.Since != nil {
+		terms = append(terms, "since:"+quote(r.Since.String()))
+	}
+	if r.Until != nil {
+		terms = append(terms, "until:"+quote(r.Until.String()))
+	}
+	if len(terms) == 0 {
+		// no bound. it matches any Data with timestamp.
+		return "key:" + quote(tags.KeyKnitTimestamp)
+	}
+	return strings.Join(terms, " and ")
+}
+
+func (r TimeRange) MarshalJSON() ([]byte, error) {
+	type timeRange TimeRange
+	return json.Marshal(map[string]timeRange{"time": timeRange(r)})
+}
+
+// Upstream matches Data created by a Run of the Plan.
+//
+// Empty fields are not checked.
+type Upstream struct {
+	// PlanId is the id of the upstream Plan.
+	PlanId plans.PlanId `json:"planId,omitempty"`
+
+	// Image is the image of the upstream Plan, in the form of "repository:tag".
+	Image string `json:"image,omitempty"`
+
+	// Name is the name of the upstream Plan.
+	Name string `json:"name,omitempty"`
+}
+
+func (u Upstream) Eval(d data.Detail) bool {
+	p := d.Upstream.Run.Plan
+	if u.PlanId != "" && p.PlanId != u.PlanId {
+		return false
+	}
+	if u.Image != "" && (p.Image == nil || p.Image.String() != u.Image) {
+		return false
+	}
+	if u.Name != "" && p.Name != u.Name {
+		return false
+	}
+	return true
+}
+
+func (u Upstream) String() string {
+	terms := []string{}
+	if u.PlanId != "" {
+		terms = append(terms, "plan:"+quote(u.PlanId.String()))
+	}
+	if u.Image != "" {
+		terms = append(terms, "image:"+quote(u.Image))
+	}
+	if u.Name != "" {
+		terms = append(terms, "name:"+quote(u.Name))
+	}
+	if len(terms) == 0 {
+		return "any"
+	}
+	return strings.Join(terms, " and ")
+}
+
+func (u Upstream) MarshalJSON() ([]byte, error) {
+	type upstream Upstream
+	return json.Marshal(map[string]upstream{"upstream": upstream(u)})
+}
+
+// And matches Data matching all Args.
+//
+// If Args is empty, it matches any Data.
+type And struct {
+	Args []Expr
+}
+
+func (a And) Eval(d data.Detail) bool {
+	for _, e := range a.Args {
+		if !e.Eval(d) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a And) String() string {
+	if len(a.Args) == 0 {
+		return "any"
+	}
+	terms := make([]string, len(a.Args))
+	for i, e := range a.Args {
+		terms[i] = group(e)
+	}
+	return strings.Join(terms, " and ")
+}
+
+func (a And) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]Expr{"and": nonNil(a.Args)})
+}
+
+// Or matches Data matching some of Args.
+//
+// If Args is empty, it matches no Data.
+type Or struct {
+	Args []Expr
+}
+
+func (o Or) Eval(d data.Detail) bool {
+	for _, e := range o.Args {
+		if e.Eval(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o Or) String() string {
+	if len(o.Args) == 0 {
+		return "not any"
+	}
+	terms := make([]string, len(o.Args))
+	for i, e := range o.Args {
+		terms[i] = group(e)
+	}
+	return strings.Join(terms, " or ")
+}
+
+func (o Or) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]Expr{"or": nonNil(o.Args)})
+}
+
+// Not matches Data not matching Arg.
+//
+// If Arg is nil, it is treated as And{} (matches any Data), so Not{} matches no Data.
+type Not struct {
+	Arg Expr
+}
+
+func (n Not) arg() Expr {
+	if n.Arg == nil {
+		return And{}
+	}
+	return n.Arg
+}
+
+func (n Not) Eval(d data.Detail) bool {
+	return !n.arg().Eval(d)
+}
+
+func (n Not) String() string {
+	return "not " + group(n.arg())
+}
+
+func (n Not) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]Expr{"not": n.arg()})
+}
+
+// group wraps compound expressions with parentheses.
+func group(e Expr) string {
+	s := e.String()
+	switch x := e.(type) {
+	case And:
+		if 1 < len(x.Args) {
+			return "(" + s + ")"
+		}
+	case Or:
+		if 1 < len(x.Args) {
+			return "(" + s + ")"
+		}
+	case TimeRange:
+		if x.Since != nil && x.Until != nil {
+			return "(" + s + ")"
+		}
+	case Upstream:
+		if strings.Contains(s, " and ") {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+func nonNil(es []Expr) []Expr {
+	if es == nil {
+		return []Expr{}
+	}
+	return es
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n()\"\\") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Query is a container of Expr, for JSON marshalling.
+//
+// In JSON, each node of Expr is an object with a single key:
+//
+//	{"tag": "key:value"}
+//	{"key": "key"}
+//	{"time": {"since": "...", "until": "..."}}
+//	{"upstream": {"planId": "...", "image": "...", "name": "..."}}
+//	{"and": [...]}
+//	{"or": [...]}
+//	{"not": {...}}
+type Query struct {
+	Expr Expr
+}
+
+func (q Query) MarshalJSON() ([]byte, error) {
+	if q.Expr == nil {
+		return []byte("null"), nil
+	}
+	return q.Expr.MarshalJSON()
+}
+
+func (q *Query) UnmarshalJSON(b []byte) error {
+	e, err := decode(b)
+	if err != nil {
+		return err
+	}
+	q.Expr = e
+	return nil
+}
+
+func (q Query) String() string {
+	if q.Expr == nil {
+		return ""
+	}
+	return q.Expr.String()
+}
+
+// Eval returns true if the Data matches the query. Empty query matches any Data.
+func (q Query) Eval(d data.Detail) bool {
+	return q.Expr == nil || q.Expr.Eval(d)
+}
+
+func decode(b []byte) (Expr, error) {
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(b, &node); err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	if node == nil {
+		return nil, nil
+	}
+	if len(node) != 1 {
+		return nil, fmt.Errorf("query: node should have exactly one key, but has %d", len(node))
+	}
+
+	for op, raw := range node {
+		switch op {
+		case "tag":
+			var t tags.Tag
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return nil, fmt.Errorf("query: tag: %w", err)
+			}
+			return Tag{Tag: t}, nil
+		case "key":
+			var k string
+			if err := json.Unmarshal(raw, &k); err != nil {
+				return nil, fmt.Errorf("query: key: %w", err)
+			}
+			return HasKey{Key: k}, nil
+		case "time":
+			type timeRange TimeRange
+			var r timeRange
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return nil, fmt.Errorf("query: time: %w", err)
+			}
+			return TimeRange(r), nil
+		case "upstream":
+			type upstream Upstream
+			var u upstream
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return nil, fmt.Errorf("query: upstream: %w", err)
+			}
+			return Upstream(u), nil
+		case "and", "or":
+			var raws []json.RawMessage
+			if err := json.Unmarshal(raw, &raws); err != nil {
+				return nil, fmt.Errorf("query: %s: %w", op, err)
+			}
+			args := make([]Expr, 0, len(raws))
+			for _, r := range raws {
+				e, err := decode(r)
+				if err != nil {
+					return nil, err
+				}
+				if e == nil {
+					return nil, fmt.Errorf("query: %s: null operand", op)
+				}
+				args = append(args, e)
+			}
+			if op == "and" {
+				return And{Args: args}, nil
+			}
+			return Or{Args: args}, nil
+		case "not":
+			e, err := decode(raw)
+			if err != nil {
+				return nil, err
+			}
+			if e == nil {
+				return nil, fmt.Errorf("query: not: null operand")
+			}
+			return Not{Arg: e}, nil
+		default:
+			return nil, fmt.Errorf("query: unknown operator: %q", op)
+		}
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,157 @@
+package query_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/query"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func detail() data.Detail {
+	return data.Detail{
+		KnitId: "0190a1b2-0000-7000-8000-000000000301",
+		Tags: []tags.Tag{
+			{Key: "project", Value: "my data"},
+			{Key: "type", Value: "csv"},
+			{Key: tags.KeyKnitTimestamp, Value: "2024-10-11T12:00:00+09:00"},
+		},
+		Upstream: data.CreatedFrom{
+			Run: runs.Summary{
+				RunId: "0190a1b2-0000-7000-8000-000000000201",
+				Plan: plans.Summary{
+					PlanId: "0190a1b2-0000-7000-8000-000000000101",
+					Image:  &plans.Image{Repository: "repo.invalid/trainer", Tag: "v1"},
+				},
+			},
+		},
+	}
+}
+
+func TestParseAndEval(t *testing.T) {
+	theory := func(expr string, want bool) func(*testing.T) {
+		return func(t *testing.T) {
+			e, err := query.Parse(expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := e.Eval(detail()); got != want {
+				t.Errorf("Eval: got %v, want %v (parsed as %s)", got, want, e)
+			}
+
+			// text form is stable
+			reparsed, err := query.Parse(e.String())
+			if err != nil {
+				t.Fatalf("unexpected error on reparse %q: %v", e.String(), err)
+			}
+			if reparsed.String() != e.String() {
+				t.Errorf("text round trip: %q --> %q", e.String(), reparsed.String())
+			}
+
+			// json form is stable
+			b, err := json.Marshal(query.Query{Expr: e})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var q query.Query
+			if err := json.Unmarshal(b, &q); err != nil {
+				t.Fatalf("unexpected error on unmarshal %s: %v", b, err)
+			}
+			if q.String() != e.String() {
+				t.Errorf("json round trip: %q --> %s --> %q", e.String(), b, q.String())
+			}
+		}
+	}
+
+	t.Run("tag", theory(`tag:"project:my data"`, true))
+	t.Run("tag (unmatch)", theory(`tag:type:json`, false))
+	t.Run("key", theory(`key:type`, true))
+	t.Run("since", theory(`since:2024-10-11T00:00:00+09:00`, true))
+	t.Run("until", theory(`until:2024-10-11T12:00:00+09:00`, false))
+	t.Run("plan", theory(`plan:0190a1b2-0000-7000-8000-000000000101`, true))
+	t.Run("image", theory(`image:repo.invalid/trainer:v1`, true))
+	t.Run("name", theory(`name:uploaded`, false))
+	t.Run("any", theory(`any`, true))
+	t.Run("and", theory(`key:type and not tag:type:json`, true))
+	t.Run("or", theory(`tag:type:json or plan:0190a1b2-0000-7000-8000-000000000101`, true))
+	t.Run("precedence", theory(`tag:type:json or plan:0190a1b2-0000-7000-8000-000000000102 and key:type`, false))
+	t.Run("parentheses", theory(`NOT (tag:type:json OR plan:0190a1b2-0000-7000-8000-000000000102) AND key:type`, true))
+	t.Run("time range", theory(
+		`(since:"2024-10-11 00:00:00+09:00" and until:2024-10-12) and key:project`, true,
+	))
+}
+
+func TestParse_error(t *testing.T) {
+	for name, expr := range map[string]string{
+		"empty":                 ``,
+		"unknown field":         `color:red`,
+		"no field":              `project`,
+		"unclosed parenthesis":  `(key:a and key:b`,
+		"unterminated quote":    `tag:"a:b`,
+		"dangling operator":     `key:a and`,
+		"bad time":              `since:yesterday`,
+		"bad tag":               `tag:nocolon`,
+		"extra closing":         `key:a)`,
+		"reserved tag value":    `tag:knit#transient:unknown`,
+		"operator without left": `or key:a`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if e, err := query.Parse(expr); err == nil {
+				t.Errorf("expected error does not occur: %s", e)
+			}
+		})
+	}
+}
+
+func TestQuery_unmarshal(t *testing.T) {
+	payload := `{"and":[{"tag":"type:csv"},{"not":{"upstream":{"planId":"0190a1b2-0000-7000-8000-000000000102"}}},{"time":{"since":"2024-10-01T00:00:00+09:00"}}]}`
+	var q query.Query
+	if err := json.Unmarshal([]byte(payload), &q); err != nil {
+		t.Fatal(err)
+	}
+	if !q.Eval(detail()) {
+		t.Errorf("should match: %s", q)
+	}
+
+	for name, payload := range map[string]string{
+		"unknown operator": `{"xor":[]}`,
+		"multiple keys":    `{"key":"a","tag":"a:b"}`,
+		"null operand":     `{"not":null}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var q query.Query
+			if err := json.Unmarshal([]byte(payload), &q); err == nil {
+				t.Errorf("expected error does not occur: %s", q)
+			}
+		})
+	}
+}
+
+func TestNot_zero(t *testing.T) {
+	zero := query.Not{}
+	if zero.Eval(detail()) {
+		t.Error("Not{} should match no Data")
+	}
+	if got := zero.String(); got != "not any" {
+		t.Errorf("String: got %q, want %q", got, "not any")
+	}
+
+	b, err := json.Marshal(query.Query{Expr: zero})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var q query.Query
+	if err := json.Unmarshal(b, &q); err != nil {
+		t.Fatalf("unexpected error on unmarshal %s: %v", b, err)
+	}
+	if q.String() != zero.String() {
+		t.Errorf("json round trip: %q --> %s --> %q", zero.String(), b, q.String())
+	}
+
+	if !(query.Not{Arg: zero}).Eval(detail()) {
+		t.Error("not Not{} should match any Data")
+	}
+}
@@ -0,0 +1,243 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+// ParamSort is the query parameter name for Sort.
+const ParamSort = "sort"
+
+// Order is the direction of sorting.
+type Order string
+
+const (
+	Asc  Order = "asc"
+	Desc Order = "desc"
+)
+
+// Resource is the kind of resources to be sorted.
+type Resource string
+
+const (
+	ResourceRuns Resource = "runs"
+	ResourceData Resource = "data"
+)
+
+// Fields of resources which can be sorted by.
+const (
+	// FieldUpdatedAt sorts Runs by their UpdatedAt.
+	FieldUpdatedAt = "updatedAt"
+
+	// FieldStatus sorts Runs by their Status, in the order of runs.Statuses.
+	FieldStatus = "status"
+
+	// FieldTimestamp sorts Data by their "knit#timestamp". Data without it come first in Asc.
+	FieldTimestamp = "timestamp"
+)
+
+// SortFields returns fields which resources of r can be sorted by.
+func SortFields(r Resource) []string {
+	switch r {
+	case ResourceRuns:
+		return []string{FieldUpdatedAt, FieldStatus}
+	case ResourceData:
+		return []string{FieldTimestamp}
+	}
+	return nil
+}
+
+// SortKey is a key of Sort, in the form "field:asc" or "field:desc".
+type SortKey struct {
+	Field string
+	Order Order
+}
+
+func (k SortKey) Equal(o SortKey) bool {
+	return k.Field == o.Field && k.Order == o.Order
+}
+
+func (k SortKey) String() string {
+	return k.Field + ":" + string(k.Order)
+}
+
+// Sort is the sort specification of list queries, like "updatedAt:desc,status:asc".
+//
+// Resources are sorted by the first key, then by the second key for ties, and so on.
+// Remaining ties are broken by ids of resources, so that all tools order results identically.
+type Sort []SortKey
+
+// ParseSort parses comma separated SortKeys, like "updatedAt:desc,status".
+//
+// The order can be omitted, and then it is Asc.
+// Fields are not checked here; use Sort.Validate.
+func ParseSort(s string) (Sort, error) {
+	ret := Sort{}
+	if strings.TrimSpace(s) == "" {
+		return ret, nil
+	}
+	for _, expr := range strings.Split(s, ",") {
+		field, order, ok := strings.Cut(strings.TrimSpace(expr), ":")
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return nil, fmt.Errorf("sort key should have field: %q", expr)
+		}
+		k := SortKey{Field: field, Order: Asc}
+		if ok {
+			switch o := Order(strings.TrimSpace(order)); o {
+			case Asc, Desc:
+				k.Order = o
+			default:
+				return nil, fmt.Errorf(`sort order should be "%s" or "%s": %q`, Asc, Desc, expr)
+			}
+		}
+		ret = append(ret, k)
+	}
+	return ret, nil
+}
+
+func (s Sort) Equal(o Sort) bool {
+	return apicmp.SliceEqual(s, o)
+}
+
+func (s Sort) String() string {
+	exprs := make([]string, 0, len(s))
+	for _, k := range s {
+		exprs = append(exprs, k.String())
+	}
+	return strings.Join(exprs, ",")
+}
+
+// Validate checks all fields are sortable for the resource r, without duplications,
+// and orders are Asc or Desc.
+func (s Sort) Validate(r Resource) error {
+	fields := SortFields(r)
+	seen := map[string]bool{}
+	for i, k := range s {
+		if !slices.Contains(fields, k.Field) {
+			return fmt.Errorf(`sort[%d]: %s cannot be sorted by %q (should be one of %s)`, i, r, k.Field, strings.Join(fields, ", "))
+		}
+		if seen[k.Field] {
+			return fmt.Errorf(`sort[%d]: duplicated field: %q`, i, k.Field)
+		}
+		seen[k.Field] = true
+		if k.Order != Asc && k.Order != Desc {
+			return fmt.Errorf(`sort[%d]: order should be "%s" or "%s": %q`, i, Asc, Desc, k.Order)
+		}
+	}
+	return nil
+}
+
+// Encode returns the Sort as url.Values. If s is empty, it returns empty url.Values.
+func (s Sort) Encode() url.Values {
+	v := url.Values{}
+	if len(s) != 0 {
+		v.Set(ParamSort, s.String())
+	}
+	return v
+}
+
+// Decode reads the Sort from url.Values.
+//
+// Multiple parameters are concatenated, so "sort=a&sort=b:desc" is same as "sort=a,b:desc".
+func (s *Sort) Decode(v url.Values) error {
+	ret, err := ParseSort(strings.Join(v[ParamSort], ","))
+	if err != nil {
+		return fmt.Errorf(`query parameter "%s": %w`, ParamSort, err)
+	}
+	*s = ret
+	return nil
+}
+
+func (s Sort) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Sort) UnmarshalJSON(b []byte) error {
+	var expr string
+	if err := json.Unmarshal(b, &expr); err != nil {
+		return err
+	}
+	ret, err := ParseSort(expr)
+	if err != nil {
+		return err
+	}
+	*s = ret
+	return nil
+}
+
+func (s Sort) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+func (s *Sort) UnmarshalYAML(n *yaml.Node) error {
+	var expr string
+	if err := n.Decode(&expr); err != nil {
+		return err
+	}
+	ret, err := ParseSort(expr)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	*s = ret
+	return nil
+}
+
+// SortRuns sorts Runs in place by s. Unknown fields are ignored.
+func (s Sort) SortRuns(rs []runs.Summary) {
+	statuses := runs.Statuses()
+	slices.SortStableFunc(rs, func(a, b runs.Summary) int {
+		for _, k := range s {
+			c := 0
+			switch k.Field {
+			case FieldUpdatedAt:
+				c = a.UpdatedAt.Time().Compare(b.UpdatedAt.Time())
+			case FieldStatus:
+				c = cmp.Compare(slices.Index(statuses, a.Status), slices.Index(statuses, b.Status))
+			}
+			if c != 0 {
+				return k.apply(c)
+			}
+		}
+		return cmp.Compare(a.RunId, b.RunId)
+	})
+}
+
+// SortData sorts Data in place by s. Unknown fields are ignored.
+func (s Sort) SortData(ds []data.Detail) {
+	timestamp := func(d data.Detail) time.Time {
+		ts, _ := tags.FindTimestamp(d.Tags)
+		return ts.Time()
+	}
+	slices.SortStableFunc(ds, func(a, b data.Detail) int {
+		for _, k := range s {
+			c := 0
+			switch k.Field {
+			case FieldTimestamp:
+				c = timestamp(a).Compare(timestamp(b))
+			}
+			if c != 0 {
+				return k.apply(c)
+			}
+		}
+		return cmp.Compare(a.KnitId, b.KnitId)
+	})
+}
+
+func (k SortKey) apply(c int) int {
+	if k.Order == Desc {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,88 @@
+package query_test
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/query"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestParseSort(t *testing.T) {
+	got, err := query.ParseSort("updatedAt:desc, status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := query.Sort{{Field: query.FieldUpdatedAt, Order: query.Desc}, {Field: query.FieldStatus, Order: query.Asc}}
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got.String() != "updatedAt:desc,status:asc" {
+		t.Errorf("String: %s", got)
+	}
+	if err := got.Validate(query.ResourceRuns); err != nil {
+		t.Error(err)
+	}
+	if err := got.Validate(query.ResourceData); err == nil {
+		t.Error("runs fields are accepted for data")
+	}
+	knittest.AssertRoundTrip(t, got)
+
+	decoded := query.Sort{}
+	if err := decoded.Decode(url.Values{query.ParamSort: {"updatedAt:desc", "status"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(want) {
+		t.Errorf("Decode: got %v, want %v", decoded, want)
+	}
+	if err := decoded.Decode(got.Encode()); err != nil || !decoded.Equal(want) {
+		t.Errorf("round trip: got %v, %v", decoded, err)
+	}
+
+	for _, expr := range []string{"updatedAt:up", ":desc", "status,"} {
+		if s, err := query.ParseSort(expr); err == nil {
+			t.Errorf("%q is accepted: %v", expr, s)
+		}
+	}
+	if err := (query.Sort{{Field: "status", Order: query.Asc}, {Field: "status", Order: query.Desc}}).Validate(query.ResourceRuns); err == nil {
+		t.Error("duplicated fields are accepted")
+	}
+}
+
+func TestSort_apply(t *testing.T) {
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rs := []runs.Summary{
+		{RunId: "run-c", Status: runs.Done, UpdatedAt: rfctime.RFC3339(base)},
+		{RunId: "run-a", Status: runs.Running, UpdatedAt: rfctime.RFC3339(base.Add(time.Hour))},
+		{RunId: "run-b", Status: runs.Done, UpdatedAt: rfctime.RFC3339(base.Add(time.Hour))},
+	}
+	query.Sort{{Field: query.FieldUpdatedAt, Order: query.Desc}, {Field: query.FieldStatus, Order: query.Asc}}.SortRuns(rs)
+	gotRuns := []runs.RunId{}
+	for _, r := range rs {
+		gotRuns = append(gotRuns, r.RunId)
+	}
+	if want := []runs.RunId{"run-a", "run-b", "run-c"}; !slices.Equal(gotRuns, want) {
+		t.Errorf("runs: got %v, want %v", gotRuns, want)
+	}
+
+	ds := []data.Detail{
+		{KnitId: "knit-b", Tags: []tags.Tag{tags.Timestamp(base)}},
+		{KnitId: "knit-a", Tags: []tags.Tag{tags.Timestamp(base)}},
+		{KnitId: "knit-c", Tags: []tags.Tag{tags.Timestamp(base.Add(-time.Hour))}},
+	}
+	query.Sort{{Field: query.FieldTimestamp, Order: query.Asc}}.SortData(ds)
+	gotData := []knitid.KnitId{}
+	for _, d := range ds {
+		gotData = append(gotData, d.KnitId)
+	}
+	if want := []knitid.KnitId{"knit-c", "knit-a", "knit-b"}; !slices.Equal(gotData, want) {
+		t.Errorf("data: got %v, want %v", gotData, want)
+	}
+}
@@ -0,0 +1,122 @@
+package runs
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/errors"
+)
+
+// BulkOperation is an operation which can be applied to many Runs at once.
+type BulkOperation string
+
+const (
+	// BulkAbort aborts Runs, as PUT /api/runs/{runId}/abort does.
+	BulkAbort BulkOperation = "abort"
+
+	// BulkRetry retries Runs, as PUT /api/runs/{runId}/retry does.
+	BulkRetry BulkOperation = "retry"
+
+	// BulkTearoff tears off Runs, as PUT /api/runs/{runId}/tearoff does.
+	BulkTearoff BulkOperation = "tearoff"
+)
+
+// Valid returns true if op is one of known BulkOperations.
+func (op BulkOperation) Valid() bool {
+	switch op {
+	case BulkAbort, BulkRetry, BulkTearoff:
+		return true
+	}
+	return false
+}
+
+// BulkRequest is the format for request body to Knitfab APIs below:
+//
+// - POST /api/runs/bulk
+//
+// It applies the operation to each Run independently.
+type BulkRequest struct {
+	// Operation is the operation to be applied.
+	Operation BulkOperation `json:"operation"`
+
+	// RunIds are ids of Runs to be operated.
+	RunIds []RunId `json:"runIds"`
+}
+
+func (b BulkRequest) Equal(o BulkRequest) bool {
+	return b.Operation == o.Operation && apicmp.SliceEqEqUnordered(b.RunIds, o.RunIds)
+}
+
+// Validate checks the operation is known, and there are at least one RunIds without duplication.
+func (b BulkRequest) Validate() error {
+	if !b.Operation.Valid() {
+		return fmt.Errorf(`"operation" should be one of "%s", "%s" or "%s": %q`, BulkAbort, BulkRetry, BulkTearoff, b.Operation)
+	}
+	if len(b.RunIds) == 0 {
+		return fmt.Errorf(`"runIds" should have at least one RunId`)
+	}
+	seen := map[RunId]bool{}
+	for i, id := range b.RunIds {
+		if id == "" {
+			return fmt.Errorf("runIds[%d]: should not be empty", i)
+		}
+		if seen[id] {
+			return fmt.Errorf("runIds[%d]: duplicated: %s", i, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// BulkResult is the format for response body from Knitfab APIs below:
+//
+// - POST /api/runs/bulk
+//
+// Each Run is operated independently,
+// so some Runs can be failed even if others are succeeded.
+type BulkResult struct {
+	// Results are the results of each Run, in the order of the request.
+	Results []BulkItemResult `json:"results"`
+}
+
+func (b BulkResult) Equal(o BulkResult) bool {
+	return apicmp.SliceEqual(b.Results, o.Results)
+}
+
+// Failed returns results which have been failed.
+func (b BulkResult) Failed() []BulkItemResult {
+	failed := []BulkItemResult{}
+	for _, r := range b.Results {
+		if !r.Ok() {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// BulkItemResult is the result of the operation for a Run.
+type BulkItemResult struct {
+	// RunId is the id of the Run.
+	RunId RunId `json:"runId"`
+
+	// Status is the status of the Run after the operation.
+	//
+	// This is empty if the operation has been failed.
+	Status Status `json:"status,omitempty"`
+
+	// Error is the reason why the operation has been failed, for example, the Run is already finished.
+	//
+	// This is nil if the operation has been succeeded.
+	Error *errors.ErrorMessage `json:"error,omitempty"`
+}
+
+// Ok returns true if the operation has been succeeded.
+func (r BulkItemResult) Ok() bool {
+	return r.Error == nil
+}
+
+func (r BulkItemResult) Equal(o BulkItemResult) bool {
+	return r.RunId == o.RunId &&
+		r.Status == o.Status &&
+		apicmp.PtrEqual(r.Error, o.Error)
+}
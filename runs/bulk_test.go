@@ -0,0 +1,70 @@
+package runs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/errors"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestBulkRequest_Validate(t *testing.T) {
+	ok := runs.BulkRequest{Operation: runs.BulkAbort, RunIds: []runs.RunId{"0190a1b2-0000-7000-8000-000000000201", "0190a1b2-0000-7000-8000-000000000202"}}
+	if err := ok.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	for name, req := range map[string]runs.BulkRequest{
+		"unknown operation": {Operation: "delete", RunIds: []runs.RunId{"0190a1b2-0000-7000-8000-000000000201"}},
+		"no runs":           {Operation: runs.BulkRetry},
+		"empty id":          {Operation: runs.BulkRetry, RunIds: []runs.RunId{""}},
+		"duplicated":        {Operation: runs.BulkTearoff, RunIds: []runs.RunId{"0190a1b2-0000-7000-8000-000000000201", "0190a1b2-0000-7000-8000-000000000201"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := req.Validate(); err == nil {
+				t.Errorf("invalid request is accepted: %+v", req)
+			}
+		})
+	}
+}
+
+func TestBulkResult(t *testing.T) {
+	payload := `{"results": [
+		{"runId": "0190a1b2-0000-7000-8000-000000000201", "status": "aborting"},
+		{"runId": "0190a1b2-0000-7000-8000-000000000202", "error": {"reason": "run is already done"}}
+	]}`
+	got := runs.BulkResult{}
+	if err := json.Unmarshal([]byte(payload), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := runs.BulkResult{Results: []runs.BulkItemResult{
+		{RunId: "0190a1b2-0000-7000-8000-000000000201", Status: runs.Aborting},
+		{RunId: "0190a1b2-0000-7000-8000-000000000202", Error: &errors.ErrorMessage{Reason: "run is already done"}},
+	}}
+	if !got.Equal(want) {
+		t.Errorf("unexpected result: %+v", got)
+	}
+	if failed := got.Failed(); len(failed) != 1 || failed[0].RunId != "0190a1b2-0000-7000-8000-000000000202" {
+		t.Errorf("unexpected failed: %+v", failed)
+	}
+}
+
+func TestBulkItemResult_Equal_templatedError(t *testing.T) {
+	result := func(params map[string]string) runs.BulkItemResult {
+		return runs.BulkItemResult{
+			RunId: "0190a1b2-0000-7000-8000-000000000202",
+			Error: &errors.ErrorMessage{
+				Reason:   "run is already done",
+				Template: "run is already {status}",
+				Params:   params,
+			},
+		}
+	}
+	if !result(map[string]string{"status": "done"}).Equal(result(map[string]string{"status": "done"})) {
+		t.Error("same results should be equal")
+	}
+	if result(map[string]string{"status": "done"}).Equal(result(map[string]string{"status": "failed"})) {
+		t.Error("results with different params should not be equal")
+	}
+}
@@ -0,0 +1,46 @@
+package runs
+
+import (
+	"github.com/opst/knitfab-api-types/internal/clone"
+)
+
+// Clone returns a deep copy of the Summary.
+func (s Summary) Clone() Summary {
+	return Summary{
+		RunId:     s.RunId,
+		Status:    s.Status,
+		UpdatedAt: s.UpdatedAt,
+		Exit:      clone.Ptr(s.Exit),
+		Plan:      s.Plan.Clone(),
+		Priority:  s.Priority,
+		Project:   s.Project,
+	}
+}
+
+// Clone returns a deep copy of the Detail.
+func (r Detail) Clone() Detail {
+	return Detail{
+		Summary: r.Summary.Clone(),
+		Inputs:  clone.SliceWith(r.Inputs, Assignment.Clone),
+		Outputs: clone.SliceWith(r.Outputs, Assignment.Clone),
+		Log:     clone.PtrWith(r.Log, LogSummary.Clone),
+
+		QueuedAt:   clone.Ptr(r.QueuedAt),
+		StartedAt:  clone.Ptr(r.StartedAt),
+		FinishedAt: clone.Ptr(r.FinishedAt),
+
+		StatusHistory: r.StatusHistory.Clone(),
+		Worker:        clone.PtrWith(r.Worker, Worker.Clone),
+		Extensions:    r.Extensions.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the Assignment.
+func (a Assignment) Clone() Assignment {
+	return Assignment{Mountpoint: a.Mountpoint.Clone(), KnitId: a.KnitId}
+}
+
+// Clone returns a deep copy of the LogSummary.
+func (l LogSummary) Clone() LogSummary {
+	return LogSummary{LogPoint: l.LogPoint.Clone(), KnitId: l.KnitId}
+}
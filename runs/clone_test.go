@@ -0,0 +1,59 @@
+package runs_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestDetail_ToSummary(t *testing.T) {
+	detail := runs.Detail{
+		Summary: runs.Summary{
+			RunId:  "run-1",
+			Status: runs.Failed,
+			Exit:   &runs.Exit{Code: 1, Message: "Error"},
+			Plan: plans.Summary{
+				PlanId:      "plan-1",
+				Image:       &plans.Image{Repository: "repo", Tag: "v1"},
+				Entrypoint:  []string{"python"},
+				Annotations: plans.Annotations{{Key: "owner", Value: "ml-team"}},
+			},
+		},
+		Inputs: []runs.Assignment{{KnitId: "knit-in"}},
+	}
+
+	got := detail.ToSummary()
+	if !got.Equal(detail.Summary) {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+
+	got.Exit.Code = 0
+	got.Plan.Image.Tag = "mutated"
+	got.Plan.Entrypoint[0] = "mutated"
+	got.Plan.Annotations[0].Value = "mutated"
+	if detail.Exit.Code != 1 || detail.Plan.Image.Tag != "v1" ||
+		detail.Plan.Entrypoint[0] != "python" || detail.Plan.Annotations[0].Value != "ml-team" {
+		t.Errorf("summary shares fields with detail: %+v", detail.Summary)
+	}
+}
+
+func TestDetail_Clone_worker(t *testing.T) {
+	detail := runs.Detail{
+		Worker: &runs.Worker{
+			Node:  "node-1",
+			Image: &plans.Image{Repository: "repo", Digest: "sha256:abc"},
+		},
+	}
+
+	got := detail.Clone()
+	if !got.Equal(detail) {
+		t.Fatalf("unexpected clone: %+v", got)
+	}
+
+	got.Worker.Node = "mutated"
+	got.Worker.Image.Digest = "mutated"
+	if detail.Worker.Node != "node-1" || detail.Worker.Image.Digest != "sha256:abc" {
+		t.Errorf("clone shares fields with detail: %+v", detail.Worker)
+	}
+}
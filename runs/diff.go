@@ -0,0 +1,13 @@
+package runs
+
+import "github.com/opst/knitfab-api-types/apidiff"
+
+// Diff returns differences from o, field by field.
+//
+// If r.Equal(o), it returns nil even if their JSON differ, for example, in order of Inputs.
+func (r Detail) Diff(o Detail) []apidiff.FieldDiff {
+	if r.Equal(o) {
+		return nil
+	}
+	return apidiff.Of(r, o)
+}
@@ -0,0 +1,145 @@
+package runs
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+// Query parameter names for FindQuery.
+const (
+	ParamPlan         = "plan"
+	ParamKnitIdInput  = "knitIdInput"
+	ParamKnitIdOutput = "knitIdOutput"
+	ParamStatus       = "status"
+	ParamSince        = "since"
+	ParamUntil        = "until"
+)
+
+// FindQuery is the query parameters for Knitfab APIs below:
+//
+// - GET /api/runs/[?...]
+//
+// Runs matching all of the conditions are found.
+// For conditions with multiple values, Runs matching any of the values are found.
+type FindQuery struct {
+	// PlanIds are ids of Plans which the Runs are based on.
+	PlanIds []plans.PlanId
+
+	// InputKnitIds are ids of Data which the Runs take as input.
+	InputKnitIds []knitid.KnitId
+
+	// OutputKnitIds are ids of Data which the Runs put as output.
+	OutputKnitIds []knitid.KnitId
+
+	// Statuses are statuses of the Runs.
+	Statuses []Status
+
+	// Since is the lower bound (inclusive) of UpdatedAt of the Runs.
+	//
+	// If nil, it is unbounded.
+	Since *rfctime.RFC3339
+
+	// Until is the upper bound (exclusive) of UpdatedAt of the Runs.
+	//
+	// If nil, it is unbounded.
+	Until *rfctime.RFC3339
+}
+
+func (q FindQuery) Equal(o FindQuery) bool {
+	return apicmp.SliceEqEqUnordered(q.PlanIds, o.PlanIds) &&
+		apicmp.SliceEqEqUnordered(q.InputKnitIds, o.InputKnitIds) &&
+		apicmp.SliceEqEqUnordered(q.OutputKnitIds, o.OutputKnitIds) &&
+		apicmp.SliceEqEqUnordered(q.Statuses, o.Statuses) &&
+		apicmp.PtrEqual(q.Since, o.Since) &&
+		apicmp.PtrEqual(q.Until, o.Until)
+}
+
+// Encode returns the query as url.Values.
+func (q FindQuery) Encode() url.Values {
+	v := url.Values{}
+	for _, id := range q.PlanIds {
+		v.Add(ParamPlan, id.String())
+	}
+	for _, id := range q.InputKnitIds {
+		v.Add(ParamKnitIdInput, id.String())
+	}
+	for _, id := range q.OutputKnitIds {
+		v.Add(ParamKnitIdOutput, id.String())
+	}
+	for _, s := range q.Statuses {
+		v.Add(ParamStatus, s.String())
+	}
+	if q.Since != nil {
+		v.Set(ParamSince, q.Since.String())
+	}
+	if q.Until != nil {
+		v.Set(ParamUntil, q.Until.String())
+	}
+	return v
+}
+
+// Decode reads the query from url.Values.
+func (q *FindQuery) Decode(v url.Values) error {
+	ret := FindQuery{}
+
+	for _, expr := range v[ParamPlan] {
+		id, err := plans.ParsePlanId(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s": %w`, ParamPlan, err)
+		}
+		ret.PlanIds = append(ret.PlanIds, id)
+	}
+	for _, p := range []struct {
+		name string
+		dest *[]knitid.KnitId
+	}{
+		{name: ParamKnitIdInput, dest: &ret.InputKnitIds},
+		{name: ParamKnitIdOutput, dest: &ret.OutputKnitIds},
+	} {
+		for _, expr := range v[p.name] {
+			id, err := knitid.ParseKnitId(expr)
+			if err != nil {
+				return fmt.Errorf(`query parameter "%s": %w`, p.name, err)
+			}
+			*p.dest = append(*p.dest, id)
+		}
+	}
+
+	for _, expr := range v[ParamStatus] {
+		var s Status
+		if err := s.Parse(expr); err != nil {
+			return fmt.Errorf(`query parameter "%s": %w`, ParamStatus, err)
+		}
+		ret.Statuses = append(ret.Statuses, s)
+	}
+
+	for _, p := range []struct {
+		name string
+		dest **rfctime.RFC3339
+	}{
+		{name: ParamSince, dest: &ret.Since},
+		{name: ParamUntil, dest: &ret.Until},
+	} {
+		expr := v.Get(p.name)
+		if expr == "" {
+			continue
+		}
+		t, err := rfctime.ParseRFC3339DateTime(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s" should be RFC3339 date-time: %q`, p.name, expr)
+		}
+		*p.dest = &t
+	}
+
+	if ret.Since != nil && ret.Until != nil && ret.Until.Time().Before(ret.Since.Time()) {
+		return fmt.Errorf(`query parameter "%s" should not be before "%s"`, ParamUntil, ParamSince)
+	}
+
+	*q = ret
+	return nil
+}
@@ -0,0 +1,67 @@
+package runs_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestFindQuery(t *testing.T) {
+	since, err := rfctime.ParseRFC3339DateTime("2024-01-01T00:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	until, err := rfctime.ParseRFC3339DateTime("2024-02-01T00:00:00.123+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, q := range map[string]runs.FindQuery{
+		"empty": {},
+		"full": {
+			PlanIds: []plans.PlanId{
+				"0190a1b2-0000-7000-8000-000000000001", "0190a1b2-0000-7000-8000-000000000002",
+			},
+			InputKnitIds: []knitid.KnitId{"0190a1b2-0000-7000-8000-0000000000a1"},
+			OutputKnitIds: []knitid.KnitId{
+				"0190a1b2-0000-7000-8000-0000000000b1", "0190a1b2-0000-7000-8000-0000000000b2",
+			},
+			Statuses: []runs.Status{runs.Running, runs.Failed},
+			Since:    &since,
+			Until:    &until,
+		},
+		"since only": {Since: &since},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := runs.FindQuery{}
+			if err := got.Decode(q.Encode()); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(q) {
+				t.Errorf("round trip: got %+v, want %+v", got, q)
+			}
+		})
+	}
+
+	for name, v := range map[string]url.Values{
+		"unknown status":   {runs.ParamStatus: {"finished"}},
+		"malformed plan":   {runs.ParamPlan: {"plan-1"}},
+		"malformed knitId": {runs.ParamKnitIdInput: {"knit-in"}},
+		"malformed since":  {runs.ParamSince: {"yesterday"}},
+		"reversed range": {
+			runs.ParamSince: {"2024-02-01T00:00:00Z"},
+			runs.ParamUntil: {"2024-01-01T00:00:00Z"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := runs.FindQuery{}
+			if err := q.Decode(v); err == nil {
+				t.Errorf("malformed query is accepted: %+v", q)
+			}
+		})
+	}
+}
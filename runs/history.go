@@ -0,0 +1,96 @@
+package runs
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// StatusTransition is a change of the status of a Run.
+type StatusTransition struct {
+	// Status is the status which the Run has changed to.
+	Status Status `json:"status" yaml:"status"`
+
+	// At is the time of the change.
+	At rfctime.RFC3339 `json:"at" yaml:"at"`
+
+	// Message is the human readable reason of the change, if any.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func (t StatusTransition) Equal(o StatusTransition) bool {
+	return t.Status == o.Status && t.At.Equal(o.At) && t.Message == o.Message
+}
+
+// StatusHistory is transitions of the status of a Run, in chronological order.
+type StatusHistory []StatusTransition
+
+// Equal returns true if h and o have the same transitions, in the same order.
+func (h StatusHistory) Equal(o StatusHistory) bool {
+	return apicmp.SliceEqual(h, o)
+}
+
+// Clone returns a copy of the StatusHistory.
+func (h StatusHistory) Clone() StatusHistory {
+	return slices.Clone(h)
+}
+
+// Validate checks statuses are known and transitions are in chronological order.
+func (h StatusHistory) Validate() error {
+	for i, t := range h {
+		if !t.Status.Valid() {
+			return fmt.Errorf("[%d]: unknown run status: %q", i, t.Status)
+		}
+		if 0 < i && t.At.Time().Before(h[i-1].At.Time()) {
+			return fmt.Errorf("[%d]: not in chronological order: %s is before %s", i, t.At, h[i-1].At)
+		}
+	}
+	return nil
+}
+
+// Current returns the last transition.
+//
+// If h is empty, it returns false.
+func (h StatusHistory) Current() (StatusTransition, bool) {
+	if len(h) == 0 {
+		return StatusTransition{}, false
+	}
+	return h[len(h)-1], true
+}
+
+// TimeIn returns how long the Run has been in the status, in total.
+//
+// If the Run is still in the status (and it is not terminal), the time until now is included.
+func (h StatusHistory) TimeIn(status Status, now time.Time) time.Duration {
+	var total time.Duration
+	for i, t := range h {
+		if t.Status != status {
+			continue
+		}
+		switch {
+		case i+1 < len(h):
+			total += h[i+1].At.Time().Sub(t.At.Time())
+		case !status.IsTerminal():
+			total += now.Sub(t.At.Time())
+		}
+	}
+	return total
+}
+
+// History is the format for response body from WebAPIs below:
+//
+// - GET /api/runs/{runId}/history
+type History struct {
+	// RunId is the id of the Run.
+	RunId RunId `json:"runId" yaml:"runId"`
+
+	// Transitions are transitions of the status of the Run, in chronological order.
+	Transitions StatusHistory `json:"transitions" yaml:"transitions"`
+}
+
+func (h History) Equal(o History) bool {
+	return h.RunId == o.RunId && h.Transitions.Equal(o.Transitions)
+}
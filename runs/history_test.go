@@ -0,0 +1,76 @@
+package runs_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestStatusHistory(t *testing.T) {
+	at := func(s string) rfctime.RFC3339 {
+		t.Helper()
+		ts, err := rfctime.ParseRFC3339DateTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	history := runs.StatusHistory{
+		{Status: runs.Waiting, At: at("2024-01-02T03:00:00+09:00")},
+		{Status: runs.Starting, At: at("2024-01-02T03:01:00+09:00")},
+		{Status: runs.Running, At: at("2024-01-02T03:04:00+09:00")},
+		{Status: runs.Starting, At: at("2024-01-02T03:10:00+09:00"), Message: "worker restarted"},
+		{Status: runs.Running, At: at("2024-01-02T03:12:00+09:00")},
+	}
+	now := at("2024-01-02T03:20:00+09:00").Time()
+
+	if err := history.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := history.TimeIn(runs.Starting, now); got != 5*time.Minute {
+		t.Errorf("TimeIn(starting): %s", got)
+	}
+	if got := history.TimeIn(runs.Running, now); got != 14*time.Minute {
+		t.Errorf("TimeIn(running): %s", got)
+	}
+	if got := history.TimeIn(runs.Done, now); got != 0 {
+		t.Errorf("TimeIn(done): %s", got)
+	}
+	if cur, ok := history.Current(); !ok || cur.Status != runs.Running {
+		t.Errorf("Current: (%+v, %v)", cur, ok)
+	}
+
+	done := append(history.Clone(), runs.StatusTransition{Status: runs.Done, At: at("2024-01-02T03:15:00+09:00")})
+	if got := done.TimeIn(runs.Done, now); got != 0 {
+		t.Errorf("TimeIn(done) of terminal status: %s", got)
+	}
+
+	reordered := history.Clone()
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+	if reordered.Equal(history) {
+		t.Error("histories in different order are equal")
+	}
+	if err := reordered.Validate(); err == nil {
+		t.Error("history not in chronological order is valid")
+	}
+
+	knittest.AssertRoundTrip(t, runs.History{RunId: "0190a1b2-0000-7000-8000-000000000201", Transitions: history})
+
+	b, err := json.Marshal(runs.Detail{StatusHistory: history[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := struct {
+		StatusHistory []map[string]any `json:"statusHistory"`
+	}{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.StatusHistory) != 1 || got.StatusHistory[0]["status"] != "waiting" {
+		t.Errorf("unexpected json: %s", b)
+	}
+}
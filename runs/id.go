@@ -0,0 +1,54 @@
+package runs
+
+import (
+	"github.com/opst/knitfab-api-types/internal/ids"
+	"gopkg.in/yaml.v3"
+)
+
+// RunId identifies Runs.
+type RunId string
+
+// ParseRunId parses s as RunId.
+func ParseRunId(s string) (RunId, error) {
+	if err := ids.Validate("RunId", s); err != nil {
+		return "", err
+	}
+	return RunId(s), nil
+}
+
+func (id RunId) String() string {
+	return string(id)
+}
+
+// IsZero returns true if id is empty.
+func (id RunId) IsZero() bool {
+	return id == ""
+}
+
+// Parse parses s as RunId, and sets it to id.
+func (id *RunId) Parse(s string) error {
+	parsed, err := ParseRunId(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+func (id *RunId) UnmarshalJSON(b []byte) error {
+	s, err := ids.UnmarshalJSON("RunId", b)
+	if err != nil {
+		return err
+	}
+	*id = RunId(s)
+	return nil
+}
+
+func (id *RunId) UnmarshalYAML(node *yaml.Node) error {
+	s, err := ids.UnmarshalYAML("RunId", node)
+	if err != nil {
+		return err
+	}
+	*id = RunId(s)
+	return nil
+}
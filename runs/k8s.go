@@ -0,0 +1,31 @@
+package runs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ReasonOOMKilled is the reason of containers killed for running out of memory.
+const ReasonOOMKilled = "OOMKilled"
+
+// ExitFromK8s makes Exit from the terminated state of the container of a Worker.
+//
+// Exit codes out of 0-255 are reported as 255.
+// If the state has no signal but the exit code is 128+N (N < 32),
+// the container is regarded as terminated by the signal N, as shells report.
+func ExitFromK8s(state corev1.ContainerStateTerminated) Exit {
+	code := state.ExitCode
+	if code < 0 || 255 < code {
+		code = 255
+	}
+	signal := state.Signal
+	if signal == 0 && 128 < code && code < 128+32 {
+		signal = code - 128
+	}
+	return Exit{
+		Code:      uint8(code),
+		Message:   state.Message,
+		Signal:    signal,
+		OOMKilled: state.Reason == ReasonOOMKilled,
+		Reason:    state.Reason,
+	}
+}
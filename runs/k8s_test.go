@@ -0,0 +1,42 @@
+package runs_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/runs"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestExitFromK8s(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		when corev1.ContainerStateTerminated
+		then runs.Exit
+	}{
+		"completed": {
+			when: corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"},
+			then: runs.Exit{Code: 0, Reason: "Completed"},
+		},
+		"error": {
+			when: corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "failed to load"},
+			then: runs.Exit{Code: 1, Reason: "Error", Message: "failed to load"},
+		},
+		"oom killed": {
+			when: corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+			then: runs.Exit{Code: 137, Signal: 9, OOMKilled: true, Reason: "OOMKilled"},
+		},
+		"killed by signal": {
+			when: corev1.ContainerStateTerminated{ExitCode: 143, Signal: 15, Reason: "Error"},
+			then: runs.Exit{Code: 143, Signal: 15, Reason: "Error"},
+		},
+		"out of range": {
+			when: corev1.ContainerStateTerminated{ExitCode: -1, Reason: "ContainerCannotRun"},
+			then: runs.Exit{Code: 255, Reason: "ContainerCannotRun"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := runs.ExitFromK8s(testcase.when); !got.Equal(testcase.then) {
+				t.Errorf("got %+v, want %+v", got, testcase.then)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package runs
+
+import "github.com/opst/knitfab-api-types/meta"
+
+// KindRunDetail is the kind of Detail, for meta.TypeMeta.
+const KindRunDetail = "RunDetail"
+
+// AddToScheme registers kinds of this package to s.
+func AddToScheme(s *meta.Scheme) error {
+	return meta.Register[Detail](s, KindRunDetail)
+}
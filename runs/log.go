@@ -0,0 +1,214 @@
+package runs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Query parameter names for LogQuery.
+//
+// "since" is shared with FindQuery, as ParamSince.
+const (
+	ParamFollow     = "follow"
+	ParamTail       = "tail"
+	ParamTimestamps = "timestamps"
+	ParamFormat     = "format"
+)
+
+// LogFormat is the format of log streams.
+type LogFormat string
+
+const (
+	// LogFormatText streams log lines as they are (Content-Type: text/plain).
+	LogFormatText LogFormat = "text"
+
+	// LogFormatJSONL streams LogChunks, one JSON object per line (Content-Type: application/jsonl).
+	LogFormatJSONL LogFormat = "jsonl"
+)
+
+// Valid returns true if f is one of known LogFormats. Empty means LogFormatText.
+func (f LogFormat) Valid() bool {
+	switch f {
+	case "", LogFormatText, LogFormatJSONL:
+		return true
+	}
+	return false
+}
+
+// LogQuery is the query parameters for Knitfab APIs below:
+//
+// - GET /api/runs/{runId}/log
+type LogQuery struct {
+	// Follow keeps the stream open until the Run finishes, streaming new lines.
+	Follow bool
+
+	// Tail is the number of lines from the end of the log to be sent.
+	//
+	// If nil, all lines are sent.
+	Tail *int
+
+	// Since is the lower bound (inclusive) of the time of lines to be sent.
+	//
+	// If nil, it is unbounded.
+	Since *rfctime.RFC3339
+
+	// Timestamps prefixes lines (for LogFormatText) or sets LogChunk.At (for LogFormatJSONL) with their time.
+	Timestamps bool
+
+	// Format is the format of the stream. If empty, it is LogFormatText.
+	Format LogFormat
+}
+
+func (q LogQuery) Equal(o LogQuery) bool {
+	return q.Follow == o.Follow &&
+		apicmp.PtrEqEq(q.Tail, o.Tail) &&
+		apicmp.PtrEqual(q.Since, o.Since) &&
+		q.Timestamps == o.Timestamps &&
+		q.Format == o.Format
+}
+
+// Encode returns the query as url.Values.
+func (q LogQuery) Encode() url.Values {
+	v := url.Values{}
+	if q.Follow {
+		v.Set(ParamFollow, "true")
+	}
+	if q.Tail != nil {
+		v.Set(ParamTail, strconv.Itoa(*q.Tail))
+	}
+	if q.Since != nil {
+		v.Set(ParamSince, q.Since.String())
+	}
+	if q.Timestamps {
+		v.Set(ParamTimestamps, "true")
+	}
+	if q.Format != "" {
+		v.Set(ParamFormat, string(q.Format))
+	}
+	return v
+}
+
+// Decode reads the query from url.Values.
+//
+// Flags without values, like "?follow", are true.
+func (q *LogQuery) Decode(v url.Values) error {
+	ret := LogQuery{}
+
+	for _, p := range []struct {
+		name string
+		dest *bool
+	}{
+		{name: ParamFollow, dest: &ret.Follow},
+		{name: ParamTimestamps, dest: &ret.Timestamps},
+	} {
+		if !v.Has(p.name) {
+			continue
+		}
+		expr := v.Get(p.name)
+		if expr == "" {
+			*p.dest = true
+			continue
+		}
+		b, err := strconv.ParseBool(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s" should be boolean: %q`, p.name, expr)
+		}
+		*p.dest = b
+	}
+
+	if expr := v.Get(ParamTail); expr != "" {
+		n, err := strconv.Atoi(expr)
+		if err != nil || n < 0 {
+			return fmt.Errorf(`query parameter "%s" should be a non-negative integer: %q`, ParamTail, expr)
+		}
+		ret.Tail = &n
+	}
+
+	if expr := v.Get(ParamSince); expr != "" {
+		t, err := rfctime.ParseRFC3339DateTime(expr)
+		if err != nil {
+			return fmt.Errorf(`query parameter "%s" should be RFC3339 date-time: %q`, ParamSince, expr)
+		}
+		ret.Since = &t
+	}
+
+	ret.Format = LogFormat(v.Get(ParamFormat))
+	if !ret.Format.Valid() {
+		return fmt.Errorf(`query parameter "%s" should be "%s" or "%s": %q`, ParamFormat, LogFormatText, LogFormatJSONL, ret.Format)
+	}
+
+	*q = ret
+	return nil
+}
+
+// LogStream is the stream where a log line is written.
+type LogStream string
+
+const (
+	Stdout LogStream = "stdout"
+	Stderr LogStream = "stderr"
+)
+
+// LogChunk is a frame of log streams in LogFormatJSONL.
+type LogChunk struct {
+	// At is the time when the line is written.
+	//
+	// This is set only when LogQuery.Timestamps is true.
+	At *rfctime.RFC3339Nano `json:"at,omitempty"`
+
+	// Stream is the stream where the line is written. It can be empty if unknown.
+	Stream LogStream `json:"stream,omitempty"`
+
+	// Line is the log line, without the trailing newline.
+	Line string `json:"line"`
+
+	// End is true for the last frame of the stream, which has no line.
+	//
+	// If the stream is closed without the End frame, it is broken, and clients may retry.
+	End bool `json:"end,omitempty"`
+}
+
+func (c LogChunk) Equal(o LogChunk) bool {
+	return apicmp.PtrEqual(c.At, o.At) &&
+		c.Stream == o.Stream &&
+		c.Line == o.Line &&
+		c.End == o.End
+}
+
+// WriteLogChunk writes the LogChunk into w as a line of JSONL.
+func WriteLogChunk(w io.Writer, c LogChunk) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// ScanLogChunks reads a stream in LogFormatJSONL from r, and calls fn with each LogChunk.
+//
+// Empty lines are skipped. It stops at a malformed line, or when fn returns error.
+func ScanLogChunks(r io.Reader, fn func(LogChunk) error) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; s.Scan(); n++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		c := LogChunk{}
+		if err := json.Unmarshal(s.Bytes(), &c); err != nil {
+			return fmt.Errorf("line %d: malformed log chunk: %w", n, err)
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
@@ -0,0 +1,114 @@
+package runs_test
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestLogQuery(t *testing.T) {
+	since, err := rfctime.ParseRFC3339DateTime("2024-01-01T00:00:00+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tail := 100
+	zero := 0
+
+	for name, q := range map[string]runs.LogQuery{
+		"empty": {},
+		"full": {
+			Follow:     true,
+			Tail:       &tail,
+			Since:      &since,
+			Timestamps: true,
+			Format:     runs.LogFormatJSONL,
+		},
+		"tail 0": {Tail: &zero},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := runs.LogQuery{}
+			if err := got.Decode(q.Encode()); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(q) {
+				t.Errorf("round trip: got %+v, want %+v", got, q)
+			}
+		})
+	}
+
+	t.Run("flags without values are true", func(t *testing.T) {
+		v, err := url.ParseQuery("follow&timestamps=false")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := runs.LogQuery{}
+		if err := got.Decode(v); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Follow || got.Timestamps {
+			t.Errorf("unexpected query: %+v", got)
+		}
+	})
+
+	for name, v := range map[string]url.Values{
+		"malformed follow": {runs.ParamFollow: {"yes please"}},
+		"negative tail":    {runs.ParamTail: {"-1"}},
+		"malformed tail":   {runs.ParamTail: {"all"}},
+		"malformed since":  {runs.ParamSince: {"yesterday"}},
+		"unknown format":   {runs.ParamFormat: {"xml"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			q := runs.LogQuery{}
+			if err := q.Decode(v); err == nil {
+				t.Errorf("malformed query is accepted: %+v", q)
+			}
+		})
+	}
+}
+
+func TestLogChunk(t *testing.T) {
+	at, err := rfctime.ParseRFC3339NanoDateTime("2024-01-02T03:04:05.123456789+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := []runs.LogChunk{
+		{At: &at, Stream: runs.Stdout, Line: "epoch 1"},
+		{Stream: runs.Stderr, Line: `warning: "lr" is large`},
+		{End: true},
+	}
+
+	buf := &bytes.Buffer{}
+	for _, c := range chunks {
+		if err := runs.WriteLogChunk(buf, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), `{"at":"2024-01-02T03:04:05.123456789+09:00","stream":"stdout","line":"epoch 1"}`+"\n") {
+		t.Errorf("unexpected stream:\n%s", buf)
+	}
+
+	got := []runs.LogChunk{}
+	if err := runs.ScanLogChunks(buf, func(c runs.LogChunk) error {
+		got = append(got, c)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(chunks) {
+		t.Fatalf("unexpected chunks: %+v", got)
+	}
+	for i := range chunks {
+		if !got[i].Equal(chunks[i]) {
+			t.Errorf("chunks[%d]: got %+v, want %+v", i, got[i], chunks[i])
+		}
+	}
+
+	err = runs.ScanLogChunks(strings.NewReader("{\"line\":\"ok\"}\n\nnot json\n"), func(runs.LogChunk) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
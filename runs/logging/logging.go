@@ -0,0 +1,179 @@
+// Package logging defines a convention to embed structured markers in logs of Runs.
+//
+// A marker is a single line in the standard output or standard error of a Worker:
+//
+//	@knit/<kind> <JSON>
+//
+// For example,
+//
+//	@knit/progress {"current":30,"total":100,"message":"epoch 3"}
+//	@knit/metrics {"step":3,"values":{"loss":0.12,"accuracy":0.97}}
+//	@knit/checkpoint {"name":"epoch-3","path":"/out/model/epoch-3.pt"}
+//
+// Markers are recorded into the log Data as they are, so that
+// clients can extract structured signals from the existing log stream.
+// Lines which are not markers are ordinary log lines.
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+)
+
+// Prefix is the prefix of marker lines.
+const Prefix = "@knit/"
+
+// Kind is the kind of markers.
+type Kind string
+
+const (
+	KindProgress   Kind = "progress"
+	KindMetrics    Kind = "metrics"
+	KindCheckpoint Kind = "checkpoint"
+)
+
+// Marker is a structured signal embedded in logs.
+type Marker interface {
+	// Kind returns the kind of the marker.
+	Kind() Kind
+}
+
+// Progress reports how far the Worker has processed.
+type Progress struct {
+	// Current is the amount of processed work.
+	Current int64 `json:"current"`
+
+	// Total is the total amount of work. If 0, it is unknown.
+	Total int64 `json:"total,omitempty"`
+
+	// Message is the human readable description of the progress.
+	Message string `json:"message,omitempty"`
+}
+
+func (Progress) Kind() Kind { return KindProgress }
+
+func (p Progress) Equal(o Progress) bool {
+	return p.Current == o.Current && p.Total == o.Total && p.Message == o.Message
+}
+
+// Ratio returns the ratio of progress in [0, 1].
+//
+// If Total is unknown, it returns false.
+func (p Progress) Ratio() (float64, bool) {
+	if p.Total <= 0 {
+		return 0, false
+	}
+	return float64(p.Current) / float64(p.Total), true
+}
+
+// Metrics reports numeric values observed by the Worker, like loss or accuracy.
+type Metrics struct {
+	// Step is the step (or epoch, iteration) when the values are observed.
+	Step *int64 `json:"step,omitempty"`
+
+	// Values are the observed values by name.
+	Values map[string]float64 `json:"values"`
+}
+
+func (Metrics) Kind() Kind { return KindMetrics }
+
+func (m Metrics) Equal(o Metrics) bool {
+	return apicmp.PtrEqEq(m.Step, o.Step) &&
+		apicmp.MapEqualWith(m.Values, o.Values, func(a, b float64) bool { return a == b })
+}
+
+// Checkpoint reports that the Worker has saved its intermediate state.
+type Checkpoint struct {
+	// Name is the name of the checkpoint.
+	Name string `json:"name"`
+
+	// Path is the path where the checkpoint is saved, typically in an output mountpoint.
+	Path string `json:"path,omitempty"`
+}
+
+func (Checkpoint) Kind() Kind { return KindCheckpoint }
+
+func (c Checkpoint) Equal(o Checkpoint) bool {
+	return c.Name == o.Name && c.Path == o.Path
+}
+
+// Format returns the marker line for m, without a trailing newline.
+func Format(m Marker) (string, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + string(m.Kind()) + " " + string(b), nil
+}
+
+// Emit writes the marker line for m into w.
+func Emit(w io.Writer, m Marker) error {
+	line, err := Format(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, line)
+	return err
+}
+
+// Parse reads a marker from a log line.
+//
+// # Returns
+//
+// - Marker: the marker in the line. It is one of Progress, Metrics or Checkpoint.
+//
+// - bool: true if the line is a marker line. If false, the line is an ordinary log line.
+//
+// - error: if the line is a marker line but malformed.
+func Parse(line string) (Marker, bool, error) {
+	line = strings.TrimRight(line, "\r\n")
+	rest, ok := strings.CutPrefix(line, Prefix)
+	if !ok {
+		return nil, false, nil
+	}
+	kind, payload, _ := strings.Cut(rest, " ")
+
+	var m Marker
+	var err error
+	switch Kind(kind) {
+	case KindProgress:
+		p := Progress{}
+		err = json.Unmarshal([]byte(payload), &p)
+		m = p
+	case KindMetrics:
+		mt := Metrics{}
+		err = json.Unmarshal([]byte(payload), &mt)
+		m = mt
+	case KindCheckpoint:
+		c := Checkpoint{}
+		err = json.Unmarshal([]byte(payload), &c)
+		m = c
+	default:
+		return nil, true, fmt.Errorf("unknown marker kind: %q", kind)
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("malformed %s marker: %w", kind, err)
+	}
+	return m, true, nil
+}
+
+// Scan reads logs from r, and calls fn with each marker found.
+//
+// Ordinary log lines and malformed markers are skipped.
+func Scan(r io.Reader, fn func(Marker)) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		m, ok, err := Parse(s.Text())
+		if !ok || err != nil {
+			continue
+		}
+		fn(m)
+	}
+	return s.Err()
+}
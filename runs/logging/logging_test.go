@@ -0,0 +1,90 @@
+package logging_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/runs/logging"
+)
+
+func TestEmitAndScan(t *testing.T) {
+	step := int64(3)
+	markers := []logging.Marker{
+		logging.Progress{Current: 30, Total: 100, Message: "epoch 3"},
+		logging.Metrics{Step: &step, Values: map[string]float64{"loss": 0.12}},
+		logging.Checkpoint{Name: "epoch-3", Path: "/out/model/epoch-3.pt"},
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("starting training\n")
+	for _, m := range markers {
+		if err := logging.Emit(buf, m); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteString("ordinary line\n")
+	}
+	buf.WriteString("@knit/progress {broken\n")
+
+	got := []logging.Marker{}
+	if err := logging.Scan(buf, func(m logging.Marker) { got = append(got, m) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(markers) {
+		t.Fatalf("unexpected markers: %+v", got)
+	}
+	if p, ok := got[0].(logging.Progress); !ok || !p.Equal(markers[0].(logging.Progress)) {
+		t.Errorf("unexpected progress: %+v", got[0])
+	}
+	if m, ok := got[1].(logging.Metrics); !ok || !m.Equal(markers[1].(logging.Metrics)) {
+		t.Errorf("unexpected metrics: %+v", got[1])
+	}
+	if c, ok := got[2].(logging.Checkpoint); !ok || !c.Equal(markers[2].(logging.Checkpoint)) {
+		t.Errorf("unexpected checkpoint: %+v", got[2])
+	}
+}
+
+func TestFormat(t *testing.T) {
+	line, err := logging.Format(logging.Progress{Current: 1, Total: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `@knit/progress {"current":1,"total":4}`; line != want {
+		t.Errorf("unexpected line: %s", line)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for name, line := range map[string]string{
+		"ordinary":    "hello world",
+		"similar":     "@knitfab/progress {}",
+		"indentation": " @knit/progress {}",
+	} {
+		t.Run("not a marker: "+name, func(t *testing.T) {
+			if _, ok, err := logging.Parse(line); ok || err != nil {
+				t.Errorf("unexpected result: ok = %v, err = %v", ok, err)
+			}
+		})
+	}
+
+	for name, line := range map[string]string{
+		"unknown kind": `@knit/heartbeat {}`,
+		"bad json":     `@knit/metrics {"values":`,
+		"no payload":   `@knit/checkpoint`,
+	} {
+		t.Run("malformed: "+name, func(t *testing.T) {
+			if _, ok, err := logging.Parse(line); !ok || err == nil {
+				t.Errorf("unexpected result: ok = %v, err = %v", ok, err)
+			}
+		})
+	}
+
+	m, ok, err := logging.Parse(strings.Join([]string{`@knit/progress {"current":5}`, ""}, "\r\n"))
+	if !ok || err != nil {
+		t.Fatalf("unexpected result: ok = %v, err = %v", ok, err)
+	}
+	if r, known := m.(logging.Progress).Ratio(); known {
+		t.Errorf("ratio should be unknown: %v", r)
+	}
+}
@@ -0,0 +1,89 @@
+package runs
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metrics is the format for response body from Knitfab APIs below:
+//
+// - GET /api/runs/{runId}/metrics
+//
+// It is the resource usage of a Run, measured on its worker.
+// Metrics of running Runs are the usage so far.
+type Metrics struct {
+	// RunId is the id of the Run.
+	RunId RunId `json:"runId" yaml:"runId"`
+
+	// MaxMemoryBytes is the peak memory usage in bytes.
+	MaxMemoryBytes int64 `json:"maxMemoryBytes" yaml:"maxMemoryBytes"`
+
+	// CPUSeconds is the CPU time used, in seconds.
+	CPUSeconds float64 `json:"cpuSeconds" yaml:"cpuSeconds"`
+
+	// GPUSeconds is the GPU time used, in seconds.
+	//
+	// It is 0 for Runs without GPUs.
+	GPUSeconds float64 `json:"gpuSeconds,omitempty" yaml:"gpuSeconds,omitempty"`
+
+	// BytesRead is the total size read from inputs, in bytes.
+	BytesRead int64 `json:"bytesRead" yaml:"bytesRead"`
+
+	// BytesWritten is the total size written to outputs and log, in bytes.
+	BytesWritten int64 `json:"bytesWritten" yaml:"bytesWritten"`
+}
+
+func (m Metrics) Equal(o Metrics) bool {
+	return m.RunId == o.RunId &&
+		m.MaxMemoryBytes == o.MaxMemoryBytes &&
+		m.CPUSeconds == o.CPUSeconds &&
+		m.GPUSeconds == o.GPUSeconds &&
+		m.BytesRead == o.BytesRead &&
+		m.BytesWritten == o.BytesWritten
+}
+
+// Validate checks all values are not negative.
+func (m Metrics) Validate() error {
+	for _, f := range []struct {
+		field string
+		value float64
+	}{
+		{field: "maxMemoryBytes", value: float64(m.MaxMemoryBytes)},
+		{field: "cpuSeconds", value: m.CPUSeconds},
+		{field: "gpuSeconds", value: m.GPUSeconds},
+		{field: "bytesRead", value: float64(m.BytesRead)},
+		{field: "bytesWritten", value: float64(m.BytesWritten)},
+	} {
+		if f.value < 0 {
+			return fmt.Errorf("%s: should not be negative: %v", f.field, f.value)
+		}
+	}
+	return nil
+}
+
+// AverageCPU returns the average number of CPU cores used during elapsed,
+// like 1.5 for 90 CPU seconds in a minute.
+//
+// It returns 0 if elapsed is not positive.
+func (m Metrics) AverageCPU(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return m.CPUSeconds / elapsed.Seconds()
+}
+
+// Aggregate sums up Metrics of Runs, for capacity planning over many Runs.
+//
+// MaxMemoryBytes of the result is the largest one, and the others are totals.
+// RunId of the result is empty.
+func Aggregate(ms ...Metrics) Metrics {
+	ret := Metrics{}
+	for _, m := range ms {
+		ret.MaxMemoryBytes = max(ret.MaxMemoryBytes, m.MaxMemoryBytes)
+		ret.CPUSeconds += m.CPUSeconds
+		ret.GPUSeconds += m.GPUSeconds
+		ret.BytesRead += m.BytesRead
+		ret.BytesWritten += m.BytesWritten
+	}
+	return ret
+}
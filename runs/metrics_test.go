@@ -0,0 +1,41 @@
+package runs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestMetrics(t *testing.T) {
+	m := runs.Metrics{
+		RunId:          "0190a1b2-0000-7000-8000-000000000201",
+		MaxMemoryBytes: 2 << 30,
+		CPUSeconds:     90.5,
+		GPUSeconds:     30,
+		BytesRead:      1 << 20,
+		BytesWritten:   4 << 20,
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, m)
+
+	if got := m.AverageCPU(time.Minute); got < 1.5 || 1.51 < got {
+		t.Errorf("AverageCPU: %v", got)
+	}
+	if got := m.AverageCPU(0); got != 0 {
+		t.Errorf("AverageCPU(0): %v", got)
+	}
+
+	other := runs.Metrics{RunId: "0190a1b2-0000-7000-8000-000000000202", MaxMemoryBytes: 1 << 30, CPUSeconds: 9.5, BytesRead: 1 << 20}
+	want := runs.Metrics{MaxMemoryBytes: 2 << 30, CPUSeconds: 100, GPUSeconds: 30, BytesRead: 2 << 20, BytesWritten: 4 << 20}
+	if got := runs.Aggregate(m, other); !got.Equal(want) {
+		t.Errorf("Aggregate: got %+v, want %+v", got, want)
+	}
+
+	if err := (runs.Metrics{CPUSeconds: -1}).Validate(); err == nil {
+		t.Error("negative metrics is accepted")
+	}
+}
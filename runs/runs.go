@@ -1,73 +1,79 @@
 package runs
 
 import (
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"time"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/knitid"
 	"github.com/opst/knitfab-api-types/misc/rfctime"
 	"github.com/opst/knitfab-api-types/plans"
 )
 
 type Summary struct {
 	// RunId is the id of the Run.
-	RunId string `json:"runId"`
+	RunId RunId `json:"runId" yaml:"runId"`
 
 	// Status is the status of the Run.
 	//
-	// This is one of:
-	//
-	// - "deactivated": This Run is deactivated. It is not going to be running.
-	//
-	// - "waiting": This Run is waiting to be running.
-	//
-	// - "ready": This Run is ready to be running. It is waiting for the worker starts.
-	//
-	// - "starting": This Run is pulling images, or preparing the environment.
-	// The Worker for the Run can be running because of the interval of the periodical health check.
-	//
-	// - "running": This Run's Worker is running.
-	//
-	// - "completing": It is observed that the run's worker has stopped successfully.
-	//
-	// - "aborting": It is observed, or should be done that the run's worker has stopped insuccessfully.
-	//
-	// - "done": This Run has been finished, successfuly.
-	// The Run's output can be used by other Runs.
-	//
-	// - "failed": This Run has been finished with error.
-	//
-	// - "invalidated": This run was discarded
-	Status string `json:"status"`
+	// See Status for its values.
+	Status Status `json:"status" yaml:"status"`
 
 	// UpdatedAt is the time of the last update of the Run.
-	UpdatedAt rfctime.RFC3339 `json:"updatedAt"`
+	UpdatedAt rfctime.RFC3339 `json:"updatedAt" yaml:"updatedAt"`
 
 	// Exit is the exit status of the Run.
 	//
 	// This is nil if the Run is not finished.
-	Exit *Exit `json:"exit,omitempty"`
+	Exit *Exit `json:"exit,omitempty" yaml:"exit,omitempty"`
 
 	// Plan which the Run is created from.
-	Plan plans.Summary `json:"plan"`
-}
+	Plan plans.Summary `json:"plan" yaml:"plan"`
 
-func (s Summary) Equal(o Summary) bool {
+	// Priority is the priority class of the Run, inherited from its Plan.
+	//
+	// If empty, the Run has plans.PriorityNormal.
+	Priority plans.Priority `json:"priority,omitempty" yaml:"priority,omitempty"`
 
-	exitEq := (s.Exit == nil && o.Exit == nil) ||
-		(s.Exit != nil && o.Exit != nil && s.Exit.Equal(*o.Exit))
+	// Project is the name of the Project which the Run belongs to, inherited from its Plan.
+	//
+	// If empty, the Run is not scoped to any Project.
+	Project string `json:"project,omitempty" yaml:"project,omitempty"`
+}
 
+func (s Summary) Equal(o Summary) bool {
 	return s.RunId == o.RunId &&
-		exitEq &&
+		apicmp.PtrEqual(s.Exit, o.Exit) &&
 		s.Plan.Equal(o.Plan) &&
 		s.Status == o.Status &&
-		s.UpdatedAt.Equal(o.UpdatedAt)
+		s.UpdatedAt.Equal(o.UpdatedAt) &&
+		s.Priority == o.Priority &&
+		s.Project == o.Project
 }
 
 type Exit struct {
-	Code    uint8  `json:"code"`
-	Message string `json:"message"`
+	Code    uint8  `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+
+	// Signal is the number of the signal which has terminated the container, like 9 for SIGKILL.
+	//
+	// This is 0 if the container exited by itself, or it is unknown.
+	Signal int32 `json:"signal,omitempty" yaml:"signal,omitempty"`
+
+	// OOMKilled is true if the container has been killed for running out of memory.
+	OOMKilled bool `json:"oomKilled,omitempty" yaml:"oomKilled,omitempty"`
+
+	// Reason is the brief reason of the termination of the container,
+	// like "Completed", "Error", "OOMKilled" or "DeadlineExceeded".
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
 }
 
 func (e Exit) Equal(o Exit) bool {
-	return e.Code == o.Code && e.Message == o.Message
+	return e.Code == o.Code &&
+		e.Message == o.Message &&
+		e.Signal == o.Signal &&
+		e.OOMKilled == o.OOMKilled &&
+		e.Reason == o.Reason
 }
 
 // Detail is the format for response body from WebAPIs below:
@@ -86,39 +92,109 @@ func (e Exit) Equal(o Exit) bool {
 //
 // Other Run related WebAPI do not use this for response.
 //
-// - GET    /api/runs/{runId}/log: text stream (Content-Type: text/plain)
+// - GET    /api/runs/{runId}/log: text stream (Content-Type: text/plain), or LogChunks in JSONL. See LogQuery.
 //
 // - DELETE /api/runs/{runId}: empty response ("204 No Content" on success)
 type Detail struct {
-	Summary
+	Summary `yaml:",inline"`
 
 	// Inputs are pairs of input mountpoints and inputted Data of the Run.
-	Inputs []Assignment `json:"inputs"`
+	Inputs []Assignment `json:"inputs" yaml:"inputs"`
 
 	// Outputs are pairs of output mountpoints and outputted Data of the Run.
-	Outputs []Assignment `json:"outputs"`
+	Outputs []Assignment `json:"outputs" yaml:"outputs"`
 
 	// Log is the log point of the Run.
-	Log *LogSummary `json:"log"`
+	Log *LogSummary `json:"log" yaml:"log"`
+
+	// QueuedAt is the time when the Run is queued to be started.
+	//
+	// This is nil if unknown, for example, the Run is created by an older Knitfab.
+	QueuedAt *rfctime.RFC3339 `json:"queuedAt,omitempty" yaml:"queuedAt,omitempty"`
+
+	// StartedAt is the time when the container of the Run is started.
+	//
+	// This is nil if the Run is not started yet, or unknown.
+	StartedAt *rfctime.RFC3339 `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+
+	// FinishedAt is the time when the Run is finished (done, failed or invalidated).
+	//
+	// This is nil if the Run is not finished yet, or unknown.
+	FinishedAt *rfctime.RFC3339 `json:"finishedAt,omitempty" yaml:"finishedAt,omitempty"`
+
+	// StatusHistory is transitions of the status of the Run, in chronological order.
+	//
+	// This is empty unless requested, because it can be long.
+	StatusHistory StatusHistory `json:"statusHistory,omitempty" yaml:"statusHistory,omitempty"`
+
+	// Worker is the placement of the container of the Run.
+	//
+	// This is nil if the Run has no Worker, or it is unknown.
+	Worker *Worker `json:"worker,omitempty" yaml:"worker,omitempty"`
+
+	// Extensions are custom fields of the Run, set by plugins or experimental features of Knitfab.
+	//
+	// Clients should keep ones they do not know as they are.
+	Extensions extensions.Extensions `json:"x-ext,omitempty" yaml:"x-ext,omitempty"`
 }
 
+// Equal reports whether r and o are same.
+//
+// Fields of the Summary, including Priority and Exit, are compared by Summary.Equal.
 func (r Detail) Equal(o Detail) bool {
+	return r.Summary.Equal(o.Summary) &&
+		apicmp.SliceEqualUnordered(r.Inputs, o.Inputs) &&
+		apicmp.SliceEqualUnordered(r.Outputs, o.Outputs) &&
+		apicmp.PtrEqual(r.Log, o.Log) &&
+		apicmp.PtrEqual(r.QueuedAt, o.QueuedAt) &&
+		apicmp.PtrEqual(r.StartedAt, o.StartedAt) &&
+		apicmp.PtrEqual(r.FinishedAt, o.FinishedAt) &&
+		r.StatusHistory.Equal(o.StatusHistory) &&
+		apicmp.PtrEqual(r.Worker, o.Worker) &&
+		r.Extensions.Equal(o.Extensions)
+}
+
+// Duration returns the time from StartedAt to FinishedAt.
+//
+// If either of them is unknown, it returns false.
+func (r Detail) Duration() (time.Duration, bool) {
+	if r.StartedAt == nil || r.FinishedAt == nil {
+		return 0, false
+	}
+	return r.FinishedAt.Time().Sub(r.StartedAt.Time()), true
+}
 
-	logEq := (r.Log == nil && o.Log == nil) ||
-		(r.Log != nil && o.Log != nil && r.Log.Equal(*o.Log))
+// QueueDuration returns the time from QueuedAt to StartedAt, that is, how long the Run waited to be started.
+//
+// If either of them is unknown, it returns false.
+func (r Detail) QueueDuration() (time.Duration, bool) {
+	if r.QueuedAt == nil || r.StartedAt == nil {
+		return 0, false
+	}
+	return r.StartedAt.Time().Sub(r.QueuedAt.Time()), true
+}
+
+// Elapsed returns the time from StartedAt to FinishedAt, or to now if the Run is not finished.
+//
+// If the Run is not started, it returns false.
+func (r Detail) Elapsed(now time.Time) (time.Duration, bool) {
+	if r.StartedAt == nil {
+		return 0, false
+	}
+	if r.FinishedAt != nil {
+		return r.Duration()
+	}
+	return now.Sub(r.StartedAt.Time()), true
+}
 
-	return r.RunId == o.RunId &&
-		r.Plan.Equal(o.Plan) &&
-		r.Status == o.Status &&
-		r.UpdatedAt.Equal(o.UpdatedAt) &&
-		cmp.SliceEqualUnordered(r.Inputs, o.Inputs) &&
-		cmp.SliceEqualUnordered(r.Outputs, o.Outputs) &&
-		logEq
+// ToSummary returns the Summary of the Run, sharing nothing with r.
+func (r Detail) ToSummary() Summary {
+	return r.Summary.Clone()
 }
 
 type Assignment struct {
-	plans.Mountpoint
-	KnitId string `json:"knitId"`
+	plans.Mountpoint `yaml:",inline"`
+	KnitId           knitid.KnitId `json:"knitId" yaml:"knitId"`
 }
 
 func (a Assignment) Equal(o Assignment) bool {
@@ -126,8 +202,8 @@ func (a Assignment) Equal(o Assignment) bool {
 }
 
 type LogSummary struct {
-	plans.LogPoint
-	KnitId string `json:"knitId"`
+	plans.LogPoint `yaml:",inline"`
+	KnitId         knitid.KnitId `json:"knitId" yaml:"knitId"`
 }
 
 func (l LogSummary) Equal(o LogSummary) bool {
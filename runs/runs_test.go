@@ -0,0 +1,86 @@
+package runs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+)
+
+func TestDetail_timeline(t *testing.T) {
+	at := func(s string) *rfctime.RFC3339 {
+		t.Helper()
+		ts, err := rfctime.ParseRFC3339DateTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &ts
+	}
+	now := at("2024-01-02T04:00:00+09:00").Time()
+
+	detail := runs.Detail{
+		QueuedAt:   at("2024-01-02T03:00:00+09:00"),
+		StartedAt:  at("2024-01-02T03:05:00+09:00"),
+		FinishedAt: at("2024-01-02T03:35:30+09:00"),
+	}
+	if d, ok := detail.Duration(); !ok || d != 30*time.Minute+30*time.Second {
+		t.Errorf("Duration: (%s, %v)", d, ok)
+	}
+	if d, ok := detail.QueueDuration(); !ok || d != 5*time.Minute {
+		t.Errorf("QueueDuration: (%s, %v)", d, ok)
+	}
+	if d, ok := detail.Elapsed(now); !ok || d != 30*time.Minute+30*time.Second {
+		t.Errorf("Elapsed: (%s, %v)", d, ok)
+	}
+
+	running := detail.Clone()
+	running.FinishedAt = nil
+	if _, ok := running.Duration(); ok {
+		t.Error("Duration of running Run is known")
+	}
+	if d, ok := running.Elapsed(now); !ok || d != 55*time.Minute {
+		t.Errorf("Elapsed of running Run: (%s, %v)", d, ok)
+	}
+	if running.Equal(detail) {
+		t.Error("Runs with different FinishedAt are equal")
+	}
+
+	queued := runs.Detail{QueuedAt: detail.QueuedAt}
+	if _, ok := queued.QueueDuration(); ok {
+		t.Error("QueueDuration of queued Run is known")
+	}
+	if _, ok := queued.Elapsed(now); ok {
+		t.Error("Elapsed of queued Run is known")
+	}
+}
+
+func TestDetail_Equal_summary(t *testing.T) {
+	base := runs.Detail{
+		Summary: runs.Summary{
+			RunId:  "0190a1b2-0000-7000-8000-000000000201",
+			Status: runs.Done,
+			Plan:   plans.Summary{PlanId: "0190a1b2-0000-7000-8000-000000000101"},
+			Exit:   &runs.Exit{Code: 0, Message: "Completed"},
+		},
+	}
+	if !base.Equal(base.Clone()) {
+		t.Fatal("clone is not equal")
+	}
+
+	for name, mod := range map[string]func(*runs.Detail){
+		"priority":   func(d *runs.Detail) { d.Priority = plans.PriorityHigh },
+		"exit code":  func(d *runs.Detail) { d.Exit.Code = 1 },
+		"exit unset": func(d *runs.Detail) { d.Exit = nil },
+		"project":    func(d *runs.Detail) { d.Project = "team-a" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			other := base.Clone()
+			mod(&other)
+			if base.Equal(other) || other.Equal(base) {
+				t.Errorf("differences in %s are not detected", name)
+			}
+		})
+	}
+}
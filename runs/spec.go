@@ -0,0 +1,105 @@
+package runs
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+// Spec is the format for request body to Knitfab APIs below:
+//
+// - POST /api/runs
+//
+// It starts a Run of the Plan with the input Data pinned, instead of waiting for Data to be assigned.
+type Spec struct {
+	// PlanId is the id of the Plan to be run.
+	PlanId plans.PlanId `json:"planId" yaml:"planId"`
+
+	// Inputs are knitIds of Data to be assigned, by paths of input mountpoints.
+	Inputs map[string]knitid.KnitId `json:"inputs" yaml:"inputs"`
+
+	// Priority is the priority class of the Run.
+	//
+	// If empty, the priority of the Plan is used.
+	Priority plans.Priority `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+func (s Spec) Equal(o Spec) bool {
+	return s.PlanId == o.PlanId &&
+		maps.Equal(s.Inputs, o.Inputs) &&
+		s.Priority == o.Priority
+}
+
+// Validate checks the Spec is well-formed by itself.
+func (s Spec) Validate() error {
+	if s.PlanId == "" {
+		return fmt.Errorf(`required field missing: "planId"`)
+	}
+	if len(s.Inputs) == 0 {
+		return fmt.Errorf(`"inputs" should have at least one Data`)
+	}
+	for _, path := range slices.Sorted(maps.Keys(s.Inputs)) {
+		if s.Inputs[path] == "" {
+			return fmt.Errorf(`inputs["%s"]: knitId should not be empty`, path)
+		}
+	}
+	if !s.Priority.Valid() {
+		return fmt.Errorf("priority: unknown priority: %q", s.Priority)
+	}
+	return nil
+}
+
+// ValidateFor checks the Spec can start a Run of the plan.
+//
+// All input mountpoints of the plan should be assigned with Data, and no others.
+//
+// If tagsOf is not nil, tags of assigned Data are also checked:
+// Data should have all tags of the input mountpoint ("knit#id" is compared with the assigned knitId).
+// tagsOf should return tags of the Data, or false if the Data is not found.
+func (s Spec) ValidateFor(plan plans.Detail, tagsOf func(knitid.KnitId) ([]tags.Tag, bool)) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.PlanId != plan.PlanId {
+		return fmt.Errorf(`planId: should be "%s": "%s"`, plan.PlanId, s.PlanId)
+	}
+
+	known := map[string]bool{}
+	for _, in := range plan.Inputs {
+		known[in.Path] = true
+		id, ok := s.Inputs[in.Path]
+		if !ok {
+			return fmt.Errorf(`inputs: no Data for "%s"`, in.Path)
+		}
+		if tagsOf == nil {
+			continue
+		}
+		ts, ok := tagsOf(id)
+		if !ok {
+			return fmt.Errorf(`inputs["%s"]: Data not found: %s`, in.Path, id)
+		}
+		has := tags.NewSet(ts...)
+		for _, t := range in.Tags {
+			switch {
+			case t.Key == tags.KeyKnitId:
+				if t.Value == id.String() {
+					continue
+				}
+			case has.Has(t):
+				continue
+			}
+			return fmt.Errorf(`inputs["%s"]: Data %s does not have tag "%s"`, in.Path, id, t)
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(s.Inputs)) {
+		if !known[path] {
+			return fmt.Errorf(`inputs["%s"]: no such input in the Plan`, path)
+		}
+	}
+	return nil
+}
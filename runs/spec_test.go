@@ -0,0 +1,102 @@
+package runs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestSpec_ValidateFor(t *testing.T) {
+	const (
+		planId  plans.PlanId  = "0190a1b2-0000-7000-8000-000000000101"
+		dataset knitid.KnitId = "0190a1b2-0000-7000-8000-000000000301"
+		config  knitid.KnitId = "0190a1b2-0000-7000-8000-000000000302"
+	)
+	plan := plans.Detail{
+		Summary: plans.Summary{PlanId: planId},
+		Inputs: []plans.Input{
+			{Mountpoint: plans.Mountpoint{Path: "/in/dataset", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}}},
+			{Mountpoint: plans.Mountpoint{Path: "/in/config", Tags: []tags.Tag{tags.KnitId(config)}}},
+		},
+	}
+	tagsOf := func(id knitid.KnitId) ([]tags.Tag, bool) {
+		switch id {
+		case dataset:
+			return []tags.Tag{{Key: "type", Value: "dataset"}, {Key: "project", Value: "demo"}, tags.KnitId(dataset)}, true
+		case config:
+			return []tags.Tag{{Key: "type", Value: "config"}, tags.KnitId(config)}, true
+		}
+		return nil, false
+	}
+
+	spec := runs.Spec{
+		PlanId:   planId,
+		Inputs:   map[string]knitid.KnitId{"/in/dataset": dataset, "/in/config": config},
+		Priority: plans.PriorityHigh,
+	}
+	if err := spec.ValidateFor(plan, tagsOf); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, spec)
+
+	for name, testcase := range map[string]struct {
+		spec runs.Spec
+		want string
+	}{
+		"missing input": {
+			spec: runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{"/in/dataset": dataset}},
+			want: `no Data for "/in/config"`,
+		},
+		"unknown input": {
+			spec: runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{
+				"/in/dataset": dataset, "/in/config": config, "/in/extra": dataset,
+			}},
+			want: `inputs["/in/extra"]: no such input`,
+		},
+		"incompatible tags": {
+			spec: runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{"/in/dataset": config, "/in/config": config}},
+			want: `does not have tag "type:dataset"`,
+		},
+		"other knitId": {
+			spec: runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{"/in/dataset": dataset, "/in/config": dataset}},
+			want: `does not have tag "knit#id:` + string(config) + `"`,
+		},
+		"data not found": {
+			spec: runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{
+				"/in/dataset": "0190a1b2-0000-7000-8000-000000000399", "/in/config": config,
+			}},
+			want: "Data not found",
+		},
+		"other plan": {
+			spec: runs.Spec{PlanId: "0190a1b2-0000-7000-8000-000000000102", Inputs: spec.Inputs},
+			want: "planId",
+		},
+		"unknown priority": {
+			spec: runs.Spec{PlanId: planId, Inputs: spec.Inputs, Priority: "asap"},
+			want: "priority",
+		},
+		"no inputs": {
+			spec: runs.Spec{PlanId: planId},
+			want: `"inputs" should have at least one Data`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.spec.ValidateFor(plan, tagsOf)
+			if err == nil || !strings.Contains(err.Error(), testcase.want) {
+				t.Errorf("unexpected error: %v (want %q)", err, testcase.want)
+			}
+		})
+	}
+
+	t.Run("tags are not checked without tagsOf", func(t *testing.T) {
+		s := runs.Spec{PlanId: planId, Inputs: map[string]knitid.KnitId{"/in/dataset": config, "/in/config": config}}
+		if err := s.ValidateFor(plan, nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
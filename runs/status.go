@@ -0,0 +1,123 @@
+package runs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Status is the status of a Run.
+type Status string
+
+const (
+	// Deactivated: This Run is deactivated. It is not going to be running.
+	Deactivated Status = "deactivated"
+
+	// Waiting: This Run is waiting to be running.
+	Waiting Status = "waiting"
+
+	// Ready: This Run is ready to be running. It is waiting for the worker starts.
+	Ready Status = "ready"
+
+	// Starting: This Run is pulling images, or preparing the environment.
+	// The Worker for the Run can be running because of the interval of the periodical health check.
+	Starting Status = "starting"
+
+	// Running: This Run's Worker is running.
+	Running Status = "running"
+
+	// Completing: It is observed that the run's worker has stopped successfully.
+	Completing Status = "completing"
+
+	// Aborting: It is observed, or should be done that the run's worker has stopped insuccessfully.
+	Aborting Status = "aborting"
+
+	// Done: This Run has been finished, successfuly.
+	// The Run's output can be used by other Runs.
+	Done Status = "done"
+
+	// Failed: This Run has been finished with error.
+	Failed Status = "failed"
+
+	// Invalidated: This run was discarded.
+	Invalidated Status = "invalidated"
+)
+
+// Statuses returns all known Statuses, in the order of the lifecycle.
+func Statuses() []Status {
+	return []Status{
+		Deactivated, Waiting, Ready, Starting, Running,
+		Completing, Aborting, Done, Failed, Invalidated,
+	}
+}
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// Valid returns true if s is one of known Statuses.
+func (s Status) Valid() bool {
+	switch s {
+	case Deactivated, Waiting, Ready, Starting, Running,
+		Completing, Aborting, Done, Failed, Invalidated:
+		return true
+	}
+	return false
+}
+
+// IsTerminal returns true if the Run in the status is not going to change its status by itself.
+//
+// These are Done, Failed and Invalidated.
+func (s Status) IsTerminal() bool {
+	switch s {
+	case Done, Failed, Invalidated:
+		return true
+	}
+	return false
+}
+
+// IsProcessing returns true if the Run in the status has a Worker, or is going to have one soon.
+//
+// These are Starting, Running, Completing and Aborting.
+func (s Status) IsProcessing() bool {
+	switch s {
+	case Starting, Running, Completing, Aborting:
+		return true
+	}
+	return false
+}
+
+// Parse parses s as Status, and returns error if it is not known.
+func (s *Status) Parse(v string) error {
+	st := Status(v)
+	if !st.Valid() {
+		return fmt.Errorf("unknown run status: %q", v)
+	}
+	*s = st
+	return nil
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+func (s *Status) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	return s.Parse(v)
+}
+
+func (s Status) MarshalYAML() (interface{}, error) {
+	return string(s), nil
+}
+
+func (s *Status) UnmarshalYAML(node *yaml.Node) error {
+	var v string
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	return s.Parse(v)
+}
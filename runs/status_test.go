@@ -0,0 +1,53 @@
+package runs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/runs"
+	"gopkg.in/yaml.v3"
+)
+
+func TestStatus(t *testing.T) {
+	for _, s := range runs.Statuses() {
+		if !s.Valid() {
+			t.Errorf("%s: should be valid", s)
+		}
+		if s.IsTerminal() && s.IsProcessing() {
+			t.Errorf("%s: should not be terminal and processing at once", s)
+		}
+
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON runs.Status
+		if err := json.Unmarshal(b, &fromJSON); err != nil || fromJSON != s {
+			t.Errorf("%s: JSON round trip: got %s (%v)", s, fromJSON, err)
+		}
+
+		y, err := yaml.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromYAML runs.Status
+		if err := yaml.Unmarshal(y, &fromYAML); err != nil || fromYAML != s {
+			t.Errorf("%s: YAML round trip: got %s (%v)", s, fromYAML, err)
+		}
+	}
+
+	if !runs.Done.IsTerminal() || runs.Running.IsTerminal() {
+		t.Error("IsTerminal is wrong")
+	}
+	if !runs.Starting.IsProcessing() || runs.Waiting.IsProcessing() {
+		t.Error("IsProcessing is wrong")
+	}
+
+	var s runs.Status
+	if err := json.Unmarshal([]byte(`"finished"`), &s); err == nil {
+		t.Errorf("unknown status is accepted: %s", s)
+	}
+	if err := yaml.Unmarshal([]byte(`finished`), &s); err == nil {
+		t.Errorf("unknown status is accepted: %s", s)
+	}
+}
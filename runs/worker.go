@@ -0,0 +1,42 @@
+package runs
+
+import (
+	"github.com/opst/knitfab-api-types/internal/clone"
+	"github.com/opst/knitfab-api-types/plans"
+)
+
+// Worker is where and how the container of a Run is placed.
+type Worker struct {
+	// Node is the name of the node where the Worker is scheduled.
+	//
+	// This is empty if the Worker is not scheduled yet.
+	Node string `json:"node,omitempty" yaml:"node,omitempty"`
+
+	// Pod is the name of the Pod of the Worker.
+	Pod string `json:"pod,omitempty" yaml:"pod,omitempty"`
+
+	// Image is the image actually pulled, with its digest.
+	//
+	// This is nil if the image is not pulled yet.
+	Image *plans.Image `json:"image,omitempty" yaml:"image,omitempty"`
+
+	// RestartCount is how many times the container of the Worker has been restarted.
+	RestartCount int32 `json:"restartCount" yaml:"restartCount"`
+}
+
+func (w Worker) Equal(o Worker) bool {
+	return w.Node == o.Node &&
+		w.Pod == o.Pod &&
+		w.Image.Equal(o.Image) &&
+		w.RestartCount == o.RestartCount
+}
+
+// Clone returns a deep copy of the Worker.
+func (w Worker) Clone() Worker {
+	return Worker{
+		Node:         w.Node,
+		Pod:          w.Pod,
+		Image:        clone.Ptr(w.Image),
+		RestartCount: w.RestartCount,
+	}
+}
@@ -0,0 +1,80 @@
+package runs_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/extensions"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/runs"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetail_roundTrip(t *testing.T) {
+	updatedAt, err := rfctime.ParseRFC3339DateTime("2024-01-02T03:04:05.678+09:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := runs.Detail{
+		Summary: runs.Summary{
+			RunId:     "0190a1b2-0000-7000-8000-000000000201",
+			Status:    runs.Failed,
+			UpdatedAt: updatedAt,
+			Exit:      &runs.Exit{Code: 137, Message: "Error", Signal: 9, OOMKilled: true, Reason: runs.ReasonOOMKilled},
+			Plan: plans.Summary{
+				PlanId:      "0190a1b2-0000-7000-8000-000000000101",
+				Image:       &plans.Image{Repository: "example.com/train", Tag: "v1"},
+				Entrypoint:  []string{"python"},
+				Args:        []string{"train.py"},
+				Annotations: plans.Annotations{{Key: "owner", Value: "ml-team"}},
+			},
+			Priority: plans.PriorityHigh,
+			Project:  "example",
+		},
+		Inputs: []runs.Assignment{
+			{
+				Mountpoint: plans.Mountpoint{Path: "/in", Tags: []tags.Tag{{Key: "type", Value: "dataset"}}, ReadOnly: true},
+				KnitId:     "0190a1b2-0000-7000-8000-000000000302",
+			},
+		},
+		Outputs: []runs.Assignment{
+			{
+				Mountpoint: plans.Mountpoint{Path: "/out", Tags: []tags.Tag{{Key: "type", Value: "model"}}},
+				KnitId:     "0190a1b2-0000-7000-8000-000000000301",
+			},
+		},
+		Log: &runs.LogSummary{
+			LogPoint: plans.LogPoint{Tags: []tags.Tag{{Key: "type", Value: "log"}}},
+			KnitId:   "0190a1b2-0000-7000-8000-000000000303",
+		},
+		StartedAt:  &updatedAt,
+		FinishedAt: &updatedAt,
+		Worker: &runs.Worker{
+			Node:         "gpu-node-1",
+			Pod:          "worker-0190a1b2",
+			Image:        &plans.Image{Repository: "example.com/train", Tag: "v1", Digest: "sha256:" + strings.Repeat("0", 64)},
+			RestartCount: 1,
+		},
+		Extensions: extensions.Extensions{
+			"example.com/cost": json.RawMessage(`{"currency": "USD", "amount": 1.5}`),
+		},
+	}
+
+	knittest.AssertRoundTrip(t, detail)
+
+	// keys in YAML are same as ones in JSON.
+	b, err := yaml.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"runId:", "updatedAt:", "planId:", "knitId:", "path: /in", "priority: high", "project: example", "read_only: true", "startedAt:", "finishedAt:", "restartCount: 1", "oomKilled: true", "x-ext:", "currency: USD"} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("missing %q in:\n%s", key, b)
+		}
+	}
+}
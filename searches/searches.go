@@ -0,0 +1,131 @@
+package searches
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+	"github.com/opst/knitfab-api-types/projects"
+	"github.com/opst/knitfab-api-types/query"
+)
+
+// Kind is the kind of resources to be searched.
+type Kind string
+
+const (
+	KindData  Kind = "data"
+	KindRuns  Kind = "runs"
+	KindPlans Kind = "plans"
+)
+
+// Valid returns true if k is one of known kinds.
+func (k Kind) Valid() bool {
+	switch k {
+	case KindData, KindRuns, KindPlans:
+		return true
+	}
+	return false
+}
+
+// Spec is the format for request body to Knitfab APIs below:
+//
+// - POST /api/searches/
+//
+// - PUT  /api/searches/{name}
+type Spec struct {
+	// Name is the name of the saved search. It is unique in the cluster.
+	Name string `json:"name"`
+
+	// Description is the human readable description of the saved search.
+	Description string `json:"description,omitempty"`
+
+	// Kind is the kind of resources to be searched.
+	Kind Kind `json:"kind"`
+
+	// Project is the name of the Project which the search is scoped to.
+	//
+	// If empty, the search is not scoped.
+	Project string `json:"project,omitempty"`
+
+	// Query is the structured query for Data.
+	//
+	// This is available only for KindData, and mutually exclusive with Find.
+	Query *query.Query `json:"query,omitempty"`
+
+	// Find is the query parameters for list endpoints,
+	// like GET /api/data/?{Find} or GET /api/runs/?{Find}.
+	//
+	// This and Query are mutually exclusive.
+	Find url.Values `json:"find,omitempty"`
+
+	// Sort is the order of results, like "updatedAt:desc".
+	Sort []string `json:"sort,omitempty"`
+}
+
+func (s Spec) Equal(o Spec) bool {
+	queryEq := (s.Query == nil && o.Query == nil) ||
+		(s.Query != nil && o.Query != nil && s.Query.String() == o.Query.String())
+	return s.Name == o.Name &&
+		s.Description == o.Description &&
+		s.Kind == o.Kind &&
+		s.Project == o.Project &&
+		queryEq &&
+		apicmp.MapEqualWith(s.Find, o.Find, apicmp.SliceEqEq[string]) &&
+		apicmp.SliceEqEq(s.Sort, o.Sort)
+}
+
+// Validate checks the Spec is well-formed.
+func (s Spec) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf(`required field missing: "name"`)
+	}
+	if !s.Kind.Valid() {
+		return fmt.Errorf(`"kind" should be one of "%s", "%s" or "%s": %q`, KindData, KindRuns, KindPlans, s.Kind)
+	}
+	if err := projects.ValidateName(s.Project); err != nil {
+		return err
+	}
+	if s.Query != nil {
+		if s.Kind != KindData {
+			return fmt.Errorf(`"query" is available only for kind "%s"`, KindData)
+		}
+		if 0 < len(s.Find) {
+			return fmt.Errorf(`"query" and "find" are mutually exclusive`)
+		}
+	}
+	return nil
+}
+
+// Detail is the format for response body from Knitfab APIs below:
+//
+// - GET    /api/searches/ (as list)
+//
+// - POST   /api/searches/
+//
+// - GET    /api/searches/{name}
+//
+// - PUT    /api/searches/{name}
+//
+// Other saved search related WebAPI do not use this for response.
+//
+// - DELETE /api/searches/{name}: empty response ("204 No Content" on success)
+type Detail struct {
+	Spec
+
+	// Owner is the user who created the saved search.
+	Owner string `json:"owner"`
+
+	// CreatedAt is the time when the saved search was created.
+	CreatedAt rfctime.RFC3339 `json:"createdAt"`
+
+	// UpdatedAt is the time when the saved search was updated at last.
+	UpdatedAt rfctime.RFC3339 `json:"updatedAt"`
+}
+
+func (d Detail) Equal(o Detail) bool {
+	return d.Spec.Equal(o.Spec) &&
+		d.Owner == o.Owner &&
+		d.CreatedAt.Equal(o.CreatedAt) &&
+		d.UpdatedAt.Equal(o.UpdatedAt)
+}
@@ -0,0 +1,69 @@
+package searches_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/query"
+	"github.com/opst/knitfab-api-types/searches"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestSpec_Validate(t *testing.T) {
+	q := &query.Query{Expr: query.Tag{Tag: tags.Tag{Key: "project", Value: "demo"}}}
+
+	for name, testcase := range map[string]struct {
+		When    searches.Spec
+		WantErr bool
+	}{
+		"data with query": {
+			When: searches.Spec{Name: "recent", Kind: searches.KindData, Query: q},
+		},
+		"runs with find": {
+			When: searches.Spec{
+				Name: "running", Kind: searches.KindRuns,
+				Find: url.Values{"status": {"running"}},
+			},
+		},
+		"plans in project": {
+			When: searches.Spec{Name: "mine", Kind: searches.KindPlans, Project: "demo"},
+		},
+		"missing name": {
+			When:    searches.Spec{Kind: searches.KindData},
+			WantErr: true,
+		},
+		"missing kind": {
+			When:    searches.Spec{Name: "recent"},
+			WantErr: true,
+		},
+		"unknown kind": {
+			When:    searches.Spec{Name: "recent", Kind: "tags"},
+			WantErr: true,
+		},
+		"invalid project": {
+			When:    searches.Spec{Name: "recent", Kind: searches.KindData, Project: "Demo_Project"},
+			WantErr: true,
+		},
+		"query for runs": {
+			When:    searches.Spec{Name: "recent", Kind: searches.KindRuns, Query: q},
+			WantErr: true,
+		},
+		"query with find": {
+			When: searches.Spec{
+				Name: "recent", Kind: searches.KindData, Query: q,
+				Find: url.Values{"tag": {"project:demo"}},
+			},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid spec is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid spec is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package stars
+
+import (
+	"fmt"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// Star is the format for response body from Knitfab APIs below:
+//
+// - GET  /api/stars/ (as list)
+//
+// - POST /api/stars/
+//
+// Other star related WebAPI do not use this for response.
+//
+// - DELETE /api/stars/{kind}/{id}: empty response ("204 No Content" on success)
+type Star struct {
+	meta.ResourceRef
+
+	// User is the user who starred the resource.
+	User string `json:"user"`
+
+	// CreatedAt is the time when the resource was starred.
+	CreatedAt rfctime.RFC3339 `json:"createdAt"`
+}
+
+func (s Star) Equal(o Star) bool {
+	return s.ResourceRef.Equal(o.ResourceRef) &&
+		s.User == o.User &&
+		s.CreatedAt.Equal(o.CreatedAt)
+}
+
+// Request is the format for request body to Knitfab APIs below:
+//
+// - POST /api/stars/
+//
+// The user starring is the requester.
+type Request struct {
+	meta.ResourceRef
+}
+
+// Validate checks the request refers a Plan or a Data.
+func (r Request) Validate() error {
+	if err := r.ResourceRef.Validate(); err != nil {
+		return err
+	}
+	if r.Kind != meta.KindPlan && r.Kind != meta.KindData {
+		return fmt.Errorf(`only "%s" and "%s" can be starred: %q`, meta.KindPlan, meta.KindData, r.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package stars_test
+
+import (
+	"testing"
+
+	"github.com/opst/knitfab-api-types/meta"
+	"github.com/opst/knitfab-api-types/stars"
+)
+
+func TestRequest_Validate(t *testing.T) {
+	for name, testcase := range map[string]struct {
+		When    stars.Request
+		WantErr bool
+	}{
+		"plan": {
+			When: stars.Request{ResourceRef: meta.ResourceRef{Kind: meta.KindPlan, Id: "0190a1b2-0000-7000-8000-000000000301"}},
+		},
+		"data": {
+			When: stars.Request{ResourceRef: meta.ResourceRef{Kind: meta.KindData, Id: "0190a1b2-0000-7000-8000-000000000302"}},
+		},
+		"run": {
+			When:    stars.Request{ResourceRef: meta.ResourceRef{Kind: meta.KindRun, Id: "0190a1b2-0000-7000-8000-000000000303"}},
+			WantErr: true,
+		},
+		"unknown kind": {
+			When:    stars.Request{ResourceRef: meta.ResourceRef{Kind: "pipeline", Id: "0190a1b2-0000-7000-8000-000000000304"}},
+			WantErr: true,
+		},
+		"missing id": {
+			When:    stars.Request{ResourceRef: meta.ResourceRef{Kind: meta.KindPlan}},
+			WantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := testcase.When.Validate()
+			if testcase.WantErr && err == nil {
+				t.Errorf("invalid request is accepted: %+v", testcase.When)
+			}
+			if !testcase.WantErr && err != nil {
+				t.Errorf("valid request is rejected: %v", err)
+			}
+		})
+	}
+}
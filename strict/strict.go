@@ -0,0 +1,225 @@
+// Package strict decodes JSON and YAML documents into API types, rejecting unknown fields.
+//
+// The standard decoders silently drop fields not in the destination type,
+// so a typo like "on_nodes" in a PlanSpec is ignored. Functions in this package
+// report such fields with their paths in the document, like "$.inputs[0].pth".
+//
+// Types with their own UnmarshalJSON/UnmarshalYAML (like plans.Image or tags.Tag)
+// are not looked into; they validate their contents by themselves.
+package strict
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownFieldError is the error for a field not in the destination type.
+type UnknownFieldError struct {
+	// Path is the path to the object having the field, like "$.inputs[0]".
+	Path string
+
+	// Field is the name of the unknown field.
+	Field string
+
+	// Type is the name of the destination type of the object.
+	Type string
+}
+
+func (e UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q at %s (in %s)", e.Field, e.Path, e.Type)
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	yamlUnmarshaler = reflect.TypeFor[yaml.Unmarshaler]()
+)
+
+// JSON decodes b into T, and returns UnknownFieldError if b has fields not in T.
+func JSON[T any](b []byte) (T, error) {
+	var zero T
+
+	var raw any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return zero, err
+	}
+	c := checker{tag: "json", unmarshaler: jsonUnmarshaler, foldCase: true}
+	if err := c.check(raw, reflect.TypeFor[T](), "$"); err != nil {
+		return zero, err
+	}
+
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return zero, err
+	}
+	return v, nil
+}
+
+// YAML decodes b into T, and returns UnknownFieldError if b has fields not in T.
+func YAML[T any](b []byte) (T, error) {
+	var zero T
+
+	var raw any
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return zero, err
+	}
+	c := checker{tag: "yaml", unmarshaler: yamlUnmarshaler}
+	if err := c.check(raw, reflect.TypeFor[T](), "$"); err != nil {
+		return zero, err
+	}
+
+	var v T
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return zero, err
+	}
+	return v, nil
+}
+
+type checker struct {
+	// tag is the struct tag name for field names.
+	tag string
+
+	// unmarshaler is the interface of types decoding themselves.
+	unmarshaler reflect.Type
+
+	// foldCase makes field names case-insensitive, as encoding/json does.
+	foldCase bool
+}
+
+func (c checker) check(v any, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(c.unmarshaler) || reflect.PointerTo(t).Implements(c.unmarshaler) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := asObject(v)
+		if !ok {
+			return nil // type mismatch is reported by the decoder.
+		}
+		fields := c.fields(t)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			ft, ok := c.lookup(fields, k)
+			if !ok {
+				return UnknownFieldError{Path: path, Field: k, Type: t.String()}
+			}
+			if err := c.check(obj[k], ft, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range arr {
+			if err := c.check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		obj, ok := asObject(v)
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			if err := c.check(obj[k], t.Elem(), path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c checker) lookup(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if t, ok := fields[key]; ok {
+		return t, true
+	}
+	if !c.foldCase {
+		return nil, false
+	}
+	for name, t := range fields {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// fields returns the names of fields of t in documents, with their types.
+//
+// Fields of embedded structs are promoted.
+func (c checker) fields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(c.tag)
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		// encoding/json promotes fields of untagged embedded structs, and yaml.v3 does for ",inline" fields.
+		promote := f.Anonymous && name == "" && ft.Kind() == reflect.Struct
+		if c.tag == "yaml" {
+			promote = slices.Contains(strings.Split(opts, ","), "inline")
+		}
+		if promote {
+			for n, t := range c.fields(ft) {
+				if _, ok := fields[n]; !ok {
+					fields[n] = t
+				}
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+			if c.tag == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func asObject(v any) (map[string]any, bool) {
+	switch o := v.(type) {
+	case map[string]any:
+		return o, true
+	case map[any]any:
+		m := make(map[string]any, len(o))
+		for k, v := range o {
+			m[fmt.Sprint(k)] = v
+		}
+		return m, true
+	}
+	return nil, false
+}
@@ -0,0 +1,120 @@
+package strict_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/data"
+	"github.com/opst/knitfab-api-types/plans"
+	"github.com/opst/knitfab-api-types/strict"
+)
+
+func TestYAML(t *testing.T) {
+	valid := `
+image: "example.com/train:v1"
+inputs:
+  - path: /in
+    tags: ["type:dataset"]
+outputs:
+  - path: /out
+    tags: ["type:model"]
+on_node:
+  must: ["accelerator=gpu"]
+resources:
+  cpu: "1"
+`
+	got, err := strict.YAML[plans.PlanSpec]([]byte(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Image.Repository != "example.com/train" || got.OnNode == nil || len(got.OnNode.Must) != 1 {
+		t.Errorf("unexpected spec: %+v", got)
+	}
+
+	for name, tc := range map[string]struct {
+		doc  string
+		path string
+		key  string
+	}{
+		"top level": {
+			doc:  "image: x:v1\ninputs: []\non_nodes:\n  must: []\n",
+			path: "$", key: "on_nodes",
+		},
+		"nested": {
+			doc:  "image: x:v1\ninputs:\n  - pth: /in\n    tags: []\n",
+			path: "$.inputs[0]", key: "pth",
+		},
+		"in pointer": {
+			doc:  "image: x:v1\ninputs: []\non_node:\n  musts: []\n",
+			path: "$.on_node", key: "musts",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := strict.YAML[plans.PlanSpec]([]byte(tc.doc))
+			var ufe strict.UnknownFieldError
+			if !errors.As(err, &ufe) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ufe.Path != tc.path || ufe.Field != tc.key {
+				t.Errorf("unexpected error: %+v", ufe)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	valid := `{
+		"knitId": "0190a1b2-0000-7000-8000-000000000301",
+		"tags": ["type:dataset"],
+		"upstream": {"run": {"runId": "0190a1b2-0000-7000-8000-000000000201", "status": "done", "updatedAt": "2024-01-01T00:00:00Z", "plan": {"planId": "0190a1b2-0000-7000-8000-000000000101"}}},
+		"downstreams": [],
+		"nomination": [{"path": "/in", "tags": ["type:dataset"], "plan": {"planId": "0190a1b2-0000-7000-8000-000000000102"}}]
+	}`
+	got, err := strict.JSON[data.Detail]([]byte(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.KnitId != "0190a1b2-0000-7000-8000-000000000301" || len(got.Nomination) != 1 {
+		t.Errorf("unexpected detail: %+v", got)
+	}
+
+	// encoding/json matches field names case-insensitively.
+	if _, err := strict.JSON[data.Detail]([]byte(`{"KnitID": "0190a1b2-0000-7000-8000-000000000301"}`)); err != nil {
+		t.Errorf("case-insensitive field is rejected: %v", err)
+	}
+
+	_, err = strict.JSON[data.Detail]([]byte(`{"knitId": "0190a1b2-0000-7000-8000-000000000301", "nomination": [{"path": "/in", "plan": {"planid": "0190a1b2-0000-7000-8000-000000000101", "imgae": "x"}}]}`))
+	var ufe strict.UnknownFieldError
+	if !errors.As(err, &ufe) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ufe.Path != "$.nomination[0].plan" || ufe.Field != "imgae" {
+		t.Errorf("unexpected error: %+v", ufe)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	doc := `{
+		"knitId": "0190a1b2-0000-7000-8000-000000000301",
+		"x-ext": {"example.com/lineage": {"source": "s3://bucket/key", "anything": [1, {"goes": true}]}}
+	}`
+	got, err := strict.JSON[data.Detail]([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Extensions.Has("example.com/lineage") {
+		t.Errorf("extension is dropped: %+v", got.Extensions)
+	}
+
+	yml := "image: x:v1\ninputs: []\n"
+	if _, err := strict.YAML[plans.PlanSpec]([]byte(yml + "x-ext:\n  example.com/flag: true\n")); err == nil {
+		t.Error("PlanSpec, which has no extensions, accepts x-ext")
+	}
+
+	// unknown fields outside of extensions are still rejected.
+	_, err = strict.JSON[data.Detail]([]byte(`{"knitId": "0190a1b2-0000-7000-8000-000000000301", "x-ext": {}, "tgas": []}`))
+	var ufe strict.UnknownFieldError
+	if !errors.As(err, &ufe) || ufe.Field != "tgas" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
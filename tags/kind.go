@@ -0,0 +1,11 @@
+package tags
+
+import "github.com/opst/knitfab-api-types/meta"
+
+// KindChange is the kind of Change, for meta.TypeMeta.
+const KindChange = "TagChange"
+
+// AddToScheme registers kinds of this package to s.
+func AddToScheme(s *meta.Scheme) error {
+	return meta.Register[Change](s, KindChange)
+}
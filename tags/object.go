@@ -0,0 +1,78 @@
+package tags
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ObjectTag is a Tag marshalled in the object form, {"key": "...", "value": "..."}.
+//
+// Tag is marshalled in the string form "key:value".
+// Convert Tag to ObjectTag when consumers want the object form.
+// Both of Tag and ObjectTag can be unmarshalled from either form.
+type ObjectTag Tag
+
+// tagObject is the object form of tags.
+type tagObject struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+func (ot ObjectTag) String() string {
+	return Tag(ot).String()
+}
+
+func (ot ObjectTag) Equal(o ObjectTag) bool {
+	return Tag(ot).Equal(Tag(o))
+}
+
+func (ot ObjectTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tagObject(ot))
+}
+
+func (ot *ObjectTag) UnmarshalJSON(data []byte) error {
+	t := Tag{}
+	if err := t.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*ot = ObjectTag(t)
+	return nil
+}
+
+func (ot ObjectTag) MarshalYAML() (interface{}, error) {
+	return tagObject(ot), nil
+}
+
+func (ot *ObjectTag) UnmarshalYAML(n *yaml.Node) error {
+	t := Tag{}
+	if err := t.UnmarshalYAML(n); err != nil {
+		return err
+	}
+	*ot = ObjectTag(t)
+	return nil
+}
+
+// AsObjects converts tags to be marshalled in the object form.
+func AsObjects(ts []Tag) []ObjectTag {
+	if ts == nil {
+		return nil
+	}
+	ret := make([]ObjectTag, 0, len(ts))
+	for _, t := range ts {
+		ret = append(ret, ObjectTag(t))
+	}
+	return ret
+}
+
+// FromObjects converts ObjectTags back to Tags.
+func FromObjects(ots []ObjectTag) []Tag {
+	if ots == nil {
+		return nil
+	}
+	ret := make([]Tag, 0, len(ots))
+	for _, ot := range ots {
+		ret = append(ret, Tag(ot))
+	}
+	return ret
+}
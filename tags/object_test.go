@@ -0,0 +1,75 @@
+package tags_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+func TestObjectTag(t *testing.T) {
+	ts := []tags.Tag{
+		{Key: "type", Value: "csv"},
+		{Key: "note", Value: "has:colon"},
+		{Key: tags.KeyKnitTimestamp, Value: "2024-01-02T03:04:05+09:00"},
+	}
+	ots := tags.AsObjects(ts)
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(ots)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `[{"key":"type","value":"csv"},{"key":"note","value":"has:colon"},` +
+			`{"key":"knit#timestamp","value":"2024-01-02T03:04:05+09:00"}]`
+		if string(b) != want {
+			t.Errorf("json.Marshal: got %s", b)
+		}
+
+		// object form can be read as Tag, and string form can be read as ObjectTag.
+		asTags := []tags.Tag{}
+		if err := json.Unmarshal(b, &asTags); err != nil {
+			t.Fatal(err)
+		}
+		if !apicmp.SliceEqual(asTags, ts) {
+			t.Errorf("json.Unmarshal as Tag: got %v", asTags)
+		}
+
+		s, err := json.Marshal(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		asObjects := []tags.ObjectTag{}
+		if err := json.Unmarshal(s, &asObjects); err != nil {
+			t.Fatal(err)
+		}
+		if !apicmp.SliceEqual(tags.FromObjects(asObjects), ts) {
+			t.Errorf("json.Unmarshal as ObjectTag: got %v", asObjects)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		b, err := yaml.Marshal(ots[:1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "- key: type\n  value: csv\n"; string(b) != want {
+			t.Errorf("yaml.Marshal: got %s", b)
+		}
+
+		asTags := []tags.Tag{}
+		if err := yaml.Unmarshal(b, &asTags); err != nil {
+			t.Fatal(err)
+		}
+		if !apicmp.SliceEqual(asTags, ts[:1]) {
+			t.Errorf("yaml.Unmarshal as Tag: got %v", asTags)
+		}
+	})
+
+	for _, ot := range ots {
+		knittest.AssertRoundTrip(t, ot)
+	}
+}
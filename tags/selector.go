@@ -0,0 +1,268 @@
+package tags
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"gopkg.in/yaml.v3"
+)
+
+// Requirement is a condition on tags, a term of Selector.
+//
+// Its string form is one of:
+//
+// - "key:value": tags should have the tag.
+//
+// - "key:*" or "key": tags should have a tag with the key, whatever its value is.
+//
+// - "key in (value1, value2, ...)": tags should have a tag with the key and one of the values.
+//
+// - "key notin (value1, value2, ...)": tags should not have a tag with the key and any of the values.
+//
+// Each form can be negated by prefixing "!", like "!type:log" or "!type:*".
+//
+// To make this type from user inputted value, use Requirement.Parse method.
+type Requirement struct {
+	// Key is the key of tags to be examined.
+	Key string
+
+	// Values are the acceptable values of the tag.
+	//
+	// If nil, any value is acceptable.
+	Values []string
+
+	// Not negates the Requirement.
+	Not bool
+}
+
+var setRequirement = regexp.MustCompile(`^(.+?)\s+(in|notin)\s*\((.*)\)$`)
+
+// Parse parses s as Requirement.
+func (r *Requirement) Parse(s string) error {
+	ret := Requirement{}
+
+	expr := strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(expr, "!"); ok {
+		ret.Not = true
+		expr = strings.TrimSpace(rest)
+	}
+
+	if m := setRequirement.FindStringSubmatch(expr); m != nil {
+		ret.Key = strings.TrimSpace(m[1])
+		if m[2] == "notin" {
+			ret.Not = !ret.Not
+		}
+		ret.Values = []string{}
+		if set := strings.TrimSpace(m[3]); set != "" {
+			for _, v := range strings.Split(set, ",") {
+				v = strings.TrimSpace(v)
+				if v == "" {
+					return fmt.Errorf("tag selector parse error: %s: empty value in set", s)
+				}
+				ret.Values = append(ret.Values, v)
+			}
+		}
+	} else if k, v, ok := strings.Cut(expr, ":"); ok {
+		ret.Key = strings.TrimSpace(k)
+		if v = strings.TrimSpace(v); v != "*" {
+			ret.Values = []string{v}
+		}
+	} else {
+		ret.Key = expr
+	}
+
+	if ret.Key == "" {
+		return fmt.Errorf("tag selector parse error: %s :no key", s)
+	}
+
+	*r = ret
+	return nil
+}
+
+func (r Requirement) String() string {
+	not := ""
+	if r.Not {
+		not = "!"
+	}
+	switch len(r.Values) {
+	case 0:
+		if r.Values == nil {
+			return not + r.Key + ":*"
+		}
+		return not + r.Key + " in ()"
+	case 1:
+		return not + r.Key + ":" + r.Values[0]
+	default:
+		return not + r.Key + " in (" + strings.Join(r.Values, ", ") + ")"
+	}
+}
+
+// Matches returns true if ts satisfy the Requirement.
+func (r Requirement) Matches(ts []Tag) bool {
+	has := slices.ContainsFunc(ts, func(t Tag) bool {
+		if t.Key != r.Key {
+			return false
+		}
+		if r.Values == nil {
+			return true
+		}
+		return slices.ContainsFunc(r.Values, func(v string) bool {
+			return t.Equal(Tag{Key: r.Key, Value: v})
+		})
+	})
+	return has != r.Not
+}
+
+func (r Requirement) Equal(o Requirement) bool {
+	return r.Key == o.Key &&
+		r.Not == o.Not &&
+		(r.Values == nil) == (o.Values == nil) &&
+		apicmp.SliceEqEqUnordered(r.Values, o.Values)
+}
+
+func (r Requirement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Requirement) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return r.Parse(s)
+}
+
+func (r Requirement) MarshalYAML() (interface{}, error) {
+	return yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Value: r.String(),
+		Style: yaml.DoubleQuotedStyle,
+	}, nil
+}
+
+func (r *Requirement) UnmarshalYAML(n *yaml.Node) error {
+	var s string
+	if err := n.Decode(&s); err != nil {
+		return err
+	}
+	return r.Parse(s)
+}
+
+// Selector selects tags satisfying all of its Requirements.
+//
+// Its string form is Requirements joined with ",", like "project:foo, type:*, !type:log".
+// Values containing "," cannot be written in the string form;
+// use the list form ([]Requirement) in JSON or YAML instead.
+//
+// Selector is marshalled as a list of Requirements,
+// and can be unmarshalled from both of the list form and the string form.
+//
+// Empty Selector matches any tags.
+type Selector []Requirement
+
+// ParseSelector parses s as Selector.
+func ParseSelector(s string) (Selector, error) {
+	ret := Selector{}
+
+	depth := 0
+	begin := 0
+	terms := []string{}
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth += 1
+		case ')':
+			depth -= 1
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[begin:i])
+				begin = i + 1
+			}
+		}
+	}
+	terms = append(terms, s[begin:])
+
+	for _, term := range terms {
+		if strings.TrimSpace(term) == "" {
+			if len(terms) == 1 {
+				break
+			}
+			return nil, fmt.Errorf("tag selector parse error: %s: empty requirement", s)
+		}
+		r := Requirement{}
+		if err := r.Parse(term); err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+// Parse parses s as Selector, and sets it to sel.
+func (sel *Selector) Parse(s string) error {
+	parsed, err := ParseSelector(s)
+	if err != nil {
+		return err
+	}
+	*sel = parsed
+	return nil
+}
+
+func (sel Selector) String() string {
+	terms := make([]string, 0, len(sel))
+	for _, r := range sel {
+		terms = append(terms, r.String())
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Matches returns true if ts satisfy all Requirements of the Selector.
+func (sel Selector) Matches(ts []Tag) bool {
+	for _, r := range sel {
+		if !r.Matches(ts) {
+			return false
+		}
+	}
+	return true
+}
+
+func (sel Selector) Equal(o Selector) bool {
+	return apicmp.SliceEqualUnordered(sel, o)
+}
+
+func (sel *Selector) UnmarshalJSON(data []byte) error {
+	{
+		s := new(string)
+		if err := json.Unmarshal(data, s); err == nil {
+			return sel.Parse(*s)
+		}
+	}
+
+	rs := []Requirement{}
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return err
+	}
+	*sel = rs
+	return nil
+}
+
+func (sel *Selector) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		s := new(string)
+		if err := n.Decode(s); err != nil {
+			return err
+		}
+		return sel.Parse(*s)
+	}
+
+	rs := []Requirement{}
+	if err := n.Decode(&rs); err != nil {
+		return err
+	}
+	*sel = rs
+	return nil
+}
@@ -0,0 +1,130 @@
+package tags_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/tags"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	ts := []tags.Tag{
+		{Key: "project", Value: "foo"},
+		{Key: "type", Value: "csv"},
+		{Key: tags.KeyKnitTimestamp, Value: "2024-01-02T03:04:05+09:00"},
+	}
+
+	for expr, want := range map[string]bool{
+		"":                                    true,
+		"project:foo":                         true,
+		"project:bar":                         false,
+		"type:*":                              true,
+		"type":                                true,
+		"owner":                               false,
+		"!type:log":                           true,
+		"!type:csv":                           false,
+		"!owner":                              true,
+		"type in (json, csv)":                 true,
+		"type in (json, log)":                 false,
+		"type notin (json, log)":              true,
+		"type notin (csv)":                    false,
+		"type in ()":                          false,
+		"project:foo, type:*":                 true,
+		"project:foo, !type:csv":              false,
+		"project in (a,b), type:*":            false,
+		"knit#timestamp:2024-01-01T18:04:05Z": true,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			sel, err := tags.ParseSelector(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sel.Matches(ts); got != want {
+				t.Errorf("%q.Matches(%v): got %v", expr, ts, got)
+			}
+		})
+	}
+}
+
+func TestSelector_Parse(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"project:foo",
+		"!type:log",
+		"type:*",
+		"!type:*",
+		"type in (json, csv)",
+		"!type in (json, csv)",
+		"type in ()",
+		"note:has:colon, type:*",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			sel, err := tags.ParseSelector(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sel.String(); got != expr {
+				t.Errorf("String(): got %q", got)
+			}
+			knittest.AssertRoundTrip(t, sel)
+		})
+	}
+
+	for _, expr := range []string{":foo", "!", "type in (a,,b)", "a:b,,c:d"} {
+		t.Run("malformed "+expr, func(t *testing.T) {
+			if sel, err := tags.ParseSelector(expr); err == nil {
+				t.Errorf("malformed selector is accepted: %#v", sel)
+			}
+		})
+	}
+}
+
+func TestSelector_unmarshal(t *testing.T) {
+	want := tags.Selector{
+		{Key: "project", Values: []string{"foo"}},
+		{Key: "type", Values: []string{"log"}, Not: true},
+		{Key: "note", Values: []string{"a,b"}},
+	}
+
+	t.Run("json list", func(t *testing.T) {
+		got := tags.Selector{}
+		if err := json.Unmarshal([]byte(`["project:foo", "!type:log", "note:a,b"]`), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("got %#v", got)
+		}
+	})
+
+	t.Run("json string", func(t *testing.T) {
+		got := tags.Selector{}
+		if err := json.Unmarshal([]byte(`"project:foo, !type:log"`), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want[:2]) {
+			t.Errorf("got %#v", got)
+		}
+	})
+
+	t.Run("yaml list", func(t *testing.T) {
+		got := tags.Selector{}
+		if err := yaml.Unmarshal([]byte("- project:foo\n- \"!type:log\"\n- note:a,b\n"), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("got %#v", got)
+		}
+	})
+
+	t.Run("yaml string", func(t *testing.T) {
+		got := tags.Selector{}
+		if err := yaml.Unmarshal([]byte(`"project:foo, !type:log"`), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want[:2]) {
+			t.Errorf("got %#v", got)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package tags
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set is a set of tags, deduplicated as Knitfab does.
+//
+// - User tags are multi-valued: tags with the same key and different values can be in a Set.
+//
+// - System tags are single-valued: when a system tag is added, tags with the same key are replaced.
+//
+// Use NewSet to make a Set from a slice of tags.
+// Set is marshalled as a list of tags, sorted by key and value.
+type Set []Tag
+
+// NewSet makes a Set of ts.
+//
+// When ts contain system tags with the same key, the last one wins.
+func NewSet(ts ...Tag) Set {
+	s := Set{}
+	s.Add(ts...)
+	return s
+}
+
+func compareTag(a, b Tag) int {
+	if c := strings.Compare(a.Key, b.Key); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Value, b.Value)
+}
+
+// Add adds tags to the Set.
+func (s *Set) Add(ts ...Tag) {
+	for _, t := range ts {
+		if t.IsSystem() {
+			*s = slices.DeleteFunc(*s, func(e Tag) bool { return e.Key == t.Key })
+		} else if s.Has(t) {
+			continue
+		}
+		i, _ := slices.BinarySearchFunc(*s, t, compareTag)
+		*s = slices.Insert(*s, i, t)
+	}
+}
+
+// Remove removes tags from the Set.
+func (s *Set) Remove(ts ...Tag) {
+	*s = slices.DeleteFunc(*s, func(e Tag) bool {
+		return slices.ContainsFunc(ts, e.Equal)
+	})
+}
+
+// RemoveKey removes tags with the keys from the Set.
+func (s *Set) RemoveKey(keys ...string) {
+	*s = slices.DeleteFunc(*s, func(e Tag) bool {
+		return slices.Contains(keys, e.Key)
+	})
+}
+
+// Has returns true if the Set contains t.
+func (s Set) Has(t Tag) bool {
+	return slices.ContainsFunc(s, t.Equal)
+}
+
+// Merge adds all tags in o to the Set.
+//
+// System tags in o take precedence over ones in the Set.
+func (s *Set) Merge(o Set) {
+	s.Add(o...)
+}
+
+// Slice returns tags in the Set, sorted by key and value.
+func (s Set) Slice() []Tag {
+	ret := append([]Tag{}, s...)
+	slices.SortFunc(ret, compareTag)
+	return ret
+}
+
+func (s Set) Equal(o Set) bool {
+	a, b := NewSet(s...), NewSet(o...)
+	return len(a) == len(b) && !slices.ContainsFunc(a, func(t Tag) bool { return !b.Has(t) })
+}
+
+func (s Set) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+func (s *Set) UnmarshalJSON(data []byte) error {
+	ts := []Tag{}
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return err
+	}
+	*s = NewSet(ts...)
+	return nil
+}
+
+func (s Set) MarshalYAML() (interface{}, error) {
+	return s.Slice(), nil
+}
+
+func (s *Set) UnmarshalYAML(n *yaml.Node) error {
+	ts := []Tag{}
+	if err := n.Decode(&ts); err != nil {
+		return err
+	}
+	*s = NewSet(ts...)
+	return nil
+}
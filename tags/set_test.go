@@ -0,0 +1,86 @@
+package tags_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestSet(t *testing.T) {
+	s := tags.NewSet(
+		tags.Tag{Key: "type", Value: "csv"},
+		tags.Tag{Key: "type", Value: "json"},
+		tags.Tag{Key: "type", Value: "csv"},
+		tags.Tag{Key: tags.KeyKnitTransient, Value: tags.ValueKnitTransientProcessing},
+		tags.Tag{Key: tags.KeyKnitTransient, Value: tags.ValueKnitTransientFailed},
+	)
+
+	want := []tags.Tag{
+		{Key: tags.KeyKnitTransient, Value: tags.ValueKnitTransientFailed},
+		{Key: "type", Value: "csv"},
+		{Key: "type", Value: "json"},
+	}
+	if got := s.Slice(); !apicmp.SliceEqual(got, want) {
+		t.Errorf("NewSet: got %v", got)
+	}
+
+	if !s.Has(tags.Tag{Key: "type", Value: "json"}) || s.Has(tags.Tag{Key: "type", Value: "log"}) {
+		t.Errorf("Has: unexpected result for %v", s)
+	}
+
+	s.Remove(tags.Tag{Key: "type", Value: "csv"})
+	s.RemoveKey(tags.KeyKnitTransient)
+	if got := s.Slice(); !apicmp.SliceEqual(got, []tags.Tag{{Key: "type", Value: "json"}}) {
+		t.Errorf("Remove: got %v", got)
+	}
+
+	s.Merge(tags.NewSet(
+		tags.Tag{Key: "project", Value: "foo"},
+		tags.Tag{Key: "type", Value: "json"},
+	))
+	want = []tags.Tag{
+		{Key: "project", Value: "foo"},
+		{Key: "type", Value: "json"},
+	}
+	if got := s.Slice(); !apicmp.SliceEqual(got, want) {
+		t.Errorf("Merge: got %v", got)
+	}
+}
+
+func TestSet_marshal(t *testing.T) {
+	s := tags.Set{
+		{Key: "type", Value: "json"},
+		{Key: "project", Value: "foo"},
+		{Key: tags.KeyKnitTimestamp, Value: "2024-01-02T03:04:05+09:00"},
+	}
+
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `["knit#timestamp:2024-01-02T03:04:05+09:00","project:foo","type:json"]`
+	if string(got) != want {
+		t.Errorf("json.Marshal: got %s", got)
+	}
+
+	empty, err := json.Marshal(tags.Set(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(empty) != "[]" {
+		t.Errorf("json.Marshal(nil): got %s", empty)
+	}
+
+	dup := tags.Set{}
+	if err := json.Unmarshal([]byte(`["type:json","type:json","knit#transient:processing","knit#transient:failed"]`), &dup); err != nil {
+		t.Fatal(err)
+	}
+	if !dup.Equal(tags.Set{{Key: "type", Value: "json"}, {Key: tags.KeyKnitTransient, Value: tags.ValueKnitTransientFailed}}) {
+		t.Errorf("json.Unmarshal: got %v", dup)
+	}
+
+	knittest.AssertRoundTrip(t, s)
+}
@@ -0,0 +1,124 @@
+package tags
+
+import (
+	"strings"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/misc/rfctime"
+)
+
+// TransientState is the value of the system tag "knit#transient".
+type TransientState string
+
+const (
+	// TransientProcessing means that the Data is being written by a Run.
+	TransientProcessing TransientState = TransientState(ValueKnitTransientProcessing)
+
+	// TransientFailed means that the Run writing the Data has been failed.
+	TransientFailed TransientState = TransientState(ValueKnitTransientFailed)
+)
+
+func (s TransientState) String() string {
+	return string(s)
+}
+
+// Valid returns true if s is one of known TransientStates.
+func (s TransientState) Valid() bool {
+	switch s {
+	case TransientProcessing, TransientFailed:
+		return true
+	}
+	return false
+}
+
+// IsSystem returns true if the tag is a system tag, whose key starts with "knit#".
+func (t Tag) IsSystem() bool {
+	return strings.HasPrefix(t.Key, SystemTagPrefix)
+}
+
+// KnitId returns the system tag "knit#id" for the Data.
+func KnitId(id knitid.KnitId) Tag {
+	return Tag{Key: KeyKnitId, Value: id.String()}
+}
+
+// Timestamp returns the system tag "knit#timestamp" for the time.
+func Timestamp(t time.Time) Tag {
+	return Tag{Key: KeyKnitTimestamp, Value: rfctime.RFC3339(t).String()}
+}
+
+// Transient returns the system tag "knit#transient" for the state.
+func Transient(state TransientState) Tag {
+	return Tag{Key: KeyKnitTransient, Value: state.String()}
+}
+
+// ExpiresAt returns the system tag "knit#expires-at" for the time.
+//
+// Data with the tag is a candidate to be purged after the time.
+func ExpiresAt(t time.Time) Tag {
+	return Tag{Key: KeyKnitExpiresAt, Value: rfctime.RFC3339(t).String()}
+}
+
+// FindKnitId returns the value of the system tag "knit#id" in ts.
+//
+// If ts does not have the tag, it returns false.
+func FindKnitId(ts []Tag) (knitid.KnitId, bool) {
+	for _, t := range ts {
+		if t.Key == KeyKnitId {
+			return knitid.KnitId(t.Value), true
+		}
+	}
+	return "", false
+}
+
+// FindTimestamp returns the value of the system tag "knit#timestamp" in ts.
+//
+// If ts does not have the tag or its value is malformed, it returns false.
+func FindTimestamp(ts []Tag) (rfctime.RFC3339, bool) {
+	for _, t := range ts {
+		if t.Key != KeyKnitTimestamp {
+			continue
+		}
+		v, err := rfctime.ParseRFC3339DateTime(t.Value)
+		if err != nil {
+			continue
+		}
+		return v, true
+	}
+	return rfctime.RFC3339{}, false
+}
+
+// FindTransient returns the value of the system tag "knit#transient" in ts.
+//
+// If ts does not have the tag, the Data is not transient and it returns false.
+func FindTransient(ts []Tag) (TransientState, bool) {
+	for _, t := range ts {
+		if t.Key == KeyKnitTransient {
+			return TransientState(t.Value), true
+		}
+	}
+	return "", false
+}
+
+// FindExpiresAt returns the value of the system tag "knit#expires-at" in ts.
+//
+// If ts does not have the tag or its value is malformed, it returns false.
+func FindExpiresAt(ts []Tag) (rfctime.RFC3339, bool) {
+	for _, t := range ts {
+		if t.Key != KeyKnitExpiresAt {
+			continue
+		}
+		v, err := rfctime.ParseRFC3339DateTime(t.Value)
+		if err != nil {
+			continue
+		}
+		return v, true
+	}
+	return rfctime.RFC3339{}, false
+}
+
+// Expired returns true if ts has the system tag "knit#expires-at" and the time is not after now.
+func Expired(ts []Tag, now time.Time) bool {
+	at, ok := FindExpiresAt(ts)
+	return ok && !now.Before(at.Time())
+}
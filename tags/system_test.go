@@ -0,0 +1,81 @@
+package tags_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opst/knitfab-api-types/knitid"
+	"github.com/opst/knitfab-api-types/tags"
+)
+
+func TestSystemTags(t *testing.T) {
+	const id knitid.KnitId = "0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b"
+	at := time.Date(2024, 1, 2, 3, 4, 5, 678_000_000, time.FixedZone("", 9*60*60))
+
+	ts := []tags.Tag{
+		{Key: "type", Value: "csv"},
+		tags.KnitId(id),
+		tags.Timestamp(at),
+		tags.Transient(tags.TransientProcessing),
+	}
+
+	for _, tag := range ts[1:] {
+		parsed := tags.Tag{}
+		if err := parsed.Parse(tag.String()); err != nil {
+			t.Errorf("system tag %s is not parsable: %v", tag, err)
+		}
+		if !tag.IsSystem() {
+			t.Errorf("%s is not a system tag", tag)
+		}
+	}
+	if ts[0].IsSystem() {
+		t.Errorf("%s is a system tag", ts[0])
+	}
+
+	if got, ok := tags.FindKnitId(ts); !ok || got != id {
+		t.Errorf("FindKnitId: got (%q, %v)", got, ok)
+	}
+	if got, ok := tags.FindTimestamp(ts); !ok || !got.Time().Equal(at) {
+		t.Errorf("FindTimestamp: got (%s, %v)", got, ok)
+	}
+	if got, ok := tags.FindTransient(ts); !ok || got != tags.TransientProcessing {
+		t.Errorf("FindTransient: got (%q, %v)", got, ok)
+	}
+
+	none := ts[:1]
+	if _, ok := tags.FindKnitId(none); ok {
+		t.Error("FindKnitId: found in user tags")
+	}
+	if _, ok := tags.FindTimestamp(none); ok {
+		t.Error("FindTimestamp: found in user tags")
+	}
+	if _, ok := tags.FindTransient(none); ok {
+		t.Error("FindTransient: found in user tags")
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts := []tags.Tag{{Key: "type", Value: "csv"}, tags.ExpiresAt(at)}
+
+	parsed := tags.Tag{}
+	if err := parsed.Parse("knit#expires-at:2024-01-02T12:04:05+09:00"); err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(ts[1]) {
+		t.Errorf("%s should equal to %s", parsed, ts[1])
+	}
+	if err := parsed.Parse("knit#expires-at:tomorrow"); err == nil {
+		t.Error("malformed expires-at is accepted")
+	}
+
+	if got, ok := tags.FindExpiresAt(ts); !ok || !got.Time().Equal(at) {
+		t.Errorf("FindExpiresAt: got (%s, %v)", got, ok)
+	}
+	if tags.Expired(ts, at.Add(-time.Second)) || !tags.Expired(ts, at) {
+		t.Error("unexpected expiry")
+	}
+	if tags.Expired(ts[:1], at) {
+		t.Error("Data without expires-at should not expire")
+	}
+}
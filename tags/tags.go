@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"github.com/opst/knitfab-api-types/apicmp"
 	"github.com/opst/knitfab-api-types/misc/rfctime"
 	"gopkg.in/yaml.v3"
 )
@@ -16,6 +17,7 @@ const (
 	KeyKnitId                    string = SystemTagPrefix + "id"
 	KeyKnitTimestamp             string = SystemTagPrefix + "timestamp"
 	KeyKnitTransient             string = SystemTagPrefix + "transient"
+	KeyKnitExpiresAt             string = SystemTagPrefix + "expires-at"
 	ValueKnitTransientFailed     string = "failed"
 	ValueKnitTransientProcessing string = "processing"
 )
@@ -37,7 +39,7 @@ func (a Tag) Equal(b Tag) bool {
 		return false
 	}
 
-	if a.Key != KeyKnitTimestamp {
+	if a.Key != KeyKnitTimestamp && a.Key != KeyKnitExpiresAt {
 		return a.Value == b.Value
 	}
 
@@ -63,7 +65,7 @@ func (t *Tag) Parse(s string) error {
 	v = strings.TrimSpace(v)
 
 	switch k {
-	case KeyKnitTimestamp:
+	case KeyKnitTimestamp, KeyKnitExpiresAt:
 		_, err := rfctime.ParseRFC3339DateTime(v)
 		if err != nil {
 			return fmt.Errorf("tag parse error: %s is not timestamp", s)
@@ -97,7 +99,7 @@ type UserTag Tag
 //
 // - bool: true if the tag is not system tag.
 func (t Tag) AsUserTag(ut *UserTag) bool {
-	if strings.HasPrefix(t.Key, SystemTagPrefix) {
+	if t.IsSystem() {
 		return false
 	}
 	*ut = UserTag(t)
@@ -163,7 +165,7 @@ func (ut *UserTag) Parse(s string) error {
 	if err := t.Parse(s); err != nil {
 		return err
 	}
-	if strings.HasPrefix(t.Key, SystemTagPrefix) {
+	if t.IsSystem() {
 		return fmt.Errorf(`tag key "%s..." is reserved for system tags`, SystemTagPrefix)
 	}
 	*ut = UserTag(*t)
@@ -211,7 +213,7 @@ func (ut *UserTag) UnmarshalJSON(data []byte) error {
 	if err := t.UnmarshalJSON(data); err != nil {
 		return err
 	}
-	if strings.HasPrefix(t.Key, SystemTagPrefix) {
+	if t.IsSystem() {
 		return fmt.Errorf(`tag key "%s..." is reserved for system tags`, SystemTagPrefix)
 	}
 	*ut = UserTag(*t)
@@ -261,7 +263,19 @@ func (c *Change) UnmarshalJSON(data []byte) error {
 
 func (c *Change) Equal(o *Change) bool {
 
-	return cmp.SliceEqualUnordered(c.AddTags, o.AddTags) &&
-		cmp.SliceEqualUnordered(c.RemoveTags, o.RemoveTags) &&
-		cmp.SliceEqEqUnordered(c.RemoveKey, o.RemoveKey)
+	return apicmp.SliceEqualUnordered(c.AddTags, o.AddTags) &&
+		apicmp.SliceEqualUnordered(c.RemoveTags, o.RemoveTags) &&
+		apicmp.SliceEqEqUnordered(c.RemoveKey, o.RemoveKey)
+}
+
+// Clone returns a deep copy of the Change.
+func (c *Change) Clone() *Change {
+	if c == nil {
+		return nil
+	}
+	return &Change{
+		AddTags:    slices.Clone(c.AddTags),
+		RemoveTags: slices.Clone(c.RemoveTags),
+		RemoveKey:  slices.Clone(c.RemoveKey),
+	}
 }
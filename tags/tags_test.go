@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/opst/knitfab-api-types/internal/utils/cmp"
+	"github.com/opst/knitfab-api-types/apicmp"
 	"github.com/opst/knitfab-api-types/tags"
 )
 
@@ -40,7 +40,7 @@ func TestTagParsing(t *testing.T) {
 			{Key: "aaa", Value: "bbb:ccc"},
 		}
 
-		if !cmp.SliceEqualUnordered(expectedTags, parsedTags) {
+		if !apicmp.SliceEqualUnordered(expectedTags, parsedTags) {
 			t.Errorf(
 				"did not match:\n=== expected === \n%+v\n=== actual ===\n%+v",
 				expectedTags, parsedTags,
@@ -0,0 +1,149 @@
+// Package webhooks defines types of webhook subscriptions and their deliveries.
+//
+// Knitfab posts a Payload to the URL of each Subscription matching an Event,
+// with the HMAC-SHA256 signature of the body in the header HeaderSignature.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/opst/knitfab-api-types/apicmp"
+	"github.com/opst/knitfab-api-types/events"
+)
+
+// Headers of webhook deliveries.
+const (
+	// HeaderSignature is the header holding the signature of the body, like "sha256=<hex>".
+	HeaderSignature = "X-Knitfab-Signature"
+
+	// HeaderEvent is the header holding the type of the event.
+	HeaderEvent = "X-Knitfab-Event"
+
+	// HeaderDelivery is the header holding the id of the delivery.
+	HeaderDelivery = "X-Knitfab-Delivery"
+)
+
+// signaturePrefix is the prefix of signatures, naming the algorithm.
+const signaturePrefix = "sha256="
+
+// ErrSignatureMismatch is returned by Verify when the signature does not match the body.
+var ErrSignatureMismatch = errors.New("signature mismatch")
+
+// Subscription is the format for request and response body for Knitfab APIs below:
+//
+// - GET    /api/webhooks/ (as list)
+//
+// - POST   /api/webhooks/
+//
+// - GET    /api/webhooks/{id}
+//
+// - DELETE /api/webhooks/{id}: empty response ("204 No Content" on success)
+type Subscription struct {
+	// Id is the id of the Subscription, assigned by Knitfab.
+	//
+	// It is ignored in requests.
+	Id string `json:"id,omitempty"`
+
+	// URL is where Payloads are posted to.
+	URL string `json:"url"`
+
+	// Events are EventTypes to be delivered.
+	//
+	// If empty, all Events are delivered.
+	Events []events.EventType `json:"events,omitempty"`
+
+	// Secret is the key to sign Payloads.
+	//
+	// It is write-only: responses do not have this.
+	Secret string `json:"secret,omitempty"`
+}
+
+func (s Subscription) Equal(o Subscription) bool {
+	return s.Id == o.Id &&
+		s.URL == o.URL &&
+		s.Secret == o.Secret &&
+		apicmp.SliceEqEqUnordered(s.Events, o.Events)
+}
+
+// Validate checks the URL is an absolute http(s) URL and Events are known.
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf(`url: should be an absolute http(s) URL: %q`, s.URL)
+	}
+	for i, t := range s.Events {
+		if !t.Valid() {
+			return fmt.Errorf("events[%d]: unknown event type: %q", i, t)
+		}
+	}
+	return nil
+}
+
+// Matches returns true if Events of the type are delivered to the Subscription.
+func (s Subscription) Matches(t events.EventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, t)
+}
+
+// Redacted returns the Subscription without Secret, for responses.
+func (s Subscription) Redacted() Subscription {
+	s.Events = slices.Clone(s.Events)
+	s.Secret = ""
+	return s
+}
+
+// Payload is the body posted to the URL of Subscriptions.
+type Payload struct {
+	// DeliveryId is the id of the delivery. It is also in the header HeaderDelivery.
+	//
+	// Retried deliveries have the same id, so receivers can deduplicate them.
+	DeliveryId string `json:"deliveryId"`
+
+	// Subscription is the id of the Subscription.
+	Subscription string `json:"subscription"`
+
+	// Event is the delivered Event.
+	Event events.Event `json:"event"`
+}
+
+func (p Payload) Equal(o Payload) bool {
+	return p.DeliveryId == o.DeliveryId &&
+		p.Subscription == o.Subscription &&
+		p.Event.Equal(o.Event)
+}
+
+// Sign returns the signature of body with secret, as the value of HeaderSignature.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature (the value of HeaderSignature) is of body with secret.
+//
+// It returns ErrSignatureMismatch if not.
+func Verify(secret string, body []byte, signature string) error {
+	sig, ok := strings.CutPrefix(signature, signaturePrefix)
+	if !ok {
+		return fmt.Errorf(`%s: should start with "%s"`, HeaderSignature, signaturePrefix)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("%s: %w", HeaderSignature, err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
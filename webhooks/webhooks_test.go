@@ -0,0 +1,73 @@
+package webhooks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/opst/knitfab-api-types/events"
+	"github.com/opst/knitfab-api-types/knittest"
+	"github.com/opst/knitfab-api-types/webhooks"
+)
+
+func TestSubscription(t *testing.T) {
+	s := webhooks.Subscription{
+		Id:     "hook-1",
+		URL:    "https://hooks.example.com/knitfab",
+		Events: []events.EventType{events.RunStatusChanged},
+		Secret: "s3cr3t",
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	knittest.AssertRoundTrip(t, s)
+
+	if !s.Matches(events.RunStatusChanged) || s.Matches(events.DataCreated) {
+		t.Error("unexpected match")
+	}
+	if r := s.Redacted(); r.Secret != "" || r.URL != s.URL || s.Secret == "" {
+		t.Errorf("unexpected redaction: %+v", r)
+	}
+
+	for name, invalid := range map[string]webhooks.Subscription{
+		"relative url":  {URL: "/knitfab"},
+		"unknown event": {URL: s.URL, Events: []events.EventType{"run.exploded"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := invalid.Validate(); err == nil {
+				t.Errorf("invalid subscription is accepted: %+v", invalid)
+			}
+		})
+	}
+}
+
+func TestSignature(t *testing.T) {
+	body, err := json.Marshal(webhooks.Payload{
+		DeliveryId:   "delivery-1",
+		Subscription: "hook-1",
+		Event:        events.Event{Id: "1", Type: "node.added"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := webhooks.Sign("s3cr3t", body)
+	if err := webhooks.Verify("s3cr3t", body, sig); err != nil {
+		t.Errorf("valid signature is rejected: %v", err)
+	}
+	if err := webhooks.Verify("other", body, sig); !errors.Is(err, webhooks.ErrSignatureMismatch) {
+		t.Errorf("signature with other secret: %v", err)
+	}
+	if err := webhooks.Verify("s3cr3t", append(body, ' '), sig); !errors.Is(err, webhooks.ErrSignatureMismatch) {
+		t.Errorf("signature of other body: %v", err)
+	}
+	if err := webhooks.Verify("s3cr3t", body, "md5=00"); err == nil {
+		t.Error("signature of unknown algorithm is accepted")
+	}
+
+	// known answer of HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	const want = "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := webhooks.Sign("key", []byte("The quick brown fox jumps over the lazy dog")); got != want {
+		t.Errorf("Sign: got %s, want %s", got, want)
+	}
+}